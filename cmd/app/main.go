@@ -3,10 +3,18 @@ package main
 import (
 	"github.com/EM-Stawberry/Stawberry/internal/adapter/auth"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/audit"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/autoresponse"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/category"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/currency"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/idempotency"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/inventory"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/notification"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/outbox"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/token"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/wanted"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/webhook"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/repository"
 	"github.com/EM-Stawberry/Stawberry/pkg/database"
@@ -20,8 +28,12 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	guestofferservice "github.com/EM-Stawberry/Stawberry/internal/domain/service/guestoffer"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offer"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offermessage"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offerstream"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/order"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/product"
 	"github.com/EM-Stawberry/Stawberry/internal/handler"
 	guesthandler "github.com/EM-Stawberry/Stawberry/internal/handler/guestoffer"
@@ -54,9 +66,20 @@ func main() {
 
 	database.DefaultAdminAcc()
 
-	router, mailer, auditMiddleware := initializeApp(cfg, db, log)
+	router, mailer, auditMiddleware, expiryWorker, wantedCloseWorker, outboxRelay, deliveryWorker,
+		idempotencyCleanupWorker, offerUpdateListener, offerStream := initializeApp(cfg, db, log)
 
-	if err := server.StartServer(router, mailer, &cfg.Server, log); err != nil {
+	expiryWorker.Start()
+	wantedCloseWorker.Start()
+	outboxRelay.Start()
+	deliveryWorker.Start()
+	idempotencyCleanupWorker.Start()
+	offerUpdateListener.Start()
+
+	err := server.StartServer(router, mailer, &cfg.Server, log,
+		expiryWorker, wantedCloseWorker, outboxRelay, deliveryWorker, idempotencyCleanupWorker,
+		offerUpdateListener, offerStream)
+	if err != nil {
 		log.Fatal("Failed to start server", zap.Error(err))
 	}
 
@@ -70,12 +93,27 @@ func initializeApp(
 ) (
 	*gin.Engine,
 	email.MailerService,
-	*middleware.AuditMiddleware) {
+	*middleware.AuditMiddleware,
+	*offer.ExpiryWorker,
+	*wanted.CloseWorker,
+	*outbox.Relay,
+	*webhook.DeliveryWorker,
+	*idempotency.CleanupWorker,
+	*repository.OfferUpdateListener,
+	*offerstream.Bus) {
 	mailer := email.NewMailer(log, &cfg.Email)
 	log.Info("Mailer initialized")
 
 	productRepository := repository.NewProductRepository(db)
+	inventoryRepository := repository.NewInventoryRepository(db)
+	categoryRepository := repository.NewCategoryRepository(db)
 	offerRepository := repository.NewOfferRepository(db)
+	offerMessageRepository := repository.NewOfferMessageRepository(db)
+	orderRepository := repository.NewOrderRepository(db)
+	autoResponseRepository := repository.NewAutoResponseRepository(db)
+	pricingPolicyRepository := repository.NewPricingPolicyRepository(db)
+	wantedRepository := repository.NewWantedRepository(db)
+	currencyRepository := repository.NewCurrencyRepository(db)
 	userRepository := repository.NewUserRepository(db)
 	notificationRepository := repository.NewNotificationRepository(db)
 	tokenRepository := repository.NewTokenRepository(db)
@@ -83,13 +121,33 @@ func initializeApp(
 	sellerReviewsRepository := repo.NewSellerReviewRepository(db, log)
 	auditRepository := repository.NewAuditRepository(db)
 	guestOfferRepository := guestofferrepo.NewRepository(db)
+	offerUpdateNotifier := repository.NewOfferUpdateNotifier(db)
+	idempotencyRepository := repository.NewIdempotencyRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
 	log.Info("Repositories initialized")
 
 	passwordManager := security.NewArgon2idPasswordManager()
 	jwtManager := auth.NewJWTManager(cfg.Token.Secret)
 
-	productService := product.NewService(productRepository)
-	offerService := offer.NewService(offerRepository, mailer)
+	currencyService := currency.NewService(currencyRepository)
+	productService := product.NewService(productRepository, cfg.Currency.Display)
+	webhookService := webhook.NewService(webhookRepository, offerRepository, &cfg.Webhook)
+	offerStream := offerstream.NewBus(offerRepository, offerUpdateNotifier, log)
+	webhookSink := outbox.NewWebhookSink(webhookService)
+	outboxRelay := outbox.NewRelay(outboxRepository, &cfg.Outbox, log)
+	outboxRelay.Register(entity.OutboxTopicOfferEvent, "offer_stream", outbox.NewOfferEventSink(offerStream))
+	outboxRelay.Register(entity.OutboxTopicOfferEvent, "webhooks", webhookSink)
+	outboxRelay.Register(entity.OutboxTopicGuestOffer, "webhooks", webhookSink)
+	outboxRelay.Register(entity.OutboxTopicEmail, "mailer", outbox.NewMailSink(mailer))
+	pricingPolicy := offer.NewPricingPolicy(pricingPolicyRepository, currencyService, &cfg.Pricing)
+	offerService := offer.NewService(offerRepository, pricingPolicy, currencyService, outboxRelay, &cfg.Quota)
+	offerMessageService := offermessage.NewService(offerMessageRepository, outboxRelay)
+	inventoryService := inventory.NewService(inventoryRepository, outboxRelay, &cfg.Inventory)
+	categoryService := category.NewService(categoryRepository)
+	orderService := order.NewService(orderRepository)
+	autoResponseService := autoresponse.NewService(autoResponseRepository)
+	wantedService := wanted.NewService(wantedRepository, outboxRelay, &cfg.Wanted)
 	tokenService := token.NewService(
 		tokenRepository,
 		jwtManager,
@@ -101,12 +159,30 @@ func initializeApp(
 	productReviewsService := reviews.NewProductReviewService(productReviewsRepository, log)
 	sellerReviewsService := reviews.NewSellerReviewService(sellerReviewsRepository, log)
 	auditService := audit.NewAuditService(auditRepository)
-	guestOfferService := guestofferservice.NewService(guestOfferRepository, mailer, log)
+	guestOfferService := guestofferservice.NewService(guestOfferRepository, guestOfferRepository, pricingPolicy,
+		outboxRelay, &cfg.GuestOffer, log)
+	idempotencyService := idempotency.NewService(idempotencyRepository, &cfg.Idempotency)
 	log.Info("Services initialized")
 
+	expiryWorker := offer.NewExpiryWorker(offerRepository, outboxRelay, &cfg.Expiry, log)
+	wantedCloseWorker := wanted.NewCloseWorker(wantedRepository, &cfg.Wanted, log)
+	deliveryWorker := webhook.NewDeliveryWorker(webhookRepository, &cfg.Webhook, log)
+	idempotencyCleanupWorker := idempotency.NewCleanupWorker(idempotencyRepository, &cfg.Idempotency, log)
+	offerUpdateListener := repository.NewOfferUpdateListener(cfg.DB.GetDBConnString(), offerStream, log)
+
 	healthHandler := handler.NewHealthHandler()
 	productHandler := handler.NewProductHandler(productService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService, log)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	currencyHandler := handler.NewCurrencyHandler(currencyService)
 	offerHandler := handler.NewOfferHandler(offerService)
+	offerStreamHandler := handler.NewOfferStreamHandler(offerStream)
+	offerMessageHandler := handler.NewOfferMessageHandler(offerMessageService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	autoResponseHandler := handler.NewAutoResponseHandler(autoResponseService)
+	pricingPolicyHandler := handler.NewPricingPolicyHandler(pricingPolicy)
+	wantedHandler := handler.NewWantedHandler(wantedService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 	userHandler := handler.NewUserHandler(cfg, userService)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
 	productReviewsHandler := hdlr.NewProductReviewHandler(productReviewsService, log)
@@ -120,7 +196,17 @@ func initializeApp(
 	router := handler.SetupRouter(
 		healthHandler,
 		productHandler,
+		inventoryHandler,
+		categoryHandler,
+		currencyHandler,
 		offerHandler,
+		offerStreamHandler,
+		offerMessageHandler,
+		orderHandler,
+		autoResponseHandler,
+		pricingPolicyHandler,
+		wantedHandler,
+		webhookHandler,
 		userHandler,
 		notificationHandler,
 		productReviewsHandler,
@@ -128,11 +214,14 @@ func initializeApp(
 		guestOfferHandler,
 		userService,
 		tokenService,
+		idempotencyService,
 		basePath,
+		cfg.Server.TrustedProxies,
 		log,
 		auditMiddleware,
 		auditHandler,
 	)
 
-	return router, mailer, auditMiddleware
+	return router, mailer, auditMiddleware, expiryWorker, wantedCloseWorker, outboxRelay, deliveryWorker,
+		idempotencyCleanupWorker, offerUpdateListener, offerStream
 }
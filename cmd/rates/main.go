@@ -0,0 +1,82 @@
+// Команда rates загружает курсы валют к EUR в таблицу currency_rates.
+//
+//	go run ./cmd/rates -f eurofxref-daily.xml
+//	go run ./cmd/rates -f rates.csv
+//
+// Поддерживаются XML ЕЦБ (eurofxref-daily.xml, из eurofxref-hist.xml берётся последний день)
+// и CSV с заголовком currency,rate. Формат определяется по расширению файла или флагу --format.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/currency"
+	"github.com/EM-Stawberry/Stawberry/internal/repository"
+	"github.com/EM-Stawberry/Stawberry/pkg/database"
+	"github.com/EM-Stawberry/Stawberry/pkg/logger"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+const (
+	formatCSV = "csv"
+	formatECB = "ecb"
+)
+
+func main() {
+	file := flag.StringP("file", "f", "", "file with currency rates to EUR")
+	format := flag.String("format", "", "file format: csv or ecb (by default from the file extension)")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	log := logger.SetupLogger(cfg.Environment)
+
+	if *file == "" {
+		log.Fatal("rates file is required, pass it with -f")
+	}
+	if *format == "" {
+		*format = formatCSV
+		if strings.EqualFold(filepath.Ext(*file), ".xml") {
+			*format = formatECB
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("Failed to open rates file", zap.Error(err))
+	}
+	defer f.Close()
+
+	rates, err := parseRates(f, *format)
+	if err != nil {
+		log.Fatal("Failed to parse rates file", zap.Error(err), zap.String("format", *format))
+	}
+
+	db, closer := database.InitDB(&cfg.DB, log)
+	defer closer()
+
+	loaded, err := currency.NewService(repository.NewCurrencyRepository(db)).LoadRates(context.Background(), rates)
+	if err != nil {
+		log.Fatal("Failed to load currency rates", zap.Error(err))
+	}
+
+	log.Info("Currency rates loaded", zap.Int("count", loaded), zap.String("file", *file))
+}
+
+func parseRates(r io.Reader, format string) ([]entity.CurrencyRate, error) {
+	switch format {
+	case formatECB:
+		return currency.ParseECB(r)
+	case formatCSV:
+		return currency.ParseCSV(r)
+	default:
+		return nil, fmt.Errorf("unknown rates file format %q, expected csv or ecb", format)
+	}
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	flag "github.com/spf13/pflag"
@@ -38,6 +40,9 @@ type ServerConfig struct {
 	Domain  string
 	Port    string
 	GinMode string
+	// TrustedProxies адреса и подсети прокси, которым верим X-Forwarded-For.
+	// Пустой список значит, что адрес клиента берётся только из соединения
+	TrustedProxies []string
 }
 
 type TokenConfig struct {
@@ -62,6 +67,70 @@ type AuditConfig struct {
 	BatchSize      int
 }
 
+type OfferExpiryConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// IdempotencyConfig сроки хранения ключей Idempotency-Key. Lease - сколько ключ остаётся занятым
+// запросом без ответа: если процесс упал посреди запроса, после него ключ можно занять снова.
+type IdempotencyConfig struct {
+	TTL             time.Duration
+	Lease           time.Duration
+	CleanupInterval time.Duration
+}
+
+type WebhookConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+	// AllowPrivateHosts разрешает вебхуки на внутренние адреса, только для локальной разработки
+	AllowPrivateHosts bool
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// OfferQuotaConfig лимиты офферов одного покупателя, 0 отключает лимит
+type OfferQuotaConfig struct {
+	MaxOpen         int
+	MaxPerHour      int
+	DeclineCooldown time.Duration
+}
+
+type OfferPricingConfig struct {
+	DefaultMinPricePercent float64
+}
+
+type CurrencyConfig struct {
+	Display string
+}
+
+type WantedConfig struct {
+	MaxDuration    time.Duration
+	CloseInterval  time.Duration
+	CloseBatchSize int
+}
+
+// InventoryConfig ограничения импорта инвентаря магазина из файла
+type InventoryConfig struct {
+	ImportMaxRows  int
+	ImportMaxBytes int64
+}
+
+type GuestOfferConfig struct {
+	LinkURL     string
+	LimitWindow time.Duration
+	MaxPerEmail int
+	MaxPerIP    int
+}
+
 type Config struct {
 	AccessKey     string
 	SecretKey     string
@@ -70,11 +139,21 @@ type Config struct {
 	SigningRegion string
 	Environment   string
 
-	DB     DBConfig
-	Server ServerConfig
-	Token  TokenConfig
-	Email  EmailConfig
-	Audit  AuditConfig
+	DB          DBConfig
+	Server      ServerConfig
+	Token       TokenConfig
+	Email       EmailConfig
+	Audit       AuditConfig
+	Expiry      OfferExpiryConfig
+	Idempotency IdempotencyConfig
+	Webhook     WebhookConfig
+	Outbox      OutboxConfig
+	GuestOffer  GuestOfferConfig
+	Pricing     OfferPricingConfig
+	Quota       OfferQuotaConfig
+	Currency    CurrencyConfig
+	Wanted      WantedConfig
+	Inventory   InventoryConfig
 }
 
 func LoadConfig() *Config {
@@ -97,6 +176,35 @@ func LoadConfig() *Config {
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
 	viper.SetDefault("SERVER_PORT", 8080)
 	viper.SetDefault("AUDIT_BATCH_SIZE", 100)
+	viper.SetDefault("OFFER_EXPIRY_INTERVAL", time.Minute)
+	viper.SetDefault("OFFER_EXPIRY_BATCH_SIZE", 100)
+	viper.SetDefault("IDEMPOTENCY_TTL", 24*time.Hour)
+	viper.SetDefault("IDEMPOTENCY_LEASE", time.Minute)
+	viper.SetDefault("IDEMPOTENCY_CLEANUP_INTERVAL", 10*time.Minute)
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", 5*time.Second)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF", 30*time.Second)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_HOSTS", false)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", 2*time.Second)
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_RETRY_BACKOFF", 10*time.Second)
+	viper.SetDefault("GUEST_OFFER_LINK_URL", "http://localhost:8080/guest/offers")
+	viper.SetDefault("GUEST_OFFER_LIMIT_WINDOW", time.Hour)
+	viper.SetDefault("GUEST_OFFER_MAX_PER_EMAIL", 5)
+	viper.SetDefault("GUEST_OFFER_MAX_PER_IP", 20)
+	viper.SetDefault("OFFER_MIN_PRICE_PERCENT", 30)
+	viper.SetDefault("OFFER_MAX_OPEN", 20)
+	viper.SetDefault("OFFER_MAX_PER_HOUR", 30)
+	viper.SetDefault("OFFER_DECLINE_COOLDOWN", 24*time.Hour)
+	viper.SetDefault("DISPLAY_CURRENCY", "USD")
+	viper.SetDefault("WANTED_MAX_DURATION", 30*24*time.Hour)
+	viper.SetDefault("WANTED_CLOSE_INTERVAL", time.Minute)
+	viper.SetDefault("WANTED_CLOSE_BATCH_SIZE", 100)
+	viper.SetDefault("INVENTORY_IMPORT_MAX_ROWS", 5000)
+	viper.SetDefault("INVENTORY_IMPORT_MAX_BYTES", 2<<20)
 
 	config := &Config{
 		AccessKey:     viper.GetString("ACCESS_KEY"),
@@ -116,9 +224,10 @@ func LoadConfig() *Config {
 			MaxIdleConns: viper.GetInt("DB_MAX_IDLE_CONNS"),
 		},
 		Server: ServerConfig{
-			Domain:  viper.GetString("SERVER_DOMAIN"),
-			Port:    viper.GetString("SERVER_PORT"),
-			GinMode: viper.GetString("GIN_MODE"),
+			Domain:         viper.GetString("SERVER_DOMAIN"),
+			Port:           viper.GetString("SERVER_PORT"),
+			GinMode:        viper.GetString("GIN_MODE"),
+			TrustedProxies: splitList(viper.GetString("SERVER_TRUSTED_PROXIES")),
 		},
 		Token: TokenConfig{
 			Secret:               viper.GetString("TOKEN_SECRET"),
@@ -139,7 +248,120 @@ func LoadConfig() *Config {
 			QueueSize:      viper.GetInt("AUDIT_QUEUE_SIZE"),
 			BatchSize:      viper.GetInt("AUDIT_BATCH_SIZE"),
 		},
+		Expiry: OfferExpiryConfig{
+			Interval:  viper.GetDuration("OFFER_EXPIRY_INTERVAL"),
+			BatchSize: viper.GetInt("OFFER_EXPIRY_BATCH_SIZE"),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:             viper.GetDuration("IDEMPOTENCY_TTL"),
+			Lease:           viper.GetDuration("IDEMPOTENCY_LEASE"),
+			CleanupInterval: viper.GetDuration("IDEMPOTENCY_CLEANUP_INTERVAL"),
+		},
+		Webhook: WebhookConfig{
+			PollInterval:      viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			BatchSize:         viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			MaxAttempts:       viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			RetryBackoff:      viper.GetDuration("WEBHOOK_RETRY_BACKOFF"),
+			Timeout:           viper.GetDuration("WEBHOOK_TIMEOUT"),
+			AllowPrivateHosts: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_HOSTS"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: viper.GetDuration("OUTBOX_POLL_INTERVAL"),
+			BatchSize:    viper.GetInt("OUTBOX_BATCH_SIZE"),
+			MaxAttempts:  viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+			RetryBackoff: viper.GetDuration("OUTBOX_RETRY_BACKOFF"),
+		},
+		GuestOffer: GuestOfferConfig{
+			LinkURL:     viper.GetString("GUEST_OFFER_LINK_URL"),
+			LimitWindow: viper.GetDuration("GUEST_OFFER_LIMIT_WINDOW"),
+			MaxPerEmail: viper.GetInt("GUEST_OFFER_MAX_PER_EMAIL"),
+			MaxPerIP:    viper.GetInt("GUEST_OFFER_MAX_PER_IP"),
+		},
+		Pricing: OfferPricingConfig{
+			DefaultMinPricePercent: viper.GetFloat64("OFFER_MIN_PRICE_PERCENT"),
+		},
+		Quota: OfferQuotaConfig{
+			MaxOpen:         viper.GetInt("OFFER_MAX_OPEN"),
+			MaxPerHour:      viper.GetInt("OFFER_MAX_PER_HOUR"),
+			DeclineCooldown: viper.GetDuration("OFFER_DECLINE_COOLDOWN"),
+		},
+		Currency: CurrencyConfig{
+			Display: viper.GetString("DISPLAY_CURRENCY"),
+		},
+		Wanted: WantedConfig{
+			MaxDuration:    viper.GetDuration("WANTED_MAX_DURATION"),
+			CloseInterval:  viper.GetDuration("WANTED_CLOSE_INTERVAL"),
+			CloseBatchSize: viper.GetInt("WANTED_CLOSE_BATCH_SIZE"),
+		},
+		Inventory: InventoryConfig{
+			ImportMaxRows:  viper.GetInt("INVENTORY_IMPORT_MAX_ROWS"),
+			ImportMaxBytes: viper.GetInt64("INVENTORY_IMPORT_MAX_BYTES"),
+		},
+	}
+
+	if err := config.validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
 	}
 
 	return config
 }
+
+// validate проверяет разделы конфигурации, которым нужны не только значения по умолчанию
+func (c *Config) validate() error {
+	return errors.Join(
+		c.Expiry.validate(),
+		c.Idempotency.validate(),
+		c.Webhook.validate(),
+		c.Outbox.validate(),
+		c.Wanted.validate(),
+	)
+}
+
+// validate проверяет интервал воркера истечения: он уходит в time.NewTicker, который паникует на нуле
+func (c *OfferExpiryConfig) validate() error {
+	return positiveDuration("OFFER_EXPIRY_INTERVAL", c.Interval)
+}
+
+// validate проверяет аренду ключа и интервал очистки: с нулевой арендой занятый ключ сразу можно занять снова
+func (c *IdempotencyConfig) validate() error {
+	return errors.Join(
+		positiveDuration("IDEMPOTENCY_LEASE", c.Lease),
+		positiveDuration("IDEMPOTENCY_CLEANUP_INTERVAL", c.CleanupInterval),
+	)
+}
+
+// validate проверяет интервал воркера доставки вебхуков
+func (c *WebhookConfig) validate() error {
+	return positiveDuration("WEBHOOK_POLL_INTERVAL", c.PollInterval)
+}
+
+// validate проверяет интервал опроса outbox
+func (c *OutboxConfig) validate() error {
+	return positiveDuration("OUTBOX_POLL_INTERVAL", c.PollInterval)
+}
+
+// validate проверяет интервал воркера закрытия запросов
+func (c *WantedConfig) validate() error {
+	return positiveDuration("WANTED_CLOSE_INTERVAL", c.CloseInterval)
+}
+
+// positiveDuration проверяет, что длительность из переменной name больше нуля.
+// viper возвращает ноль и для нераспознанного значения, поэтому в ошибке показывается исходная строка.
+func positiveDuration(name string, value time.Duration) error {
+	if value <= 0 {
+		return fmt.Errorf("%s must be a positive duration, got %q", name, viper.GetString(name))
+	}
+	return nil
+}
+
+// splitList разбирает список через запятую, пропуская пустые элементы
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
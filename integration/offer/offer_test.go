@@ -3,23 +3,48 @@ package handler_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/pkg/email"
 
 	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/autoresponse"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/category"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/currency"
+	guestofferservice "github.com/EM-Stawberry/Stawberry/internal/domain/service/guestoffer"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/idempotency"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/inventory"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offer"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offermessage"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offerstream"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/order"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/outbox"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/product"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/wanted"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/webhook"
 	"github.com/EM-Stawberry/Stawberry/internal/handler"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/repository"
+	guestofferrepo "github.com/EM-Stawberry/Stawberry/internal/repository/guestoffer"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"github.com/onsi/ginkgo/v2"
@@ -28,6 +53,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
 )
 
 var (
@@ -166,7 +192,8 @@ func (m *mockMailer) StatusUpdate(offerID uint, status string, userMail string)
 func (m *mockMailer) OfferReceived(offerID uint, userMail string) {
 }
 
-func (m *mockMailer) SendGuestOfferNotification(email string, subject string, body string) {
+func (m *mockMailer) Send(ctx context.Context, userMail, subject, body string) error {
+	return nil
 }
 
 func (m *mockMailer) Stop(ctx context.Context) {
@@ -179,10 +206,16 @@ func setupRouter(authMiddleware gin.HandlerFunc, method, path string, handlerFun
 	router.Use(authMiddleware)
 
 	switch method {
+	case http.MethodGet:
+		router.GET(path, handlerFunc)
 	case http.MethodPatch:
 		router.PATCH(path, handlerFunc)
 	case http.MethodPost:
 		router.POST(path, handlerFunc)
+	case http.MethodPut:
+		router.PUT(path, handlerFunc)
+	case http.MethodDelete:
+		router.DELETE(path, handlerFunc)
 	default:
 		panic(fmt.Sprintf("unsupported HTTP method: %s", method))
 	}
@@ -195,7 +228,13 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 		db        *sqlx.DB
 		offerRepo offer.Repository
 		offerServ *offer.Service
+		pricing   *offer.PricingPolicy
+		rates     *currency.Service
+		offerBus  *offerstream.Bus
+		relay     *outbox.Relay
 		offerHand *handler.OfferHandler
+		orderHand *handler.OrderHandler
+		ruleHand  *handler.AutoResponseHandler
 		router    *gin.Engine
 	)
 
@@ -210,8 +249,23 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 		mailer := newMockMailer()
 
 		offerRepo = repository.NewOfferRepository(db)
-		offerServ = offer.NewService(offerRepo, mailer)
+		offerBus = offerstream.NewBus(repository.NewOfferRepository(db), nil, zap.NewNop())
+		relay = outbox.NewRelay(repository.NewOutboxRepository(db), &config.OutboxConfig{
+			PollInterval: time.Hour,
+			BatchSize:    100,
+			MaxAttempts:  3,
+			RetryBackoff: time.Minute,
+		}, zap.NewNop())
+		relay.Register(entity.OutboxTopicOfferEvent, "offer_stream", outbox.NewOfferEventSink(offerBus))
+		relay.Register(entity.OutboxTopicEmail, "mailer", outbox.NewMailSink(mailer))
+		// без минимума по умолчанию, чтобы цены тестовых офферов не зависели от политики
+		rates = currency.NewService(repository.NewCurrencyRepository(db))
+		pricing = offer.NewPricingPolicy(repository.NewPricingPolicyRepository(db), rates,
+			&config.OfferPricingConfig{})
+		offerServ = offer.NewService(offerRepo, pricing, rates, relay, &config.OfferQuotaConfig{})
 		offerHand = handler.NewOfferHandler(offerServ)
+		orderHand = handler.NewOrderHandler(order.NewService(repository.NewOrderRepository(db)))
+		ruleHand = handler.NewAutoResponseHandler(autoresponse.NewService(repository.NewAutoResponseRepository(db)))
 	})
 
 	ginkgo.AfterAll(func() {
@@ -424,7 +478,7 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 		})
 
 		ginkgo.It("fails to update the status to `accepted`, "+
-			"since that status can only be used by shop owner", func() {
+			"since the buyer can only accept a shop's counter-offer", func() {
 			correctOfferID := 3
 			correctStatus := "accepted"
 			jsonBody, _ := json.Marshal(struct {
@@ -441,7 +495,7 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			rec := httptest.NewRecorder()
 			router.ServeHTTP(rec, req)
 
-			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
 		})
 	})
 
@@ -472,6 +526,282 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 		})
 	})
 
+	ginkgo.Context("when the shop and the buyer negotiate the price", ginkgo.Ordered, func() {
+		var shopRouter, buyerRouter *gin.Engine
+
+		ginkgo.BeforeEach(func() {
+			shopRouter = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodPatch, "/api/test/offers/:offerID", offerHand.PatchOfferStatus)
+			buyerRouter = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodPatch, "/api/test/offers/:offerID", offerHand.PatchOfferStatus)
+		})
+
+		patchStatus := func(router *gin.Engine, offerID int, body any) *httptest.ResponseRecorder {
+			jsonBody, _ := json.Marshal(body)
+
+			req := httptest.NewRequest(http.MethodPatch,
+				fmt.Sprintf("/api/test/offers/%d", offerID),
+				bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		negotiatedOfferID := 4
+
+		ginkgo.It("fails to counter without a price", func() {
+			rec := patchStatus(shopRouter, negotiatedOfferID, dto.PatchOfferStatusReq{Status: "countered"})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+		})
+
+		ginkgo.It("lets the shop counter a pending offer", func() {
+			price := 150.0
+			rec := patchStatus(shopRouter, negotiatedOfferID,
+				dto.PatchOfferStatusReq{Status: "countered", Price: &price})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var ofr dto.PatchOfferStatusResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &ofr)
+			gomega.Expect(ofr.NewStatus).To(gomega.Equal("countered"))
+			gomega.Expect(ofr.Price).To(gomega.Equal(price))
+		})
+
+		ginkgo.It("does not let the shop counter twice in a row", func() {
+			price := 140.0
+			rec := patchStatus(shopRouter, negotiatedOfferID,
+				dto.PatchOfferStatusReq{Status: "countered", Price: &price})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
+		})
+
+		ginkgo.It("lets the buyer counter back, returning the offer to the shop", func() {
+			price := 120.0
+			rec := patchStatus(buyerRouter, negotiatedOfferID,
+				dto.PatchOfferStatusReq{Status: "countered", Price: &price})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var ofr dto.PatchOfferStatusResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &ofr)
+			gomega.Expect(ofr.NewStatus).To(gomega.Equal("pending"))
+			gomega.Expect(ofr.Price).To(gomega.Equal(price))
+		})
+
+		ginkgo.It("does not let anyone but the buyer counter as a buyer", func() {
+			// владелец магазина участвует в торге, но покупателем по офферу не является
+			ownerAsBuyerRouter := setupRouter(func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+				c.Set(helpers.UserIsStoreKey, false)
+				c.Next()
+			}, http.MethodPatch, "/api/test/offers/:offerID", offerHand.PatchOfferStatus)
+
+			price := 120.0
+			rec := patchStatus(ownerAsBuyerRouter, negotiatedOfferID,
+				dto.PatchOfferStatusReq{Status: "countered", Price: &price})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("lets the buyer accept the shop's counter-offer", func() {
+			price := 130.0
+			rec := patchStatus(shopRouter, negotiatedOfferID,
+				dto.PatchOfferStatusReq{Status: "countered", Price: &price})
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			rec = patchStatus(buyerRouter, negotiatedOfferID, dto.PatchOfferStatusReq{Status: "accepted"})
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var ofr dto.PatchOfferStatusResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &ofr)
+			gomega.Expect(ofr.NewStatus).To(gomega.Equal("accepted"))
+
+			var rounds int
+			err := db.Get(&rounds, "select count(*) from offer_rounds where offer_id = $1", negotiatedOfferID)
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(rounds).To(gomega.Equal(3))
+		})
+	})
+
+	ginkgo.Context("when the offer history is requested", func() {
+		getHistory := func(router *gin.Engine, offerID int) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet,
+				fmt.Sprintf("/api/test/offers/%d/history", offerID), nil)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		ginkgo.It("returns every negotiation step to the buyer in order", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID/history", offerHand.GetOfferHistory)
+
+			rec := getHistory(router, 4) // negotiated in the previous context
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetOfferHistoryResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.HaveLen(4))
+			gomega.Expect(resp.Data[0].EventType).To(gomega.Equal("countered"))
+			gomega.Expect(resp.Data[0].FromStatus).To(gomega.Equal("pending"))
+			gomega.Expect(resp.Data[0].ToStatus).To(gomega.Equal("countered"))
+			gomega.Expect(resp.Data[0].ActorRole).To(gomega.Equal("shop"))
+			gomega.Expect(resp.Data[3].EventType).To(gomega.Equal("status_changed"))
+			gomega.Expect(resp.Data[3].ToStatus).To(gomega.Equal("accepted"))
+			gomega.Expect(resp.Data[3].ActorRole).To(gomega.Equal("buyer"))
+		})
+
+		ginkgo.It("hides the history from users unrelated to the offer", func() {
+			router = setupRouter(mockAuthIncorrectShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID/history", offerHand.GetOfferHistory)
+
+			rec := getHistory(router, 4)
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+	})
+
+	ginkgo.Context("when a single offer is requested", func() {
+		getOffer := func(router *gin.Engine, offerID string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/test/offers/"+offerID, nil)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		ginkgo.It("returns the offer with product and shop names to the buyer", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID", offerHand.GetOffer)
+
+			rec := getOffer(router, "4")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetOfferResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.ID).To(gomega.Equal(uint(4)))
+			gomega.Expect(resp.ProductName).To(gomega.Equal("product4"))
+			gomega.Expect(resp.ShopName).To(gomega.Equal("shop1"))
+			gomega.Expect(resp.Status).To(gomega.Equal("accepted"))
+		})
+
+		ginkgo.It("returns the offer to the shop owner", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID", offerHand.GetOffer)
+
+			rec := getOffer(router, "4")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+		})
+
+		ginkgo.It("hides the offer from users unrelated to it", func() {
+			router = setupRouter(mockAuthIncorrectShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID", offerHand.GetOffer)
+
+			rec := getOffer(router, "4")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("returns 404 for a missing offer", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID", offerHand.GetOffer)
+
+			rec := getOffer(router, "999")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("fails data validation if the offerID is non-numeric", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/offers/:offerID", offerHand.GetOffer)
+
+			rec := getOffer(router, "abc")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+		})
+	})
+
+	ginkgo.Context("when the shop owner opens the offer inbox", func() {
+		getInbox := func(router *gin.Engine, query string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/test/shop/offers?"+query, nil)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		ginkgo.It("lists offers of all owned shops with status counts", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/shop/offers", offerHand.GetShopOffers)
+
+			rec := getInbox(router, "sort=price&order=asc")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetShopOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Meta.TotalItems).To(gomega.Equal(4))
+			gomega.Expect(resp.Data).To(gomega.HaveLen(4))
+			gomega.Expect(resp.Data[0].Price).To(gomega.Equal(45.0))
+			gomega.Expect(resp.StatusCounts).To(gomega.HaveKeyWithValue("accepted", 2))
+			gomega.Expect(resp.StatusCounts).To(gomega.HaveKey("countered"))
+		})
+
+		ginkgo.It("applies the status and product filters", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/shop/offers", offerHand.GetShopOffers)
+
+			rec := getInbox(router, "status=accepted&product_id=4")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetShopOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.HaveLen(1))
+			gomega.Expect(resp.Data[0].ID).To(gomega.Equal(uint(4)))
+			gomega.Expect(resp.Data[0].UserID).To(gomega.Equal(uint(2)))
+		})
+
+		ginkgo.It("rejects an inverted price range", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/shop/offers", offerHand.GetShopOffers)
+
+			rec := getInbox(router, "min_price=100&max_price=10")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+		})
+
+		ginkgo.It("returns an empty inbox to a shop owner without offers", func() {
+			router = setupRouter(mockAuthIncorrectShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/shop/offers", offerHand.GetShopOffers)
+
+			rec := getInbox(router, "")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetShopOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.BeEmpty())
+			gomega.Expect(resp.StatusCounts).To(gomega.HaveKeyWithValue("pending", 0))
+		})
+
+		ginkgo.It("is forbidden for buyers", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/shop/offers", offerHand.GetShopOffers)
+
+			rec := getInbox(router, "")
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusForbidden))
+		})
+	})
+
 	ginkgo.Context("Offer Post Handler for buyers", ginkgo.Ordered, func() {
 		ginkgo.BeforeEach(func() {
 			router = setupRouter(mockAuthBuyerMiddleware(),
@@ -519,6 +849,24 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
 		})
 
+		ginkgo.It("fails to create an offer on a product the shop does not sell now", func() {
+			_, err := db.Exec(`update shop_inventory set is_available = false where shop_id = 2 and product_id = 3`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			defer func() {
+				_, _ = db.Exec(`update shop_inventory set is_available = true where shop_id = 2 and product_id = 3`)
+			}()
+
+			jsonBody, _ := json.Marshal(dto.PostOfferReq{ProductID: 3, ShopID: 2, Price: 150, Currency: "USD"})
+			req := httptest.NewRequest(http.MethodPost, "/api/test/offers", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(rec.Body.String()).To(gomega.ContainSubstring("not available"))
+		})
+
 		ginkgo.It("fails to create an offer with missing required fields (e.g., ProductID)", func() {
 			reqBody := dto.PostOfferReq{
 				// ProductID is missing, which is required by `binding:"required"`
@@ -562,4 +910,2027 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusForbidden))
 		})
 	})
+
+	ginkgo.Context("when an open offer passes its expiry date", func() {
+		ginkgo.It("cannot be answered before the expiry worker gets to it", func() {
+			var offerID uint
+			err := db.Get(&offerID, "select id from offers where shop_id = 1 and status = 'pending' order by id limit 1")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = db.Exec("update offers set expires_at = now() - interval '1 minute' where id = $1", offerID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			defer func() {
+				_, _ = db.Exec("update offers set expires_at = now() + interval '7 days' where id = $1", offerID)
+			}()
+
+			offerRepo := repository.NewOfferRepository(db)
+
+			_, err = offerRepo.UpdateOfferStatus(context.Background(),
+				entity.Offer{ID: offerID, Status: "accepted"}, 1, true)
+			gomega.Expect(errors.Is(err, apperror.ErrOfferExpired)).To(gomega.BeTrue())
+
+			_, err = offerRepo.CounterOffer(context.Background(), entity.Offer{ID: offerID, Price: 50}, 1, true)
+			gomega.Expect(errors.Is(err, apperror.ErrOfferExpired)).To(gomega.BeTrue())
+
+			var orders int
+			_ = db.Get(&orders, "select count(*) from orders where offer_id = $1", offerID)
+			gomega.Expect(orders).To(gomega.BeZero())
+		})
+
+		ginkgo.It("is moved to `expired` by the expiry worker and logged", func() {
+			_, err := db.Exec("update offers set expires_at = now() - interval '1 hour' where id = 3")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			expired, err := repository.NewOfferRepository(db).ExpireOffers(context.Background(), time.Now(), 10)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(expired).To(gomega.ConsistOf(entity.ExpiredOffer{OfferID: 3, BuyerEmail: "user2email"}))
+
+			var status string
+			_ = db.Get(&status, "select status from offers where id = 3")
+			gomega.Expect(status).To(gomega.Equal("expired"))
+
+			var events int
+			_ = db.Get(&events, "select count(*) from offer_events where offer_id = 3 and event_type = 'expired'")
+			gomega.Expect(events).To(gomega.Equal(1))
+
+			var topics []string
+			_ = db.Select(&topics, "select topic from outbox where aggregate_id = 3 and status = 'pending' order by id")
+			gomega.Expect(topics).To(gomega.ConsistOf(entity.OutboxTopicOfferEvent, entity.OutboxTopicEmail))
+		})
+
+		ginkgo.It("does not touch the offer again on the next run", func() {
+			expired, err := repository.NewOfferRepository(db).ExpireOffers(context.Background(), time.Now(), 10)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(expired).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Context("when an offer has been accepted", ginkgo.Ordered, func() {
+		var (
+			orderID     uint
+			buyerRouter *gin.Engine
+			shopRouter  *gin.Engine
+		)
+
+		patchOrder := func(router *gin.Engine, status string) *httptest.ResponseRecorder {
+			jsonBody, _ := json.Marshal(dto.PatchOrderStatusReq{Status: status})
+			req := httptest.NewRequest(http.MethodPatch,
+				fmt.Sprintf("/api/test/orders/%d", orderID), bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		ginkgo.BeforeAll(func() {
+			buyerRouter = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodPatch, "/api/test/orders/:orderID", orderHand.PatchOrderStatus)
+			shopRouter = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodPatch, "/api/test/orders/:orderID", orderHand.PatchOrderStatus)
+		})
+
+		ginkgo.It("has an order with the agreed price awaiting payment", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/orders", orderHand.GetUserOrders)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/test/orders", nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetOrdersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.HaveLen(2)) // offers 1 and 4
+
+			// заказы отсортированы от новых к старым, первый - по офферу 4 после торга
+			gomega.Expect(resp.Data[0].OfferID).To(gomega.Equal(uint(4)))
+			gomega.Expect(resp.Data[0].Price).To(gomega.Equal(130.0))
+			gomega.Expect(resp.Data[0].Status).To(gomega.Equal("awaiting_payment"))
+			orderID = resp.Data[0].ID
+		})
+
+		ginkgo.It("does not let the shop ship an unpaid order", func() {
+			gomega.Expect(patchOrder(shopRouter, "shipped").Code).To(gomega.Equal(http.StatusConflict))
+		})
+
+		ginkgo.It("does not let the buyer ship the order", func() {
+			gomega.Expect(patchOrder(buyerRouter, "shipped").Code).To(gomega.Equal(http.StatusBadRequest))
+		})
+
+		ginkgo.It("does not let the buyer mark the order as paid", func() {
+			gomega.Expect(patchOrder(buyerRouter, "paid").Code).To(gomega.Equal(http.StatusBadRequest))
+		})
+
+		ginkgo.It("moves the order through payment, shipping and completion", func() {
+			gomega.Expect(patchOrder(shopRouter, "paid").Code).To(gomega.Equal(http.StatusOK))
+			gomega.Expect(patchOrder(shopRouter, "shipped").Code).To(gomega.Equal(http.StatusOK))
+
+			rec := patchOrder(buyerRouter, "completed")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.OrderResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Status).To(gomega.Equal("completed"))
+		})
+
+		ginkgo.It("hides the order from users unrelated to it", func() {
+			router = setupRouter(mockAuthIncorrectShopOwnerMiddleware(),
+				http.MethodPatch, "/api/test/orders/:orderID", orderHand.PatchOrderStatus)
+
+			gomega.Expect(patchOrder(router, "cancelled").Code).To(gomega.Equal(http.StatusNotFound))
+		})
+	})
+
+	ginkgo.Context("when the shop has auto-response rules", ginkgo.Ordered, func() {
+		putRule := func(router *gin.Engine, shopID int, rule string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPut,
+				fmt.Sprintf("/api/test/shops/%d/auto-rules", shopID), bytes.NewBufferString(rule))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		postOffer := func(productID uint, price float64) dto.PostOfferResp {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodPost, "/api/test/offers", offerHand.PostOffer)
+
+			jsonBody, _ := json.Marshal(dto.PostOfferReq{
+				ProductID: productID,
+				ShopID:    2,
+				Price:     price,
+				Currency:  "USD",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/test/offers", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+
+			var resp dto.PostOfferResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp
+		}
+
+		ginkgo.It("lets the shop owner save shop-wide and per-product rules", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodPut, "/api/test/shops/:shopID/auto-rules", ruleHand.PutRule)
+
+			rec := putRule(router, 2, `{"accept_from": 100, "decline_below": 50, "counter_price": 90}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			rec = putRule(router, 2, `{"product_id": 3, "accept_from": 10}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+		})
+
+		ginkgo.It("rejects rules for products outside the shop inventory", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodPut, "/api/test/shops/:shopID/auto-rules", ruleHand.PutRule)
+
+			rec := putRule(router, 2, `{"product_id": 999, "accept_from": 10}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("does not let another shop owner edit the rules", func() {
+			router = setupRouter(mockAuthIncorrectShopOwnerMiddleware(),
+				http.MethodPut, "/api/test/shops/:shopID/auto-rules", ruleHand.PutRule)
+
+			rec := putRule(router, 2, `{"accept_from": 1}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("declines offers below the floor", func() {
+			resp := postOffer(2, 40)
+			gomega.Expect(resp.Status).To(gomega.Equal("declined"))
+		})
+
+		ginkgo.It("counters offers between the floor and the counter price", func() {
+			resp := postOffer(4, 60)
+			gomega.Expect(resp.Status).To(gomega.Equal("countered"))
+			gomega.Expect(resp.Price).To(gomega.Equal(90.0))
+
+			var rounds int
+			_ = db.Get(&rounds, "select count(*) from offer_rounds where offer_id = $1 and proposed_by = 'shop'",
+				resp.ID)
+			gomega.Expect(rounds).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("prefers the per-product rule and creates an order on acceptance", func() {
+			resp := postOffer(3, 20)
+			gomega.Expect(resp.Status).To(gomega.Equal("accepted"))
+
+			var orders int
+			_ = db.Get(&orders, "select count(*) from orders where offer_id = $1", resp.ID)
+			gomega.Expect(orders).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("lists the rules with the shop-wide one first", func() {
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodGet, "/api/test/shops/:shopID/auto-rules", ruleHand.GetRules)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/test/shops/2/auto-rules", nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetAutoResponseRulesResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.HaveLen(2))
+			gomega.Expect(resp.Data[0].ProductID).To(gomega.BeNil())
+		})
+	})
+
+	ginkgo.Context("when the shop answers several offers at once", ginkgo.Ordered, func() {
+		var pendingID uint
+
+		patchBulk := func(router *gin.Engine, body dto.PatchShopOffersReq) *httptest.ResponseRecorder {
+			jsonBody, _ := json.Marshal(body)
+			req := httptest.NewRequest(http.MethodPatch, "/api/test/shop/offers", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		ginkgo.BeforeAll(func() {
+			err := db.Get(&pendingID, "insert into offers (offer_price, currency, user_id, product_id, shop_id) "+
+				"values (70, 'usd', 2, 1, 1) returning id")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodPatch, "/api/test/shop/offers", offerHand.PatchShopOffers)
+		})
+
+		ginkgo.It("applies nothing in atomic mode if one offer fails", func() {
+			rec := patchBulk(router, dto.PatchShopOffersReq{OfferIDs: []uint{pendingID, 1}, Status: "accepted"})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.PatchShopOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Mode).To(gomega.Equal("atomic"))
+			gomega.Expect(resp.Succeeded).To(gomega.Equal(0))
+			gomega.Expect(resp.Failed).To(gomega.Equal(2))
+			gomega.Expect(resp.Results[1].Error.Code).To(gomega.Equal("CONFLICT"))
+
+			var status string
+			_ = db.Get(&status, "select status from offers where id = $1", pendingID)
+			gomega.Expect(status).To(gomega.Equal("pending"))
+		})
+
+		ginkgo.It("applies valid offers in per-item mode and reports the rest", func() {
+			rec := patchBulk(router, dto.PatchShopOffersReq{
+				OfferIDs: []uint{pendingID, 1, 999},
+				Status:   "accepted",
+				Mode:     "per_item",
+			})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.PatchShopOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Succeeded).To(gomega.Equal(1))
+			gomega.Expect(resp.Results[0].OK).To(gomega.BeTrue())
+			gomega.Expect(resp.Results[0].Status).To(gomega.Equal("accepted"))
+			gomega.Expect(resp.Results[1].Error.Code).To(gomega.Equal("CONFLICT"))
+			gomega.Expect(resp.Results[2].Error.Code).To(gomega.Equal("NOT_FOUND"))
+
+			var orders int
+			_ = db.Get(&orders, "select count(*) from orders where offer_id = $1", pendingID)
+			gomega.Expect(orders).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("does not touch offers of other shops", func() {
+			router = setupRouter(mockAuthIncorrectShopOwnerMiddleware(),
+				http.MethodPatch, "/api/test/shop/offers", offerHand.PatchShopOffers)
+
+			rec := patchBulk(router, dto.PatchShopOffersReq{OfferIDs: []uint{5}, Status: "declined", Mode: "per_item"})
+
+			var resp dto.PatchShopOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Failed).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("is forbidden for buyers", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodPatch, "/api/test/shop/offers", offerHand.PatchShopOffers)
+
+			rec := patchBulk(router, dto.PatchShopOffersReq{OfferIDs: []uint{5}, Status: "declined"})
+
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusForbidden))
+		})
+	})
+
+	ginkgo.Context("when an offer is posted with an Idempotency-Key", ginkgo.Ordered, func() {
+		postWithKey := func(key string, price float64) *httptest.ResponseRecorder {
+			jsonBody, _ := json.Marshal(dto.PostOfferReq{
+				ProductID: 2,
+				ShopID:    2,
+				Price:     price,
+				Currency:  "USD",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/test/offers", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middleware.IdempotencyKeyHeader, key)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		var idempotencyServ *idempotency.Service
+
+		ginkgo.BeforeAll(func() {
+			idempotencyServ = idempotency.NewService(repository.NewIdempotencyRepository(db),
+				&config.IdempotencyConfig{TTL: time.Hour, Lease: time.Minute})
+
+			router = gin.New()
+			router.Use(middleware.Errors())
+			router.Use(mockAuthBuyerMiddleware())
+			router.POST("/api/test/offers", middleware.Idempotency(idempotencyServ), offerHand.PostOffer)
+		})
+
+		ginkgo.It("creates the offer once and replays the first response", func() {
+			first := postWithKey("retry-1", 40)
+			gomega.Expect(first.Code).To(gomega.Equal(http.StatusCreated))
+
+			second := postWithKey("retry-1", 40)
+			gomega.Expect(second.Code).To(gomega.Equal(http.StatusCreated))
+			gomega.Expect(second.Header().Get(middleware.IdempotencyReplayedHeader)).To(gomega.Equal("true"))
+			gomega.Expect(second.Body.String()).To(gomega.Equal(first.Body.String()))
+
+			var offers int
+			_ = db.Get(&offers, "select count(*) from offers where user_id = 2 and shop_id = 2 and product_id = 2")
+			gomega.Expect(offers).To(gomega.Equal(2))
+		})
+
+		ginkgo.It("rejects the same key with a different body", func() {
+			rec := postWithKey("retry-1", 45)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+		})
+
+		ginkgo.It("releases the key when the handler panics", func() {
+			panicking := gin.New()
+			panicking.Use(gin.Recovery())
+			panicking.Use(mockAuthBuyerMiddleware())
+			panicking.POST("/api/test/panic", middleware.Idempotency(idempotencyServ), func(*gin.Context) {
+				panic("boom")
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/test/panic", bytes.NewBufferString("{}"))
+			req.Header.Set(middleware.IdempotencyKeyHeader, "panic-1")
+			rec := httptest.NewRecorder()
+			panicking.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusInternalServerError))
+
+			var keys int
+			_ = db.Get(&keys, "select count(*) from idempotency_keys where key = 'panic-1'")
+			gomega.Expect(keys).To(gomega.Equal(0))
+		})
+
+		ginkgo.It("does not share guest keys between clients", func() {
+			guests := gin.New()
+			guests.Use(middleware.Errors())
+			guests.POST("/api/test/guest", middleware.Idempotency(idempotencyServ), func(c *gin.Context) {
+				c.JSON(http.StatusCreated, gin.H{"client": c.ClientIP()})
+			})
+
+			postFrom := func(remoteAddr string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/api/test/guest", bytes.NewBufferString("{}"))
+				req.Header.Set(middleware.IdempotencyKeyHeader, "guest-1")
+				req.RemoteAddr = remoteAddr
+				rec := httptest.NewRecorder()
+				guests.ServeHTTP(rec, req)
+				return rec
+			}
+
+			first := postFrom("203.0.113.1:1234")
+			gomega.Expect(first.Code).To(gomega.Equal(http.StatusCreated))
+
+			other := postFrom("203.0.113.2:1234")
+			gomega.Expect(other.Code).To(gomega.Equal(http.StatusCreated))
+			gomega.Expect(other.Header().Get(middleware.IdempotencyReplayedHeader)).To(gomega.BeEmpty())
+			gomega.Expect(other.Body.String()).To(gomega.ContainSubstring("203.0.113.2"))
+
+			retry := postFrom("203.0.113.1:1234")
+			gomega.Expect(retry.Header().Get(middleware.IdempotencyReplayedHeader)).To(gomega.Equal("true"))
+			gomega.Expect(retry.Body.String()).To(gomega.Equal(first.Body.String()))
+		})
+
+		ginkgo.It("replays a client error instead of running the request again", func() {
+			calls := 0
+			failing := gin.New()
+			failing.Use(middleware.Errors())
+			failing.Use(mockAuthBuyerMiddleware())
+			failing.POST("/api/test/failing", middleware.Idempotency(idempotencyServ), func(c *gin.Context) {
+				calls++
+				_ = c.Error(apperror.New(apperror.Conflict, "the shop declined your offer", nil))
+			})
+
+			post := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/api/test/failing", bytes.NewBufferString("{}"))
+				req.Header.Set(middleware.IdempotencyKeyHeader, "conflict-1")
+				rec := httptest.NewRecorder()
+				failing.ServeHTTP(rec, req)
+				return rec
+			}
+
+			first := post()
+			gomega.Expect(first.Code).To(gomega.Equal(http.StatusConflict))
+
+			retry := post()
+			gomega.Expect(retry.Code).To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(retry.Header().Get(middleware.IdempotencyReplayedHeader)).To(gomega.Equal("true"))
+			gomega.Expect(retry.Body.String()).To(gomega.MatchJSON(first.Body.String()))
+			gomega.Expect(calls).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("leaves the key to the request that claimed it after an expired lease", func() {
+			repo := repository.NewIdempotencyRepository(db)
+			claim := func(lease string, expiresAt time.Time) bool {
+				_, claimed, err := repo.Claim(context.Background(), entity.IdempotencyRecord{
+					Scope: "POST /api/test/lease", Key: "lease-1", RequestHash: strings.Repeat("a", 64),
+					LeaseToken: lease, ExpiresAt: expiresAt,
+				})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return claimed
+			}
+
+			gomega.Expect(claim("stale", time.Now().Add(-time.Minute))).To(gomega.BeTrue())
+			gomega.Expect(claim("fresh", time.Now().Add(time.Minute))).To(gomega.BeTrue())
+
+			gomega.Expect(repo.Complete(context.Background(), "POST /api/test/lease", "lease-1", "stale",
+				http.StatusCreated, []byte(`{}`), time.Now().Add(time.Hour))).To(gomega.Succeed())
+			gomega.Expect(repo.Release(context.Background(), "POST /api/test/lease", "lease-1", "stale")).
+				To(gomega.Succeed())
+
+			var leases []string
+			_ = db.Select(&leases, `select lease_token from idempotency_keys
+				where key = 'lease-1' and status_code is null`)
+			gomega.Expect(leases).To(gomega.Equal([]string{"fresh"}))
+		})
+	})
+
+	ginkgo.Context("when offer updates are streamed", ginkgo.Ordered, func() {
+		ginkgo.It("delivers a status change to the buyer and the shop owner", func() {
+			buyerUpdates, unsubscribeBuyer := offerBus.Subscribe(2)
+			defer unsubscribeBuyer()
+			ownerUpdates, unsubscribeOwner := offerBus.Subscribe(1)
+			defer unsubscribeOwner()
+
+			_, err := offerServ.UpdateOfferStatus(context.Background(),
+				entity.Offer{ID: 5, Status: "declined"}, 1, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// событие попадает в шину только через outbox
+			gomega.Expect(buyerUpdates).NotTo(gomega.Receive())
+			_, err = relay.RelayDue(context.Background())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var update entity.OfferUpdate
+			gomega.Expect(buyerUpdates).To(gomega.Receive(&update))
+			gomega.Expect(update.Type).To(gomega.Equal(entity.OfferUpdateStatusChanged))
+			gomega.Expect(update.OfferID).To(gomega.Equal(uint(5)))
+			gomega.Expect(update.Status).To(gomega.Equal("declined"))
+			gomega.Expect(update.ShopOwnerID).To(gomega.Equal(uint(1)))
+			gomega.Expect(ownerUpdates).To(gomega.Receive())
+		})
+
+		ginkgo.It("fans events out to other instances over LISTEN/NOTIFY", func() {
+			connString, err := dbCont.ConnectionString(context.Background(), "sslmode=disable")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			otherInstance := offerstream.NewBus(repository.NewOfferRepository(db), nil, zap.NewNop())
+			listener := repository.NewOfferUpdateListener(connString, otherInstance, zap.NewNop())
+			listener.Start()
+			defer listener.Stop(context.Background())
+
+			updates, unsubscribe := otherInstance.Subscribe(2)
+			defer unsubscribe()
+
+			notifier := repository.NewOfferUpdateNotifier(db)
+			update := entity.OfferUpdate{Type: entity.OfferUpdateCreated, OfferID: 5, BuyerID: 2, ShopOwnerID: 1}
+
+			// слушатель подключается асинхронно, поэтому уведомление повторяется до получения
+			gomega.Eventually(func() bool {
+				_ = notifier.Broadcast(context.Background(), update)
+				select {
+				case received := <-updates:
+					return received.OfferID == 5
+				case <-time.After(200 * time.Millisecond):
+					return false
+				}
+			}).WithTimeout(10 * time.Second).Should(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Context("when the shop has webhooks", ginkgo.Ordered, func() {
+		type received struct {
+			header http.Header
+			body   []byte
+		}
+
+		var (
+			receiver    *httptest.Server
+			requests    chan received
+			webhookRepo *repository.WebhookRepository
+			webhookCfg  *config.WebhookConfig
+			worker      *webhook.DeliveryWorker
+			shopID      uint
+			webhookID   uint
+			secret      string
+		)
+
+		ginkgo.BeforeAll(func() {
+			requests = make(chan received, 10)
+			receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- received{header: r.Header.Clone(), body: body}
+			}))
+
+			webhookRepo = repository.NewWebhookRepository(db)
+			webhookCfg = &config.WebhookConfig{
+				PollInterval: time.Second,
+				BatchSize:    10,
+				MaxAttempts:  3,
+				RetryBackoff: time.Minute,
+				Timeout:      5 * time.Second,
+				// получатель слушает на 127.0.0.1
+				AllowPrivateHosts: true,
+			}
+			worker = webhook.NewDeliveryWorker(webhookRepo, webhookCfg, zap.NewNop())
+
+			_ = db.Get(&shopID, "select shop_id from offers where id = 5")
+		})
+
+		ginkgo.AfterAll(func() {
+			receiver.Close()
+		})
+
+		ginkgo.It("registers a webhook and returns its secret once", func() {
+			webhookHand := handler.NewWebhookHandler(
+				webhook.NewService(webhookRepo, repository.NewOfferRepository(db), webhookCfg))
+			router = setupRouter(mockAuthShopOwnerMiddleware(),
+				http.MethodPost, "/api/test/shops/:shopID/webhooks", webhookHand.PostWebhook)
+
+			jsonBody, _ := json.Marshal(dto.PostWebhookReq{
+				URL:    receiver.URL,
+				Events: []string{entity.WebhookEventOfferStatusChanged},
+			})
+			req := httptest.NewRequest(http.MethodPost,
+				fmt.Sprintf("/api/test/shops/%d/webhooks", shopID), bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+
+			var resp dto.CreatedWebhookResp
+			gomega.Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(gomega.Succeed())
+			gomega.Expect(resp.Secret).NotTo(gomega.BeEmpty())
+			webhookID, secret = resp.ID, resp.Secret
+		})
+
+		ginkgo.It("delivers subscribed offer events once with a valid signature", func() {
+			sink := outbox.NewWebhookSink(webhook.NewService(webhookRepo, repository.NewOfferRepository(db), webhookCfg))
+			created := entity.OutboxMessage{
+				Topic:       entity.OutboxTopicOfferEvent,
+				AggregateID: 5,
+				Payload:     []byte(`{"type":"created","event_id":1001,"status":"pending","price":50}`),
+				CreatedAt:   time.Now(),
+			}
+			countered := entity.OutboxMessage{
+				Topic:       entity.OutboxTopicOfferEvent,
+				AggregateID: 5,
+				Payload:     []byte(`{"type":"status_changed","event_id":1002,"status":"countered","price":45}`),
+				CreatedAt:   time.Now(),
+			}
+			gomega.Expect(sink.Handle(context.Background(), created)).To(gomega.Succeed())
+			gomega.Expect(sink.Handle(context.Background(), countered)).To(gomega.Succeed())
+			// повтор сообщения outbox второй доставки не создаёт
+			gomega.Expect(sink.Handle(context.Background(), countered)).To(gomega.Succeed())
+
+			delivered, err := worker.DeliverDue(context.Background())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(delivered).To(gomega.Equal(1))
+
+			var req received
+			gomega.Expect(requests).To(gomega.Receive(&req))
+			gomega.Expect(req.header.Get(webhook.EventHeader)).To(gomega.Equal(entity.WebhookEventOfferStatusChanged))
+
+			timestamp, err := strconv.ParseInt(req.header.Get(webhook.TimestampHeader), 10, 64)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(req.header.Get(webhook.SignatureHeader)).To(gomega.Equal(webhook.Sign(secret, timestamp, req.body)))
+			gomega.Expect(string(req.body)).To(gomega.ContainSubstring(`"id":"offer_event:1002"`))
+			gomega.Expect(string(req.body)).To(gomega.ContainSubstring(`"offer_id":5`))
+			// статус из события, хотя оффер с тех пор уже отклонён
+			gomega.Expect(string(req.body)).To(gomega.ContainSubstring(`"status":"countered"`))
+		})
+
+		ginkgo.It("redelivers a delivery from the log", func() {
+			webhookHand := handler.NewWebhookHandler(
+				webhook.NewService(webhookRepo, repository.NewOfferRepository(db), webhookCfg))
+			router = gin.New()
+			router.Use(middleware.Errors())
+			router.Use(mockAuthShopOwnerMiddleware())
+			router.GET("/api/test/shops/:shopID/webhooks/:webhookID/deliveries", webhookHand.GetDeliveries)
+			router.POST("/api/test/shops/:shopID/webhooks/:webhookID/deliveries/:deliveryID/redeliver",
+				webhookHand.Redeliver)
+
+			req := httptest.NewRequest(http.MethodGet,
+				fmt.Sprintf("/api/test/shops/%d/webhooks/%d/deliveries", shopID, webhookID), nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var deliveries dto.GetWebhookDeliveriesResp
+			gomega.Expect(json.Unmarshal(rec.Body.Bytes(), &deliveries)).To(gomega.Succeed())
+			gomega.Expect(deliveries.Data).To(gomega.HaveLen(1))
+			gomega.Expect(deliveries.Data[0].Status).To(gomega.Equal(entity.WebhookDeliveryDelivered))
+			gomega.Expect(deliveries.Data[0].Attempts).To(gomega.Equal(1))
+
+			req = httptest.NewRequest(http.MethodPost, fmt.Sprintf(
+				"/api/test/shops/%d/webhooks/%d/deliveries/%d/redeliver", shopID, webhookID, deliveries.Data[0].ID), nil)
+			rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusAccepted))
+
+			delivered, err := worker.DeliverDue(context.Background())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(delivered).To(gomega.Equal(1))
+			gomega.Expect(requests).To(gomega.Receive())
+		})
+	})
+
+	ginkgo.Context("when a guest makes an offer", ginkgo.Ordered, func() {
+		const guestEmail = "guest@example.com"
+
+		var (
+			guestOfferRepo *guestofferrepo.Repository
+			guestServ      guestofferservice.Service
+		)
+
+		// guestToken достаёт токен из ссылки в последнем письме гостю, ждущем отправки в outbox
+		guestToken := func() string {
+			var link string
+			_ = db.Get(&link, `select payload->>'link' from outbox where topic = $1 and payload->>'kind' = $2
+				and payload->>'to' = $3 order by id desc limit 1`,
+				entity.OutboxTopicEmail, entity.EmailGuestOfferSent, guestEmail)
+			return link[strings.LastIndex(link, "/")+1:]
+		}
+
+		makeOffer := func(productID uint) {
+			err := guestServ.ProcessGuestOffer(context.Background(), entity.GuestOfferData{
+				ProductID:  productID,
+				StoreID:    2,
+				Price:      70,
+				Currency:   "USD",
+				GuestName:  "guest",
+				GuestEmail: guestEmail,
+				GuestPhone: "guestphone",
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+
+		ginkgo.BeforeAll(func() {
+			guestOfferRepo = guestofferrepo.NewRepository(db)
+			guestServ = guestofferservice.NewService(guestOfferRepo, guestOfferRepo, pricing, relay,
+				&config.GuestOfferConfig{LinkURL: "http://localhost:8080/guest/offers"}, zap.NewNop())
+		})
+
+		ginkgo.It("stores the offer and emails the guest a link to it", func() {
+			makeOffer(2)
+
+			offer, err := guestServ.GetGuestOffer(context.Background(), guestToken())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var created []string
+			_ = db.Select(&created, "select payload->>'to' from outbox where topic = 'email' and aggregate_id = $1",
+				offer.ID)
+			gomega.Expect(created).To(gomega.ConsistOf("user1email", guestEmail))
+
+			gomega.Expect(offer.Status).To(gomega.Equal("pending"))
+			gomega.Expect(offer.ShopName).To(gomega.Equal("shop2"))
+			gomega.Expect(offer.ProductName).To(gomega.Equal("product2"))
+			gomega.Expect(offer.GuestName).To(gomega.Equal("guest"))
+
+			// вебхуки магазина получают оффер через outbox
+			var queued int
+			_ = db.Get(&queued, "select count(*) from outbox where topic = $1 and aggregate_id = $2",
+				entity.OutboxTopicGuestOffer, offer.ID)
+			gomega.Expect(queued).To(gomega.Equal(1))
+
+			_, err = guestServ.GetGuestOffer(context.Background(), "not-a-token")
+			gomega.Expect(err).To(gomega.MatchError(apperror.ErrOfferNotFound))
+		})
+
+		ginkgo.It("lets the shop decline it through the offer flow", func() {
+			offer, err := guestServ.GetGuestOffer(context.Background(), guestToken())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			shopOffers, _, _, err := offerServ.GetShopOffers(context.Background(), 1,
+				entity.ShopOfferFilter{ShopID: 2}, 1, 100, "")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(shopOffers).To(gomega.ContainElement(
+				gomega.HaveField("ID", offer.ID)))
+
+			_, err = offerServ.UpdateOfferStatus(context.Background(),
+				entity.Offer{ID: offer.ID, Status: "declined"}, 1, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			offer, err = guestServ.GetGuestOffer(context.Background(), guestToken())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(offer.Status).To(gomega.Equal("declined"))
+
+			var recipients []string
+			_ = db.Select(&recipients, `select payload->>'to' from outbox where topic = 'email' and aggregate_id = $1
+				and payload->>'kind' = $2`, offer.ID, entity.EmailStatusUpdate)
+			gomega.Expect(recipients).To(gomega.ConsistOf(guestEmail))
+
+			_, err = guestServ.CancelGuestOffer(context.Background(), guestToken())
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("does not let the shop counter it", func() {
+			makeOffer(3)
+			offer, err := guestServ.GetGuestOffer(context.Background(), guestToken())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = offerServ.UpdateOfferStatus(context.Background(),
+				entity.Offer{ID: offer.ID, Status: "countered", Price: 80}, 1, true)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("lets the guest cancel a pending offer", func() {
+			offer, err := guestServ.CancelGuestOffer(context.Background(), guestToken())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(offer.Status).To(gomega.Equal("cancelled"))
+
+			var events int
+			_ = db.Get(&events, "select count(*) from offer_events where offer_id = $1 and to_status = 'cancelled'",
+				offer.ID)
+			gomega.Expect(events).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("limits offers per guest email", func() {
+			limited := guestofferservice.NewService(guestOfferRepo, guestOfferRepo, pricing, relay,
+				&config.GuestOfferConfig{LimitWindow: time.Hour, MaxPerEmail: 2}, zap.NewNop())
+
+			err := limited.ProcessGuestOffer(context.Background(), entity.GuestOfferData{
+				ProductID: 1, StoreID: 2, Price: 70, Currency: "USD",
+				GuestName: "guest", GuestEmail: "GUEST@example.com", GuestPhone: "guestphone",
+			})
+
+			var appErr apperror.AppError
+			gomega.Expect(errors.As(err, &appErr)).To(gomega.BeTrue())
+			gomega.Expect(appErr.Code()).To(gomega.Equal(apperror.TooManyRequests))
+		})
+
+		ginkgo.It("rejects offers for products the shop does not sell", func() {
+			_, err := db.Exec("update shop_inventory set is_available = false where product_id = 1 and shop_id = 2")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			defer func() {
+				_, _ = db.Exec("update shop_inventory set is_available = true where product_id = 1 and shop_id = 2")
+			}()
+
+			err = guestServ.ProcessGuestOffer(context.Background(), entity.GuestOfferData{
+				ProductID: 1, StoreID: 2, Price: 70, Currency: "USD",
+				GuestName: "guest", GuestEmail: "other@example.com", GuestPhone: "guestphone",
+			})
+
+			var appErr apperror.AppError
+			gomega.Expect(errors.As(err, &appErr)).To(gomega.BeTrue())
+			gomega.Expect(appErr.Code()).To(gomega.Equal(apperror.Conflict))
+		})
+
+		ginkgo.It("hands the offers over to the guest who registers with the link token", func() {
+			makeOffer(4)
+			token := guestToken()
+			offer, err := guestServ.GetGuestOffer(context.Background(), token)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = offerServ.UpdateOfferStatus(context.Background(),
+				entity.Offer{ID: offer.ID, Status: "accepted"}, 1, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var orders int
+			_ = db.Get(&orders, "select count(*) from orders where offer_id = $1", offer.ID)
+			gomega.Expect(orders).To(gomega.Equal(0))
+
+			// регистрация с той же почтой без токена ничего не забирает: почта не подтверждена
+			_, claimed, err := repository.NewUserRepository(db).InsertUser(context.Background(), user.User{
+				Name:     "guest",
+				Email:    "Guest@Example.com",
+				Phone:    "guestphone",
+				Password: "hash",
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(claimed).To(gomega.Equal(0))
+
+			var guestOffers int
+			_ = db.Get(&guestOffers, "select count(*) from guest_offers where guest_email = $1", guestEmail)
+			gomega.Expect(guestOffers).To(gomega.Equal(3))
+			_, err = guestServ.GetGuestOffer(context.Background(), token)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			tokenHash := sha256.Sum256([]byte(token))
+			userID, claimed, err := repository.NewUserRepository(db).InsertUser(context.Background(), user.User{
+				Name:       "guest",
+				Email:      "guest.account@example.com",
+				Phone:      "guestphone",
+				Password:   "hash",
+				GuestToken: hex.EncodeToString(tokenHash[:]),
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(claimed).To(gomega.Equal(3))
+
+			claimedOffer, err := offerServ.GetOffer(context.Background(), offer.ID, userID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(claimedOffer.Status).To(gomega.Equal("accepted"))
+
+			_ = db.Get(&orders, "select count(*) from orders where offer_id = $1 and user_id = $2", offer.ID, userID)
+			gomega.Expect(orders).To(gomega.Equal(1))
+
+			_, err = guestServ.GetGuestOffer(context.Background(), token)
+			gomega.Expect(err).To(gomega.MatchError(apperror.ErrOfferNotFound))
+		})
+	})
+
+	ginkgo.Context("when the shop has a pricing policy", ginkgo.Ordered, func() {
+		var policyHand *handler.PricingPolicyHandler
+
+		putPolicy := func(authMiddleware gin.HandlerFunc, policy string) *httptest.ResponseRecorder {
+			router = setupRouter(authMiddleware,
+				http.MethodPut, "/api/test/shops/:shopID/pricing-policy", policyHand.PutPolicy)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/test/shops/1/pricing-policy",
+				bytes.NewBufferString(policy))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		postOffer := func(price float64, currency string) *httptest.ResponseRecorder {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodPost, "/api/test/offers", offerHand.PostOffer)
+
+			jsonBody, _ := json.Marshal(dto.PostOfferReq{
+				ProductID: 1,
+				ShopID:    1,
+				Price:     price,
+				Currency:  currency,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/test/offers", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		violations := func(rec *httptest.ResponseRecorder) []apperror.Violation {
+			var resp struct {
+				Code       string               `json:"code"`
+				Violations []apperror.Violation `json:"violations"`
+			}
+			gomega.Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(gomega.Succeed())
+			gomega.Expect(resp.Code).To(gomega.Equal(apperror.BadRequest))
+			return resp.Violations
+		}
+
+		ginkgo.BeforeAll(func() {
+			policyHand = handler.NewPricingPolicyHandler(pricing)
+		})
+
+		ginkgo.AfterAll(func() {
+			_, _ = db.Exec("delete from shop_pricing_policies")
+			_, _ = db.Exec("delete from currency_rates where currency <> 'EUR'")
+		})
+
+		ginkgo.It("lets the shop owner set the minimal offer price", func() {
+			rec := putPolicy(mockAuthShopOwnerMiddleware(), `{"min_price_percent": 50}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.PricingPolicyResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.MinPricePercent).To(gomega.Equal(50.0))
+			gomega.Expect(resp.UpdatedAt).NotTo(gomega.BeNil())
+		})
+
+		ginkgo.It("does not let another shop owner change it", func() {
+			rec := putPolicy(mockAuthIncorrectShopOwnerMiddleware(), `{"min_price_percent": 0}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("rejects offers below the minimum with the violated rule", func() {
+			rec := postOffer(49.99, "USD")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+			gomega.Expect(violations(rec)).To(gomega.ConsistOf(
+				gomega.HaveField("Rule", "min_price_percent")))
+		})
+
+		ginkgo.It("rejects offers in a currency other than the inventory's", func() {
+			rec := postOffer(100, "EUR")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+			gomega.Expect(violations(rec)).To(gomega.ConsistOf(
+				gomega.HaveField("Field", "currency")))
+		})
+
+		ginkgo.It("accepts offers at the minimum", func() {
+			rec := postOffer(50, "USD")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+		})
+
+		ginkgo.It("converts offers in another currency once rates are loaded", func() {
+			loaded, err := rates.LoadRates(context.Background(), []entity.CurrencyRate{{Currency: "usd", Rate: 1.25}})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(loaded).To(gomega.Equal(1))
+
+			rec := postOffer(39.99, "EUR")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
+			gomega.Expect(violations(rec)).To(gomega.ConsistOf(
+				gomega.HaveField("Rule", "min_price_percent")))
+
+			rec = postOffer(40, "EUR")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+		})
+
+		ginkgo.It("shows the buyer's offers in the requested currency", func() {
+			router = setupRouter(mockAuthBuyerMiddleware(),
+				http.MethodGet, "/api/test/offers", offerHand.GetUserOffers)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/test/offers?currency=EUR&limit=100", nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetUserOffersResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.ContainElement(gomega.And(
+				gomega.HaveField("Price", 50.0),
+				gomega.HaveField("Currency", "USD"),
+				gomega.HaveField("DisplayPrice", gomega.HaveValue(gomega.Equal(40.0))),
+				gomega.HaveField("DisplayCurrency", "EUR"),
+			)))
+		})
+	})
+
+	ginkgo.Context("when a buyer publishes a wanted request", ginkgo.Ordered, func() {
+		var (
+			wantedHand *handler.WantedHandler
+			requestID  uint
+		)
+
+		serve := func(authMiddleware gin.HandlerFunc, method, path, url string, handlerFunc gin.HandlerFunc,
+			body string) *httptest.ResponseRecorder {
+			router = setupRouter(authMiddleware, method, path, handlerFunc)
+
+			req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		putBid := func(authMiddleware gin.HandlerFunc, shopID int, body string) *httptest.ResponseRecorder {
+			return serve(authMiddleware, http.MethodPut, "/api/test/shops/:shopID/wanted/:requestID/bid",
+				fmt.Sprintf("/api/test/shops/%d/wanted/%d/bid", shopID, requestID), wantedHand.PutBid, body)
+		}
+
+		getRequest := func() dto.WantedRequestResp {
+			rec := serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/wanted/:requestID",
+				fmt.Sprintf("/api/test/wanted/%d", requestID), wantedHand.GetRequest, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.WantedRequestResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp
+		}
+
+		ginkgo.BeforeAll(func() {
+			wantedHand = handler.NewWantedHandler(wanted.NewService(repository.NewWantedRepository(db), relay,
+				&config.WantedConfig{MaxDuration: 24 * time.Hour}))
+		})
+
+		ginkgo.It("publishes a request for a category", func() {
+			body := fmt.Sprintf(`{"category_id": 1, "target_price": 70, "currency": "usd", "deadline": %q}`,
+				time.Now().Add(time.Hour).Format(time.RFC3339))
+			rec := serve(mockAuthBuyerMiddleware(), http.MethodPost, "/api/test/wanted", "/api/test/wanted",
+				wantedHand.PostRequest, body)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+
+			var resp dto.WantedRequestResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Status).To(gomega.Equal(entity.WantedOpen))
+			gomega.Expect(resp.Currency).To(gomega.Equal("USD"))
+			requestID = resp.ID
+		})
+
+		ginkgo.It("shows the request to shops stocking a matching product", func() {
+			rec := serve(mockAuthShopOwnerMiddleware(), http.MethodGet, "/api/test/shops/:shopID/wanted",
+				"/api/test/shops/1/wanted", wantedHand.GetShopRequests, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetWantedRequestsResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Data).To(gomega.ContainElement(gomega.HaveField("ID", requestID)))
+		})
+
+		ginkgo.It("lets shops bid and hides the request from other owners' shops", func() {
+			gomega.Expect(putBid(mockAuthShopOwnerMiddleware(), 1, `{"product_id": 2, "price": 75}`).Code).
+				To(gomega.Equal(http.StatusOK))
+			gomega.Expect(putBid(mockAuthShopOwnerMiddleware(), 2, `{"product_id": 3, "price": 72}`).Code).
+				To(gomega.Equal(http.StatusOK))
+			gomega.Expect(putBid(mockAuthIncorrectShopOwnerMiddleware(), 1, `{"product_id": 2, "price": 1}`).Code).
+				To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("shows the buyer all bids, lowest first", func() {
+			resp := getRequest()
+			gomega.Expect(resp.Bids).To(gomega.HaveLen(2))
+			gomega.Expect(resp.Bids[0].ShopName).To(gomega.Equal("shop2"))
+			gomega.Expect(resp.Bids[0].Price).To(gomega.Equal(72.0))
+		})
+
+		ginkgo.It("turns the awarded bid into an accepted offer with an order", func() {
+			winner := getRequest().Bids[0]
+
+			rec := serve(mockAuthBuyerMiddleware(), http.MethodPost, "/api/test/wanted/:requestID/bids/:bidID/award",
+				fmt.Sprintf("/api/test/wanted/%d/bids/%d/award", requestID, winner.ID), wantedHand.AwardBid, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.WantedRequestResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Status).To(gomega.Equal(entity.WantedAwarded))
+			gomega.Expect(resp.OfferID).NotTo(gomega.BeNil())
+
+			awardedOffer, err := offerServ.GetOffer(context.Background(), *resp.OfferID, 2)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(awardedOffer.Status).To(gomega.Equal("accepted"))
+			gomega.Expect(awardedOffer.Price).To(gomega.Equal(72.0))
+			gomega.Expect(awardedOffer.ShopID).To(gomega.Equal(uint(2)))
+
+			var orders int
+			_ = db.Get(&orders, "select count(*) from orders where offer_id = $1 and price = 72", *resp.OfferID)
+			gomega.Expect(orders).To(gomega.Equal(1))
+
+			gomega.Expect(getRequest().Bids).To(gomega.ConsistOf(
+				gomega.HaveField("Status", entity.BidWon),
+				gomega.HaveField("Status", entity.BidLost),
+			))
+		})
+
+		ginkgo.It("no longer accepts bids", func() {
+			rec := putBid(mockAuthShopOwnerMiddleware(), 1, `{"product_id": 2, "price": 70}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
+		})
+
+		ginkgo.It("closes requests past their deadline", func() {
+			wantedRepo := repository.NewWantedRepository(db)
+			productID := uint(1)
+			request, err := wantedRepo.InsertRequest(context.Background(), entity.WantedRequest{
+				UserID:      2,
+				ProductID:   &productID,
+				TargetPrice: 50,
+				Currency:    "USD",
+				Deadline:    time.Now().Add(time.Hour),
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = wantedRepo.UpsertBid(context.Background(),
+				entity.WantedBid{RequestID: request.ID, ShopID: 1, ProductID: 1, Price: 60}, 1)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, _ = db.Exec("update wanted_requests set deadline = now() - interval '1 minute' where id = $1",
+				request.ID)
+
+			closed, err := wanted.NewCloseWorker(wantedRepo, &config.WantedConfig{CloseBatchSize: 10},
+				zap.NewNop()).CloseExpired(context.Background())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(closed).To(gomega.Equal(1))
+
+			expired, err := wantedRepo.GetRequest(context.Background(), request.ID, 2)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(expired.Status).To(gomega.Equal(entity.WantedExpired))
+			gomega.Expect(expired.Bids).To(gomega.ConsistOf(gomega.HaveField("Status", entity.BidLost)))
+		})
+
+		ginkgo.It("drops bids on products the shop stops selling", func() {
+			var productID uint
+			err := db.Get(&productID, `insert into products (name, category_id, description)
+				values ('Coffee grinder', 1, 'burr') returning id`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = db.Exec(`insert into shop_inventory (shop_id, product_id, price, currency, is_available)
+				values (1, $1, 90, 'USD', true)`, productID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			wantedRepo := repository.NewWantedRepository(db)
+			request, err := wantedRepo.InsertRequest(context.Background(), entity.WantedRequest{
+				UserID:      2,
+				ProductID:   &productID,
+				TargetPrice: 80,
+				Currency:    "USD",
+				Deadline:    time.Now().Add(time.Hour),
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			bid, err := wantedRepo.UpsertBid(context.Background(),
+				entity.WantedBid{RequestID: request.ID, ShopID: 1, ProductID: productID, Price: 85}, 1)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// позиция снята с продажи в обход репозитория: ставка ещё активна, но выбрать её нельзя
+			_, _ = db.Exec("update shop_inventory set is_available = false where shop_id = 1 and product_id = $1",
+				productID)
+			_, err = wantedRepo.AwardBid(context.Background(), request.ID, bid.ID, 2)
+			gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("no longer available")))
+
+			_, _ = db.Exec("update shop_inventory set is_available = true where shop_id = 1 and product_id = $1",
+				productID)
+			available := false
+			_, _, err = repository.NewInventoryRepository(db).UpdateItem(context.Background(), 1, productID, 1,
+				entity.InventoryUpdate{IsAvailable: &available})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			open, err := wantedRepo.GetRequest(context.Background(), request.ID, 2)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(open.Status).To(gomega.Equal(entity.WantedOpen))
+			gomega.Expect(open.Bids).To(gomega.ConsistOf(gomega.HaveField("Status", entity.BidLost)))
+
+			_, err = wantedRepo.AwardBid(context.Background(), request.ID, bid.ID, 2)
+			gomega.Expect(err).To(gomega.MatchError(apperror.ErrWantedBidNotFound))
+		})
+	})
+
+	ginkgo.Context("when the buyer and the shop discuss an offer", ginkgo.Ordered, func() {
+		var (
+			messageHand *handler.OfferMessageHandler
+			offerID     uint
+		)
+
+		serve := func(authMiddleware gin.HandlerFunc, method, path, url string, handlerFunc gin.HandlerFunc,
+			body string) *httptest.ResponseRecorder {
+			router = setupRouter(authMiddleware, method, path, handlerFunc)
+
+			req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		postMessage := func(authMiddleware gin.HandlerFunc, body string) *httptest.ResponseRecorder {
+			return serve(authMiddleware, http.MethodPost, "/api/test/offers/:offerID/messages",
+				fmt.Sprintf("/api/test/offers/%d/messages", offerID), messageHand.PostMessage, body)
+		}
+
+		getMessages := func(authMiddleware gin.HandlerFunc) *httptest.ResponseRecorder {
+			return serve(authMiddleware, http.MethodGet, "/api/test/offers/:offerID/messages",
+				fmt.Sprintf("/api/test/offers/%d/messages", offerID), messageHand.GetMessages, "")
+		}
+
+		getUnread := func(authMiddleware gin.HandlerFunc) dto.GetUnreadMessagesResp {
+			rec := serve(authMiddleware, http.MethodGet, "/api/test/messages/unread", "/api/test/messages/unread",
+				messageHand.GetUnread, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetUnreadMessagesResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp
+		}
+
+		ginkgo.BeforeAll(func() {
+			messageHand = handler.NewOfferMessageHandler(offermessage.NewService(
+				repository.NewOfferMessageRepository(db), relay))
+
+			err := db.Get(&offerID, `insert into offers (user_id, shop_id, product_id, offer_price, currency, status,
+				created_at, updated_at, expires_at)
+				values (2, 2, 3, 90, 'usd', 'pending', now(), now(), now() + interval '1 day') returning id`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("lets the buyer write and notifies the shop owner once", func() {
+			gomega.Expect(postMessage(mockAuthBuyerMiddleware(), `{"body": "can you include delivery?"}`).Code).
+				To(gomega.Equal(http.StatusCreated))
+			gomega.Expect(postMessage(mockAuthBuyerMiddleware(), `{"body": "to Moscow"}`).Code).
+				To(gomega.Equal(http.StatusCreated))
+
+			var recipients []string
+			_ = db.Select(&recipients, "select payload->>'to' from outbox where topic = 'email' and aggregate_id = $1",
+				offerID)
+			gomega.Expect(recipients).To(gomega.ConsistOf("user1email"))
+		})
+
+		ginkgo.It("counts the messages as unread for the shop owner only", func() {
+			gomega.Expect(getUnread(mockAuthShopOwnerMiddleware()).Offers).To(gomega.ContainElement(
+				dto.OfferUnreadResp{OfferID: offerID, Unread: 2}))
+			gomega.Expect(getUnread(mockAuthBuyerMiddleware()).Offers).NotTo(gomega.ContainElement(
+				gomega.HaveField("OfferID", offerID)))
+		})
+
+		ginkgo.It("marks the messages as read when the shop owner opens the thread", func() {
+			rec := getMessages(mockAuthShopOwnerMiddleware())
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetOfferMessagesResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Meta.Unread).To(gomega.Equal(2))
+			gomega.Expect(resp.Meta.TotalItems).To(gomega.Equal(2))
+			gomega.Expect(resp.Data[0].Body).To(gomega.Equal("can you include delivery?"))
+			gomega.Expect(resp.Data[0].SenderRole).To(gomega.Equal("buyer"))
+
+			gomega.Expect(getUnread(mockAuthShopOwnerMiddleware()).Offers).NotTo(gomega.ContainElement(
+				gomega.HaveField("OfferID", offerID)))
+		})
+
+		ginkgo.It("hides the thread from other users", func() {
+			gomega.Expect(getMessages(mockAuthIncorrectShopOwnerMiddleware()).Code).To(gomega.Equal(http.StatusNotFound))
+			gomega.Expect(postMessage(mockAuthIncorrectShopOwnerMiddleware(), `{"body": "hi"}`).Code).
+				To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("keeps the thread open while the offer is countered", func() {
+			_, err := db.Exec("update offers set status = 'countered' where id = $1", offerID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			defer func() {
+				_, _ = db.Exec("update offers set status = 'pending' where id = $1", offerID)
+			}()
+
+			gomega.Expect(postMessage(mockAuthShopOwnerMiddleware(), `{"body": "delivery is included"}`).Code).
+				To(gomega.Equal(http.StatusCreated))
+		})
+
+		ginkgo.It("makes the thread read-only once the offer is closed", func() {
+			_, err := offerServ.UpdateOfferStatus(context.Background(),
+				entity.Offer{ID: offerID, Status: "declined"}, 1, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(postMessage(mockAuthShopOwnerMiddleware(), `{"body": "sorry"}`).Code).
+				To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(getMessages(mockAuthBuyerMiddleware()).Code).To(gomega.Equal(http.StatusOK))
+		})
+
+		ginkgo.It("marks as read only the messages on the opened page", func() {
+			// три сообщения уже прочитаны, четыре новых от покупателя
+			_, err := db.Exec(`insert into offer_messages (offer_id, sender_id, sender_role, body)
+				select $1, 2, 'buyer', 'follow-up ' || n from generate_series(1, 4) n`, offerID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			getPage := func(page int) dto.GetOfferMessagesResp {
+				rec := serve(mockAuthShopOwnerMiddleware(), http.MethodGet, "/api/test/offers/:offerID/messages",
+					fmt.Sprintf("/api/test/offers/%d/messages?page=%d&limit=5", offerID, page),
+					messageHand.GetMessages, "")
+				gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+				var resp dto.GetOfferMessagesResp
+				_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+				return resp
+			}
+
+			gomega.Expect(getPage(1).Meta.Unread).To(gomega.Equal(2))
+			gomega.Expect(getUnread(mockAuthShopOwnerMiddleware()).Offers).To(gomega.ContainElement(
+				dto.OfferUnreadResp{OfferID: offerID, Unread: 2}))
+
+			gomega.Expect(getPage(2).Meta.Unread).To(gomega.Equal(2))
+			gomega.Expect(getUnread(mockAuthShopOwnerMiddleware()).Offers).NotTo(gomega.ContainElement(
+				gomega.HaveField("OfferID", offerID)))
+		})
+	})
+
+	ginkgo.Context("when a shop manages its products", ginkgo.Ordered, func() {
+		var (
+			productHand *handler.ProductHandler
+			productID   int
+		)
+
+		serve := func(authMiddleware gin.HandlerFunc, method, path, url string, handlerFunc gin.HandlerFunc,
+			body string) *httptest.ResponseRecorder {
+			router = setupRouter(authMiddleware, method, path, handlerFunc)
+
+			req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		patchProduct := func(authMiddleware gin.HandlerFunc, shopID int, body string) *httptest.ResponseRecorder {
+			return serve(authMiddleware, http.MethodPatch, "/api/test/shops/:shopID/products/:productID",
+				fmt.Sprintf("/api/test/shops/%d/products/%d", shopID, productID), productHand.PatchProduct, body)
+		}
+
+		catalogTotal := func() int {
+			rec := serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/products",
+				"/api/test/products?name=Kettle", productHand.GetProducts, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp struct {
+				Meta struct {
+					TotalItems int `json:"total_items"`
+				} `json:"meta"`
+			}
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp.Meta.TotalItems
+		}
+
+		ginkgo.BeforeAll(func() {
+			productHand = handler.NewProductHandler(product.NewService(repository.NewProductRepository(db), "USD"))
+		})
+
+		ginkgo.It("creates a product with attributes", func() {
+			rec := serve(mockAuthShopOwnerMiddleware(), http.MethodPost, "/api/test/shops/:shopID/products",
+				"/api/test/shops/1/products", productHand.PostProduct,
+				`{"name": "Kettle", "category_id": 1, "product_attributes": {"volume": 1.7, "color": "white"}}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+
+			var created entity.Product
+			_ = json.Unmarshal(rec.Body.Bytes(), &created)
+			gomega.Expect(*created.ShopID).To(gomega.Equal(1))
+			gomega.Expect(created.Attributes).To(gomega.HaveKeyWithValue("color", "white"))
+			productID = created.ID
+
+			gomega.Expect(catalogTotal()).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("rejects products in a missing category", func() {
+			rec := serve(mockAuthShopOwnerMiddleware(), http.MethodPost, "/api/test/shops/:shopID/products",
+				"/api/test/shops/1/products", productHand.PostProduct, `{"name": "Kettle", "category_id": 999}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("replaces the attributes on update", func() {
+			rec := patchProduct(mockAuthShopOwnerMiddleware(), 1, `{"product_attributes": {"color": "black"}}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var updated entity.Product
+			_ = json.Unmarshal(rec.Body.Bytes(), &updated)
+			gomega.Expect(updated.Name).To(gomega.Equal("Kettle"))
+			gomega.Expect(updated.Attributes).To(gomega.Equal(map[string]interface{}{"color": "black"}))
+		})
+
+		ginkgo.It("hides the product from other shops and other owners", func() {
+			gomega.Expect(patchProduct(mockAuthShopOwnerMiddleware(), 2, `{"name": "Teapot"}`).Code).
+				To(gomega.Equal(http.StatusNotFound))
+			gomega.Expect(patchProduct(mockAuthIncorrectShopOwnerMiddleware(), 1, `{"name": "Teapot"}`).Code).
+				To(gomega.Equal(http.StatusNotFound))
+			gomega.Expect(patchProduct(mockAuthBuyerMiddleware(), 1, `{"name": "Teapot"}`).Code).
+				To(gomega.Equal(http.StatusForbidden))
+		})
+
+		ginkgo.It("archives the product and removes it from the catalog", func() {
+			archive := func() *httptest.ResponseRecorder {
+				return serve(mockAuthShopOwnerMiddleware(), http.MethodPost,
+					"/api/test/shops/:shopID/products/:productID/archive",
+					fmt.Sprintf("/api/test/shops/1/products/%d/archive", productID), productHand.ArchiveProduct, "")
+			}
+
+			gomega.Expect(archive().Code).To(gomega.Equal(http.StatusOK))
+			gomega.Expect(archive().Code).To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(catalogTotal()).To(gomega.Equal(0))
+			gomega.Expect(patchProduct(mockAuthShopOwnerMiddleware(), 1, `{"name": "Teapot"}`).Code).
+				To(gomega.Equal(http.StatusConflict))
+
+			rec := serve(mockAuthShopOwnerMiddleware(), http.MethodGet, "/api/test/shops/:shopID/products",
+				"/api/test/shops/1/products", productHand.GetShopProducts, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+			gomega.Expect(rec.Body.String()).To(gomega.ContainSubstring(`"archived_at"`))
+		})
+
+		ginkgo.It("does not archive a product other shops still sell", func() {
+			var frotherID int
+			err := db.Get(&frotherID, `insert into products (name, category_id, description, shop_id)
+				values ('Milk frother', 1, 'handheld', 1) returning id`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = db.Exec(`insert into shop_inventory (shop_id, product_id, price, currency, is_available)
+				values (1, $1, 20, 'USD', true), (2, $1, 25, 'USD', true)`, frotherID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			archive := func() *httptest.ResponseRecorder {
+				return serve(mockAuthShopOwnerMiddleware(), http.MethodPost,
+					"/api/test/shops/:shopID/products/:productID/archive",
+					fmt.Sprintf("/api/test/shops/1/products/%d/archive", frotherID), productHand.ArchiveProduct, "")
+			}
+
+			rec := archive()
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(rec.Body.String()).To(gomega.ContainSubstring("other shops"))
+
+			_, err = db.Exec(`update shop_inventory set is_available = false where shop_id = 2 and product_id = $1`,
+				frotherID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(archive().Code).To(gomega.Equal(http.StatusOK))
+
+			var available []bool
+			err = db.Select(&available, `select is_available from shop_inventory where product_id = $1`, frotherID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(available).To(gomega.ConsistOf(false, false))
+		})
+	})
+
+	ginkgo.Context("when a shop manages its inventory", ginkgo.Ordered, func() {
+		var (
+			inventoryHand *handler.InventoryHandler
+			productID     uint
+			offerID       uint
+		)
+
+		serve := func(authMiddleware gin.HandlerFunc, method, path, url string, handlerFunc gin.HandlerFunc,
+			body string) *httptest.ResponseRecorder {
+			router = setupRouter(authMiddleware, method, path, handlerFunc)
+
+			req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		postItem := func(authMiddleware gin.HandlerFunc, body string) *httptest.ResponseRecorder {
+			return serve(authMiddleware, http.MethodPost, "/api/test/shops/:shopID/inventory",
+				"/api/test/shops/2/inventory", inventoryHand.PostItem, body)
+		}
+
+		patchItem := func(body string) *httptest.ResponseRecorder {
+			return serve(mockAuthShopOwnerMiddleware(), http.MethodPatch, "/api/test/shops/:shopID/inventory/:productID",
+				fmt.Sprintf("/api/test/shops/2/inventory/%d", productID), inventoryHand.PatchItem, body)
+		}
+
+		deleteItem := func(id uint) *httptest.ResponseRecorder {
+			return serve(mockAuthShopOwnerMiddleware(), http.MethodDelete, "/api/test/shops/:shopID/inventory/:productID",
+				fmt.Sprintf("/api/test/shops/2/inventory/%d", id), inventoryHand.DeleteItem, "")
+		}
+
+		history := func(id uint) []string {
+			rec := serve(mockAuthShopOwnerMiddleware(), http.MethodGet,
+				"/api/test/shops/:shopID/inventory/:productID/history",
+				fmt.Sprintf("/api/test/shops/2/inventory/%d/history", id), inventoryHand.GetItemHistory, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.GetInventoryHistoryResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+
+			changes := make([]string, 0, len(resp.Data))
+			for _, change := range resp.Data {
+				changes = append(changes, change.Change)
+			}
+			return changes
+		}
+
+		ginkgo.BeforeAll(func() {
+			inventoryHand = handler.NewInventoryHandler(inventory.NewService(repository.NewInventoryRepository(db), relay,
+				&config.InventoryConfig{ImportMaxRows: 100, ImportMaxBytes: 1 << 16}), zap.NewNop())
+
+			err := db.Get(&productID, `insert into products (name, category_id, description)
+				values ('Toaster', 1, 'two slots') returning id`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("adds the product once and only for the owner", func() {
+			body := fmt.Sprintf(`{"product_id": %d, "price": 40, "currency": "usd"}`, productID)
+
+			rec := postItem(mockAuthShopOwnerMiddleware(), body)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+
+			var item dto.InventoryItemResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &item)
+			gomega.Expect(item.ProductName).To(gomega.Equal("Toaster"))
+			gomega.Expect(item.Currency).To(gomega.Equal("USD"))
+			gomega.Expect(item.IsAvailable).To(gomega.BeTrue())
+
+			gomega.Expect(postItem(mockAuthShopOwnerMiddleware(), body).Code).To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(postItem(mockAuthIncorrectShopOwnerMiddleware(), body).Code).
+				To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("reprices the item and records the history", func() {
+			rec := patchItem(`{"price": 35.5}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.PatchInventoryItemResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.Price).To(gomega.Equal(35.5))
+			gomega.Expect(resp.DeclinedOffers).To(gomega.BeZero())
+
+			gomega.Expect(history(productID)).To(gomega.Equal([]string{"updated", "added"}))
+		})
+
+		ginkgo.It("declines open offers and emails the buyer when the item becomes unavailable", func() {
+			err := db.Get(&offerID, `insert into offers (user_id, shop_id, product_id, offer_price, currency, status,
+				created_at, updated_at, expires_at)
+				values (2, 2, $1, 30, 'usd', 'pending', now(), now(), now() + interval '1 day') returning id`, productID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			rec := patchItem(`{"is_available": false}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp dto.PatchInventoryItemResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.IsAvailable).To(gomega.BeFalse())
+			gomega.Expect(resp.DeclinedOffers).To(gomega.Equal(1))
+
+			var status string
+			_ = db.Get(&status, "select status from offers where id = $1", offerID)
+			gomega.Expect(status).To(gomega.Equal("declined"))
+
+			var payloads []string
+			_ = db.Select(&payloads, "select payload::text from outbox where topic = 'email' and aggregate_id = $1",
+				offerID)
+			gomega.Expect(payloads).To(gomega.HaveLen(1))
+			gomega.Expect(payloads[0]).To(gomega.ContainSubstring("user2email"))
+			gomega.Expect(payloads[0]).To(gomega.ContainSubstring("declined"))
+
+			rec = patchItem(`{"is_available": false}`)
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			gomega.Expect(resp.DeclinedOffers).To(gomega.BeZero())
+		})
+
+		ginkgo.It("keeps items with offers and removes the rest", func() {
+			gomega.Expect(deleteItem(productID).Code).To(gomega.Equal(http.StatusConflict))
+
+			_, err := offerRepo.DeleteOffer(context.Background(), offerID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(deleteItem(productID).Code).To(gomega.Equal(http.StatusNoContent))
+			gomega.Expect(deleteItem(productID).Code).To(gomega.Equal(http.StatusNotFound))
+			gomega.Expect(history(productID)[0]).To(gomega.Equal("removed"))
+		})
+
+		ginkgo.It("imports a file with a dry run first and reports bad rows", func() {
+			importFile := func(dryRun bool) dto.InventoryImportResp {
+				file := fmt.Sprintf("product_id,price,currency,is_available\n%d,42,usd,true\n999999,10,usd,\n1,0,usd,\n",
+					productID)
+				rec := serve(mockAuthShopOwnerMiddleware(), http.MethodPost, "/api/test/shops/:shopID/inventory/import",
+					fmt.Sprintf("/api/test/shops/2/inventory/import?dry_run=%t", dryRun), inventoryHand.ImportInventory,
+					file)
+				gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+				var resp dto.InventoryImportResp
+				_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+				return resp
+			}
+
+			report := importFile(true)
+			gomega.Expect(report.Created).To(gomega.Equal(1))
+			gomega.Expect(report.Failed).To(gomega.Equal(2))
+			gomega.Expect(report.Errors[0].Line).To(gomega.Equal(3))
+			gomega.Expect(history(productID)[0]).To(gomega.Equal("removed"))
+
+			gomega.Expect(importFile(false).Created).To(gomega.Equal(1))
+			gomega.Expect(history(productID)[0]).To(gomega.Equal("added"))
+
+			report = importFile(false)
+			gomega.Expect(report.Created).To(gomega.BeZero())
+			gomega.Expect(report.Unchanged).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("exports the inventory in the import format", func() {
+			rec := serve(mockAuthShopOwnerMiddleware(), http.MethodGet, "/api/test/shops/:shopID/inventory/export",
+				"/api/test/shops/2/inventory/export?format=ndjson", inventoryHand.ExportInventory, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+			gomega.Expect(rec.Header().Get("Content-Type")).To(gomega.Equal("application/x-ndjson"))
+			gomega.Expect(rec.Body.String()).To(gomega.ContainSubstring(
+				fmt.Sprintf(`{"product_id":%d,"product_name":"Toaster","price":42,"currency":"USD","is_available":true}`,
+					productID)))
+
+			rec = serve(mockAuthIncorrectShopOwnerMiddleware(), http.MethodGet,
+				"/api/test/shops/:shopID/inventory/export", "/api/test/shops/2/inventory/export",
+				inventoryHand.ExportInventory, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+	})
+
+	ginkgo.Context("when an admin edits the category tree", ginkgo.Ordered, func() {
+		var (
+			categoryHand *handler.CategoryHandler
+			productHand  *handler.ProductHandler
+			kitchenID    uint
+			kettlesID    uint
+			gardenID     uint
+			productID    uint
+		)
+
+		adminMiddleware := func(c *gin.Context) {
+			mockAuthShopOwnerMiddleware()(c)
+			c.Set(helpers.UserIsAdminKey, true)
+			middleware.Admin()(c)
+		}
+
+		serve := func(authMiddleware gin.HandlerFunc, method, path, url string, handlerFunc gin.HandlerFunc,
+			body string) *httptest.ResponseRecorder {
+			router = setupRouter(authMiddleware, method, path, handlerFunc)
+
+			req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		postCategory := func(body string) dto.CategoryResp {
+			rec := serve(adminMiddleware, http.MethodPost, "/api/test/admin/categories", "/api/test/admin/categories",
+				categoryHand.PostCategory, body)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusCreated))
+
+			var resp dto.CategoryResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp
+		}
+
+		moveCategory := func(categoryID uint, body string) *httptest.ResponseRecorder {
+			return serve(adminMiddleware, http.MethodPost, "/api/test/admin/categories/:categoryID/move",
+				fmt.Sprintf("/api/test/admin/categories/%d/move", categoryID), categoryHand.MoveCategory, body)
+		}
+
+		deleteCategory := func(categoryID uint) *httptest.ResponseRecorder {
+			return serve(adminMiddleware, http.MethodDelete, "/api/test/admin/categories/:categoryID",
+				fmt.Sprintf("/api/test/admin/categories/%d", categoryID), categoryHand.DeleteCategory, "")
+		}
+
+		filteredTotal := func(categoryID uint) int {
+			rec := serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/products",
+				fmt.Sprintf("/api/test/products?name=Gooseneck&category_id=%d", categoryID), productHand.GetProducts, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp struct {
+				Meta struct {
+					TotalItems int `json:"total_items"`
+				} `json:"meta"`
+			}
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp.Meta.TotalItems
+		}
+
+		// expectConsistentTree проверяет, что lft и rgt всех категорий вместе дают числа от 1 до 2n без повторов
+		expectConsistentTree := func() {
+			var bounds struct {
+				Total    int `db:"total"`
+				Distinct int `db:"distinct_bounds"`
+				Max      int `db:"max_bound"`
+				Inverted int `db:"inverted"`
+			}
+			err := db.Get(&bounds, `select
+					(select count(*) from categories) as total,
+					(select count(distinct n) from (select lft n from categories union all
+						select rgt from categories) b) as distinct_bounds,
+					(select coalesce(max(rgt), 0) from categories) as max_bound,
+					(select count(*) from categories where lft >= rgt) as inverted`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(bounds.Distinct).To(gomega.Equal(2 * bounds.Total))
+			gomega.Expect(bounds.Max).To(gomega.Equal(2 * bounds.Total))
+			gomega.Expect(bounds.Inverted).To(gomega.BeZero())
+		}
+
+		ginkgo.BeforeAll(func() {
+			categoryHand = handler.NewCategoryHandler(category.NewService(repository.NewCategoryRepository(db)))
+			productHand = handler.NewProductHandler(product.NewService(repository.NewProductRepository(db), "USD"))
+		})
+
+		ginkgo.It("lets only admins create categories", func() {
+			rec := serve(func(c *gin.Context) {
+				mockAuthBuyerMiddleware()(c)
+				c.Set(helpers.UserIsAdminKey, false)
+				middleware.Admin()(c)
+			}, http.MethodPost, "/api/test/admin/categories", "/api/test/admin/categories",
+				categoryHand.PostCategory, `{"name": "Kitchen"}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusForbidden))
+
+			kitchenID = postCategory(`{"name": "Kitchen", "parent_id": 1}`).ID
+			kettlesID = postCategory(fmt.Sprintf(`{"name": "Kettles", "parent_id": %d}`, kitchenID)).ID
+			gardenID = postCategory(`{"name": "Garden"}`).ID
+			expectConsistentTree()
+
+			rec = serve(adminMiddleware, http.MethodPost, "/api/test/admin/categories", "/api/test/admin/categories",
+				categoryHand.PostCategory, `{"name": "Kettles"}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
+
+			rec = serve(adminMiddleware, http.MethodPost, "/api/test/admin/categories", "/api/test/admin/categories",
+				categoryHand.PostCategory, `{"name": "Lawn", "parent_id": 999}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("returns the tree, a subtree and the breadcrumb path", func() {
+			rec := serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/categories", "/api/test/categories",
+				categoryHand.GetTree, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var tree []dto.CategoryResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &tree)
+			gomega.Expect(tree).To(gomega.HaveLen(2))
+			gomega.Expect(tree[0].ID).To(gomega.Equal(uint(1)))
+			gomega.Expect(tree[0].Children).To(gomega.HaveLen(1))
+			gomega.Expect(tree[0].Children[0].ID).To(gomega.Equal(kitchenID))
+			gomega.Expect(tree[0].Children[0].Children[0].ID).To(gomega.Equal(kettlesID))
+			gomega.Expect(tree[1].ID).To(gomega.Equal(gardenID))
+
+			rec = serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/categories/:categoryID",
+				fmt.Sprintf("/api/test/categories/%d", kitchenID), categoryHand.GetSubtree, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var subtree dto.CategoryResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &subtree)
+			gomega.Expect(subtree.ID).To(gomega.Equal(kitchenID))
+			gomega.Expect(subtree.Children).To(gomega.HaveLen(1))
+
+			rec = serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/categories/:categoryID/path",
+				fmt.Sprintf("/api/test/categories/%d/path", kettlesID), categoryHand.GetPath, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var path []dto.CategoryResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &path)
+			gomega.Expect(path).To(gomega.HaveLen(3))
+			gomega.Expect([]uint{path[0].ID, path[1].ID, path[2].ID}).To(gomega.Equal([]uint{1, kitchenID, kettlesID}))
+
+			rec = serve(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/categories/:categoryID",
+				"/api/test/categories/999", categoryHand.GetSubtree, "")
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusNotFound))
+		})
+
+		ginkgo.It("filters products by the whole subtree of a category", func() {
+			err := db.Get(&productID, `insert into products (name, category_id, description)
+				values ('Gooseneck kettle', $1, 'for pour-over') returning id`, kettlesID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(filteredTotal(1)).To(gomega.Equal(1))
+			gomega.Expect(filteredTotal(kitchenID)).To(gomega.Equal(1))
+			gomega.Expect(filteredTotal(gardenID)).To(gomega.Equal(0))
+		})
+
+		ginkgo.It("moves a category with its subtree", func() {
+			rec := moveCategory(kitchenID, fmt.Sprintf(`{"parent_id": %d}`, gardenID))
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+			expectConsistentTree()
+
+			gomega.Expect(filteredTotal(1)).To(gomega.Equal(0))
+			gomega.Expect(filteredTotal(gardenID)).To(gomega.Equal(1))
+
+			rec = moveCategory(gardenID, fmt.Sprintf(`{"parent_id": %d}`, kettlesID))
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusConflict))
+
+			rec = moveCategory(kitchenID, `{}`)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+			expectConsistentTree()
+
+			var parentID *uint
+			err := db.Get(&parentID, "select parent_id from categories where id = $1", kitchenID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(parentID).To(gomega.BeNil())
+		})
+
+		ginkgo.It("deletes only empty leaf categories", func() {
+			gomega.Expect(deleteCategory(kitchenID).Code).To(gomega.Equal(http.StatusConflict))
+			gomega.Expect(deleteCategory(kettlesID).Code).To(gomega.Equal(http.StatusConflict))
+
+			_, err := db.Exec("delete from products where id = $1", productID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(deleteCategory(kettlesID).Code).To(gomega.Equal(http.StatusNoContent))
+			gomega.Expect(deleteCategory(kitchenID).Code).To(gomega.Equal(http.StatusNoContent))
+
+			var requestID uint
+			err = db.Get(&requestID, `insert into wanted_requests (user_id, category_id, target_price, currency,
+				status, deadline) values (2, $1, 10, 'usd', 'expired', now()) returning id`, gardenID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(deleteCategory(gardenID).Code).To(gomega.Equal(http.StatusConflict))
+
+			_, err = db.Exec("delete from wanted_requests where id = $1", requestID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(deleteCategory(gardenID).Code).To(gomega.Equal(http.StatusNoContent))
+			gomega.Expect(deleteCategory(gardenID).Code).To(gomega.Equal(http.StatusNotFound))
+			expectConsistentTree()
+		})
+	})
+
+	ginkgo.Context("when a buyer searches the catalog", ginkgo.Ordered, func() {
+		var productHand *handler.ProductHandler
+
+		type searchResp struct {
+			Data []struct {
+				Name    string  `json:"name"`
+				Snippet *string `json:"snippet"`
+			} `json:"data"`
+			Meta struct {
+				TotalItems int `json:"total_items"`
+			} `json:"meta"`
+		}
+
+		search := func(query string) searchResp {
+			router = setupRouter(mockAuthBuyerMiddleware(), http.MethodGet, "/api/test/products",
+				productHand.GetProducts)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/test/products?"+query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			var resp searchResp
+			_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+			return resp
+		}
+
+		names := func(resp searchResp) []string {
+			result := make([]string, 0, len(resp.Data))
+			for _, product := range resp.Data {
+				result = append(result, product.Name)
+			}
+			return result
+		}
+
+		ginkgo.BeforeAll(func() {
+			productHand = handler.NewProductHandler(product.NewService(repository.NewProductRepository(db), "USD"))
+
+			_, err := db.Exec(`insert into products (name, category_id, description) values
+				('Tea tray', 1, 'Fits any <b>samovar</b> up to five litres'),
+				('Brass samovar', 1, 'Wood-fired, five litres'),
+				('Электрический самовар', 1, 'Нержавеющая сталь, три литра')`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("ranks name matches above description matches", func() {
+			resp := search("q=samovar")
+
+			gomega.Expect(resp.Meta.TotalItems).To(gomega.Equal(2))
+			gomega.Expect(names(resp)).To(gomega.Equal([]string{"Brass samovar", "Tea tray"}))
+		})
+
+		ginkgo.It("returns escaped snippets with the query words highlighted", func() {
+			resp := search("q=samovar")
+
+			for _, product := range resp.Data {
+				gomega.Expect(product.Snippet).NotTo(gomega.BeNil())
+				gomega.Expect(*product.Snippet).To(gomega.ContainSubstring("<mark>samovar</mark>"))
+				gomega.Expect(*product.Snippet).NotTo(gomega.ContainSubstring("<b>"))
+			}
+
+			resp = search("name=samovar")
+			gomega.Expect(resp.Data).NotTo(gomega.BeEmpty())
+			gomega.Expect(resp.Data[0].Snippet).To(gomega.BeNil())
+		})
+
+		ginkgo.It("matches Russian word forms and search operators", func() {
+			resp := search("q=" + url.QueryEscape("самовары"))
+			gomega.Expect(names(resp)).To(gomega.Equal([]string{"Электрический самовар"}))
+
+			resp = search("q=" + url.QueryEscape("samovar -tray"))
+			gomega.Expect(names(resp)).To(gomega.Equal([]string{"Brass samovar"}))
+		})
+
+		ginkgo.It("pages through ranked results", func() {
+			resp := search("q=samovar&limit=1&page=2")
+
+			gomega.Expect(resp.Meta.TotalItems).To(gomega.Equal(2))
+			gomega.Expect(names(resp)).To(gomega.Equal([]string{"Tea tray"}))
+		})
+
+		ginkgo.It("ignores a blank query", func() {
+			resp := search("q=" + url.QueryEscape("   ") + "&name=" + url.QueryEscape("Brass samovar"))
+
+			gomega.Expect(names(resp)).To(gomega.Equal([]string{"Brass samovar"}))
+		})
+	})
+
+	ginkgo.Context("when a user who acted on offers is deleted", func() {
+		ginkgo.It("keeps the offer history and the offer itself", func() {
+			var userID int
+			err := db.Get(&userID, `insert into users (name, phone_number, password_hash, email, is_store)
+				values ('leaving', 'leaving_phone', 'hash', 'leaving@email', false) returning id`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var offerID int
+			err = db.Get(&offerID, `insert into offers (user_id, shop_id, product_id, offer_price, currency, status)
+				values ($1, 1, 1, 10, 'usd', 'cancelled') returning id`, userID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = db.Exec(`insert into offer_events (offer_id, event_type, from_status, to_status, price,
+				actor_id, actor_role) values ($1, 'status_changed', 'pending', 'cancelled', 10, $2, 'buyer')`,
+				offerID, userID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = db.Exec("delete from users where id = $1", userID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var actors []int
+			_ = db.Select(&actors, "select actor_id from offer_events where actor_id = $1", userID)
+			gomega.Expect(actors).To(gomega.Equal([]int{userID}))
+
+			_, err = db.Exec("delete from offers where id = $1", offerID)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			deleted, err := offerRepo.DeleteOffer(context.Background(), uint(offerID))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(deleted.ID).To(gomega.Equal(uint(offerID)))
+
+			var events int
+			_ = db.Get(&events, "select count(*) from offer_events where offer_id = $1", offerID)
+			gomega.Expect(events).To(gomega.BeZero())
+
+			_, err = offerRepo.DeleteOffer(context.Background(), uint(offerID))
+			gomega.Expect(err).To(gomega.MatchError(apperror.ErrOfferNotFound))
+		})
+	})
+
+	ginkgo.Context("when a buyer sends offers concurrently", func() {
+		ginkgo.It("does not let them exceed the open offers limit", func() {
+			const requests = 6
+
+			var buyerID uint
+			err := db.Get(&buyerID, `insert into users (name, phone_number, password_hash, email, is_store)
+				values ('hasty', 'hasty_phone', 'hash', 'hasty@email', false) returning id`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var productIDs []uint
+			err = db.Select(&productIDs, `insert into products (name, category_id, description)
+				select 'Gaiwan ' || n, 1, 'porcelain' from generate_series(1, $1) n returning id`, requests)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = db.Exec(`insert into shop_inventory (shop_id, product_id, price, currency, is_available)
+				select 2, id, 10, 'USD', true from products where name like 'Gaiwan %'`)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			limited := offer.NewService(offerRepo, pricing, rates, relay, &config.OfferQuotaConfig{MaxOpen: 2})
+			buyer := entity.User{ID: buyerID, Name: "hasty", Email: "hasty@email"}
+
+			errs := make([]error, requests)
+			var wg sync.WaitGroup
+			for i, productID := range productIDs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = limited.CreateOffer(context.Background(), entity.Offer{
+						Price: 10, Currency: "USD", ShopID: 2, ProductID: productID, UserID: buyerID,
+					}, buyer)
+				}()
+			}
+			wg.Wait()
+
+			created := 0
+			for _, err := range errs {
+				if err == nil {
+					created++
+					continue
+				}
+				var appErr apperror.AppError
+				gomega.Expect(errors.As(err, &appErr)).To(gomega.BeTrue())
+				gomega.Expect(appErr.Code()).To(gomega.Equal(apperror.TooManyRequests))
+			}
+			gomega.Expect(created).To(gomega.Equal(2))
+
+			var open int
+			_ = db.Get(&open, "select count(*) from offers where user_id = $1", buyerID)
+			gomega.Expect(open).To(gomega.Equal(2))
+		})
+	})
+
+	ginkgo.Context("when a guest sends offers concurrently", func() {
+		ginkgo.It("does not let them exceed the limit per IP", func() {
+			const (
+				requests = 6
+				guestIP  = "198.51.100.9"
+			)
+
+			guestOfferRepo := guestofferrepo.NewRepository(db)
+			limited := guestofferservice.NewService(guestOfferRepo, guestOfferRepo, pricing, relay,
+				&config.GuestOfferConfig{LimitWindow: time.Hour, MaxPerIP: 2}, zap.NewNop())
+
+			errs := make([]error, requests)
+			var wg sync.WaitGroup
+			for i := range requests {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = limited.ProcessGuestOffer(context.Background(), entity.GuestOfferData{
+						ProductID: 1, StoreID: 2, Price: 70, Currency: "USD", GuestName: "crowd",
+						GuestEmail: fmt.Sprintf("crowd%d@example.com", i), GuestPhone: "crowdphone", GuestIP: guestIP,
+					})
+				}()
+			}
+			wg.Wait()
+
+			created := 0
+			for _, err := range errs {
+				if err == nil {
+					created++
+					continue
+				}
+				var appErr apperror.AppError
+				gomega.Expect(errors.As(err, &appErr)).To(gomega.BeTrue())
+				gomega.Expect(appErr.Code()).To(gomega.Equal(apperror.TooManyRequests))
+			}
+			gomega.Expect(created).To(gomega.Equal(2))
+
+			var stored int
+			_ = db.Get(&stored, "select count(*) from guest_offers where guest_ip = $1", guestIP)
+			gomega.Expect(stored).To(gomega.Equal(2))
+		})
+	})
 })
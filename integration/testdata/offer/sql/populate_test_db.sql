@@ -9,7 +9,7 @@ values ('user3','user3phone', 'no','user3email', true);
 insert into shops (name, user_id) values ('shop1', 1);
 insert into shops (name, user_id) values ('shop2', 1);
 
-insert into categories (name, lft, rgt, parent_id) values ('test_cat', 1, 1, 1);
+insert into categories (name, lft, rgt, parent_id) values ('test_cat', 1, 2, null);
 
 insert into products (name, category_id, description) VALUES ('product1', 1, 'description1');
 insert into products (name, category_id, description) VALUES ('product2', 1, 'description2');
@@ -2,6 +2,7 @@ package apperror
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -15,6 +16,7 @@ const (
 	InvalidFingerprint = "INVALID_FINGERPRINT"
 	Conflict           = "CONFLICT"
 	Forbidden          = "FORBIDDEN"
+	TooManyRequests    = "TOO_MANY_REQUESTS"
 )
 
 type AppError interface {
@@ -44,11 +46,49 @@ func New(code, message string, cause error) *Error {
 	return &Error{ErrCode: code, ErrMsg: message, WrappedErr: cause}
 }
 
+// Violation нарушенное правило проверки одного поля запроса
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError ошибка BadRequest со списком всех нарушенных правил,
+// чтобы клиент мог показать их разом, а не по одному.
+type ValidationError struct {
+	ErrMsg     string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return fmt.Sprintf("%s: %s", e.ErrMsg, strings.Join(messages, "; "))
+}
+func (e *ValidationError) Code() string    { return BadRequest }
+func (e *ValidationError) Message() string { return e.ErrMsg }
+func (e *ValidationError) Unwrap() error   { return nil }
+
+func NewValidationError(message string, violations ...Violation) *ValidationError {
+	return &ValidationError{ErrMsg: message, Violations: violations}
+}
+
 var (
 	ErrProductNotFound = New(NotFound, "product not found", nil)
 	ErrStoreNotFound   = New(NotFound, "store not found", nil)
 
+	ErrInventoryItemNotFound = New(NotFound, "inventory item not found", nil)
+
+	ErrCategoryNotFound = New(NotFound, "category not found", nil)
+
 	ErrOfferNotFound = New(NotFound, "offer not found", nil)
+	ErrOfferExpired  = New(Conflict, "offer has expired", nil)
+	ErrOrderNotFound = New(NotFound, "order not found", nil)
+
+	ErrWantedRequestNotFound = New(NotFound, "wanted request not found", nil)
+	ErrWantedBidNotFound     = New(NotFound, "bid not found", nil)
 
 	ErrUserNotFound             = New(NotFound, "user not found", nil)
 	ErrIncorrectPassword        = New(Unauthorized, "incorrect password", nil)
@@ -59,6 +99,11 @@ var (
 	ErrTokenNotFound = New(NotFound, "token not found", nil)
 
 	ErrNotificationNotFound = New(NotFound, "notification not found", nil)
+
+	ErrWebhookNotFound         = New(NotFound, "webhook not found", nil)
+	ErrWebhookDeliveryNotFound = New(NotFound, "webhook delivery not found", nil)
+
+	ErrCurrencyNotConvertible = New(BadRequest, "currency is not convertible", nil)
 )
 
 // ReviewError представляет ошибку, связанную с отзывами
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// AutoResponseRule правило автоответа магазина на входящие офферы.
+// ProductID == nil означает правило для всего магазина. Незаданные пороги не применяются.
+type AutoResponseRule struct {
+	ID           uint
+	ShopID       uint
+	ProductID    *uint
+	AcceptFrom   *float64
+	DeclineBelow *float64
+	CounterPrice *float64
+	UpdatedAt    time.Time
+}
+
+// AutoResponse решение по офферу, принятое правилом
+type AutoResponse struct {
+	Status       string
+	CounterPrice float64
+}
+
+// Decide применяет правило к цене оффера. Порядок проверок: принять от AcceptFrom,
+// отклонить ниже DeclineBelow, иначе предложить CounterPrice, если оффер ниже её.
+// Второе значение false, если правило оставляет оффер на ручное рассмотрение.
+func (r AutoResponseRule) Decide(price float64) (AutoResponse, bool) {
+	switch {
+	case r.AcceptFrom != nil && price >= *r.AcceptFrom:
+		return AutoResponse{Status: "accepted"}, true
+	case r.DeclineBelow != nil && price < *r.DeclineBelow:
+		return AutoResponse{Status: "declined"}, true
+	case r.CounterPrice != nil && price < *r.CounterPrice:
+		return AutoResponse{Status: "countered", CounterPrice: *r.CounterPrice}, true
+	}
+
+	return AutoResponse{}, false
+}
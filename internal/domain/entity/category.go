@@ -0,0 +1,12 @@
+package entity
+
+// Category категория каталога. Дерево хранится вложенными множествами: потомки категории -
+// категории, у которых Lft лежит между Lft и Rgt родителя.
+type Category struct {
+	ID       uint
+	Name     string
+	ParentID *uint
+	Lft      int
+	Rgt      int
+	Children []Category
+}
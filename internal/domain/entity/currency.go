@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// BaseCurrency валюта, к которой хранятся курсы, как в таблицах ЕЦБ
+const BaseCurrency = "EUR"
+
+// CurrencyRate курс валюты: сколько единиц Currency дают за 1 EUR
+type CurrencyRate struct {
+	Currency  string
+	Rate      float64
+	UpdatedAt time.Time
+}
+
+// RateTable курсы валют к EUR по коду валюты в верхнем регистре
+type RateTable map[string]float64
+
+// Convert пересчитывает сумму из одной валюты в другую через EUR.
+// Одинаковые валюты пересчитываются без курса. false, если курса одной из валют нет.
+func (t RateTable) Convert(amount float64, from, to string) (float64, bool) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, true
+	}
+
+	fromRate, ok := t[from]
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := t[to]
+	if !ok {
+		return 0, false
+	}
+
+	return amount / fromRate * toRate, true
+}
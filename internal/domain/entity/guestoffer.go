@@ -2,6 +2,7 @@ package entity
 
 // GuestOfferData represents the data of a guest offer
 type GuestOfferData struct {
+	OfferID    uint
 	ProductID  uint
 	StoreID    uint
 	Price      float64
@@ -9,4 +10,15 @@ type GuestOfferData struct {
 	GuestName  string
 	GuestEmail string
 	GuestPhone string
+	// GuestIP is the address the offer was sent from, used to limit guest offers
+	GuestIP string
+}
+
+// GuestOffer is a stored guest offer together with the guest's contacts.
+// The guest reaches it by the magic link token sent by email.
+type GuestOffer struct {
+	OfferDetails
+	GuestName  string
+	GuestEmail string
+	GuestPhone string
 }
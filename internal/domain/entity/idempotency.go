@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord сохранённый ответ на запрос с заголовком Idempotency-Key.
+// StatusCode == 0, пока первый запрос ещё обрабатывается. LeaseToken выдаётся запросу,
+// занявшему ключ: только он может сохранить ответ или освободить ключ.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	LeaseToken  string
+	StatusCode  int
+	Body        []byte
+	ExpiresAt   time.Time
+}
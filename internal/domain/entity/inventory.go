@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+const (
+	InventoryAdded   = "added"
+	InventoryUpdated = "updated"
+	InventoryRemoved = "removed"
+)
+
+// InventoryItem позиция инвентаря: цена товара в магазине и его доступность для офферов
+type InventoryItem struct {
+	ShopID      uint
+	ProductID   uint
+	ProductName string
+	Price       float64
+	Currency    string
+	IsAvailable bool
+}
+
+// InventoryUpdate изменения позиции инвентаря, nil-поля не меняются
+type InventoryUpdate struct {
+	Price       *float64
+	Currency    *string
+	IsAvailable *bool
+}
+
+// InventoryChange запись истории позиции: состояние после изменения Change (added, updated, removed)
+type InventoryChange struct {
+	ID          uint
+	ShopID      uint
+	ProductID   uint
+	Change      string
+	Price       float64
+	Currency    string
+	IsAvailable bool
+	ChangedBy   *uint
+	CreatedAt   time.Time
+}
+
+// InventoryImportRow строка файла импорта инвентаря. Line - номер строки в файле,
+// Error - почему строку не удалось разобрать, такая строка не применяется.
+type InventoryImportRow struct {
+	Line  int
+	Item  InventoryItem
+	Error string
+}
+
+// InventoryImportError ошибка строки файла импорта
+type InventoryImportError struct {
+	Line  int
+	Error string
+}
+
+// InventoryImportReport итог импорта инвентаря. При DryRun изменения не сохраняются,
+// но счётчики те же, что были бы при настоящем импорте.
+type InventoryImportReport struct {
+	DryRun         bool
+	Rows           int
+	Created        int
+	Updated        int
+	Unchanged      int
+	DeclinedOffers int
+	Errors         []InventoryImportError
+}
@@ -13,4 +13,91 @@ type Offer struct {
 	ShopID    uint
 	UserID    uint
 	ProductID uint
+
+	// DisplayPrice цена в запрошенной валюте отображения DisplayCurrency.
+	// nil, если валюта не запрошена или курса для неё нет.
+	DisplayPrice    *float64
+	DisplayCurrency string
+}
+
+// OfferDetails оффер вместе с названиями товара и магазина для страницы оффера
+type OfferDetails struct {
+	Offer
+	ProductName string
+	ShopName    string
+}
+
+type OfferEvent struct {
+	ID         uint
+	OfferID    uint
+	EventType  string
+	FromStatus string
+	ToStatus   string
+	Price      float64
+	ActorID    uint
+	ActorRole  string
+	CreatedAt  time.Time
+}
+
+// BulkOfferResult результат массового ответа магазина по одному офферу.
+// Err == nil, если статус оффера изменён на Status.
+type BulkOfferResult struct {
+	OfferID uint
+	Status  string
+	Err     error
+}
+
+// ExpiredOffer оффер, просроченный фоновым воркером, и почта покупателя для уведомления
+type ExpiredOffer struct {
+	OfferID    uint
+	BuyerEmail string
+}
+
+// OfferQuotaUsage сколько офферов покупатель уже открыл, для проверки квот перед новым.
+// LastDeclinedAt - когда магазин в последний раз отклонил его оффер на тот же товар, nil - не отклонял.
+type OfferQuotaUsage struct {
+	Open           int
+	CreatedSince   int
+	LastDeclinedAt *time.Time
+}
+
+const (
+	OfferUpdateCreated       = "created"
+	OfferUpdateStatusChanged = "status_changed"
+	OfferUpdateExpired       = "expired"
+)
+
+// OfferUpdate событие жизненного цикла оффера для подписчиков в реальном времени.
+// Доставляется покупателю и владельцу магазина-получателя. EventID - ID события в журнале оффера,
+// Status и Price - состояние оффера сразу после события.
+type OfferUpdate struct {
+	Type        string
+	EventID     uint
+	OfferID     uint
+	Status      string
+	Price       float64
+	Currency    string
+	BuyerID     uint
+	ShopID      uint
+	ShopOwnerID uint
+	OccurredAt  time.Time
+}
+
+const (
+	OfferSortCreatedAt = "created_at"
+	OfferSortExpiresAt = "expires_at"
+	OfferSortPrice     = "price"
+)
+
+// ShopOfferFilter фильтры входящих офферов магазина. Нулевые значения не ограничивают выборку.
+type ShopOfferFilter struct {
+	Statuses  []string
+	ShopID    uint
+	ProductID uint
+	From      time.Time
+	To        time.Time
+	MinPrice  *float64
+	MaxPrice  *float64
+	SortBy    string
+	SortDesc  bool
 }
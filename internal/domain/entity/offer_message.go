@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// OfferMessage сообщение в переписке по офферу. SenderRole - buyer или shop,
+// ReadAt проставляется, когда сообщение открывает вторая сторона.
+type OfferMessage struct {
+	ID         uint
+	OfferID    uint
+	SenderID   uint
+	SenderRole string
+	Body       string
+	CreatedAt  time.Time
+	ReadAt     *time.Time
+}
+
+// OfferUnread число непрочитанных пользователем сообщений по офферу
+type OfferUnread struct {
+	OfferID uint
+	Unread  int
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Order заказ, созданный из принятого оффера. Цена и валюта фиксируются на момент принятия.
+type Order struct {
+	ID        uint
+	OfferID   uint
+	UserID    uint
+	ShopID    uint
+	ProductID uint
+	Price     float64
+	Currency  string
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
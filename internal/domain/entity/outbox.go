@@ -0,0 +1,81 @@
+package entity
+
+import "time"
+
+const (
+	// OutboxTopicOfferEvent событие из журнала оффера, AggregateID - ID оффера
+	OutboxTopicOfferEvent = "offer.event"
+	// OutboxTopicEmail письмо-уведомление по офферу, AggregateID - ID оффера
+	OutboxTopicEmail = "email"
+	// OutboxTopicGuestOffer новый оффер гостя, AggregateID - ID оффера
+	OutboxTopicGuestOffer = "guest_offer"
+)
+
+const (
+	OutboxPending   = "pending"
+	OutboxProcessed = "processed"
+	OutboxFailed    = "failed"
+)
+
+const (
+	EmailRegistered   = "registered"
+	EmailStatusUpdate = "status_update"
+	EmailOfferMessage = "offer_message"
+	// EmailGuestOfferReceived письмо владельцу магазина о новом оффере гостя
+	EmailGuestOfferReceived = "guest_offer_received"
+	// EmailGuestOfferSent письмо гостю со ссылкой для просмотра и отмены оффера
+	EmailGuestOfferSent = "guest_offer_sent"
+)
+
+// OutboxMessage сообщение, записанное вместе с бизнес-изменением и ожидающее доставки.
+// DoneSinks перечисляет получателей, которые уже обработали сообщение.
+type OutboxMessage struct {
+	ID          uint
+	Topic       string
+	AggregateID uint
+	DedupeKey   string
+	Payload     []byte
+	Attempts    int
+	DoneSinks   []string
+	CreatedAt   time.Time
+}
+
+// OfferEventPayload тело сообщения OutboxTopicOfferEvent, Type - один из OfferUpdate*.
+// EventID, Status и Price снимаются с события, чтобы запоздавшая доставка описывала
+// его, а не текущее состояние оффера.
+type OfferEventPayload struct {
+	Type    string  `json:"type"`
+	EventID uint    `json:"event_id"`
+	Status  string  `json:"status"`
+	Price   float64 `json:"price"`
+}
+
+// GuestOfferPayload тело сообщения OutboxTopicGuestOffer: оффер и контакты гостя на момент отправки
+type GuestOfferPayload struct {
+	ProductID  uint    `json:"product_id"`
+	ShopID     uint    `json:"shop_id"`
+	Price      float64 `json:"price"`
+	Currency   string  `json:"currency"`
+	GuestName  string  `json:"guest_name"`
+	GuestEmail string  `json:"guest_email"`
+	GuestPhone string  `json:"guest_phone"`
+}
+
+// EmailNotification тело сообщения OutboxTopicEmail. Kind определяет шаблон письма.
+// Guest и Link заполняются только в письмах по офферам гостей.
+type EmailNotification struct {
+	Kind   string             `json:"kind"`
+	To     string             `json:"to"`
+	Name   string             `json:"name,omitempty"`
+	Status string             `json:"status,omitempty"`
+	Guest  *GuestOfferPayload `json:"guest,omitempty"`
+	Link   string             `json:"link,omitempty"`
+}
+
+// OutboxResult итог обработки сообщения релеем. NextAttemptAt имеет смысл только для pending.
+type OutboxResult struct {
+	MessageID     uint
+	Status        string
+	Error         string
+	NextAttemptAt time.Time
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// PricingPolicy ценовая политика магазина для входящих офферов.
+// Оффер ниже MinPricePercent процентов от цены позиции инвентаря отклоняется при создании.
+type PricingPolicy struct {
+	ShopID          uint
+	MinPricePercent float64
+	UpdatedAt       time.Time
+}
+
+// PriceListing цена позиции инвентаря и минимум магазина в процентах.
+// MinPricePercent == nil, если магазин не задал свою политику.
+type PriceListing struct {
+	Price           float64
+	Currency        string
+	MinPricePercent *float64
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type Product struct {
 	ID            int                    `json:"id"`
 	Name          string                 `json:"name"`
@@ -7,9 +9,16 @@ type Product struct {
 	CategoryID    int                    `json:"category_id"`
 	MinimalPrice  int                    `json:"minimal_price"`
 	MaximalPrice  int                    `json:"maximal_price"`
+	Currency      string                 `json:"currency"`
 	AverageRating float64                `json:"average_rating"`
 	CountReviews  int                    `json:"count_reviews"`
 	Attributes    map[string]interface{} `json:"product_attributes"`
+	// ShopID магазин, который завёл товар и управляет им, nil для товаров из сидов
+	ShopID     *int       `json:"shop_id"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// Snippet фрагмент текста товара, где слова поискового запроса обёрнуты в <mark>.
+	// Остальной текст экранирован для HTML. Заполняется только при поиске.
+	Snippet *string `json:"snippet,omitempty"`
 }
 
 type NewProduct struct {
@@ -18,3 +27,12 @@ type NewProduct struct {
 	Description string `json:"description"`
 	CategoryID  int    `json:"category_id"`
 }
+
+// ProductUpdate изменения товара магазином: nil-поля не меняются,
+// Attributes, если заданы, заменяют атрибуты товара целиком
+type ProductUpdate struct {
+	Name        *string
+	Description *string
+	CategoryID  *int
+	Attributes  map[string]interface{}
+}
@@ -7,4 +7,5 @@ type User struct {
 	Email    string
 	Phone    string
 	IsStore  bool
+	IsAdmin  bool
 }
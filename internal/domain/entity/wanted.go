@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+const (
+	WantedOpen      = "open"
+	WantedAwarded   = "awarded"
+	WantedExpired   = "expired"
+	WantedCancelled = "cancelled"
+)
+
+const (
+	BidActive    = "active"
+	BidWon       = "won"
+	BidLost      = "lost"
+	BidWithdrawn = "withdrawn"
+)
+
+// WantedRequest запрос покупателя на покупку (обратный аукцион). Задан ровно один
+// из ProductID и CategoryID: по категории подходят товары из неё и её подкатегорий.
+// OfferID заполняется, когда покупатель выбирает победившую ставку.
+type WantedRequest struct {
+	ID          uint
+	UserID      uint
+	ProductID   *uint
+	CategoryID  *uint
+	TargetPrice float64
+	Currency    string
+	Status      string
+	Deadline    time.Time
+	OfferID     *uint
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Bids ставки по запросу: покупателю видны все, магазину - только своя
+	Bids []WantedBid
+}
+
+// WantedBid ставка магазина по запросу. Цена указана в валюте запроса.
+type WantedBid struct {
+	ID          uint
+	RequestID   uint
+	ShopID      uint
+	ShopName    string
+	ProductID   uint
+	ProductName string
+	Price       float64
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
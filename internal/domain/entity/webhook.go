@@ -0,0 +1,64 @@
+package entity
+
+import "time"
+
+const (
+	WebhookEventOfferCreated       = "offer.created"
+	WebhookEventOfferStatusChanged = "offer.status_changed"
+	WebhookEventGuestOfferReceived = "guest_offer.received"
+)
+
+// WebhookEvents события, на которые магазин может подписать вебхук
+var WebhookEvents = []string{
+	WebhookEventOfferCreated, WebhookEventOfferStatusChanged, WebhookEventGuestOfferReceived,
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook адрес магазина, на который отправляются события из Events
+type Webhook struct {
+	ID        uint
+	ShopID    uint
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// WebhookDelivery одна доставка события на вебхук. LastStatusCode и LastError
+// описывают последнюю попытку, DeliveredAt заполняется после успешной.
+type WebhookDelivery struct {
+	ID             uint
+	WebhookID      uint
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookAttempt доставка, взятая воркером в отправку, вместе с адресом и секретом вебхука
+type WebhookAttempt struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookAttemptResult итог попытки доставки для записи в журнал.
+// NextAttemptAt имеет смысл только для статуса pending.
+type WebhookAttemptResult struct {
+	DeliveryID    uint
+	Status        string
+	StatusCode    int
+	Error         string
+	NextAttemptAt time.Time
+}
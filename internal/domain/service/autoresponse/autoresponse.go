@@ -0,0 +1,62 @@
+package autoresponse
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=autoresponse_mock_test.go -package=autoresponse Repository
+
+type Repository interface {
+	SelectShopRules(ctx context.Context, shopID, userID uint) ([]entity.AutoResponseRule, error)
+	UpsertRule(ctx context.Context, rule entity.AutoResponseRule, userID uint) (entity.AutoResponseRule, error)
+	DeleteRule(ctx context.Context, shopID, ruleID, userID uint) error
+}
+
+// Service управляет правилами автоответа магазинов на офферы.
+// Сами правила применяются в offer.Service.CreateOffer.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) GetShopRules(ctx context.Context, shopID, userID uint) ([]entity.AutoResponseRule, error) {
+	return s.repo.SelectShopRules(ctx, shopID, userID)
+}
+
+// SaveRule проверяет согласованность порогов и сохраняет правило.
+// Должно выполняться decline_below <= counter_price <= accept_from для заданных значений.
+func (s *Service) SaveRule(
+	ctx context.Context,
+	rule entity.AutoResponseRule,
+	userID uint,
+) (entity.AutoResponseRule, error) {
+	if rule.AcceptFrom == nil && rule.DeclineBelow == nil && rule.CounterPrice == nil {
+		return entity.AutoResponseRule{}, apperror.New(apperror.BadRequest,
+			"at least one of accept_from, decline_below or counter_price must be set", nil)
+	}
+
+	if rule.DeclineBelow != nil && rule.AcceptFrom != nil && *rule.DeclineBelow > *rule.AcceptFrom {
+		return entity.AutoResponseRule{}, apperror.New(apperror.BadRequest,
+			"decline_below must not exceed accept_from", nil)
+	}
+	if rule.CounterPrice != nil && rule.AcceptFrom != nil && *rule.CounterPrice > *rule.AcceptFrom {
+		return entity.AutoResponseRule{}, apperror.New(apperror.BadRequest,
+			"counter_price must not exceed accept_from", nil)
+	}
+	if rule.CounterPrice != nil && rule.DeclineBelow != nil && *rule.CounterPrice < *rule.DeclineBelow {
+		return entity.AutoResponseRule{}, apperror.New(apperror.BadRequest,
+			"counter_price must not be below decline_below", nil)
+	}
+
+	return s.repo.UpsertRule(ctx, rule, userID)
+}
+
+func (s *Service) DeleteRule(ctx context.Context, shopID, ruleID, userID uint) error {
+	return s.repo.DeleteRule(ctx, shopID, ruleID, userID)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: autoresponse.go
+//
+// Generated by this command:
+//
+//	mockgen -source=autoresponse.go -destination=autoresponse_mock_test.go -package=autoresponse Repository
+//
+
+// Package autoresponse is a generated GoMock package.
+package autoresponse
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRule mocks base method.
+func (m *MockRepository) DeleteRule(ctx context.Context, shopID, ruleID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, shopID, ruleID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockRepositoryMockRecorder) DeleteRule(ctx, shopID, ruleID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockRepository)(nil).DeleteRule), ctx, shopID, ruleID, userID)
+}
+
+// SelectShopRules mocks base method.
+func (m *MockRepository) SelectShopRules(ctx context.Context, shopID, userID uint) ([]entity.AutoResponseRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopRules", ctx, shopID, userID)
+	ret0, _ := ret[0].([]entity.AutoResponseRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectShopRules indicates an expected call of SelectShopRules.
+func (mr *MockRepositoryMockRecorder) SelectShopRules(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopRules", reflect.TypeOf((*MockRepository)(nil).SelectShopRules), ctx, shopID, userID)
+}
+
+// UpsertRule mocks base method.
+func (m *MockRepository) UpsertRule(ctx context.Context, rule entity.AutoResponseRule, userID uint) (entity.AutoResponseRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRule", ctx, rule, userID)
+	ret0, _ := ret[0].(entity.AutoResponseRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertRule indicates an expected call of UpsertRule.
+func (mr *MockRepositoryMockRecorder) UpsertRule(ctx, rule, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRule", reflect.TypeOf((*MockRepository)(nil).UpsertRule), ctx, rule, userID)
+}
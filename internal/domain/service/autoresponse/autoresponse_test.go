@@ -0,0 +1,102 @@
+package autoresponse
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestAutoResponseServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auto-response Service Suite")
+}
+
+func price(v float64) *float64 {
+	return &v
+}
+
+var _ = Describe("AutoResponseService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		service = NewService(repo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("SaveRule", func() {
+		It("saves a consistent rule", func() {
+			rule := entity.AutoResponseRule{
+				ShopID:       1,
+				AcceptFrom:   price(100),
+				DeclineBelow: price(50),
+				CounterPrice: price(90),
+			}
+			repo.EXPECT().UpsertRule(ctx, rule, uint(7)).Return(rule, nil)
+
+			_, err := service.SaveRule(ctx, rule, 7)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("rejects inconsistent thresholds",
+			func(rule entity.AutoResponseRule) {
+				_, err := service.SaveRule(ctx, rule, 7)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("no thresholds", entity.AutoResponseRule{ShopID: 1}),
+			Entry("decline above accept", entity.AutoResponseRule{
+				ShopID: 1, AcceptFrom: price(100), DeclineBelow: price(120)}),
+			Entry("counter above accept", entity.AutoResponseRule{
+				ShopID: 1, AcceptFrom: price(100), CounterPrice: price(110)}),
+			Entry("counter below decline", entity.AutoResponseRule{
+				ShopID: 1, DeclineBelow: price(50), CounterPrice: price(40)}),
+		)
+	})
+})
+
+var _ = Describe("AutoResponseRule.Decide", func() {
+	rule := entity.AutoResponseRule{
+		AcceptFrom:   price(100),
+		DeclineBelow: price(50),
+		CounterPrice: price(90),
+	}
+
+	DescribeTable("picks the response for the offered price",
+		func(offered float64, status string, counter float64, decided bool) {
+			resp, ok := rule.Decide(offered)
+
+			Expect(ok).To(Equal(decided))
+			Expect(resp.Status).To(Equal(status))
+			Expect(resp.CounterPrice).To(Equal(counter))
+		},
+		Entry("accepts at the threshold", 100.0, "accepted", 0.0, true),
+		Entry("declines below the floor", 49.99, "declined", 0.0, true),
+		Entry("counters in between", 60.0, "countered", 90.0, true),
+		Entry("leaves offers above the counter price to the shop", 95.0, "", 0.0, false),
+	)
+
+	It("does nothing without thresholds", func() {
+		_, ok := entity.AutoResponseRule{}.Decide(10)
+
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,99 @@
+package category
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=category_mock_test.go -package=category Repository
+
+const maxNameLength = 255
+
+type Repository interface {
+	SelectTree(ctx context.Context) ([]entity.Category, error)
+	SelectSubtree(ctx context.Context, categoryID uint) ([]entity.Category, error)
+	SelectPath(ctx context.Context, categoryID uint) ([]entity.Category, error)
+	InsertCategory(ctx context.Context, name string, parentID *uint) (entity.Category, error)
+	MoveCategory(ctx context.Context, categoryID uint, parentID *uint) (entity.Category, error)
+	DeleteCategory(ctx context.Context, categoryID uint) error
+}
+
+// Service отдаёт дерево категорий каталога и меняет его. Новые и перенесённые категории
+// становятся последним потомком родителя или последним корнем.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// GetTree возвращает всё дерево категорий: корни с вложенными потомками
+func (s *Service) GetTree(ctx context.Context) ([]entity.Category, error) {
+	categories, err := s.repo.SelectTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(categories), nil
+}
+
+// GetSubtree возвращает категорию с вложенными потомками
+func (s *Service) GetSubtree(ctx context.Context, categoryID uint) (entity.Category, error) {
+	categories, err := s.repo.SelectSubtree(ctx, categoryID)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	return buildTree(categories)[0], nil
+}
+
+// GetPath возвращает цепочку категорий от корня до категории для хлебных крошек
+func (s *Service) GetPath(ctx context.Context, categoryID uint) ([]entity.Category, error) {
+	return s.repo.SelectPath(ctx, categoryID)
+}
+
+func (s *Service) CreateCategory(ctx context.Context, name string, parentID *uint) (entity.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return entity.Category{}, apperror.New(apperror.BadRequest, "name must be 1-255 characters long", nil)
+	}
+
+	return s.repo.InsertCategory(ctx, name, parentID)
+}
+
+func (s *Service) MoveCategory(ctx context.Context, categoryID uint, parentID *uint) (entity.Category, error) {
+	if parentID != nil && *parentID == categoryID {
+		return entity.Category{}, apperror.New(apperror.Conflict, "category cannot be moved into its own subtree", nil)
+	}
+
+	return s.repo.MoveCategory(ctx, categoryID, parentID)
+}
+
+func (s *Service) DeleteCategory(ctx context.Context, categoryID uint) error {
+	return s.repo.DeleteCategory(ctx, categoryID)
+}
+
+// buildTree собирает категории, упорядоченные по lft, в дерево: идущие следом категории
+// с lft меньше rgt текущей - её потомки
+func buildTree(categories []entity.Category) []entity.Category {
+	tree := make([]entity.Category, 0)
+	for i := 0; i < len(categories); {
+		node := categories[i]
+
+		end := i + 1
+		for end < len(categories) && categories[end].Lft < node.Rgt {
+			end++
+		}
+
+		node.Children = buildTree(categories[i+1 : end])
+		tree = append(tree, node)
+		i = end
+	}
+
+	return tree
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: category.go
+//
+// Generated by this command:
+//
+//	mockgen -source=category.go -destination=category_mock_test.go -package=category Repository
+//
+
+// Package category is a generated GoMock package.
+package category
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteCategory mocks base method.
+func (m *MockRepository) DeleteCategory(ctx context.Context, categoryID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, categoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockRepositoryMockRecorder) DeleteCategory(ctx, categoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockRepository)(nil).DeleteCategory), ctx, categoryID)
+}
+
+// InsertCategory mocks base method.
+func (m *MockRepository) InsertCategory(ctx context.Context, name string, parentID *uint) (entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCategory", ctx, name, parentID)
+	ret0, _ := ret[0].(entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCategory indicates an expected call of InsertCategory.
+func (mr *MockRepositoryMockRecorder) InsertCategory(ctx, name, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCategory", reflect.TypeOf((*MockRepository)(nil).InsertCategory), ctx, name, parentID)
+}
+
+// MoveCategory mocks base method.
+func (m *MockRepository) MoveCategory(ctx context.Context, categoryID uint, parentID *uint) (entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveCategory", ctx, categoryID, parentID)
+	ret0, _ := ret[0].(entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveCategory indicates an expected call of MoveCategory.
+func (mr *MockRepositoryMockRecorder) MoveCategory(ctx, categoryID, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveCategory", reflect.TypeOf((*MockRepository)(nil).MoveCategory), ctx, categoryID, parentID)
+}
+
+// SelectPath mocks base method.
+func (m *MockRepository) SelectPath(ctx context.Context, categoryID uint) ([]entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPath", ctx, categoryID)
+	ret0, _ := ret[0].([]entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectPath indicates an expected call of SelectPath.
+func (mr *MockRepositoryMockRecorder) SelectPath(ctx, categoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPath", reflect.TypeOf((*MockRepository)(nil).SelectPath), ctx, categoryID)
+}
+
+// SelectSubtree mocks base method.
+func (m *MockRepository) SelectSubtree(ctx context.Context, categoryID uint) ([]entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectSubtree", ctx, categoryID)
+	ret0, _ := ret[0].([]entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectSubtree indicates an expected call of SelectSubtree.
+func (mr *MockRepositoryMockRecorder) SelectSubtree(ctx, categoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSubtree", reflect.TypeOf((*MockRepository)(nil).SelectSubtree), ctx, categoryID)
+}
+
+// SelectTree mocks base method.
+func (m *MockRepository) SelectTree(ctx context.Context) ([]entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectTree", ctx)
+	ret0, _ := ret[0].([]entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectTree indicates an expected call of SelectTree.
+func (mr *MockRepositoryMockRecorder) SelectTree(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectTree", reflect.TypeOf((*MockRepository)(nil).SelectTree), ctx)
+}
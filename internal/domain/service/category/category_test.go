@@ -0,0 +1,145 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestCategoryServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Category Service Suite")
+}
+
+func ptr(id uint) *uint {
+	return &id
+}
+
+var _ = Describe("CategoryService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		service = NewService(repo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetTree", func() {
+		It("nests categories by their lft and rgt ranges", func() {
+			repo.EXPECT().SelectTree(ctx).Return([]entity.Category{
+				{ID: 1, Name: "Electronics", Lft: 1, Rgt: 8},
+				{ID: 2, Name: "Phones", ParentID: ptr(1), Lft: 2, Rgt: 5},
+				{ID: 3, Name: "Smartphones", ParentID: ptr(2), Lft: 3, Rgt: 4},
+				{ID: 4, Name: "Laptops", ParentID: ptr(1), Lft: 6, Rgt: 7},
+				{ID: 5, Name: "Books", Lft: 9, Rgt: 10},
+			}, nil)
+
+			tree, err := service.GetTree(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree).To(HaveLen(2))
+			Expect(tree[0].ID).To(Equal(uint(1)))
+			Expect(tree[0].Children).To(HaveLen(2))
+			Expect(tree[0].Children[0].ID).To(Equal(uint(2)))
+			Expect(tree[0].Children[0].Children).To(HaveLen(1))
+			Expect(tree[0].Children[0].Children[0].ID).To(Equal(uint(3)))
+			Expect(tree[0].Children[1].ID).To(Equal(uint(4)))
+			Expect(tree[1].ID).To(Equal(uint(5)))
+			Expect(tree[1].Children).To(BeEmpty())
+		})
+
+		It("returns an empty tree when there are no categories", func() {
+			repo.EXPECT().SelectTree(ctx).Return(nil, nil)
+
+			tree, err := service.GetTree(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree).NotTo(BeNil())
+			Expect(tree).To(BeEmpty())
+		})
+	})
+
+	Describe("GetSubtree", func() {
+		It("returns the requested category with its descendants", func() {
+			repo.EXPECT().SelectSubtree(ctx, uint(2)).Return([]entity.Category{
+				{ID: 2, Name: "Phones", ParentID: ptr(1), Lft: 2, Rgt: 5},
+				{ID: 3, Name: "Smartphones", ParentID: ptr(2), Lft: 3, Rgt: 4},
+			}, nil)
+
+			category, err := service.GetSubtree(ctx, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(category.ID).To(Equal(uint(2)))
+			Expect(category.Children).To(HaveLen(1))
+			Expect(category.Children[0].ID).To(Equal(uint(3)))
+		})
+
+		It("passes through a missing category", func() {
+			repo.EXPECT().SelectSubtree(ctx, uint(9)).Return(nil, apperror.ErrCategoryNotFound)
+
+			_, err := service.GetSubtree(ctx, 9)
+
+			Expect(errors.Is(err, apperror.ErrCategoryNotFound)).To(BeTrue())
+		})
+	})
+
+	Describe("CreateCategory", func() {
+		It("trims the name", func() {
+			repo.EXPECT().InsertCategory(ctx, "Phones", ptr(1)).
+				Return(entity.Category{ID: 2, Name: "Phones", ParentID: ptr(1), Lft: 2, Rgt: 3}, nil)
+
+			category, err := service.CreateCategory(ctx, "  Phones ", ptr(1))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(category.Name).To(Equal("Phones"))
+		})
+
+		It("rejects a blank or too long name without touching the repository", func() {
+			for _, name := range []string{"   ", strings.Repeat("a", maxNameLength+1)} {
+				_, err := service.CreateCategory(ctx, name, nil)
+
+				var appErr *apperror.Error
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+			}
+		})
+	})
+
+	Describe("MoveCategory", func() {
+		It("rejects moving a category under itself without touching the repository", func() {
+			_, err := service.MoveCategory(ctx, 2, ptr(2))
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Conflict))
+		})
+
+		It("moves a category to the root", func() {
+			repo.EXPECT().MoveCategory(ctx, uint(2), (*uint)(nil)).
+				Return(entity.Category{ID: 2, Name: "Phones", Lft: 9, Rgt: 12}, nil)
+
+			category, err := service.MoveCategory(ctx, 2, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(category.ParentID).To(BeNil())
+		})
+	})
+})
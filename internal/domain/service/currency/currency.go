@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	isocurrency "golang.org/x/text/currency"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=currency_mock_test.go -package=currency Repository
+
+type Repository interface {
+	SelectRates(ctx context.Context) ([]entity.CurrencyRate, error)
+	UpsertRates(ctx context.Context, rates []entity.CurrencyRate) error
+}
+
+// Service пересчитывает цены между валютами по локальной таблице курсов к EUR.
+// Курсы загружаются командой cmd/rates, сервис их только читает.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) GetRates(ctx context.Context) ([]entity.CurrencyRate, error) {
+	return s.repo.SelectRates(ctx)
+}
+
+// RateTable возвращает курсы для пересчёта множества сумм без повторных запросов
+func (s *Service) RateTable(ctx context.Context) (entity.RateTable, error) {
+	rates, err := s.repo.SelectRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make(entity.RateTable, len(rates))
+	for _, rate := range rates {
+		table[strings.ToUpper(rate.Currency)] = rate.Rate
+	}
+
+	return table, nil
+}
+
+// Convert пересчитывает сумму между валютами. Возвращает apperror.ErrCurrencyNotConvertible,
+// если курса одной из валют нет.
+func (s *Service) Convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+
+	table, err := s.RateTable(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	converted, ok := table.Convert(amount, from, to)
+	if !ok {
+		return 0, apperror.ErrCurrencyNotConvertible
+	}
+
+	return converted, nil
+}
+
+// LoadRates проверяет и сохраняет курсы. Курс EUR всегда равен 1 и из загрузки не берётся,
+// из повторов валюты остаётся последний. Возвращает число сохранённых курсов.
+func (s *Service) LoadRates(ctx context.Context, rates []entity.CurrencyRate) (int, error) {
+	valid := make([]entity.CurrencyRate, 0, len(rates))
+	seen := make(map[string]int, len(rates))
+	for _, rate := range rates {
+		code := strings.ToUpper(rate.Currency)
+		if _, err := isocurrency.ParseISO(code); err != nil {
+			return 0, apperror.New(apperror.BadRequest, fmt.Sprintf("unknown currency %q", rate.Currency), err)
+		}
+		if rate.Rate <= 0 {
+			return 0, apperror.New(apperror.BadRequest, fmt.Sprintf("rate for %s must be positive", code), nil)
+		}
+		if code == entity.BaseCurrency {
+			continue
+		}
+
+		if i, ok := seen[code]; ok {
+			valid[i].Rate = rate.Rate
+			continue
+		}
+		seen[code] = len(valid)
+		valid = append(valid, entity.CurrencyRate{Currency: code, Rate: rate.Rate})
+	}
+
+	if len(valid) == 0 {
+		return 0, apperror.New(apperror.BadRequest, "no currency rates to load", nil)
+	}
+
+	err := s.repo.UpsertRates(ctx, valid)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(valid), nil
+}
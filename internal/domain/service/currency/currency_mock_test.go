@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: currency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=currency.go -destination=currency_mock_test.go -package=currency Repository
+//
+
+// Package currency is a generated GoMock package.
+package currency
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SelectRates mocks base method.
+func (m *MockRepository) SelectRates(ctx context.Context) ([]entity.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectRates", ctx)
+	ret0, _ := ret[0].([]entity.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectRates indicates an expected call of SelectRates.
+func (mr *MockRepositoryMockRecorder) SelectRates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectRates", reflect.TypeOf((*MockRepository)(nil).SelectRates), ctx)
+}
+
+// UpsertRates mocks base method.
+func (m *MockRepository) UpsertRates(ctx context.Context, rates []entity.CurrencyRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRates", ctx, rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRates indicates an expected call of UpsertRates.
+func (mr *MockRepositoryMockRecorder) UpsertRates(ctx, rates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRates", reflect.TypeOf((*MockRepository)(nil).UpsertRates), ctx, rates)
+}
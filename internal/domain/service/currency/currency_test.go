@@ -0,0 +1,168 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestCurrencyServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Currency Service Suite")
+}
+
+var _ = Describe("CurrencyService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		service = NewService(repo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("Convert", func() {
+		It("returns the amount as is for the same currency without loading rates", func() {
+			converted, err := service.Convert(ctx, 12.5, "usd", "USD")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(converted).To(Equal(12.5))
+		})
+
+		It("converts through the base currency", func() {
+			repo.EXPECT().SelectRates(ctx).Return([]entity.CurrencyRate{
+				{Currency: "EUR", Rate: 1},
+				{Currency: "USD", Rate: 1.25},
+				{Currency: "GBP", Rate: 0.8},
+			}, nil)
+
+			converted, err := service.Convert(ctx, 50, "USD", "gbp")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(converted).To(BeNumerically("~", 32, 1e-9))
+		})
+
+		It("reports a currency without a rate as not convertible", func() {
+			repo.EXPECT().SelectRates(ctx).Return([]entity.CurrencyRate{{Currency: "EUR", Rate: 1}}, nil)
+
+			_, err := service.Convert(ctx, 50, "USD", "EUR")
+
+			Expect(err).To(MatchError(apperror.ErrCurrencyNotConvertible))
+		})
+
+		It("returns the repository error", func() {
+			dbErr := apperror.New(apperror.DatabaseError, "error selecting currency rates", errors.New("down"))
+			repo.EXPECT().SelectRates(ctx).Return(nil, dbErr)
+
+			_, err := service.Convert(ctx, 50, "USD", "EUR")
+
+			Expect(err).To(MatchError(dbErr))
+		})
+	})
+
+	Describe("LoadRates", func() {
+		It("saves normalized rates without the base currency, keeping the last duplicate", func() {
+			repo.EXPECT().UpsertRates(ctx, []entity.CurrencyRate{
+				{Currency: "USD", Rate: 1.2},
+				{Currency: "GBP", Rate: 0.85},
+			}).Return(nil)
+
+			loaded, err := service.LoadRates(ctx, []entity.CurrencyRate{
+				{Currency: "usd", Rate: 1.1},
+				{Currency: "EUR", Rate: 1},
+				{Currency: "GBP", Rate: 0.85},
+				{Currency: "USD", Rate: 1.2},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(2))
+		})
+
+		DescribeTable("rejects invalid rates without saving anything",
+			func(rates []entity.CurrencyRate) {
+				_, err := service.LoadRates(ctx, rates)
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("unknown currency code", []entity.CurrencyRate{{Currency: "XYZ1", Rate: 1}}),
+			Entry("zero rate", []entity.CurrencyRate{{Currency: "USD", Rate: 0}}),
+			Entry("negative rate", []entity.CurrencyRate{{Currency: "USD", Rate: -1.1}}),
+			Entry("only the base currency", []entity.CurrencyRate{{Currency: "EUR", Rate: 1}}),
+			Entry("no rates", []entity.CurrencyRate{}),
+		)
+	})
+
+	Describe("ParseCSV", func() {
+		It("reads currency and rate columns", func() {
+			rates, err := ParseCSV(strings.NewReader("currency,rate\nUSD, 1.0842\ngbp,0.8501\n"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rates).To(Equal([]entity.CurrencyRate{
+				{Currency: "USD", Rate: 1.0842},
+				{Currency: "gbp", Rate: 0.8501},
+			}))
+		})
+
+		It("rejects a file without the header", func() {
+			_, err := ParseCSV(strings.NewReader("USD,1.0842\n"))
+
+			Expect(err).To(MatchError(ContainSubstring("currency,rate")))
+		})
+
+		It("reports the line of an invalid rate", func() {
+			_, err := ParseCSV(strings.NewReader("currency,rate\nUSD,1.08\nGBP,abc\n"))
+
+			Expect(err).To(MatchError(ContainSubstring("line 3")))
+		})
+	})
+
+	Describe("ParseECB", func() {
+		It("reads the rates of the latest day", func() {
+			rates, err := ParseECB(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01"
+	xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2025-06-24">
+			<Cube currency="USD" rate="1.1604"/>
+			<Cube currency="JPY" rate="168.84"/>
+		</Cube>
+		<Cube time="2025-06-23">
+			<Cube currency="USD" rate="1.1520"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rates).To(Equal([]entity.CurrencyRate{
+				{Currency: "USD", Rate: 1.1604},
+				{Currency: "JPY", Rate: 168.84},
+			}))
+		})
+
+		It("fails on a document without rates", func() {
+			_, err := ParseECB(strings.NewReader(`<Envelope><Cube></Cube></Envelope>`))
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
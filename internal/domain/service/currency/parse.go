@@ -0,0 +1,80 @@
+package currency
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// ParseCSV читает курсы из CSV с заголовком currency,rate.
+// rate - сколько единиц валюты дают за 1 EUR.
+func ParseCSV(r io.Reader) ([]entity.CurrencyRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	if !strings.EqualFold(header[0], "currency") || !strings.EqualFold(header[1], "rate") {
+		return nil, fmt.Errorf("csv header must be currency,rate, got %s", strings.Join(header, ","))
+	}
+
+	var rates []entity.CurrencyRate
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+
+		rate, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			line, _ := reader.FieldPos(1)
+			return nil, fmt.Errorf("line %d: invalid rate %q: %w", line, record[1], err)
+		}
+		rates = append(rates, entity.CurrencyRate{Currency: record[0], Rate: rate})
+	}
+
+	return rates, nil
+}
+
+// ecbEnvelope формат eurofxref-daily.xml и eurofxref-hist.xml ЕЦБ: курсы по дням, последний день первый
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECB читает курсы последнего дня из XML ЕЦБ
+func ParseECB(r io.Reader) ([]entity.CurrencyRate, error) {
+	var envelope ecbEnvelope
+	err := xml.NewDecoder(r).Decode(&envelope)
+	if err != nil {
+		return nil, fmt.Errorf("decode ecb xml: %w", err)
+	}
+	if len(envelope.Days) == 0 {
+		return nil, errors.New("ecb xml has no rates")
+	}
+
+	latest := envelope.Days[0]
+	rates := make([]entity.CurrencyRate, len(latest.Rates))
+	for i, rate := range latest.Rates {
+		rates[i] = entity.CurrencyRate{Currency: rate.Currency, Rate: rate.Rate}
+	}
+
+	return rates, nil
+}
@@ -2,47 +2,111 @@ package guestoffer
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"golang.org/x/text/currency"
+
 	guestofferrepo "github.com/EM-Stawberry/Stawberry/internal/repository/guestoffer"
 	"go.uber.org/zap"
 )
 
-// NotificationSender interface for sending guest offer notifications
-type NotificationSender interface {
-	SendGuestOfferNotification(email string, subject string, body string)
+// Relay delivers the outbox messages written together with the offer: offer events,
+// webhooks and the emails to the shop owner and the guest
+type Relay interface {
+	Wake()
+}
+
+// Repository stores guest offers. The guest is identified by the SHA-256 hash
+// of the magic link token, only the guest's email in the outbox carries the token itself.
+// checkLimits, if set, gets the number of the guest's offers since limitsSince by email
+// and by IP inside the insert transaction and may reject the offer.
+type Repository interface {
+	InsertGuestOffer(
+		ctx context.Context,
+		offer entity.Offer,
+		guest entity.GuestOfferData,
+		tokenHash string,
+		limitsSince time.Time,
+		checkLimits func(byEmail, byIP int) error,
+		outbox []entity.OutboxMessage,
+	) (uint, error)
+	GetGuestOfferByToken(ctx context.Context, tokenHash string) (entity.GuestOffer, error)
+	IsProductAvailable(ctx context.Context, shopID, productID uint) (bool, error)
+	CancelGuestOffer(ctx context.Context, tokenHash string) (entity.Offer, error)
+}
+
+// PricingPolicy checks the offer price against the shop's pricing policy like for registered buyers
+type PricingPolicy interface {
+	Check(ctx context.Context, offer entity.Offer) error
 }
 
 // Service describes the interface for the guest offer service
 type Service interface {
 	ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error
+	GetGuestOffer(ctx context.Context, token string) (entity.GuestOffer, error)
+	CancelGuestOffer(ctx context.Context, token string) (entity.Offer, error)
 }
 
+const (
+	// length of the magic link token in bytes, twice as long in hex
+	tokenBytes = 32
+
+	statusPending = "pending"
+	offerLifetime = 7 * 24 * time.Hour
+)
+
 // GuestOfferService implements the Service interface
 type GuestOfferService struct {
-	storeInfoGetter    guestofferrepo.StoreInfoGetter
-	notificationSender NotificationSender
-	log                *zap.Logger
+	storeInfoGetter guestofferrepo.StoreInfoGetter
+	repo            Repository
+	pricing         PricingPolicy
+	relay           Relay
+	cfg             *config.GuestOfferConfig
+	log             *zap.Logger
 }
 
 // NewService creates a new instance of GuestOfferService and returns the Service interface
 func NewService(
 	storeInfoGetter guestofferrepo.StoreInfoGetter,
-	notificationSender NotificationSender,
+	repo Repository,
+	pricing PricingPolicy,
+	relay Relay,
+	cfg *config.GuestOfferConfig,
 	log *zap.Logger,
 ) Service {
 	return &GuestOfferService{
-		storeInfoGetter:    storeInfoGetter,
-		notificationSender: notificationSender,
-		log:                log,
+		storeInfoGetter: storeInfoGetter,
+		repo:            repo,
+		pricing:         pricing,
+		relay:           relay,
+		cfg:             cfg,
+		log:             log,
 	}
 }
 
-// ProcessGuestOffer handles the guest offer business logic
+// ProcessGuestOffer stores the guest offer, notifies the shop owner and emails the guest
+// a magic link to track or cancel the offer. The shop answers it like any other offer.
+// Both emails are written to the outbox with the offer, so a failed send is retried
+// and the guest never loses the link. The offer is validated like a registered one
+// and guests are limited by email and IP. The limits are checked in the insert transaction,
+// so concurrent requests from one guest can't exceed them.
 func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error {
+	if offerData.Price <= 0 {
+		return apperror.New(apperror.BadRequest, "offer price must be positive", nil)
+	}
+	if _, err := currency.ParseISO(offerData.Currency); err != nil {
+		return apperror.New(apperror.BadRequest, "currency must be an ISO 4217 code", err)
+	}
+
 	shopOwnerEmail, err := s.storeInfoGetter.GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID)
 	if err != nil {
 		s.log.Error("Failed to get shop owner email", zap.Error(err), zap.Uint("store_id", offerData.StoreID))
@@ -55,25 +119,125 @@ func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData ent
 		return fmt.Errorf("failed to get store owner email from repository: %w", err)
 	}
 
-	emailSubject := "New guest offer received"
-	emailBody := fmt.Sprintf(
-		"A new guest offer has been received:\n\n"+
-			"Product ID: %d\n"+
-			"Store ID: %d\n"+
-			"Proposed Price: %.2f %s\n"+
-			"Guest Name: %s\n"+
-			"Guest Email: %s\n"+
-			"Guest Phone: %s",
-		offerData.ProductID,
-		offerData.StoreID,
-		offerData.Price,
-		offerData.Currency,
-		offerData.GuestName,
-		offerData.GuestEmail,
-		offerData.GuestPhone,
-	)
-
-	s.notificationSender.SendGuestOfferNotification(shopOwnerEmail, emailSubject, emailBody)
+	available, err := s.repo.IsProductAvailable(ctx, offerData.StoreID, offerData.ProductID)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return apperror.New(apperror.Conflict, "product is not available in this store", nil)
+	}
+
+	err = s.pricing.Check(ctx, entity.Offer{
+		Price:     offerData.Price,
+		Currency:  offerData.Currency,
+		ShopID:    offerData.StoreID,
+		ProductID: offerData.ProductID,
+	})
+	if err != nil {
+		return err
+	}
+
+	tokenRaw := make([]byte, tokenBytes)
+	if _, err = rand.Read(tokenRaw); err != nil {
+		return apperror.New(apperror.InternalError, "failed to generate guest offer token", err)
+	}
+	token := hex.EncodeToString(tokenRaw)
+
+	outbox, err := guestOfferEmails(offerData, shopOwnerEmail, fmt.Sprintf("%s/%s", s.cfg.LinkURL, token))
+	if err != nil {
+		return err
+	}
+
+	var checkLimits func(byEmail, byIP int) error
+	if s.cfg.MaxPerEmail > 0 || s.cfg.MaxPerIP > 0 {
+		checkLimits = func(byEmail, byIP int) error {
+			return s.checkLimits(offerData, byEmail, byIP)
+		}
+	}
+
+	now := time.Now()
+	offerData.OfferID, err = s.repo.InsertGuestOffer(ctx, entity.Offer{
+		Price:     offerData.Price,
+		Currency:  offerData.Currency,
+		Status:    statusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(offerLifetime),
+		ShopID:    offerData.StoreID,
+		ProductID: offerData.ProductID,
+	}, offerData, hashToken(token), now.Add(-s.cfg.LimitWindow), checkLimits, outbox)
+	if err != nil {
+		// an exceeded limit is already logged by checkLimits
+		var appErr apperror.AppError
+		if !errors.As(err, &appErr) || appErr.Code() != apperror.TooManyRequests {
+			s.log.Error("Failed to store guest offer", zap.Error(err), zap.Uint("store_id", offerData.StoreID))
+		}
+		return err
+	}
+	s.relay.Wake()
+
+	return nil
+}
+
+// guestOfferEmails builds the outbox emails about a new guest offer: the details for the shop
+// owner and the magic link for the guest. The repository sets their AggregateID to the offer ID.
+func guestOfferEmails(
+	offerData entity.GuestOfferData,
+	shopOwnerEmail, link string,
+) ([]entity.OutboxMessage, error) {
+	guest := &entity.GuestOfferPayload{
+		ProductID:  offerData.ProductID,
+		ShopID:     offerData.StoreID,
+		Price:      offerData.Price,
+		Currency:   offerData.Currency,
+		GuestName:  offerData.GuestName,
+		GuestEmail: offerData.GuestEmail,
+		GuestPhone: offerData.GuestPhone,
+	}
+	notifications := []entity.EmailNotification{
+		{Kind: entity.EmailGuestOfferReceived, To: shopOwnerEmail, Guest: guest},
+		{Kind: entity.EmailGuestOfferSent, To: offerData.GuestEmail, Guest: guest, Link: link},
+	}
+
+	outbox := make([]entity.OutboxMessage, len(notifications))
+	for i, n := range notifications {
+		payload, err := json.Marshal(n)
+		if err != nil {
+			return nil, apperror.New(apperror.InternalError, "failed to encode guest offer email", err)
+		}
+		outbox[i] = entity.OutboxMessage{Topic: entity.OutboxTopicEmail, Payload: payload}
+	}
+
+	return outbox, nil
+}
+
+// checkLimits rejects the offer when the guest's email or IP has already sent
+// the maximum number of offers within the limit window. A zero maximum disables the limit.
+func (s *GuestOfferService) checkLimits(offerData entity.GuestOfferData, byEmail, byIP int) error {
+	if s.cfg.MaxPerEmail > 0 && byEmail >= s.cfg.MaxPerEmail {
+		s.log.Warn("Guest offer limit reached for email", zap.String("email", offerData.GuestEmail))
+		return apperror.New(apperror.TooManyRequests, "too many offers from this email, try again later", nil)
+	}
+	if s.cfg.MaxPerIP > 0 && byIP >= s.cfg.MaxPerIP {
+		s.log.Warn("Guest offer limit reached for IP", zap.String("ip", offerData.GuestIP))
+		return apperror.New(apperror.TooManyRequests, "too many offers from this address, try again later", nil)
+	}
 
 	return nil
 }
+
+// GetGuestOffer returns the guest offer the magic link token belongs to
+func (s *GuestOfferService) GetGuestOffer(ctx context.Context, token string) (entity.GuestOffer, error) {
+	return s.repo.GetGuestOfferByToken(ctx, hashToken(token))
+}
+
+// CancelGuestOffer cancels the guest offer the magic link token belongs to
+// while the shop has not answered it yet
+func (s *GuestOfferService) CancelGuestOffer(ctx context.Context, token string) (entity.Offer, error) {
+	return s.repo.CancelGuestOffer(ctx, hashToken(token))
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -2,9 +2,14 @@ package guestoffer_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"strings"
+	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	guestofferservice "github.com/EM-Stawberry/Stawberry/internal/domain/service/guestoffer"
@@ -18,22 +23,32 @@ import (
 
 var _ = Describe("GuestOfferService", func() {
 	var (
-		ctrl                   *gomock.Controller
-		mockStoreInfoGetter    *repomocks.MockStoreInfoGetter
-		mockNotificationSender *guestofferservice.MockNotificationSender
-		service                guestofferservice.Service
-		ctx                    context.Context
-		log                    *zap.Logger
-		offerData              entity.GuestOfferData
+		ctrl                *gomock.Controller
+		mockStoreInfoGetter *repomocks.MockStoreInfoGetter
+		mockRepository      *guestofferservice.MockRepository
+		mockRelay           *guestofferservice.MockRelay
+		mockPricingPolicy   *guestofferservice.MockPricingPolicy
+		service             guestofferservice.Service
+		ctx                 context.Context
+		log                 *zap.Logger
+		offerData           entity.GuestOfferData
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockStoreInfoGetter = repomocks.NewMockStoreInfoGetter(ctrl)
-		mockNotificationSender = guestofferservice.NewMockNotificationSender(ctrl)
+		mockRepository = guestofferservice.NewMockRepository(ctrl)
+		mockRelay = guestofferservice.NewMockRelay(ctrl)
+		mockPricingPolicy = guestofferservice.NewMockPricingPolicy(ctrl)
 		log = zaptest.NewLogger(GinkgoT())
 
-		service = guestofferservice.NewService(mockStoreInfoGetter, mockNotificationSender, log)
+		service = guestofferservice.NewService(mockStoreInfoGetter, mockRepository, mockPricingPolicy,
+			mockRelay, &config.GuestOfferConfig{
+				LinkURL:     "https://stawberry.example.com/guest/offers",
+				LimitWindow: time.Hour,
+				MaxPerEmail: 5,
+				MaxPerIP:    20,
+			}, log)
 		ctx = context.Background()
 
 		offerData = entity.GuestOfferData{
@@ -44,16 +59,31 @@ var _ = Describe("GuestOfferService", func() {
 			GuestName:  "John Doe",
 			GuestEmail: "john.doe@example.com",
 			GuestPhone: "123-456-7890",
+			GuestIP:    "203.0.113.7",
 		}
 	})
 
+	expectAllowed := func() {
+		mockRepository.EXPECT().
+			IsProductAvailable(ctx, offerData.StoreID, offerData.ProductID).
+			Return(true, nil)
+		mockPricingPolicy.EXPECT().
+			Check(ctx, entity.Offer{
+				Price:     offerData.Price,
+				Currency:  offerData.Currency,
+				ShopID:    offerData.StoreID,
+				ProductID: offerData.ProductID,
+			}).
+			Return(nil)
+	}
+
 	AfterEach(func() {
 		ctrl.Finish()
 	})
 
 	Describe("ProcessGuestOffer", func() {
 		Context("when getting store owner email is successful", func() {
-			It("should send a notification and return nil", func() {
+			It("should store the offer together with the emails to the owner and the guest", func() {
 				expectedEmail := "owner@example.com"
 
 				mockStoreInfoGetter.EXPECT().
@@ -61,31 +91,170 @@ var _ = Describe("GuestOfferService", func() {
 					Return(expectedEmail, nil).
 					Times(1)
 
-				expectedSubject := "New guest offer received"
-				expectedBody := fmt.Sprintf(
-					"A new guest offer has been received:\n\n"+
-						"Product ID: %d\n"+
-						"Store ID: %d\n"+
-						"Proposed Price: %.2f %s\n"+
-						"Guest Name: %s\n"+
-						"Guest Email: %s\n"+
-						"Guest Phone: %s",
-					offerData.ProductID,
-					offerData.StoreID,
-					offerData.Price,
-					offerData.Currency,
-					offerData.GuestName,
-					offerData.GuestEmail,
-					offerData.GuestPhone,
+				expectAllowed()
+				var (
+					tokenHash string
+					emails    []entity.EmailNotification
 				)
-
-				mockNotificationSender.EXPECT().
-					SendGuestOfferNotification(expectedEmail, expectedSubject, expectedBody).
-					Times(1)
+				mockRepository.EXPECT().
+					InsertGuestOffer(ctx, gomock.Any(), offerData, gomock.Any(), gomock.Any(), gomock.Any(),
+						gomock.Any()).
+					DoAndReturn(func(_ context.Context, offer entity.Offer, _ entity.GuestOfferData, hash string,
+						_ time.Time, checkLimits func(int, int) error, outbox []entity.OutboxMessage) (uint, error) {
+						Expect(checkLimits(4, 19)).To(Succeed())
+						Expect(offer.Status).To(Equal("pending"))
+						Expect(offer.ShopID).To(Equal(offerData.StoreID))
+						Expect(offer.ProductID).To(Equal(offerData.ProductID))
+						Expect(offer.UserID).To(BeZero())
+						Expect(offer.ExpiresAt).To(BeTemporally("~", time.Now().Add(7*24*time.Hour), time.Minute))
+						tokenHash = hash
+						for _, m := range outbox {
+							Expect(m.Topic).To(Equal(entity.OutboxTopicEmail))
+							var n entity.EmailNotification
+							Expect(json.Unmarshal(m.Payload, &n)).To(Succeed())
+							emails = append(emails, n)
+						}
+						return 42, nil
+					})
+				mockRelay.EXPECT().Wake()
 
 				err := service.ProcessGuestOffer(ctx, offerData)
 
 				Expect(err).NotTo(HaveOccurred())
+				guest := &entity.GuestOfferPayload{
+					ProductID:  offerData.ProductID,
+					ShopID:     offerData.StoreID,
+					Price:      offerData.Price,
+					Currency:   offerData.Currency,
+					GuestName:  offerData.GuestName,
+					GuestEmail: offerData.GuestEmail,
+					GuestPhone: offerData.GuestPhone,
+				}
+				Expect(emails).To(HaveLen(2))
+				Expect(emails[0]).To(Equal(entity.EmailNotification{
+					Kind: entity.EmailGuestOfferReceived, To: expectedEmail, Guest: guest,
+				}))
+				Expect(emails[1].Kind).To(Equal(entity.EmailGuestOfferSent))
+				Expect(emails[1].To).To(Equal(offerData.GuestEmail))
+				Expect(emails[1].Guest).To(Equal(guest))
+				Expect(emails[1].Link).To(HavePrefix("https://stawberry.example.com/guest/offers/"))
+
+				// токен есть только в письме гостю, в базе - его хеш
+				link := emails[1].Link
+				token := link[strings.LastIndex(link, "/")+1:]
+				Expect(token).To(HaveLen(64))
+				hash := sha256.Sum256([]byte(token))
+				Expect(tokenHash).To(Equal(hex.EncodeToString(hash[:])))
+			})
+		})
+
+		Context("when storing the offer fails", func() {
+			It("should return the error without waking the relay", func() {
+				mockStoreInfoGetter.EXPECT().
+					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
+					Return("owner@example.com", nil)
+				expectAllowed()
+				mockRepository.EXPECT().
+					InsertGuestOffer(ctx, gomock.Any(), offerData, gomock.Any(), gomock.Any(), gomock.Any(),
+						gomock.Any()).
+					Return(uint(0), apperror.ErrProductNotFound)
+
+				err := service.ProcessGuestOffer(ctx, offerData)
+
+				Expect(err).To(MatchError(apperror.ErrProductNotFound))
+			})
+		})
+
+		DescribeTable("when the offer data is invalid",
+			func(change func(*entity.GuestOfferData)) {
+				change(&offerData)
+
+				err := service.ProcessGuestOffer(ctx, offerData)
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("zero price", func(o *entity.GuestOfferData) { o.Price = 0 }),
+			Entry("unknown currency", func(o *entity.GuestOfferData) { o.Currency = "XYZ" }),
+			Entry("not a currency code", func(o *entity.GuestOfferData) { o.Currency = "dollars" }),
+		)
+
+		DescribeTable("when the guest has sent too many offers",
+			func(byEmail, byIP int) {
+				mockStoreInfoGetter.EXPECT().
+					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
+					Return("owner@example.com", nil)
+				expectAllowed()
+				mockRepository.EXPECT().
+					InsertGuestOffer(ctx, gomock.Any(), offerData, gomock.Any(), gomock.Any(), gomock.Any(),
+						gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ entity.Offer, _ entity.GuestOfferData, _ string,
+						since time.Time, checkLimits func(int, int) error, _ []entity.OutboxMessage) (uint, error) {
+						Expect(since).To(BeTemporally("~", time.Now().Add(-time.Hour), time.Second))
+						return 0, checkLimits(byEmail, byIP)
+					})
+
+				err := service.ProcessGuestOffer(ctx, offerData)
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.TooManyRequests))
+			},
+			Entry("from the email", 5, 0),
+			Entry("from the IP", 0, 20),
+		)
+
+		Context("when the guest limits are disabled", func() {
+			It("should not count the guest's offers", func() {
+				service = guestofferservice.NewService(mockStoreInfoGetter, mockRepository, mockPricingPolicy,
+					mockRelay, &config.GuestOfferConfig{LinkURL: "https://stawberry.example.com/guest/offers"}, log)
+				mockStoreInfoGetter.EXPECT().
+					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
+					Return("owner@example.com", nil)
+				expectAllowed()
+				mockRepository.EXPECT().
+					InsertGuestOffer(ctx, gomock.Any(), offerData, gomock.Any(), gomock.Any(), gomock.Nil(),
+						gomock.Any()).
+					Return(uint(42), nil)
+				mockRelay.EXPECT().Wake()
+
+				Expect(service.ProcessGuestOffer(ctx, offerData)).To(Succeed())
+			})
+		})
+
+		Context("when the product is not available in the store", func() {
+			It("should return a conflict", func() {
+				mockStoreInfoGetter.EXPECT().
+					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
+					Return("owner@example.com", nil)
+				mockRepository.EXPECT().
+					IsProductAvailable(ctx, offerData.StoreID, offerData.ProductID).
+					Return(false, nil)
+
+				err := service.ProcessGuestOffer(ctx, offerData)
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.Conflict))
+			})
+		})
+
+		Context("when the offer violates the shop pricing policy", func() {
+			It("should return the validation error without storing the offer", func() {
+				violation := apperror.NewValidationError("offer violates the shop pricing policy",
+					apperror.Violation{Field: "price", Rule: "min_price_percent"})
+				mockStoreInfoGetter.EXPECT().
+					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
+					Return("owner@example.com", nil)
+				mockRepository.EXPECT().
+					IsProductAvailable(ctx, offerData.StoreID, offerData.ProductID).
+					Return(true, nil)
+				mockPricingPolicy.EXPECT().Check(ctx, gomock.Any()).Return(violation)
+
+				err := service.ProcessGuestOffer(ctx, offerData)
+
+				Expect(err).To(MatchError(violation))
 			})
 		})
 
@@ -97,8 +266,6 @@ var _ = Describe("GuestOfferService", func() {
 					Return("", repoError).
 					Times(1)
 
-				mockNotificationSender.EXPECT().SendGuestOfferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-
 				err := service.ProcessGuestOffer(ctx, offerData)
 
 				Expect(err).To(HaveOccurred())
@@ -117,8 +284,6 @@ var _ = Describe("GuestOfferService", func() {
 					Return("", repoError).
 					Times(1)
 
-				mockNotificationSender.EXPECT().SendGuestOfferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-
 				err := service.ProcessGuestOffer(ctx, offerData)
 
 				Expect(err).To(HaveOccurred())
@@ -126,4 +291,31 @@ var _ = Describe("GuestOfferService", func() {
 			})
 		})
 	})
+
+	Describe("GetGuestOffer", func() {
+		It("should look the offer up by the token hash", func() {
+			hash := sha256.Sum256([]byte("token"))
+			mockRepository.EXPECT().
+				GetGuestOfferByToken(ctx, hex.EncodeToString(hash[:])).
+				Return(entity.GuestOffer{GuestName: "John Doe"}, nil)
+
+			offer, err := service.GetGuestOffer(ctx, "token")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(offer.GuestName).To(Equal("John Doe"))
+		})
+	})
+
+	Describe("CancelGuestOffer", func() {
+		It("should cancel the offer by the token hash", func() {
+			hash := sha256.Sum256([]byte("token"))
+			mockRepository.EXPECT().
+				CancelGuestOffer(ctx, hex.EncodeToString(hash[:])).
+				Return(entity.Offer{}, apperror.ErrOfferNotFound)
+
+			_, err := service.CancelGuestOffer(ctx, "token")
+
+			Expect(err).To(MatchError(apperror.ErrOfferNotFound))
+		})
+	})
 })
@@ -12,45 +12,168 @@ package guestoffer
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockNotificationSender is a mock of NotificationSender interface.
-type MockNotificationSender struct {
+// MockRelay is a mock of Relay interface.
+type MockRelay struct {
 	ctrl     *gomock.Controller
-	recorder *MockNotificationSenderMockRecorder
+	recorder *MockRelayMockRecorder
 	isgomock struct{}
 }
 
-// MockNotificationSenderMockRecorder is the mock recorder for MockNotificationSender.
-type MockNotificationSenderMockRecorder struct {
-	mock *MockNotificationSender
+// MockRelayMockRecorder is the mock recorder for MockRelay.
+type MockRelayMockRecorder struct {
+	mock *MockRelay
 }
 
-// NewMockNotificationSender creates a new mock instance.
-func NewMockNotificationSender(ctrl *gomock.Controller) *MockNotificationSender {
-	mock := &MockNotificationSender{ctrl: ctrl}
-	mock.recorder = &MockNotificationSenderMockRecorder{mock}
+// NewMockRelay creates a new mock instance.
+func NewMockRelay(ctrl *gomock.Controller) *MockRelay {
+	mock := &MockRelay{ctrl: ctrl}
+	mock.recorder = &MockRelayMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockNotificationSender) EXPECT() *MockNotificationSenderMockRecorder {
+func (m *MockRelay) EXPECT() *MockRelayMockRecorder {
 	return m.recorder
 }
 
-// SendGuestOfferNotification mocks base method.
-func (m *MockNotificationSender) SendGuestOfferNotification(email, subject, body string) {
+// Wake mocks base method.
+func (m *MockRelay) Wake() {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SendGuestOfferNotification", email, subject, body)
+	m.ctrl.Call(m, "Wake")
 }
 
-// SendGuestOfferNotification indicates an expected call of SendGuestOfferNotification.
-func (mr *MockNotificationSenderMockRecorder) SendGuestOfferNotification(email, subject, body any) *gomock.Call {
+// Wake indicates an expected call of Wake.
+func (mr *MockRelayMockRecorder) Wake() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendGuestOfferNotification", reflect.TypeOf((*MockNotificationSender)(nil).SendGuestOfferNotification), email, subject, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wake", reflect.TypeOf((*MockRelay)(nil).Wake))
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelGuestOffer mocks base method.
+func (m *MockRepository) CancelGuestOffer(ctx context.Context, tokenHash string) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelGuestOffer", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelGuestOffer indicates an expected call of CancelGuestOffer.
+func (mr *MockRepositoryMockRecorder) CancelGuestOffer(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelGuestOffer", reflect.TypeOf((*MockRepository)(nil).CancelGuestOffer), ctx, tokenHash)
+}
+
+// GetGuestOfferByToken mocks base method.
+func (m *MockRepository) GetGuestOfferByToken(ctx context.Context, tokenHash string) (entity.GuestOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestOfferByToken", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.GuestOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestOfferByToken indicates an expected call of GetGuestOfferByToken.
+func (mr *MockRepositoryMockRecorder) GetGuestOfferByToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestOfferByToken", reflect.TypeOf((*MockRepository)(nil).GetGuestOfferByToken), ctx, tokenHash)
+}
+
+// InsertGuestOffer mocks base method.
+func (m *MockRepository) InsertGuestOffer(ctx context.Context, offer entity.Offer, guest entity.GuestOfferData, tokenHash string, limitsSince time.Time, checkLimits func(int, int) error, outbox []entity.OutboxMessage) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertGuestOffer", ctx, offer, guest, tokenHash, limitsSince, checkLimits, outbox)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertGuestOffer indicates an expected call of InsertGuestOffer.
+func (mr *MockRepositoryMockRecorder) InsertGuestOffer(ctx, offer, guest, tokenHash, limitsSince, checkLimits, outbox any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGuestOffer", reflect.TypeOf((*MockRepository)(nil).InsertGuestOffer), ctx, offer, guest, tokenHash, limitsSince, checkLimits, outbox)
+}
+
+// IsProductAvailable mocks base method.
+func (m *MockRepository) IsProductAvailable(ctx context.Context, shopID, productID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsProductAvailable", ctx, shopID, productID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsProductAvailable indicates an expected call of IsProductAvailable.
+func (mr *MockRepositoryMockRecorder) IsProductAvailable(ctx, shopID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProductAvailable", reflect.TypeOf((*MockRepository)(nil).IsProductAvailable), ctx, shopID, productID)
+}
+
+// MockPricingPolicy is a mock of PricingPolicy interface.
+type MockPricingPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingPolicyMockRecorder
+	isgomock struct{}
+}
+
+// MockPricingPolicyMockRecorder is the mock recorder for MockPricingPolicy.
+type MockPricingPolicyMockRecorder struct {
+	mock *MockPricingPolicy
+}
+
+// NewMockPricingPolicy creates a new mock instance.
+func NewMockPricingPolicy(ctrl *gomock.Controller) *MockPricingPolicy {
+	mock := &MockPricingPolicy{ctrl: ctrl}
+	mock.recorder = &MockPricingPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricingPolicy) EXPECT() *MockPricingPolicyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockPricingPolicy) Check(ctx context.Context, offer entity.Offer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, offer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockPricingPolicyMockRecorder) Check(ctx, offer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockPricingPolicy)(nil).Check), ctx, offer)
 }
 
 // MockService is a mock of Service interface.
@@ -77,6 +200,36 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelGuestOffer mocks base method.
+func (m *MockService) CancelGuestOffer(ctx context.Context, token string) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelGuestOffer", ctx, token)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelGuestOffer indicates an expected call of CancelGuestOffer.
+func (mr *MockServiceMockRecorder) CancelGuestOffer(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelGuestOffer", reflect.TypeOf((*MockService)(nil).CancelGuestOffer), ctx, token)
+}
+
+// GetGuestOffer mocks base method.
+func (m *MockService) GetGuestOffer(ctx context.Context, token string) (entity.GuestOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestOffer", ctx, token)
+	ret0, _ := ret[0].(entity.GuestOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestOffer indicates an expected call of GetGuestOffer.
+func (mr *MockServiceMockRecorder) GetGuestOffer(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestOffer", reflect.TypeOf((*MockService)(nil).GetGuestOffer), ctx, token)
+}
+
 // ProcessGuestOffer mocks base method.
 func (m *MockService) ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,50 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/pkg/worker"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=cleanup_mock_test.go -package=idempotency CleanupRepository
+
+type CleanupRepository interface {
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// CleanupWorker периодически удаляет истёкшие ключи Idempotency-Key: и сохранённые ответы
+// старше ttl, и брошенные ключи с прошедшей арендой.
+type CleanupWorker struct {
+	*worker.Runner
+
+	repo CleanupRepository
+	log  *zap.Logger
+}
+
+func NewCleanupWorker(repo CleanupRepository, cfg *config.IdempotencyConfig, log *zap.Logger) *CleanupWorker {
+	w := &CleanupWorker{repo: repo, log: log}
+	w.Runner = worker.NewRunner("idempotency key cleanup worker", cfg.CleanupInterval,
+		func(ctx context.Context) error {
+			_, err := w.DeleteExpired(ctx)
+			return err
+		}, log)
+	return w
+}
+
+// DeleteExpired удаляет истёкшие ключи и возвращает их количество
+func (w *CleanupWorker) DeleteExpired(ctx context.Context) (int, error) {
+	deleted, err := w.repo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		w.log.Info("expired idempotency keys deleted", zap.Int("count", deleted))
+	}
+
+	return deleted, nil
+}
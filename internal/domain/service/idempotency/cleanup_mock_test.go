@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cleanup.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cleanup.go -destination=cleanup_mock_test.go -package=idempotency CleanupRepository
+//
+
+// Package idempotency is a generated GoMock package.
+package idempotency
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCleanupRepository is a mock of CleanupRepository interface.
+type MockCleanupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCleanupRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCleanupRepositoryMockRecorder is the mock recorder for MockCleanupRepository.
+type MockCleanupRepositoryMockRecorder struct {
+	mock *MockCleanupRepository
+}
+
+// NewMockCleanupRepository creates a new mock instance.
+func NewMockCleanupRepository(ctrl *gomock.Controller) *MockCleanupRepository {
+	mock := &MockCleanupRepository{ctrl: ctrl}
+	mock.recorder = &MockCleanupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCleanupRepository) EXPECT() *MockCleanupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockCleanupRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockCleanupRepositoryMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockCleanupRepository)(nil).DeleteExpired), ctx, now)
+}
@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=idempotency_mock_test.go -package=idempotency Repository
+
+type Repository interface {
+	Claim(ctx context.Context, record entity.IdempotencyRecord) (entity.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, scope, key, lease string, statusCode int, body []byte, expiresAt time.Time) error
+	Release(ctx context.Context, scope, key, lease string) error
+}
+
+// Service хранит ответы на запросы с Idempotency-Key, чтобы повторы запроса
+// (например, ретраи мобильных клиентов) не выполняли его второй раз.
+// Пока запрос выполняется, ключ занят на короткую аренду, сохранённый ответ хранится ttl.
+type Service struct {
+	repo  Repository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewService(repo Repository, cfg *config.IdempotencyConfig) *Service {
+	return &Service{repo: repo, ttl: cfg.TTL, lease: cfg.Lease}
+}
+
+// Begin занимает ключ под запрос. Если запрос с этим ключом уже выполнен, возвращается
+// сохранённый ответ для повтора, иначе nil и токен аренды: запрос нужно выполнить
+// и передать токен в Complete или Release.
+// Ключ, использованный для другого запроса или ещё обрабатываемый, приводит к ошибке.
+func (s *Service) Begin(
+	ctx context.Context,
+	scope, key, requestHash string,
+) (*entity.IdempotencyRecord, string, error) {
+	lease := uuid.NewString()
+	existing, claimed, err := s.repo.Claim(ctx, entity.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		LeaseToken:  lease,
+		ExpiresAt:   time.Now().Add(s.lease),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if claimed {
+		return nil, lease, nil
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, "", apperror.New(apperror.BadRequest,
+			"Idempotency-Key has already been used for a different request", nil)
+	}
+	if existing.StatusCode == 0 {
+		return nil, "", apperror.New(apperror.Conflict,
+			"a request with this Idempotency-Key is still being processed", nil)
+	}
+
+	return &existing, "", nil
+}
+
+// Complete сохраняет ответ на выполненный запрос на ttl. Если аренда lease истекла и ключ
+// уже занял другой запрос, ответ не сохраняется.
+func (s *Service) Complete(ctx context.Context, scope, key, lease string, statusCode int, body []byte) error {
+	return s.repo.Complete(ctx, scope, key, lease, statusCode, body, time.Now().Add(s.ttl))
+}
+
+// Release освобождает ключ после неуспешного запроса, чтобы повтор выполнил его заново.
+// Ключ, который после истечения аренды lease занял другой запрос, не освобождается.
+func (s *Service) Release(ctx context.Context, scope, key, lease string) error {
+	return s.repo.Release(ctx, scope, key, lease)
+}
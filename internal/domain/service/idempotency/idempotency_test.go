@@ -0,0 +1,182 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestIdempotencyServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Idempotency Service Suite")
+}
+
+var _ = Describe("IdempotencyService", func() {
+	const (
+		scope = "POST /api/v1/offers user:2"
+		key   = "5f1c7d2e"
+		hash  = "a1b2c3"
+	)
+
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		service = NewService(repo, &config.IdempotencyConfig{TTL: time.Hour, Lease: time.Minute})
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("Begin", func() {
+		It("claims a new key for the lease only", func() {
+			var claimedLease string
+			repo.EXPECT().Claim(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, r entity.IdempotencyRecord) (entity.IdempotencyRecord, bool, error) {
+					Expect(r.Scope).To(Equal(scope))
+					Expect(r.Key).To(Equal(key))
+					Expect(r.RequestHash).To(Equal(hash))
+					Expect(r.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+					claimedLease = r.LeaseToken
+					return r, true, nil
+				})
+
+			saved, lease, err := service.Begin(ctx, scope, key, hash)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeNil())
+			Expect(lease).NotTo(BeEmpty())
+			Expect(lease).To(Equal(claimedLease))
+		})
+
+		It("gives every claim its own lease", func() {
+			repo.EXPECT().Claim(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, r entity.IdempotencyRecord) (entity.IdempotencyRecord, bool, error) {
+					return r, true, nil
+				}).Times(2)
+
+			_, first, _ := service.Begin(ctx, scope, key, hash)
+			_, second, _ := service.Begin(ctx, scope, key, hash)
+
+			Expect(first).NotTo(Equal(second))
+		})
+
+		It("returns the saved response for a completed request", func() {
+			repo.EXPECT().Claim(ctx, gomock.Any()).Return(entity.IdempotencyRecord{
+				Scope: scope, Key: key, RequestHash: hash, StatusCode: 201, Body: []byte(`{"id":7}`),
+			}, false, nil)
+
+			saved, lease, err := service.Begin(ctx, scope, key, hash)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lease).To(BeEmpty())
+			Expect(saved).NotTo(BeNil())
+			Expect(saved.StatusCode).To(Equal(201))
+			Expect(saved.Body).To(MatchJSON(`{"id":7}`))
+		})
+
+		It("rejects a key reused for a different request body", func() {
+			repo.EXPECT().Claim(ctx, gomock.Any()).Return(entity.IdempotencyRecord{
+				Scope: scope, Key: key, RequestHash: "other", StatusCode: 201,
+			}, false, nil)
+
+			_, _, err := service.Begin(ctx, scope, key, hash)
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("reports a conflict while the first request is still in progress", func() {
+			repo.EXPECT().Claim(ctx, gomock.Any()).Return(entity.IdempotencyRecord{
+				Scope: scope, Key: key, RequestHash: hash,
+			}, false, nil)
+
+			_, _, err := service.Begin(ctx, scope, key, hash)
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Conflict))
+		})
+
+		It("passes repository errors through", func() {
+			repoErr := errors.New("db is down")
+			repo.EXPECT().Claim(ctx, gomock.Any()).Return(entity.IdempotencyRecord{}, false, repoErr)
+
+			_, _, err := service.Begin(ctx, scope, key, hash)
+
+			Expect(err).To(MatchError(repoErr))
+		})
+	})
+
+	Describe("Complete", func() {
+		It("keeps the saved response for the ttl", func() {
+			repo.EXPECT().Complete(ctx, scope, key, "lease-1", 201, []byte(`{"id":7}`), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _, _ string, _ int, _ []byte, expiresAt time.Time) error {
+					Expect(expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+					return nil
+				})
+
+			Expect(service.Complete(ctx, scope, key, "lease-1", 201, []byte(`{"id":7}`))).To(Succeed())
+		})
+	})
+
+	Describe("Release", func() {
+		It("releases the key only for the lease that claimed it", func() {
+			repo.EXPECT().Release(ctx, scope, key, "lease-1").Return(nil)
+
+			Expect(service.Release(ctx, scope, key, "lease-1")).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("CleanupWorker", func() {
+	var (
+		ctrl   *gomock.Controller
+		repo   *MockCleanupRepository
+		worker *CleanupWorker
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockCleanupRepository(ctrl)
+		worker = NewCleanupWorker(repo, &config.IdempotencyConfig{CleanupInterval: time.Hour}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("deletes keys that expired by now", func() {
+		repo.EXPECT().DeleteExpired(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, now time.Time) (int, error) {
+				Expect(now).To(BeTemporally("~", time.Now(), time.Second))
+				return 3, nil
+			})
+
+		deleted, err := worker.DeleteExpired(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal(3))
+	})
+})
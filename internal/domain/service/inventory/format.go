@@ -0,0 +1,265 @@
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// Форматы файлов импорта и выгрузки инвентаря
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Колонки CSV. product_name есть только в выгрузке, при импорте он, как и любые
+// незнакомые колонки, пропускается, так что выгрузку можно загрузить обратно.
+const (
+	columnProductID   = "product_id"
+	columnProductName = "product_name"
+	columnPrice       = "price"
+	columnCurrency    = "currency"
+	columnIsAvailable = "is_available"
+)
+
+// importLine строка импорта в NDJSON, поля-указатели отличают отсутствующее поле от нулевого
+type importLine struct {
+	ProductID   *int64   `json:"product_id"`
+	Price       *float64 `json:"price"`
+	Currency    string   `json:"currency"`
+	IsAvailable *bool    `json:"is_available"`
+}
+
+// exportLine строка выгрузки в NDJSON
+type exportLine struct {
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
+	IsAvailable bool    `json:"is_available"`
+}
+
+// parseCSV читает строки импорта из CSV с заголовком. Обязательны колонки product_id, price и currency,
+// is_available по умолчанию true. Ошибки значений остаются в строках, ошибкой возвращается только
+// то, из-за чего файл нельзя читать дальше.
+func parseCSV(r io.Reader, maxRows int) ([]entity.InventoryImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, apperror.New(apperror.BadRequest, "csv file is empty", nil)
+	}
+	if err != nil {
+		return nil, apperror.New(apperror.BadRequest, "invalid csv header", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{columnProductID, columnPrice, columnCurrency} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperror.New(apperror.BadRequest,
+				fmt.Sprintf("csv header must contain %s, %s and %s columns", columnProductID, columnPrice,
+					columnCurrency), nil)
+		}
+	}
+
+	var rows []entity.InventoryImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(rows) == maxRows {
+			return nil, apperror.New(apperror.BadRequest, fmt.Sprintf("file has more than %d rows", maxRows), nil)
+		}
+
+		// у строки с неверным числом полей record всё равно заполнен, остальной файл читается дальше
+		if errors.Is(err, csv.ErrFieldCount) {
+			rows = append(rows, entity.InventoryImportRow{Line: line, Error: "wrong number of fields"})
+			continue
+		}
+		if err != nil {
+			return nil, apperror.New(apperror.BadRequest, "invalid csv", err)
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		rows = append(rows, parseCSVRecord(line, field))
+	}
+
+	return rows, nil
+}
+
+func parseCSVRecord(line int, field func(name string) string) entity.InventoryImportRow {
+	row := entity.InventoryImportRow{Line: line, Item: entity.InventoryItem{IsAvailable: true}}
+
+	productID, err := strconv.ParseUint(field(columnProductID), 10, 64)
+	if err != nil || productID == 0 {
+		row.Error = "product_id must be a positive number"
+		return row
+	}
+	row.Item.ProductID = uint(productID)
+
+	row.Item.Price, err = strconv.ParseFloat(field(columnPrice), 64)
+	if err != nil {
+		row.Error = "price must be a number"
+		return row
+	}
+
+	row.Item.Currency = field(columnCurrency)
+
+	if available := field(columnIsAvailable); available != "" {
+		row.Item.IsAvailable, err = strconv.ParseBool(available)
+		if err != nil {
+			row.Error = "is_available must be true or false"
+			return row
+		}
+	}
+
+	return row
+}
+
+// parseNDJSON читает строки импорта из JSON Lines, пустые строки пропускаются
+func parseNDJSON(r io.Reader, maxRows int) ([]entity.InventoryImportRow, error) {
+	scanner := bufio.NewScanner(r)
+
+	var rows []entity.InventoryImportRow
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, apperror.New(apperror.BadRequest, fmt.Sprintf("file has more than %d rows", maxRows), nil)
+		}
+
+		rows = append(rows, parseNDJSONLine(line, data))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, apperror.New(apperror.BadRequest, "invalid ndjson", err)
+	}
+
+	return rows, nil
+}
+
+func parseNDJSONLine(line int, data []byte) entity.InventoryImportRow {
+	row := entity.InventoryImportRow{Line: line, Item: entity.InventoryItem{IsAvailable: true}}
+
+	var decoded importLine
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		row.Error = "invalid json"
+		return row
+	}
+
+	if decoded.ProductID == nil || *decoded.ProductID <= 0 {
+		row.Error = "product_id must be a positive number"
+		return row
+	}
+	row.Item.ProductID = uint(*decoded.ProductID)
+
+	if decoded.Price == nil {
+		row.Error = "price is required"
+		return row
+	}
+	row.Item.Price = *decoded.Price
+
+	row.Item.Currency = strings.TrimSpace(decoded.Currency)
+
+	if decoded.IsAvailable != nil {
+		row.Item.IsAvailable = *decoded.IsAvailable
+	}
+
+	return row
+}
+
+// itemEncoder пишет позиции выгрузки в одном из форматов
+type itemEncoder interface {
+	Encode(item entity.InventoryItem) error
+	Flush() error
+}
+
+func newItemEncoder(format string, w io.Writer) (itemEncoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w)
+	case FormatNDJSON:
+		return &ndjsonEncoder{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, apperror.New(apperror.BadRequest, fmt.Sprintf("unknown format %q", format), nil)
+	}
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{columnProductID, columnProductName, columnPrice, columnCurrency, columnIsAvailable})
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvEncoder{w: writer}, nil
+}
+
+func (e *csvEncoder) Encode(item entity.InventoryItem) error {
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(item.ProductID), 10),
+		item.ProductName,
+		strconv.FormatFloat(item.Price, 'f', 2, 64),
+		item.Currency,
+		strconv.FormatBool(item.IsAvailable),
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *ndjsonEncoder) Encode(item entity.InventoryItem) error {
+	data, err := json.Marshal(exportLine{
+		ProductID:   item.ProductID,
+		ProductName: item.ProductName,
+		Price:       item.Price,
+		Currency:    item.Currency,
+		IsAvailable: item.IsAvailable,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = e.w.Write(data); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return e.w.Flush()
+}
@@ -0,0 +1,258 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	isocurrency "golang.org/x/text/currency"
+)
+
+// maxPrice верхняя граница цены, DECIMAL(10,2) в shop_inventory больше не вместит
+const maxPrice = 1e8
+
+var errFileTooLarge = errors.New("import file is too large")
+
+//go:generate mockgen -source=$GOFILE -destination=inventory_mock_test.go -package=inventory Repository,Relay
+
+type Repository interface {
+	SelectShopInventory(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.InventoryItem, int, error)
+	InsertItem(ctx context.Context, item entity.InventoryItem, userID uint) (entity.InventoryItem, error)
+	UpdateItem(
+		ctx context.Context,
+		shopID, productID, userID uint,
+		update entity.InventoryUpdate,
+	) (item entity.InventoryItem, declined int, err error)
+	DeleteItem(ctx context.Context, shopID, productID, userID uint) error
+	SelectItemHistory(
+		ctx context.Context,
+		shopID, productID, userID uint,
+		limit, offset int,
+	) ([]entity.InventoryChange, int, error)
+	ImportItems(
+		ctx context.Context,
+		shopID, userID uint,
+		rows []entity.InventoryImportRow,
+		dryRun bool,
+	) (entity.InventoryImportReport, error)
+	ExportItems(ctx context.Context, shopID, userID uint, fn func(entity.InventoryItem) error) error
+}
+
+// Relay будит релей outbox, чтобы письма об отклонённых офферах ушли без ожидания тика
+type Relay interface {
+	Wake()
+}
+
+// Service управляет инвентарём магазина: ценами, валютой и доступностью товаров.
+// Каждое изменение попадает в историю позиции. Инвентарь можно загрузить и выгрузить файлом CSV или NDJSON.
+type Service struct {
+	repo           Repository
+	relay          Relay
+	importMaxRows  int
+	importMaxBytes int64
+}
+
+func NewService(repo Repository, relay Relay, cfg *config.InventoryConfig) *Service {
+	return &Service{
+		repo:           repo,
+		relay:          relay,
+		importMaxRows:  cfg.ImportMaxRows,
+		importMaxBytes: cfg.ImportMaxBytes,
+	}
+}
+
+func (s *Service) GetShopInventory(
+	ctx context.Context,
+	shopID, userID uint,
+	page, limit int,
+) ([]entity.InventoryItem, int, error) {
+	offset := (page - 1) * limit
+
+	return s.repo.SelectShopInventory(ctx, shopID, userID, limit, offset)
+}
+
+func (s *Service) AddItem(ctx context.Context, item entity.InventoryItem, userID uint) (entity.InventoryItem, error) {
+	item.Currency = strings.ToUpper(item.Currency)
+
+	return s.repo.InsertItem(ctx, item, userID)
+}
+
+// UpdateItem применяет переданные поля к позиции. Если позиция снята с продажи,
+// открытые офферы на неё отклоняются, declined - сколько их было.
+func (s *Service) UpdateItem(
+	ctx context.Context,
+	shopID, productID, userID uint,
+	update entity.InventoryUpdate,
+) (item entity.InventoryItem, declined int, err error) {
+	if update.Price == nil && update.Currency == nil && update.IsAvailable == nil {
+		return entity.InventoryItem{}, 0, apperror.New(apperror.BadRequest, "nothing to update", nil)
+	}
+	if update.Currency != nil {
+		currency := strings.ToUpper(*update.Currency)
+		update.Currency = &currency
+	}
+
+	item, declined, err = s.repo.UpdateItem(ctx, shopID, productID, userID, update)
+	if err != nil {
+		return entity.InventoryItem{}, 0, err
+	}
+
+	if declined > 0 {
+		s.relay.Wake()
+	}
+
+	return item, declined, nil
+}
+
+func (s *Service) RemoveItem(ctx context.Context, shopID, productID, userID uint) error {
+	return s.repo.DeleteItem(ctx, shopID, productID, userID)
+}
+
+func (s *Service) GetItemHistory(
+	ctx context.Context,
+	shopID, productID, userID uint,
+	page, limit int,
+) ([]entity.InventoryChange, int, error) {
+	offset := (page - 1) * limit
+
+	return s.repo.SelectItemHistory(ctx, shopID, productID, userID, limit, offset)
+}
+
+// ImportInventory загружает инвентарь магазина из файла в формате CSV или NDJSON: товары, которых
+// в инвентаре нет, добавляются, остальные обновляются. Строки с ошибками пропускаются и попадают
+// в отчёт с номером строки файла. При dryRun ничего не сохраняется.
+func (s *Service) ImportInventory(
+	ctx context.Context,
+	shopID, userID uint,
+	format string,
+	file io.Reader,
+	dryRun bool,
+) (entity.InventoryImportReport, error) {
+	file = &limitedReader{r: file, left: s.importMaxBytes}
+
+	var (
+		rows []entity.InventoryImportRow
+		err  error
+	)
+	switch format {
+	case FormatCSV:
+		rows, err = parseCSV(file, s.importMaxRows)
+	case FormatNDJSON:
+		rows, err = parseNDJSON(file, s.importMaxRows)
+	default:
+		return entity.InventoryImportReport{}, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("unknown format %q", format), nil)
+	}
+	if errors.Is(err, errFileTooLarge) {
+		return entity.InventoryImportReport{}, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("file is larger than %d bytes", s.importMaxBytes), nil)
+	}
+	if err != nil {
+		return entity.InventoryImportReport{}, err
+	}
+
+	valid, invalid := validateImportRows(rows)
+
+	report, err := s.repo.ImportItems(ctx, shopID, userID, valid, dryRun)
+	if err != nil {
+		return entity.InventoryImportReport{}, err
+	}
+
+	report.Rows = len(rows)
+	report.Errors = append(report.Errors, invalid...)
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+
+	if report.DeclinedOffers > 0 && !dryRun {
+		s.relay.Wake()
+	}
+
+	return report, nil
+}
+
+// validateImportRows отделяет строки, которые можно применить, от строк с ошибками.
+// Товар может встречаться в файле только один раз.
+func validateImportRows(rows []entity.InventoryImportRow) ([]entity.InventoryImportRow, []entity.InventoryImportError) {
+	valid := make([]entity.InventoryImportRow, 0, len(rows))
+	var invalid []entity.InventoryImportError
+	seen := make(map[uint]int, len(rows))
+
+	for _, row := range rows {
+		if row.Error == "" {
+			row.Error = validateImportItem(&row.Item)
+		}
+		if row.Error == "" {
+			if line, ok := seen[row.Item.ProductID]; ok {
+				row.Error = fmt.Sprintf("product %d is already listed on line %d", row.Item.ProductID, line)
+			}
+		}
+		if row.Error != "" {
+			invalid = append(invalid, entity.InventoryImportError{Line: row.Line, Error: row.Error})
+			continue
+		}
+
+		seen[row.Item.ProductID] = row.Line
+		valid = append(valid, row)
+	}
+
+	return valid, invalid
+}
+
+func validateImportItem(item *entity.InventoryItem) string {
+	if item.Price <= 0 || item.Price >= maxPrice {
+		return fmt.Sprintf("price must be greater than 0 and less than %.0f", maxPrice)
+	}
+
+	item.Currency = strings.ToUpper(item.Currency)
+	if _, err := isocurrency.ParseISO(item.Currency); err != nil {
+		return fmt.Sprintf("unknown currency %q", item.Currency)
+	}
+
+	return ""
+}
+
+// ExportInventory пишет в w текущий инвентарь магазина в формате CSV или NDJSON, тем же, что принимает импорт
+func (s *Service) ExportInventory(ctx context.Context, shopID, userID uint, format string, w io.Writer) error {
+	encoder, err := newItemEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.ExportItems(ctx, shopID, userID, encoder.Encode)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Flush()
+}
+
+// limitedReader читает не больше left байт, а на следующем чтении возвращает errFileTooLarge,
+// чтобы обрезанный файл не импортировался как целый
+type limitedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, errFileTooLarge
+	}
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return 0, errFileTooLarge
+	}
+
+	return n, err
+}
@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: inventory.go
+//
+// Generated by this command:
+//
+//	mockgen -source=inventory.go -destination=inventory_mock_test.go -package=inventory Repository,Relay
+//
+
+// Package inventory is a generated GoMock package.
+package inventory
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteItem mocks base method.
+func (m *MockRepository) DeleteItem(ctx context.Context, shopID, productID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", ctx, shopID, productID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockRepositoryMockRecorder) DeleteItem(ctx, shopID, productID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockRepository)(nil).DeleteItem), ctx, shopID, productID, userID)
+}
+
+// ExportItems mocks base method.
+func (m *MockRepository) ExportItems(ctx context.Context, shopID, userID uint, fn func(entity.InventoryItem) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportItems", ctx, shopID, userID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportItems indicates an expected call of ExportItems.
+func (mr *MockRepositoryMockRecorder) ExportItems(ctx, shopID, userID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportItems", reflect.TypeOf((*MockRepository)(nil).ExportItems), ctx, shopID, userID, fn)
+}
+
+// ImportItems mocks base method.
+func (m *MockRepository) ImportItems(ctx context.Context, shopID, userID uint, rows []entity.InventoryImportRow, dryRun bool) (entity.InventoryImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportItems", ctx, shopID, userID, rows, dryRun)
+	ret0, _ := ret[0].(entity.InventoryImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportItems indicates an expected call of ImportItems.
+func (mr *MockRepositoryMockRecorder) ImportItems(ctx, shopID, userID, rows, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportItems", reflect.TypeOf((*MockRepository)(nil).ImportItems), ctx, shopID, userID, rows, dryRun)
+}
+
+// InsertItem mocks base method.
+func (m *MockRepository) InsertItem(ctx context.Context, item entity.InventoryItem, userID uint) (entity.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertItem", ctx, item, userID)
+	ret0, _ := ret[0].(entity.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertItem indicates an expected call of InsertItem.
+func (mr *MockRepositoryMockRecorder) InsertItem(ctx, item, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertItem", reflect.TypeOf((*MockRepository)(nil).InsertItem), ctx, item, userID)
+}
+
+// SelectItemHistory mocks base method.
+func (m *MockRepository) SelectItemHistory(ctx context.Context, shopID, productID, userID uint, limit, offset int) ([]entity.InventoryChange, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectItemHistory", ctx, shopID, productID, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.InventoryChange)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectItemHistory indicates an expected call of SelectItemHistory.
+func (mr *MockRepositoryMockRecorder) SelectItemHistory(ctx, shopID, productID, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectItemHistory", reflect.TypeOf((*MockRepository)(nil).SelectItemHistory), ctx, shopID, productID, userID, limit, offset)
+}
+
+// SelectShopInventory mocks base method.
+func (m *MockRepository) SelectShopInventory(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.InventoryItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopInventory", ctx, shopID, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.InventoryItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectShopInventory indicates an expected call of SelectShopInventory.
+func (mr *MockRepositoryMockRecorder) SelectShopInventory(ctx, shopID, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopInventory", reflect.TypeOf((*MockRepository)(nil).SelectShopInventory), ctx, shopID, userID, limit, offset)
+}
+
+// UpdateItem mocks base method.
+func (m *MockRepository) UpdateItem(ctx context.Context, shopID, productID, userID uint, update entity.InventoryUpdate) (entity.InventoryItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, shopID, productID, userID, update)
+	ret0, _ := ret[0].(entity.InventoryItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockRepositoryMockRecorder) UpdateItem(ctx, shopID, productID, userID, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockRepository)(nil).UpdateItem), ctx, shopID, productID, userID, update)
+}
+
+// MockRelay is a mock of Relay interface.
+type MockRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayMockRecorder
+	isgomock struct{}
+}
+
+// MockRelayMockRecorder is the mock recorder for MockRelay.
+type MockRelayMockRecorder struct {
+	mock *MockRelay
+}
+
+// NewMockRelay creates a new mock instance.
+func NewMockRelay(ctrl *gomock.Controller) *MockRelay {
+	mock := &MockRelay{ctrl: ctrl}
+	mock.recorder = &MockRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelay) EXPECT() *MockRelayMockRecorder {
+	return m.recorder
+}
+
+// Wake mocks base method.
+func (m *MockRelay) Wake() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Wake")
+}
+
+// Wake indicates an expected call of Wake.
+func (mr *MockRelayMockRecorder) Wake() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wake", reflect.TypeOf((*MockRelay)(nil).Wake))
+}
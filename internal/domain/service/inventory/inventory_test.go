@@ -0,0 +1,215 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestInventoryServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Inventory Service Suite")
+}
+
+var _ = Describe("InventoryService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		relay   *MockRelay
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		relay = NewMockRelay(ctrl)
+		service = NewService(repo, relay, &config.InventoryConfig{ImportMaxRows: 4, ImportMaxBytes: 1 << 10})
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("AddItem", func() {
+		It("normalizes the currency code", func() {
+			repo.EXPECT().InsertItem(ctx, entity.InventoryItem{ShopID: 1, ProductID: 2, Price: 10, Currency: "USD"}, uint(3)).
+				Return(entity.InventoryItem{ShopID: 1, ProductID: 2, Price: 10, Currency: "USD"}, nil)
+
+			item, err := service.AddItem(ctx, entity.InventoryItem{ShopID: 1, ProductID: 2, Price: 10, Currency: "usd"}, 3)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Currency).To(Equal("USD"))
+		})
+	})
+
+	Describe("UpdateItem", func() {
+		It("rejects an empty update without touching the repository", func() {
+			_, _, err := service.UpdateItem(ctx, 1, 2, 3, entity.InventoryUpdate{})
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("wakes the relay when offers were declined", func() {
+			unavailable := false
+			repo.EXPECT().UpdateItem(ctx, uint(1), uint(2), uint(3), entity.InventoryUpdate{IsAvailable: &unavailable}).
+				Return(entity.InventoryItem{ShopID: 1, ProductID: 2}, 2, nil)
+			relay.EXPECT().Wake()
+
+			_, declined, err := service.UpdateItem(ctx, 1, 2, 3, entity.InventoryUpdate{IsAvailable: &unavailable})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(declined).To(Equal(2))
+		})
+
+		It("does not wake the relay on a plain reprice", func() {
+			price := 12.5
+			currency := "eur"
+			repo.EXPECT().UpdateItem(ctx, uint(1), uint(2), uint(3), gomock.Any()).
+				DoAndReturn(func(
+					_ context.Context,
+					_, _, _ uint,
+					update entity.InventoryUpdate,
+				) (entity.InventoryItem, int, error) {
+					Expect(*update.Currency).To(Equal("EUR"))
+					return entity.InventoryItem{Price: *update.Price, Currency: *update.Currency}, 0, nil
+				})
+
+			item, declined, err := service.UpdateItem(ctx, 1, 2, 3,
+				entity.InventoryUpdate{Price: &price, Currency: &currency})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(declined).To(BeZero())
+			Expect(item.Price).To(Equal(12.5))
+		})
+	})
+
+	Describe("GetItemHistory", func() {
+		It("converts the page into an offset", func() {
+			repo.EXPECT().SelectItemHistory(ctx, uint(1), uint(2), uint(3), 10, 20).
+				Return([]entity.InventoryChange{{ID: 7}}, 21, nil)
+
+			changes, total, err := service.GetItemHistory(ctx, 1, 2, 3, 3, 10)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(HaveLen(1))
+			Expect(total).To(Equal(21))
+		})
+	})
+
+	Describe("ImportInventory", func() {
+		It("passes valid CSV rows to the repository and reports the rest by line", func() {
+			file := "product_id,product_name,price,currency,is_available\n" +
+				"1,Kettle,10.5,usd,true\n" +
+				"2,Toaster,-3,usd,\n" +
+				"1,Kettle,11,usd,false\n" +
+				"3,Mixer,7,zzz,true\n"
+
+			repo.EXPECT().ImportItems(ctx, uint(1), uint(2), []entity.InventoryImportRow{
+				{Line: 2, Item: entity.InventoryItem{ProductID: 1, Price: 10.5, Currency: "USD", IsAvailable: true}},
+			}, true).Return(entity.InventoryImportReport{DryRun: true, Created: 1}, nil)
+
+			report, err := service.ImportInventory(ctx, 1, 2, FormatCSV, strings.NewReader(file), true)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Rows).To(Equal(4))
+			Expect(report.Created).To(Equal(1))
+			Expect(report.Errors).To(HaveLen(3))
+			Expect(report.Errors[0].Line).To(Equal(3))
+			Expect(report.Errors[1]).To(Equal(entity.InventoryImportError{
+				Line: 4, Error: "product 1 is already listed on line 2"}))
+			Expect(report.Errors[2].Error).To(ContainSubstring("unknown currency"))
+		})
+
+		It("merges repository errors with parse errors in line order", func() {
+			file := `{"product_id": 9, "price": 5, "currency": "EUR"}` + "\n\n" +
+				`{"product_id": "x"}` + "\n" +
+				`{"product_id": 4, "price": 6, "currency": "eur", "is_available": false}` + "\n"
+
+			repo.EXPECT().ImportItems(ctx, uint(1), uint(2), gomock.Len(2), false).
+				Return(entity.InventoryImportReport{
+					Updated:        1,
+					DeclinedOffers: 2,
+					Errors:         []entity.InventoryImportError{{Line: 1, Error: "product not found"}},
+				}, nil)
+			relay.EXPECT().Wake()
+
+			report, err := service.ImportInventory(ctx, 1, 2, FormatNDJSON, strings.NewReader(file), false)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Rows).To(Equal(3))
+			Expect(report.Errors).To(Equal([]entity.InventoryImportError{
+				{Line: 1, Error: "product not found"},
+				{Line: 3, Error: "invalid json"},
+			}))
+		})
+
+		It("rejects a CSV file without the required columns", func() {
+			_, err := service.ImportInventory(ctx, 1, 2, FormatCSV, strings.NewReader("product_id,price\n1,10\n"), false)
+
+			Expect(err).To(MatchError(ContainSubstring("currency")))
+		})
+
+		It("rejects files over the row and size limits", func() {
+			rows := "product_id,price,currency\n" + strings.Repeat("1,1,USD\n", 5)
+			_, err := service.ImportInventory(ctx, 1, 2, FormatCSV, strings.NewReader(rows), false)
+			Expect(err).To(MatchError(ContainSubstring("more than 4 rows")))
+
+			large := "product_id,price,currency\n" + strings.Repeat("1,1,USD\n", 200)
+			_, err = service.ImportInventory(ctx, 1, 2, FormatCSV, strings.NewReader(large), false)
+			Expect(err).To(MatchError(ContainSubstring("larger than 1024 bytes")))
+		})
+	})
+
+	Describe("ExportInventory", func() {
+		items := []entity.InventoryItem{
+			{ProductID: 1, ProductName: "Kettle, white", Price: 10.5, Currency: "USD", IsAvailable: true},
+			{ProductID: 2, ProductName: "Toaster", Price: 20, Currency: "EUR"},
+		}
+
+		BeforeEach(func() {
+			repo.EXPECT().ExportItems(ctx, uint(1), uint(2), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ uint, fn func(entity.InventoryItem) error) error {
+					for _, item := range items {
+						if err := fn(item); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+		})
+
+		It("writes CSV that the import reads back", func() {
+			var out strings.Builder
+			Expect(service.ExportInventory(ctx, 1, 2, FormatCSV, &out)).To(Succeed())
+
+			Expect(out.String()).To(Equal("product_id,product_name,price,currency,is_available\n" +
+				"1,\"Kettle, white\",10.50,USD,true\n" +
+				"2,Toaster,20.00,EUR,false\n"))
+
+			rows, err := parseCSV(strings.NewReader(out.String()), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows[1].Item).To(Equal(entity.InventoryItem{ProductID: 2, Price: 20, Currency: "EUR"}))
+		})
+
+		It("writes one JSON object per line", func() {
+			var out strings.Builder
+			Expect(service.ExportInventory(ctx, 1, 2, FormatNDJSON, &out)).To(Succeed())
+
+			Expect(strings.Split(strings.TrimSpace(out.String()), "\n")).To(HaveLen(2))
+			Expect(out.String()).To(HavePrefix(`{"product_id":1,"product_name":"Kettle, white","price":10.5,`))
+		})
+	})
+})
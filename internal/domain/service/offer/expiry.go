@@ -0,0 +1,70 @@
+package offer
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/worker"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=expiry_mock_test.go -package=offer ExpiryRepository
+
+type ExpiryRepository interface {
+	ExpireOffers(ctx context.Context, now time.Time, limit int) ([]entity.ExpiredOffer, error)
+}
+
+// ExpiryWorker периодически переводит просроченные офферы в статус expired.
+// Письма покупателям репозиторий записывает в outbox вместе со сменой статуса.
+type ExpiryWorker struct {
+	*worker.Runner
+
+	repo      ExpiryRepository
+	relay     Relay
+	batchSize int
+	log       *zap.Logger
+}
+
+func NewExpiryWorker(
+	repo ExpiryRepository,
+	relay Relay,
+	cfg *config.OfferExpiryConfig,
+	log *zap.Logger,
+) *ExpiryWorker {
+	w := &ExpiryWorker{repo: repo, relay: relay, batchSize: cfg.BatchSize, log: log}
+	w.Runner = worker.NewRunner("offer expiry worker", cfg.Interval, func(ctx context.Context) error {
+		_, err := w.ExpireOffers(ctx)
+		return err
+	}, log)
+	return w
+}
+
+// ExpireOffers обрабатывает просроченные офферы пакетами, пока не разберёт все,
+// и возвращает общее количество переведённых в expired.
+func (w *ExpiryWorker) ExpireOffers(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		expired, err := w.repo.ExpireOffers(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		if len(expired) > 0 {
+			w.relay.Wake()
+		}
+		total += len(expired)
+
+		if len(expired) < w.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.log.Info("offers expired", zap.Int("count", total))
+	}
+
+	return total, nil
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: expiry.go
+//
+// Generated by this command:
+//
+//	mockgen -source=expiry.go -destination=expiry_mock_test.go -package=offer ExpiryRepository
+//
+
+// Package offer is a generated GoMock package.
+package offer
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExpiryRepository is a mock of ExpiryRepository interface.
+type MockExpiryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpiryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExpiryRepositoryMockRecorder is the mock recorder for MockExpiryRepository.
+type MockExpiryRepositoryMockRecorder struct {
+	mock *MockExpiryRepository
+}
+
+// NewMockExpiryRepository creates a new mock instance.
+func NewMockExpiryRepository(ctrl *gomock.Controller) *MockExpiryRepository {
+	mock := &MockExpiryRepository{ctrl: ctrl}
+	mock.recorder = &MockExpiryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpiryRepository) EXPECT() *MockExpiryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExpireOffers mocks base method.
+func (m *MockExpiryRepository) ExpireOffers(ctx context.Context, now time.Time, limit int) ([]entity.ExpiredOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireOffers", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.ExpiredOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireOffers indicates an expected call of ExpireOffers.
+func (mr *MockExpiryRepositoryMockRecorder) ExpireOffers(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireOffers", reflect.TypeOf((*MockExpiryRepository)(nil).ExpireOffers), ctx, now, limit)
+}
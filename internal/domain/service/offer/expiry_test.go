@@ -0,0 +1,84 @@
+package offer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+var _ = Describe("ExpiryWorker", func() {
+	var (
+		ctrl   *gomock.Controller
+		repo   *MockExpiryRepository
+		relay  *MockRelay
+		worker *ExpiryWorker
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockExpiryRepository(ctrl)
+		relay = NewMockRelay(ctrl)
+		worker = NewExpiryWorker(repo, relay,
+			&config.OfferExpiryConfig{Interval: time.Hour, BatchSize: 2}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ExpireOffers", func() {
+		It("processes batches until a partial one and wakes the relay after each", func() {
+			gomock.InOrder(
+				repo.EXPECT().ExpireOffers(ctx, gomock.Any(), 2).Return([]entity.ExpiredOffer{
+					{OfferID: 1, BuyerEmail: "a@example.com"},
+					{OfferID: 2, BuyerEmail: "b@example.com"},
+				}, nil),
+				repo.EXPECT().ExpireOffers(ctx, gomock.Any(), 2).Return([]entity.ExpiredOffer{
+					{OfferID: 3, BuyerEmail: "a@example.com"},
+				}, nil),
+			)
+			relay.EXPECT().Wake().Times(2)
+
+			total, err := worker.ExpireOffers(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(3))
+		})
+
+		It("does nothing when there are no expired offers", func() {
+			repo.EXPECT().ExpireOffers(ctx, gomock.Any(), 2).Return([]entity.ExpiredOffer{}, nil)
+
+			total, err := worker.ExpireOffers(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(BeZero())
+		})
+
+		It("stops on a repository error and reports what was already expired", func() {
+			repoErr := errors.New("db is down")
+			gomock.InOrder(
+				repo.EXPECT().ExpireOffers(ctx, gomock.Any(), 2).Return([]entity.ExpiredOffer{
+					{OfferID: 1, BuyerEmail: "a@example.com"},
+					{OfferID: 2, BuyerEmail: "b@example.com"},
+				}, nil),
+				repo.EXPECT().ExpireOffers(ctx, gomock.Any(), 2).Return(nil, repoErr),
+			)
+			relay.EXPECT().Wake()
+
+			total, err := worker.ExpireOffers(ctx)
+
+			Expect(err).To(MatchError(repoErr))
+			Expect(total).To(Equal(2))
+		})
+	})
+})
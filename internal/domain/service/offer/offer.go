@@ -2,19 +2,65 @@ package offer
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
-	"github.com/EM-Stawberry/Stawberry/pkg/email"
 )
 
+//go:generate mockgen -source=$GOFILE -destination=offer_mock_test.go -package=offer Repository,Relay,Pricing,RateSource
+
 type Repository interface {
-	InsertOffer(ctx context.Context, offer entity.Offer) (uint, error)
-	GetOfferByID(ctx context.Context, offerID uint) (entity.Offer, error)
+	InsertOffer(
+		ctx context.Context,
+		offer entity.Offer,
+		quotaSince time.Time,
+		checkQuota func(entity.OfferQuotaUsage) error,
+		autoResponse *entity.AutoResponse,
+		outbox []entity.OutboxMessage,
+	) (uint, error)
+	GetOfferByID(ctx context.Context, offerID uint, userID uint) (entity.OfferDetails, error)
 	SelectUserOffers(ctx context.Context, userID uint, limit, offset int) ([]entity.Offer, int, error)
 	UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
+	CounterOffer(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
+	SelectOfferEvents(ctx context.Context, offerID uint, userID uint) ([]entity.OfferEvent, error)
+	SelectShopOffers(
+		ctx context.Context,
+		userID uint,
+		filter entity.ShopOfferFilter,
+		limit, offset int,
+	) ([]entity.Offer, int, map[string]int, error)
 	DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error)
+	GetAutoResponseRule(ctx context.Context, shopID, productID uint) (entity.AutoResponseRule, bool, error)
+	BulkUpdateOfferStatus(
+		ctx context.Context,
+		offerIDs []uint,
+		status string,
+		userID uint,
+		atomic bool,
+	) ([]entity.BulkOfferResult, error)
+}
+
+// Relay доставляет сообщения, которые репозиторий записывает в outbox вместе с изменением
+// оффера. Wake позволяет доставить их сразу, не дожидаясь следующего опроса.
+type Relay interface {
+	Wake()
+}
+
+// Pricing проверяет цену оффера покупателя по ценовой политике магазина
+type Pricing interface {
+	Check(ctx context.Context, offer entity.Offer) error
+}
+
+// RateSource отдаёт таблицу курсов для пересчёта цен офферов в валюту отображения
+type RateSource interface {
+	RateTable(ctx context.Context) (entity.RateTable, error)
 }
 
 const (
@@ -22,24 +68,44 @@ const (
 	statusDeclined  = "declined"
 	statusCancelled = "cancelled"
 	statusPending   = "pending"
+	statusCountered = "countered"
+	statusExpired   = "expired"
 
 	offerLifetime = 7 * 24 * time.Hour
+	// quotaWindow окно, в котором считается лимит новых офферов MaxPerHour
+	quotaWindow = time.Hour
 )
 
+var offerStatuses = []string{
+	statusPending, statusCountered, statusAccepted, statusDeclined, statusCancelled, statusExpired,
+}
+
 type Service struct {
 	offerRepository Repository
-	mailer          email.MailerService
+	pricing         Pricing
+	rates           RateSource
+	relay           Relay
+	quota           config.OfferQuotaConfig
 }
 
-func NewService(offerRepository Repository, mailer email.MailerService) *Service {
-	return &Service{offerRepository: offerRepository, mailer: mailer}
+func NewService(
+	offerRepository Repository,
+	pricing Pricing,
+	rates RateSource,
+	relay Relay,
+	quota *config.OfferQuotaConfig,
+) *Service {
+	return &Service{offerRepository: offerRepository, pricing: pricing, rates: rates, relay: relay, quota: *quota}
 }
 
+// CreateOffer проверяет оффер по ценовой политике магазина, создаёт его и сразу применяет правило
+// автоответа магазина, если оно сработало. Квоты покупателя проверяются в транзакции создания,
+// чтобы параллельные запросы не обходили лимиты. Возвращается оффер с итоговыми статусом и ценой.
 func (os *Service) CreateOffer(
 	ctx context.Context,
 	offer entity.Offer,
 	user entity.User,
-) (uint, error) {
+) (entity.Offer, error) {
 
 	t := time.Now()
 	offer.Status = statusPending
@@ -47,35 +113,186 @@ func (os *Service) CreateOffer(
 	offer.UpdatedAt = t
 	offer.ExpiresAt = t.Add(offerLifetime)
 
-	offerID, err := os.offerRepository.InsertOffer(ctx, offer)
+	err := os.pricing.Check(ctx, offer)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	rule, found, err := os.offerRepository.GetAutoResponseRule(ctx, offer.ShopID, offer.ProductID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	var autoResponse *entity.AutoResponse
+	if found {
+		if decision, ok := rule.Decide(offer.Price); ok {
+			autoResponse = &decision
+		}
+	}
+
+	notifications := []entity.EmailNotification{{Kind: entity.EmailRegistered, To: user.Email, Name: user.Name}}
+	if autoResponse != nil {
+		notifications = append(notifications,
+			entity.EmailNotification{Kind: entity.EmailStatusUpdate, To: user.Email, Status: autoResponse.Status})
+	}
+
+	outbox := make([]entity.OutboxMessage, len(notifications))
+	for i, n := range notifications {
+		payload, err := json.Marshal(n)
+		if err != nil {
+			return entity.Offer{}, apperror.New(apperror.InternalError, "failed to encode email notification", err)
+		}
+		outbox[i] = entity.OutboxMessage{Topic: entity.OutboxTopicEmail, Payload: payload}
+	}
+
+	var checkQuota func(entity.OfferQuotaUsage) error
+	if os.quota.MaxOpen > 0 || os.quota.MaxPerHour > 0 || os.quota.DeclineCooldown > 0 {
+		checkQuota = func(usage entity.OfferQuotaUsage) error {
+			return os.checkQuota(offer, usage)
+		}
+	}
+
+	offer.ID, err = os.offerRepository.InsertOffer(ctx, offer, offer.CreatedAt.Add(-quotaWindow), checkQuota,
+		autoResponse, outbox)
 	if err != nil {
-		return 0, err
+		return entity.Offer{}, err
 	}
 
-	os.mailer.Registered(user.Name, user.Email)
+	if autoResponse != nil {
+		offer.Status = autoResponse.Status
+		if autoResponse.Status == statusCountered {
+			offer.Price = autoResponse.CounterPrice
+		}
+	}
+
+	os.relay.Wake()
+
+	return offer, nil
+}
+
+// checkQuota не даёт покупателю держать слишком много открытых офферов, создавать их слишком
+// часто и сразу повторять оффер на товар, от которого магазин только что отказался.
+func (os *Service) checkQuota(offer entity.Offer, usage entity.OfferQuotaUsage) error {
+	if os.quota.MaxOpen > 0 && usage.Open >= os.quota.MaxOpen {
+		return apperror.New(apperror.TooManyRequests, fmt.Sprintf(
+			"too many open offers (limit %d), wait for answers or cancel some", os.quota.MaxOpen), nil)
+	}
+	if os.quota.MaxPerHour > 0 && usage.CreatedSince >= os.quota.MaxPerHour {
+		return apperror.New(apperror.TooManyRequests, fmt.Sprintf(
+			"too many new offers (limit %d per hour), try again later", os.quota.MaxPerHour), nil)
+	}
+	if os.quota.DeclineCooldown > 0 && usage.LastDeclinedAt != nil {
+		retryAt := usage.LastDeclinedAt.Add(os.quota.DeclineCooldown)
+		if offer.CreatedAt.Before(retryAt) {
+			return apperror.New(apperror.Conflict, fmt.Sprintf(
+				"the shop declined your offer on this product, you can offer again after %s",
+				retryAt.Format(time.RFC3339)), nil)
+		}
+	}
 
-	return offerID, nil
+	return nil
 }
 
+// GetOffer возвращает оффер покупателю или владельцу магазина-получателя
 func (os *Service) GetOffer(
 	ctx context.Context,
 	offerID uint,
-) (entity.Offer, error) {
-	return os.offerRepository.GetOfferByID(ctx, offerID)
+	userID uint,
+) (entity.OfferDetails, error) {
+	return os.offerRepository.GetOfferByID(ctx, offerID, userID)
 }
 
+// GetUserOffers возвращает офферы покупателя. Если задана displayCurrency,
+// офферам проставляется цена в этой валюте.
 func (os *Service) GetUserOffers(
 	ctx context.Context,
 	userID uint,
 	page,
 	limit int,
+	displayCurrency string,
 ) ([]entity.Offer, int, error) {
 	offset := (page - 1) * limit
 
 	offers, total, err := os.offerRepository.SelectUserOffers(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return offers, total, err
+	err = os.setDisplayPrices(ctx, offers, displayCurrency)
+	if err != nil {
+		return nil, 0, err
+	}
 
+	return offers, total, nil
+}
+
+// GetShopOffers возвращает входящие офферы по всем магазинам пользователя
+// и количество офферов в каждом статусе. Цены пересчитываются в displayCurrency, если она задана.
+func (os *Service) GetShopOffers(
+	ctx context.Context,
+	userID uint,
+	filter entity.ShopOfferFilter,
+	page,
+	limit int,
+	displayCurrency string,
+) ([]entity.Offer, int, map[string]int, error) {
+	for _, status := range filter.Statuses {
+		if !slices.Contains(offerStatuses, status) {
+			return nil, 0, nil, apperror.New(apperror.BadRequest, "invalid status filter value: "+status, nil)
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, 0, nil, apperror.New(apperror.BadRequest, "'from' must be before 'to'", nil)
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, 0, nil, apperror.New(apperror.BadRequest, "min_price must not exceed max_price", nil)
+	}
+
+	offset := (page - 1) * limit
+
+	offers, total, statusCounts, err := os.offerRepository.SelectShopOffers(ctx, userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	for _, status := range offerStatuses {
+		if _, ok := statusCounts[status]; !ok {
+			statusCounts[status] = 0
+		}
+	}
+
+	err = os.setDisplayPrices(ctx, offers, displayCurrency)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return offers, total, statusCounts, nil
+}
+
+// setDisplayPrices пересчитывает цены офферов в валюту отображения с округлением до копеек.
+// Офферы в валютах без курса остаются без цены отображения.
+func (os *Service) setDisplayPrices(ctx context.Context, offers []entity.Offer, displayCurrency string) error {
+	if displayCurrency == "" || len(offers) == 0 {
+		return nil
+	}
+
+	rates, err := os.rates.RateTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	displayCurrency = strings.ToUpper(displayCurrency)
+	for i := range offers {
+		price, ok := rates.Convert(offers[i].Price, offers[i].Currency, displayCurrency)
+		if !ok {
+			continue
+		}
+		price = math.Round(price*100) / 100
+		offers[i].DisplayPrice = &price
+		offers[i].DisplayCurrency = displayCurrency
+	}
+
+	return nil
 }
 
 func (os *Service) UpdateOfferStatus(
@@ -86,25 +303,105 @@ func (os *Service) UpdateOfferStatus(
 ) (entity.Offer, error) {
 	if isStore {
 		validStatusesShop := map[string]struct{}{
-			statusAccepted: {},
-			statusDeclined: {},
+			statusAccepted:  {},
+			statusDeclined:  {},
+			statusCountered: {},
 		}
 		if _, ok := validStatusesShop[offer.Status]; !ok {
 			return entity.Offer{}, apperror.New(apperror.BadRequest, "invalid status field value", nil)
 		}
 
 	} else {
+		// Покупатель может принять или оспорить только встречное предложение магазина,
+		// это проверяется в репозитории по текущему статусу оффера.
 		validStatusesBuyer := map[string]struct{}{
 			statusCancelled: {},
+			statusAccepted:  {},
+			statusCountered: {},
 		}
 		if _, ok := validStatusesBuyer[offer.Status]; !ok {
 			return entity.Offer{}, apperror.New(apperror.BadRequest, "invalid status field value", nil)
 		}
 	}
 
-	offerResp, err := os.offerRepository.UpdateOfferStatus(ctx, offer, userID, isStore)
+	var (
+		offerResp entity.Offer
+		err       error
+	)
+	if offer.Status == statusCountered {
+		if offer.Price <= 0 {
+			return entity.Offer{}, apperror.New(apperror.BadRequest,
+				"price field must be provided for countered status", nil)
+		}
+
+		if !isStore {
+			err = os.checkBuyerCounter(ctx, offer, userID)
+			if err != nil {
+				return entity.Offer{}, err
+			}
+		}
 
-	return offerResp, err
+		offerResp, err = os.offerRepository.CounterOffer(ctx, offer, userID, isStore)
+	} else {
+		offerResp, err = os.offerRepository.UpdateOfferStatus(ctx, offer, userID, isStore)
+	}
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	os.relay.Wake()
+
+	return offerResp, nil
+}
+
+// checkBuyerCounter проверяет встречную цену покупателя по политике магазина так же, как новый оффер
+func (os *Service) checkBuyerCounter(ctx context.Context, counter entity.Offer, userID uint) error {
+	details, err := os.offerRepository.GetOfferByID(ctx, counter.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	details.Price = counter.Price
+	return os.pricing.Check(ctx, details.Offer)
+}
+
+// BulkUpdateOfferStatus принимает или отклоняет магазином несколько офферов за раз.
+// Повторяющиеся ID обрабатываются один раз, отчёт возвращается в порядке запроса.
+func (os *Service) BulkUpdateOfferStatus(
+	ctx context.Context,
+	offerIDs []uint,
+	status string,
+	userID uint,
+	atomic bool,
+) ([]entity.BulkOfferResult, error) {
+	if status != statusAccepted && status != statusDeclined {
+		return nil, apperror.New(apperror.BadRequest, "bulk status must be accepted or declined", nil)
+	}
+
+	uniqueIDs := make([]uint, 0, len(offerIDs))
+	for _, id := range offerIDs {
+		if !slices.Contains(uniqueIDs, id) {
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	results, err := os.offerRepository.BulkUpdateOfferStatus(ctx, uniqueIDs, status, userID, atomic)
+	if err != nil {
+		return nil, err
+	}
+
+	os.relay.Wake()
+
+	return results, nil
+}
+
+// GetOfferHistory возвращает журнал изменений оффера покупателю или магазину-получателю
+func (os *Service) GetOfferHistory(
+	ctx context.Context,
+	offerID uint,
+	userID uint,
+) ([]entity.OfferEvent, error) {
+	return os.offerRepository.SelectOfferEvents(ctx, offerID, userID)
 }
 
 func (os *Service) DeleteOffer(
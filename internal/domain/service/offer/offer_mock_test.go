@@ -0,0 +1,310 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: offer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=offer.go -destination=offer_mock_test.go -package=offer Repository,Relay,Pricing,RateSource
+//
+
+// Package offer is a generated GoMock package.
+package offer
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BulkUpdateOfferStatus mocks base method.
+func (m *MockRepository) BulkUpdateOfferStatus(ctx context.Context, offerIDs []uint, status string, userID uint, atomic bool) ([]entity.BulkOfferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateOfferStatus", ctx, offerIDs, status, userID, atomic)
+	ret0, _ := ret[0].([]entity.BulkOfferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateOfferStatus indicates an expected call of BulkUpdateOfferStatus.
+func (mr *MockRepositoryMockRecorder) BulkUpdateOfferStatus(ctx, offerIDs, status, userID, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateOfferStatus", reflect.TypeOf((*MockRepository)(nil).BulkUpdateOfferStatus), ctx, offerIDs, status, userID, atomic)
+}
+
+// CounterOffer mocks base method.
+func (m *MockRepository) CounterOffer(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CounterOffer", ctx, offer, userID, isStore)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CounterOffer indicates an expected call of CounterOffer.
+func (mr *MockRepositoryMockRecorder) CounterOffer(ctx, offer, userID, isStore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CounterOffer", reflect.TypeOf((*MockRepository)(nil).CounterOffer), ctx, offer, userID, isStore)
+}
+
+// DeleteOffer mocks base method.
+func (m *MockRepository) DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOffer", ctx, offerID)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOffer indicates an expected call of DeleteOffer.
+func (mr *MockRepositoryMockRecorder) DeleteOffer(ctx, offerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOffer", reflect.TypeOf((*MockRepository)(nil).DeleteOffer), ctx, offerID)
+}
+
+// GetAutoResponseRule mocks base method.
+func (m *MockRepository) GetAutoResponseRule(ctx context.Context, shopID, productID uint) (entity.AutoResponseRule, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutoResponseRule", ctx, shopID, productID)
+	ret0, _ := ret[0].(entity.AutoResponseRule)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAutoResponseRule indicates an expected call of GetAutoResponseRule.
+func (mr *MockRepositoryMockRecorder) GetAutoResponseRule(ctx, shopID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoResponseRule", reflect.TypeOf((*MockRepository)(nil).GetAutoResponseRule), ctx, shopID, productID)
+}
+
+// GetOfferByID mocks base method.
+func (m *MockRepository) GetOfferByID(ctx context.Context, offerID, userID uint) (entity.OfferDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOfferByID", ctx, offerID, userID)
+	ret0, _ := ret[0].(entity.OfferDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOfferByID indicates an expected call of GetOfferByID.
+func (mr *MockRepositoryMockRecorder) GetOfferByID(ctx, offerID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOfferByID", reflect.TypeOf((*MockRepository)(nil).GetOfferByID), ctx, offerID, userID)
+}
+
+// InsertOffer mocks base method.
+func (m *MockRepository) InsertOffer(ctx context.Context, offer entity.Offer, quotaSince time.Time, checkQuota func(entity.OfferQuotaUsage) error, autoResponse *entity.AutoResponse, outbox []entity.OutboxMessage) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOffer", ctx, offer, quotaSince, checkQuota, autoResponse, outbox)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOffer indicates an expected call of InsertOffer.
+func (mr *MockRepositoryMockRecorder) InsertOffer(ctx, offer, quotaSince, checkQuota, autoResponse, outbox any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOffer", reflect.TypeOf((*MockRepository)(nil).InsertOffer), ctx, offer, quotaSince, checkQuota, autoResponse, outbox)
+}
+
+// SelectOfferEvents mocks base method.
+func (m *MockRepository) SelectOfferEvents(ctx context.Context, offerID, userID uint) ([]entity.OfferEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOfferEvents", ctx, offerID, userID)
+	ret0, _ := ret[0].([]entity.OfferEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOfferEvents indicates an expected call of SelectOfferEvents.
+func (mr *MockRepositoryMockRecorder) SelectOfferEvents(ctx, offerID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOfferEvents", reflect.TypeOf((*MockRepository)(nil).SelectOfferEvents), ctx, offerID, userID)
+}
+
+// SelectShopOffers mocks base method.
+func (m *MockRepository) SelectShopOffers(ctx context.Context, userID uint, filter entity.ShopOfferFilter, limit, offset int) ([]entity.Offer, int, map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopOffers", ctx, userID, filter, limit, offset)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(map[string]int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// SelectShopOffers indicates an expected call of SelectShopOffers.
+func (mr *MockRepositoryMockRecorder) SelectShopOffers(ctx, userID, filter, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopOffers", reflect.TypeOf((*MockRepository)(nil).SelectShopOffers), ctx, userID, filter, limit, offset)
+}
+
+// SelectUserOffers mocks base method.
+func (m *MockRepository) SelectUserOffers(ctx context.Context, userID uint, limit, offset int) ([]entity.Offer, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserOffers", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectUserOffers indicates an expected call of SelectUserOffers.
+func (mr *MockRepositoryMockRecorder) SelectUserOffers(ctx, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserOffers", reflect.TypeOf((*MockRepository)(nil).SelectUserOffers), ctx, userID, limit, offset)
+}
+
+// UpdateOfferStatus mocks base method.
+func (m *MockRepository) UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOfferStatus", ctx, offer, userID, isStore)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOfferStatus indicates an expected call of UpdateOfferStatus.
+func (mr *MockRepositoryMockRecorder) UpdateOfferStatus(ctx, offer, userID, isStore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOfferStatus", reflect.TypeOf((*MockRepository)(nil).UpdateOfferStatus), ctx, offer, userID, isStore)
+}
+
+// MockRelay is a mock of Relay interface.
+type MockRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayMockRecorder
+	isgomock struct{}
+}
+
+// MockRelayMockRecorder is the mock recorder for MockRelay.
+type MockRelayMockRecorder struct {
+	mock *MockRelay
+}
+
+// NewMockRelay creates a new mock instance.
+func NewMockRelay(ctrl *gomock.Controller) *MockRelay {
+	mock := &MockRelay{ctrl: ctrl}
+	mock.recorder = &MockRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelay) EXPECT() *MockRelayMockRecorder {
+	return m.recorder
+}
+
+// Wake mocks base method.
+func (m *MockRelay) Wake() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Wake")
+}
+
+// Wake indicates an expected call of Wake.
+func (mr *MockRelayMockRecorder) Wake() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wake", reflect.TypeOf((*MockRelay)(nil).Wake))
+}
+
+// MockPricing is a mock of Pricing interface.
+type MockPricing struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingMockRecorder
+	isgomock struct{}
+}
+
+// MockPricingMockRecorder is the mock recorder for MockPricing.
+type MockPricingMockRecorder struct {
+	mock *MockPricing
+}
+
+// NewMockPricing creates a new mock instance.
+func NewMockPricing(ctrl *gomock.Controller) *MockPricing {
+	mock := &MockPricing{ctrl: ctrl}
+	mock.recorder = &MockPricingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricing) EXPECT() *MockPricingMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockPricing) Check(ctx context.Context, offer entity.Offer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, offer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockPricingMockRecorder) Check(ctx, offer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockPricing)(nil).Check), ctx, offer)
+}
+
+// MockRateSource is a mock of RateSource interface.
+type MockRateSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockRateSourceMockRecorder is the mock recorder for MockRateSource.
+type MockRateSourceMockRecorder struct {
+	mock *MockRateSource
+}
+
+// NewMockRateSource creates a new mock instance.
+func NewMockRateSource(ctrl *gomock.Controller) *MockRateSource {
+	mock := &MockRateSource{ctrl: ctrl}
+	mock.recorder = &MockRateSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateSource) EXPECT() *MockRateSourceMockRecorder {
+	return m.recorder
+}
+
+// RateTable mocks base method.
+func (m *MockRateSource) RateTable(ctx context.Context) (entity.RateTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateTable", ctx)
+	ret0, _ := ret[0].(entity.RateTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RateTable indicates an expected call of RateTable.
+func (mr *MockRateSourceMockRecorder) RateTable(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateTable", reflect.TypeOf((*MockRateSource)(nil).RateTable), ctx)
+}
@@ -0,0 +1,300 @@
+package offer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+var _ = Describe("Service", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		relay   *MockRelay
+		pricing *MockPricing
+		rates   *MockRateSource
+		service *Service
+		ctx     context.Context
+		buyer   entity.User
+		newOfr  entity.Offer
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		relay = NewMockRelay(ctrl)
+		pricing = NewMockPricing(ctrl)
+		rates = NewMockRateSource(ctrl)
+		service = NewService(repo, pricing, rates, relay, &config.OfferQuotaConfig{})
+		ctx = context.Background()
+		buyer = entity.User{ID: 2, Name: "buyer", Email: "buyer@example.com"}
+		newOfr = entity.Offer{Price: 60, Currency: "USD", ShopID: 1, ProductID: 3, UserID: 2}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// emails разбирает письма, переданные репозиторию для записи в outbox
+	emails := func(outbox []entity.OutboxMessage) []entity.EmailNotification {
+		notifications := make([]entity.EmailNotification, len(outbox))
+		for i, m := range outbox {
+			Expect(m.Topic).To(Equal(entity.OutboxTopicEmail))
+			Expect(json.Unmarshal(m.Payload, &notifications[i])).To(Succeed())
+		}
+		return notifications
+	}
+
+	Describe("CreateOffer", func() {
+		It("leaves the offer pending when the shop has no rule", func() {
+			pricing.EXPECT().Check(ctx, gomock.Any()).Return(nil)
+			repo.EXPECT().GetAutoResponseRule(ctx, uint(1), uint(3)).Return(entity.AutoResponseRule{}, false, nil)
+			repo.EXPECT().InsertOffer(ctx, gomock.Any(), gomock.Any(), nil, nil, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ entity.Offer, _ time.Time, _ func(entity.OfferQuotaUsage) error,
+					_ *entity.AutoResponse,
+					outbox []entity.OutboxMessage) (uint, error) {
+					Expect(emails(outbox)).To(Equal([]entity.EmailNotification{
+						{Kind: entity.EmailRegistered, To: buyer.Email, Name: buyer.Name},
+					}))
+					return 10, nil
+				})
+			relay.EXPECT().Wake()
+
+			created, err := service.CreateOffer(ctx, newOfr, buyer)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.ID).To(Equal(uint(10)))
+			Expect(created.Status).To(Equal(statusPending))
+		})
+
+		It("applies the shop rule and notifies the buyer", func() {
+			counter := 90.0
+			pricing.EXPECT().Check(ctx, gomock.Any()).Return(nil)
+			repo.EXPECT().GetAutoResponseRule(ctx, uint(1), uint(3)).
+				Return(entity.AutoResponseRule{CounterPrice: &counter}, true, nil)
+			repo.EXPECT().InsertOffer(ctx, gomock.Any(), gomock.Any(), nil,
+				&entity.AutoResponse{Status: statusCountered, CounterPrice: 90}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ entity.Offer, _ time.Time, _ func(entity.OfferQuotaUsage) error,
+					_ *entity.AutoResponse,
+					outbox []entity.OutboxMessage) (uint, error) {
+					Expect(emails(outbox)).To(ContainElement(
+						entity.EmailNotification{Kind: entity.EmailStatusUpdate, To: buyer.Email, Status: statusCountered}))
+					return 11, nil
+				})
+			relay.EXPECT().Wake()
+
+			created, err := service.CreateOffer(ctx, newOfr, buyer)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.Status).To(Equal(statusCountered))
+			Expect(created.Price).To(Equal(90.0))
+		})
+
+		It("leaves the offer pending when the rule does not fire", func() {
+			acceptFrom := 100.0
+			pricing.EXPECT().Check(ctx, gomock.Any()).Return(nil)
+			repo.EXPECT().GetAutoResponseRule(ctx, uint(1), uint(3)).
+				Return(entity.AutoResponseRule{AcceptFrom: &acceptFrom}, true, nil)
+			repo.EXPECT().InsertOffer(ctx, gomock.Any(), gomock.Any(), nil, nil, gomock.Len(1)).Return(uint(12), nil)
+			relay.EXPECT().Wake()
+
+			created, err := service.CreateOffer(ctx, newOfr, buyer)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.Status).To(Equal(statusPending))
+		})
+
+		It("rejects an offer violating the shop pricing policy before storing it", func() {
+			violation := apperror.NewValidationError("offer violates the shop pricing policy",
+				apperror.Violation{Field: "price", Rule: ruleMinPricePercent})
+			pricing.EXPECT().Check(ctx, gomock.Any()).Return(violation)
+
+			_, err := service.CreateOffer(ctx, newOfr, buyer)
+
+			Expect(err).To(MatchError(violation))
+		})
+	})
+
+	Describe("CreateOffer quotas", func() {
+		BeforeEach(func() {
+			service = NewService(repo, pricing, rates, relay, &config.OfferQuotaConfig{
+				MaxOpen:         3,
+				MaxPerHour:      5,
+				DeclineCooldown: 24 * time.Hour,
+			})
+		})
+
+		expectCode := func(err error, code string) {
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(code))
+		}
+
+		// insertWithUsage ждёт создания оффера и передаёт проверке квот usage,
+		// как репозиторий делает это в транзакции создания
+		insertWithUsage := func(usage entity.OfferQuotaUsage) {
+			pricing.EXPECT().Check(ctx, gomock.Any()).Return(nil)
+			repo.EXPECT().GetAutoResponseRule(ctx, uint(1), uint(3)).Return(entity.AutoResponseRule{}, false, nil)
+			repo.EXPECT().InsertOffer(ctx, gomock.Any(), gomock.Any(), gomock.Not(gomock.Nil()), nil, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ entity.Offer, since time.Time,
+					checkQuota func(entity.OfferQuotaUsage) error, _ *entity.AutoResponse,
+					_ []entity.OutboxMessage) (uint, error) {
+					Expect(since).To(BeTemporally("~", time.Now().Add(-time.Hour), time.Second))
+					if err := checkQuota(usage); err != nil {
+						return 0, err
+					}
+					return 10, nil
+				})
+		}
+
+		It("counts new offers within the last hour when creating the offer", func() {
+			insertWithUsage(entity.OfferQuotaUsage{Open: 2, CreatedSince: 4})
+			relay.EXPECT().Wake()
+
+			_, err := service.CreateOffer(ctx, newOfr, buyer)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("rejects the offer over a limit",
+			func(usage entity.OfferQuotaUsage, code string) {
+				insertWithUsage(usage)
+
+				_, err := service.CreateOffer(ctx, newOfr, buyer)
+
+				expectCode(err, code)
+			},
+			Entry("too many open offers", entity.OfferQuotaUsage{Open: 3}, apperror.TooManyRequests),
+			Entry("too many offers this hour", entity.OfferQuotaUsage{CreatedSince: 5}, apperror.TooManyRequests),
+			Entry("declined recently", entity.OfferQuotaUsage{LastDeclinedAt: ptr(time.Now().Add(-time.Hour))},
+				apperror.Conflict),
+		)
+
+		It("allows offering again once the decline cooldown has passed", func() {
+			insertWithUsage(entity.OfferQuotaUsage{LastDeclinedAt: ptr(time.Now().Add(-25 * time.Hour))})
+			relay.EXPECT().Wake()
+
+			_, err := service.CreateOffer(ctx, newOfr, buyer)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("UpdateOfferStatus", func() {
+		It("wakes the relay to deliver the status change", func() {
+			ofr := entity.Offer{ID: 5, Status: statusAccepted}
+			repo.EXPECT().UpdateOfferStatus(ctx, ofr, uint(1), true).Return(ofr, nil)
+			relay.EXPECT().Wake()
+
+			_, err := service.UpdateOfferStatus(ctx, ofr, 1, true)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not wake the relay when the update fails", func() {
+			ofr := entity.Offer{ID: 5, Status: statusCountered, Price: 80}
+			repo.EXPECT().GetOfferByID(ctx, uint(5), uint(2)).Return(entity.OfferDetails{Offer: newOfr}, nil)
+			pricing.EXPECT().Check(ctx, gomock.Any()).Return(nil)
+			repo.EXPECT().CounterOffer(ctx, ofr, uint(2), false).Return(entity.Offer{}, errors.New("conflict"))
+
+			_, err := service.UpdateOfferStatus(ctx, ofr, 2, false)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("checks the buyer's counter price against the shop pricing policy", func() {
+			ofr := entity.Offer{ID: 5, Status: statusCountered, Price: 1}
+			violation := apperror.NewValidationError("offer violates the shop pricing policy")
+			repo.EXPECT().GetOfferByID(ctx, uint(5), uint(2)).
+				Return(entity.OfferDetails{Offer: newOfr, ShopName: "shop"}, nil)
+			pricing.EXPECT().Check(ctx, entity.Offer{
+				Price: 1, Currency: "USD", ShopID: 1, ProductID: 3, UserID: 2,
+			}).Return(violation)
+
+			_, err := service.UpdateOfferStatus(ctx, ofr, 2, false)
+
+			Expect(err).To(MatchError(violation))
+		})
+
+		It("does not check the shop's counter price", func() {
+			ofr := entity.Offer{ID: 5, Status: statusCountered, Price: 1}
+			repo.EXPECT().CounterOffer(ctx, ofr, uint(1), true).Return(ofr, nil)
+			relay.EXPECT().Wake()
+
+			_, err := service.UpdateOfferStatus(ctx, ofr, 1, true)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("GetUserOffers", func() {
+		It("adds prices in the display currency where a rate is known", func() {
+			repo.EXPECT().SelectUserOffers(ctx, uint(2), 10, 0).Return([]entity.Offer{
+				{ID: 1, Price: 110, Currency: "usd"},
+				{ID: 2, Price: 50, Currency: "EUR"},
+				{ID: 3, Price: 900, Currency: "JPY"},
+			}, 3, nil)
+			rates.EXPECT().RateTable(ctx).Return(entity.RateTable{"EUR": 1, "USD": 1.1}, nil)
+
+			offers, _, err := service.GetUserOffers(ctx, 2, 1, 10, "eur")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*offers[0].DisplayPrice).To(Equal(100.0))
+			Expect(offers[0].DisplayCurrency).To(Equal("EUR"))
+			Expect(*offers[1].DisplayPrice).To(Equal(50.0))
+			Expect(offers[2].DisplayPrice).To(BeNil())
+		})
+
+		It("does not load rates without a display currency", func() {
+			repo.EXPECT().SelectUserOffers(ctx, uint(2), 10, 0).
+				Return([]entity.Offer{{ID: 1, Price: 110, Currency: "usd"}}, 1, nil)
+
+			offers, _, err := service.GetUserOffers(ctx, 2, 1, 10, "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(offers[0].DisplayPrice).To(BeNil())
+		})
+	})
+
+	Describe("BulkUpdateOfferStatus", func() {
+		It("passes each offer once, keeping the request order", func() {
+			repo.EXPECT().BulkUpdateOfferStatus(ctx, []uint{3, 1, 2}, statusDeclined, uint(1), true).
+				Return([]entity.BulkOfferResult{}, nil)
+			relay.EXPECT().Wake()
+
+			_, err := service.BulkUpdateOfferStatus(ctx, []uint{3, 1, 3, 2, 1}, statusDeclined, 1, true)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the per-offer report", func() {
+			repo.EXPECT().BulkUpdateOfferStatus(ctx, []uint{1, 2, 3}, statusAccepted, uint(1), false).
+				Return([]entity.BulkOfferResult{
+					{OfferID: 1, Status: statusAccepted},
+					{OfferID: 2, Err: errors.New("conflict")},
+					{OfferID: 3, Status: statusAccepted},
+				}, nil)
+			relay.EXPECT().Wake()
+
+			results, err := service.BulkUpdateOfferStatus(ctx, []uint{1, 2, 3}, statusAccepted, 1, false)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(3))
+		})
+
+		It("allows only accepting or declining", func() {
+			_, err := service.BulkUpdateOfferStatus(ctx, []uint{1}, statusCountered, 1, false)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
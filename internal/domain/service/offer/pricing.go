@@ -0,0 +1,115 @@
+package offer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=pricing_mock_test.go -package=offer PricingRepository,CurrencyConverter
+
+type PricingRepository interface {
+	GetPriceListing(ctx context.Context, shopID, productID uint) (entity.PriceListing, error)
+	SelectPricingPolicy(ctx context.Context, shopID, userID uint) (entity.PricingPolicy, bool, error)
+	UpsertPricingPolicy(ctx context.Context, policy entity.PricingPolicy, userID uint) (entity.PricingPolicy, error)
+}
+
+// CurrencyConverter пересчитывает сумму оффера в валюту позиции инвентаря.
+// Возвращает apperror.ErrCurrencyNotConvertible, если курса для пары валют нет.
+type CurrencyConverter interface {
+	Convert(ctx context.Context, amount float64, from, to string) (float64, error)
+}
+
+// правила политики в ответе с ошибкой проверки
+const (
+	ruleMinPricePercent     = "min_price_percent"
+	ruleCurrencyConvertible = "currency_convertible"
+)
+
+// PricingPolicy проверяет цену оффера относительно цены позиции инвентаря по политике магазина.
+// Магазины без своей политики используют минимум из конфига, 0 отключает проверку цены.
+type PricingPolicy struct {
+	repo              PricingRepository
+	converter         CurrencyConverter
+	defaultMinPercent float64
+}
+
+func NewPricingPolicy(
+	repo PricingRepository,
+	converter CurrencyConverter,
+	cfg *config.OfferPricingConfig,
+) *PricingPolicy {
+	return &PricingPolicy{repo: repo, converter: converter, defaultMinPercent: cfg.DefaultMinPricePercent}
+}
+
+// Check возвращает apperror.ValidationError, если оффер нарушает политику магазина.
+// Цена в другой валюте сравнивается с ценой позиции после пересчёта конвертером.
+func (p *PricingPolicy) Check(ctx context.Context, offer entity.Offer) error {
+	listing, err := p.repo.GetPriceListing(ctx, offer.ShopID, offer.ProductID)
+	if err != nil {
+		return err
+	}
+
+	price, err := p.converter.Convert(ctx, offer.Price, offer.Currency, listing.Currency)
+	if errors.Is(err, apperror.ErrCurrencyNotConvertible) {
+		return apperror.NewValidationError("offer violates the shop pricing policy", apperror.Violation{
+			Field: "currency",
+			Rule:  ruleCurrencyConvertible,
+			Message: fmt.Sprintf("currency %s can't be converted to the listing currency %s",
+				offer.Currency, strings.ToUpper(listing.Currency)),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	minPercent := p.defaultMinPercent
+	if listing.MinPricePercent != nil {
+		minPercent = *listing.MinPricePercent
+	}
+
+	// минимум округляется до копеек, чтобы оффер ровно на границе проходил
+	minPrice := math.Round(listing.Price*minPercent) / 100
+	if price < minPrice {
+		return apperror.NewValidationError("offer violates the shop pricing policy", apperror.Violation{
+			Field: "price",
+			Rule:  ruleMinPricePercent,
+			Message: fmt.Sprintf("price must be at least %.2f %s (%g%% of the list price)",
+				minPrice, strings.ToUpper(listing.Currency), minPercent),
+		})
+	}
+
+	return nil
+}
+
+// GetShopPolicy возвращает политику магазина владельцу, а если её нет - политику по умолчанию
+func (p *PricingPolicy) GetShopPolicy(ctx context.Context, shopID, userID uint) (entity.PricingPolicy, error) {
+	policy, found, err := p.repo.SelectPricingPolicy(ctx, shopID, userID)
+	if err != nil {
+		return entity.PricingPolicy{}, err
+	}
+	if !found {
+		return entity.PricingPolicy{ShopID: shopID, MinPricePercent: p.defaultMinPercent}, nil
+	}
+
+	return policy, nil
+}
+
+func (p *PricingPolicy) SaveShopPolicy(
+	ctx context.Context,
+	policy entity.PricingPolicy,
+	userID uint,
+) (entity.PricingPolicy, error) {
+	if policy.MinPricePercent < 0 || policy.MinPricePercent > 100 {
+		return entity.PricingPolicy{}, apperror.New(apperror.BadRequest,
+			"min_price_percent must be between 0 and 100", nil)
+	}
+
+	return p.repo.UpsertPricingPolicy(ctx, policy, userID)
+}
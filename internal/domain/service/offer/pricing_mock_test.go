@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pricing.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pricing.go -destination=pricing_mock_test.go -package=offer PricingRepository,CurrencyConverter
+//
+
+// Package offer is a generated GoMock package.
+package offer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPricingRepository is a mock of PricingRepository interface.
+type MockPricingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPricingRepositoryMockRecorder is the mock recorder for MockPricingRepository.
+type MockPricingRepositoryMockRecorder struct {
+	mock *MockPricingRepository
+}
+
+// NewMockPricingRepository creates a new mock instance.
+func NewMockPricingRepository(ctrl *gomock.Controller) *MockPricingRepository {
+	mock := &MockPricingRepository{ctrl: ctrl}
+	mock.recorder = &MockPricingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricingRepository) EXPECT() *MockPricingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPriceListing mocks base method.
+func (m *MockPricingRepository) GetPriceListing(ctx context.Context, shopID, productID uint) (entity.PriceListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceListing", ctx, shopID, productID)
+	ret0, _ := ret[0].(entity.PriceListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceListing indicates an expected call of GetPriceListing.
+func (mr *MockPricingRepositoryMockRecorder) GetPriceListing(ctx, shopID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceListing", reflect.TypeOf((*MockPricingRepository)(nil).GetPriceListing), ctx, shopID, productID)
+}
+
+// SelectPricingPolicy mocks base method.
+func (m *MockPricingRepository) SelectPricingPolicy(ctx context.Context, shopID, userID uint) (entity.PricingPolicy, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPricingPolicy", ctx, shopID, userID)
+	ret0, _ := ret[0].(entity.PricingPolicy)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectPricingPolicy indicates an expected call of SelectPricingPolicy.
+func (mr *MockPricingRepositoryMockRecorder) SelectPricingPolicy(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPricingPolicy", reflect.TypeOf((*MockPricingRepository)(nil).SelectPricingPolicy), ctx, shopID, userID)
+}
+
+// UpsertPricingPolicy mocks base method.
+func (m *MockPricingRepository) UpsertPricingPolicy(ctx context.Context, policy entity.PricingPolicy, userID uint) (entity.PricingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPricingPolicy", ctx, policy, userID)
+	ret0, _ := ret[0].(entity.PricingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertPricingPolicy indicates an expected call of UpsertPricingPolicy.
+func (mr *MockPricingRepositoryMockRecorder) UpsertPricingPolicy(ctx, policy, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPricingPolicy", reflect.TypeOf((*MockPricingRepository)(nil).UpsertPricingPolicy), ctx, policy, userID)
+}
+
+// MockCurrencyConverter is a mock of CurrencyConverter interface.
+type MockCurrencyConverter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyConverterMockRecorder
+	isgomock struct{}
+}
+
+// MockCurrencyConverterMockRecorder is the mock recorder for MockCurrencyConverter.
+type MockCurrencyConverterMockRecorder struct {
+	mock *MockCurrencyConverter
+}
+
+// NewMockCurrencyConverter creates a new mock instance.
+func NewMockCurrencyConverter(ctrl *gomock.Controller) *MockCurrencyConverter {
+	mock := &MockCurrencyConverter{ctrl: ctrl}
+	mock.recorder = &MockCurrencyConverterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyConverter) EXPECT() *MockCurrencyConverterMockRecorder {
+	return m.recorder
+}
+
+// Convert mocks base method.
+func (m *MockCurrencyConverter) Convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Convert", ctx, amount, from, to)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Convert indicates an expected call of Convert.
+func (mr *MockCurrencyConverterMockRecorder) Convert(ctx, amount, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Convert", reflect.TypeOf((*MockCurrencyConverter)(nil).Convert), ctx, amount, from, to)
+}
@@ -0,0 +1,138 @@
+package offer
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+var _ = Describe("PricingPolicy", func() {
+	var (
+		ctrl      *gomock.Controller
+		repo      *MockPricingRepository
+		converter *MockCurrencyConverter
+		policy    *PricingPolicy
+		ctx       context.Context
+		ofr       entity.Offer
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockPricingRepository(ctrl)
+		converter = NewMockCurrencyConverter(ctrl)
+		policy = NewPricingPolicy(repo, converter, &config.OfferPricingConfig{DefaultMinPricePercent: 30})
+		ctx = context.Background()
+		ofr = entity.Offer{Price: 30, Currency: "USD", ShopID: 1, ProductID: 3}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// violations достаёт нарушенные правила из ошибки проверки
+	violations := func(err error) []apperror.Violation {
+		var validationErr *apperror.ValidationError
+		Expect(errors.As(err, &validationErr)).To(BeTrue())
+		Expect(validationErr.Code()).To(Equal(apperror.BadRequest))
+		return validationErr.Violations
+	}
+
+	Describe("Check", func() {
+		DescribeTable("compares the price with the minimal share of the list price",
+			func(price float64, shopPercent *float64, violated bool) {
+				ofr.Price = price
+				repo.EXPECT().GetPriceListing(ctx, uint(1), uint(3)).
+					Return(entity.PriceListing{Price: 100, Currency: "usd", MinPricePercent: shopPercent}, nil)
+				converter.EXPECT().Convert(ctx, price, "USD", "usd").Return(price, nil)
+
+				err := policy.Check(ctx, ofr)
+
+				if !violated {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+				Expect(violations(err)).To(ConsistOf(HaveField("Rule", ruleMinPricePercent)))
+			},
+			Entry("at the default minimum", 30.0, nil, false),
+			Entry("below the default minimum", 29.99, nil, true),
+			Entry("above the shop's own minimum", 50.0, ptr(50.0), false),
+			Entry("below the shop's own minimum", 49.0, ptr(50.0), true),
+			Entry("with the check disabled by the shop", 0.01, ptr(0.0), false),
+		)
+
+		It("compares a converted price in another currency", func() {
+			ofr.Currency = "EUR"
+			repo.EXPECT().GetPriceListing(ctx, uint(1), uint(3)).
+				Return(entity.PriceListing{Price: 100, Currency: "USD"}, nil)
+			converter.EXPECT().Convert(ctx, 30.0, "EUR", "USD").Return(25.0, nil)
+
+			err := policy.Check(ctx, ofr)
+
+			Expect(violations(err)).To(ConsistOf(apperror.Violation{
+				Field:   "price",
+				Rule:    ruleMinPricePercent,
+				Message: "price must be at least 30.00 USD (30% of the list price)",
+			}))
+		})
+
+		It("rejects a currency that can't be converted", func() {
+			ofr.Currency = "EUR"
+			repo.EXPECT().GetPriceListing(ctx, uint(1), uint(3)).
+				Return(entity.PriceListing{Price: 100, Currency: "USD"}, nil)
+			converter.EXPECT().Convert(ctx, 30.0, "EUR", "USD").Return(0.0, apperror.ErrCurrencyNotConvertible)
+
+			err := policy.Check(ctx, ofr)
+
+			Expect(violations(err)).To(ConsistOf(HaveField("Field", "currency")))
+		})
+
+		It("returns the error when the product is not in the shop inventory", func() {
+			repo.EXPECT().GetPriceListing(ctx, uint(1), uint(3)).
+				Return(entity.PriceListing{}, apperror.ErrProductNotFound)
+
+			err := policy.Check(ctx, ofr)
+
+			Expect(err).To(MatchError(apperror.ErrProductNotFound))
+		})
+	})
+
+	Describe("GetShopPolicy", func() {
+		It("returns the default policy when the shop has none", func() {
+			repo.EXPECT().SelectPricingPolicy(ctx, uint(1), uint(7)).Return(entity.PricingPolicy{}, false, nil)
+
+			got, err := policy.GetShopPolicy(ctx, 1, 7)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(entity.PricingPolicy{ShopID: 1, MinPricePercent: 30}))
+		})
+	})
+
+	Describe("SaveShopPolicy", func() {
+		It("rejects a percentage over 100", func() {
+			_, err := policy.SaveShopPolicy(ctx, entity.PricingPolicy{ShopID: 1, MinPricePercent: 101}, 7)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("saves a valid policy", func() {
+			saved := entity.PricingPolicy{ShopID: 1, MinPricePercent: 60}
+			repo.EXPECT().UpsertPricingPolicy(ctx, saved, uint(7)).Return(saved, nil)
+
+			got, err := policy.SaveShopPolicy(ctx, saved, 7)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(saved))
+		})
+	})
+})
+
+func ptr[T any](v T) *T {
+	return &v
+}
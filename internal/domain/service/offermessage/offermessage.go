@@ -0,0 +1,67 @@
+package offermessage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=offermessage_mock_test.go -package=offermessage Repository,Relay
+
+type Repository interface {
+	InsertMessage(ctx context.Context, message entity.OfferMessage) (entity.OfferMessage, error)
+	SelectMessages(
+		ctx context.Context,
+		offerID, userID uint,
+		limit, offset int,
+	) (messages []entity.OfferMessage, total, unread int, err error)
+	SelectUnreadCounts(ctx context.Context, userID uint) ([]entity.OfferUnread, error)
+}
+
+// Relay будит релей outbox, чтобы письмо о новом сообщении ушло без ожидания тика
+type Relay interface {
+	Wake()
+}
+
+// Service ведёт переписку покупателя и владельца магазина по офферу
+type Service struct {
+	repo  Repository
+	relay Relay
+}
+
+func NewService(repo Repository, relay Relay) *Service {
+	return &Service{repo: repo, relay: relay}
+}
+
+// SendMessage добавляет сообщение в переписку. Пробелы по краям текста отбрасываются.
+func (s *Service) SendMessage(ctx context.Context, message entity.OfferMessage) (entity.OfferMessage, error) {
+	message.Body = strings.TrimSpace(message.Body)
+	if message.Body == "" {
+		return entity.OfferMessage{}, apperror.New(apperror.BadRequest, "message must not be blank", nil)
+	}
+
+	sent, err := s.repo.InsertMessage(ctx, message)
+	if err != nil {
+		return entity.OfferMessage{}, err
+	}
+
+	s.relay.Wake()
+
+	return sent, nil
+}
+
+// GetThread возвращает страницу переписки и помечает сообщения второй стороны на ней прочитанными
+func (s *Service) GetThread(
+	ctx context.Context,
+	offerID, userID uint,
+	page, limit int,
+) (messages []entity.OfferMessage, total, unread int, err error) {
+	offset := (page - 1) * limit
+	return s.repo.SelectMessages(ctx, offerID, userID, limit, offset)
+}
+
+func (s *Service) GetUnreadCounts(ctx context.Context, userID uint) ([]entity.OfferUnread, error) {
+	return s.repo.SelectUnreadCounts(ctx, userID)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: offermessage.go
+//
+// Generated by this command:
+//
+//	mockgen -source=offermessage.go -destination=offermessage_mock_test.go -package=offermessage Repository,Relay
+//
+
+// Package offermessage is a generated GoMock package.
+package offermessage
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// InsertMessage mocks base method.
+func (m *MockRepository) InsertMessage(ctx context.Context, message entity.OfferMessage) (entity.OfferMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMessage", ctx, message)
+	ret0, _ := ret[0].(entity.OfferMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMessage indicates an expected call of InsertMessage.
+func (mr *MockRepositoryMockRecorder) InsertMessage(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMessage", reflect.TypeOf((*MockRepository)(nil).InsertMessage), ctx, message)
+}
+
+// SelectMessages mocks base method.
+func (m *MockRepository) SelectMessages(ctx context.Context, offerID, userID uint, limit, offset int) ([]entity.OfferMessage, int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectMessages", ctx, offerID, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.OfferMessage)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// SelectMessages indicates an expected call of SelectMessages.
+func (mr *MockRepositoryMockRecorder) SelectMessages(ctx, offerID, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectMessages", reflect.TypeOf((*MockRepository)(nil).SelectMessages), ctx, offerID, userID, limit, offset)
+}
+
+// SelectUnreadCounts mocks base method.
+func (m *MockRepository) SelectUnreadCounts(ctx context.Context, userID uint) ([]entity.OfferUnread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUnreadCounts", ctx, userID)
+	ret0, _ := ret[0].([]entity.OfferUnread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectUnreadCounts indicates an expected call of SelectUnreadCounts.
+func (mr *MockRepositoryMockRecorder) SelectUnreadCounts(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUnreadCounts", reflect.TypeOf((*MockRepository)(nil).SelectUnreadCounts), ctx, userID)
+}
+
+// MockRelay is a mock of Relay interface.
+type MockRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayMockRecorder
+	isgomock struct{}
+}
+
+// MockRelayMockRecorder is the mock recorder for MockRelay.
+type MockRelayMockRecorder struct {
+	mock *MockRelay
+}
+
+// NewMockRelay creates a new mock instance.
+func NewMockRelay(ctrl *gomock.Controller) *MockRelay {
+	mock := &MockRelay{ctrl: ctrl}
+	mock.recorder = &MockRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelay) EXPECT() *MockRelayMockRecorder {
+	return m.recorder
+}
+
+// Wake mocks base method.
+func (m *MockRelay) Wake() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Wake")
+}
+
+// Wake indicates an expected call of Wake.
+func (mr *MockRelayMockRecorder) Wake() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wake", reflect.TypeOf((*MockRelay)(nil).Wake))
+}
@@ -0,0 +1,85 @@
+package offermessage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestOfferMessageServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Offer Message Service Suite")
+}
+
+var _ = Describe("OfferMessageService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		relay   *MockRelay
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		relay = NewMockRelay(ctrl)
+		service = NewService(repo, relay)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("SendMessage", func() {
+		It("saves the trimmed message and wakes the relay to send the email", func() {
+			repo.EXPECT().InsertMessage(ctx, entity.OfferMessage{OfferID: 1, SenderID: 2, Body: "with delivery?"}).
+				Return(entity.OfferMessage{ID: 5, OfferID: 1, SenderID: 2, SenderRole: "buyer", Body: "with delivery?"}, nil)
+			relay.EXPECT().Wake()
+
+			sent, err := service.SendMessage(ctx, entity.OfferMessage{OfferID: 1, SenderID: 2, Body: "  with delivery?\n"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sent.ID).To(Equal(uint(5)))
+		})
+
+		It("rejects a blank message without saving it", func() {
+			_, err := service.SendMessage(ctx, entity.OfferMessage{OfferID: 1, SenderID: 2, Body: " \t "})
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("does not wake the relay when the thread is read-only", func() {
+			readOnly := apperror.New(apperror.Conflict, "read-only", nil)
+			repo.EXPECT().InsertMessage(ctx, gomock.Any()).Return(entity.OfferMessage{}, readOnly)
+
+			_, err := service.SendMessage(ctx, entity.OfferMessage{OfferID: 1, SenderID: 2, Body: "hi"})
+
+			Expect(err).To(MatchError(readOnly))
+		})
+	})
+
+	Describe("GetThread", func() {
+		It("converts the page into an offset", func() {
+			repo.EXPECT().SelectMessages(ctx, uint(1), uint(2), 20, 40).
+				Return([]entity.OfferMessage{{ID: 41}}, 41, 1, nil)
+
+			messages, total, unread, err := service.GetThread(ctx, 1, 2, 3, 20)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(total).To(Equal(41))
+			Expect(unread).To(Equal(1))
+		})
+	})
+})
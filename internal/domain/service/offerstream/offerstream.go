@@ -0,0 +1,163 @@
+package offerstream
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=offerstream_mock_test.go -package=offerstream Repository,Broadcaster
+
+type Repository interface {
+	SelectOfferUpdates(ctx context.Context, offerIDs []uint) ([]entity.OfferUpdate, error)
+}
+
+// Broadcaster рассылает событие всем экземплярам приложения, включая текущий.
+// Каждый экземпляр доставляет его своим подписчикам через Dispatch.
+type Broadcaster interface {
+	Broadcast(ctx context.Context, update entity.OfferUpdate) error
+}
+
+// размер буфера подписки. События для подписчика, который не успевает их читать, отбрасываются.
+const subscriptionBuffer = 16
+
+type subscription struct {
+	userID  uint
+	updates chan entity.OfferUpdate
+}
+
+// Bus шина событий жизненного цикла офферов. Сервисы не публикуют в неё напрямую: событие
+// пишется в outbox в транзакции, меняющей оффер, и попадает в шину через outbox.OfferEventSink
+// только после коммита, а при сбое доставка повторяется. Подписчики (SSE-соединения) получают
+// события своих офферов как покупатель или как владелец магазина.
+type Bus struct {
+	repo        Repository
+	broadcaster Broadcaster
+	log         *zap.Logger
+
+	mu     sync.RWMutex
+	subs   map[uint]map[*subscription]struct{}
+	closed bool
+}
+
+// NewBus создаёт шину. Без broadcaster события доставляются только подписчикам этого экземпляра.
+func NewBus(repo Repository, broadcaster Broadcaster, log *zap.Logger) *Bus {
+	return &Bus{
+		repo:        repo,
+		broadcaster: broadcaster,
+		log:         log,
+		subs:        make(map[uint]map[*subscription]struct{}),
+	}
+}
+
+// Publish рассылает событие оффера. Статус и цена берутся из события, а покупатель, магазин
+// и валюта - из оффера. Ошибка возвращается, только если оффер не удалось загрузить:
+// тогда событие никому не доставлено, и relay outbox повторит его позже.
+func (b *Bus) Publish(ctx context.Context, update entity.OfferUpdate) error {
+	offers, err := b.repo.SelectOfferUpdates(ctx, []uint{update.OfferID})
+	if err != nil {
+		return err
+	}
+	if len(offers) == 0 {
+		return nil
+	}
+
+	update.Currency = offers[0].Currency
+	update.BuyerID = offers[0].BuyerID
+	update.ShopID = offers[0].ShopID
+	update.ShopOwnerID = offers[0].ShopOwnerID
+
+	if b.broadcaster == nil {
+		b.Dispatch(update)
+		return nil
+	}
+
+	if err := b.broadcaster.Broadcast(ctx, update); err != nil {
+		// другие экземпляры событие не получат, но свои подписчики получат
+		b.log.Error("failed to broadcast offer update", zap.Uint("offer id", update.OfferID), zap.Error(err))
+		b.Dispatch(update)
+	}
+
+	return nil
+}
+
+// Dispatch доставляет событие подписчикам этого экземпляра: покупателю и владельцу магазина
+func (b *Bus) Dispatch(update entity.OfferUpdate) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	b.deliver(update.BuyerID, update)
+	if update.ShopOwnerID != update.BuyerID {
+		b.deliver(update.ShopOwnerID, update)
+	}
+}
+
+func (b *Bus) deliver(userID uint, update entity.OfferUpdate) {
+	for sub := range b.subs[userID] {
+		select {
+		case sub.updates <- update:
+		default:
+			b.log.Warn("offer update subscriber is too slow, dropping event",
+				zap.Uint("user id", userID), zap.Uint("offer id", update.OfferID))
+		}
+	}
+}
+
+// Subscribe подписывает пользователя на события его офферов. Канал закрывается
+// вызовом unsubscribe, который нужно выполнить, когда события больше не нужны.
+func (b *Bus) Subscribe(userID uint) (<-chan entity.OfferUpdate, func()) {
+	sub := &subscription{
+		userID:  userID,
+		updates: make(chan entity.OfferUpdate, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(sub.updates)
+		return sub.updates, func() {}
+	}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.updates, func() {
+		once.Do(func() { b.unsubscribe(sub) })
+	}
+}
+
+func (b *Bus) unsubscribe(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub.userID][sub]; !ok {
+		return
+	}
+
+	delete(b.subs[sub.userID], sub)
+	if len(b.subs[sub.userID]) == 0 {
+		delete(b.subs, sub.userID)
+	}
+	close(sub.updates)
+}
+
+// Stop закрывает каналы всех подписок, чтобы долгоживущие соединения завершились
+// до остановки HTTP-сервера. Новые подписки после этого сразу закрыты.
+func (b *Bus) Stop(_ context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for userID, subs := range b.subs {
+		for sub := range subs {
+			close(sub.updates)
+		}
+		delete(b.subs, userID)
+	}
+}
@@ -0,0 +1,184 @@
+package offerstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestOfferStreamSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Offer Stream Suite")
+}
+
+var _ = Describe("Bus", func() {
+	var (
+		ctrl        *gomock.Controller
+		repo        *MockRepository
+		broadcaster *MockBroadcaster
+		ctx         context.Context
+		update      entity.OfferUpdate
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		broadcaster = NewMockBroadcaster(ctrl)
+		ctx = context.Background()
+		update = entity.OfferUpdate{OfferID: 7, Status: "accepted", BuyerID: 2, ShopID: 1, ShopOwnerID: 1}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("Dispatch", func() {
+		It("delivers the event to the buyer and the shop owner only", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			buyer, unsubscribeBuyer := bus.Subscribe(2)
+			defer unsubscribeBuyer()
+			owner, unsubscribeOwner := bus.Subscribe(1)
+			defer unsubscribeOwner()
+			stranger, unsubscribeStranger := bus.Subscribe(3)
+			defer unsubscribeStranger()
+
+			bus.Dispatch(update)
+
+			Expect(buyer).To(Receive(Equal(update)))
+			Expect(owner).To(Receive(Equal(update)))
+			Expect(stranger).NotTo(Receive())
+		})
+
+		It("drops events for a subscriber that does not read them", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(2)
+			defer unsubscribe()
+
+			for range subscriptionBuffer + 1 {
+				bus.Dispatch(update)
+			}
+
+			Expect(updates).To(HaveLen(subscriptionBuffer))
+		})
+
+		It("closes the channel on unsubscribe", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(2)
+
+			unsubscribe()
+			unsubscribe()
+			bus.Dispatch(update)
+
+			Expect(updates).To(BeClosed())
+		})
+	})
+
+	Describe("Stop", func() {
+		It("closes existing and new subscriptions", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			before, unsubscribe := bus.Subscribe(2)
+
+			bus.Stop(ctx)
+			unsubscribe()
+			after, _ := bus.Subscribe(2)
+
+			Expect(before).To(BeClosed())
+			Expect(after).To(BeClosed())
+		})
+	})
+
+	Describe("Publish", func() {
+		var event entity.OfferUpdate
+
+		BeforeEach(func() {
+			event = entity.OfferUpdate{
+				Type:       entity.OfferUpdateStatusChanged,
+				EventID:    40,
+				OfferID:    7,
+				Status:     "countered",
+				Price:      90,
+				OccurredAt: time.Now(),
+			}
+		})
+
+		It("loads the offer and dispatches locally without a broadcaster", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(2)
+			defer unsubscribe()
+			repo.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return([]entity.OfferUpdate{update}, nil)
+
+			Expect(bus.Publish(ctx, event)).To(Succeed())
+
+			var received entity.OfferUpdate
+			Expect(updates).To(Receive(&received))
+			Expect(received.Type).To(Equal(entity.OfferUpdateStatusChanged))
+			Expect(received.EventID).To(Equal(uint(40)))
+			Expect(received.OccurredAt).To(Equal(event.OccurredAt))
+			Expect(received.ShopOwnerID).To(Equal(uint(1)))
+		})
+
+		It("reports the state after the event, not the current state of the offer", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(2)
+			defer unsubscribe()
+			repo.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return([]entity.OfferUpdate{update}, nil)
+
+			Expect(bus.Publish(ctx, event)).To(Succeed())
+
+			var received entity.OfferUpdate
+			Expect(updates).To(Receive(&received))
+			Expect(received.Status).To(Equal("countered"))
+			Expect(received.Price).To(Equal(90.0))
+		})
+
+		It("skips an offer that no longer exists", func() {
+			bus := NewBus(repo, nil, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(2)
+			defer unsubscribe()
+			repo.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return(nil, nil)
+
+			Expect(bus.Publish(ctx, event)).To(Succeed())
+
+			Expect(updates).NotTo(Receive())
+		})
+
+		It("leaves delivery to the broadcaster when there is one", func() {
+			bus := NewBus(repo, broadcaster, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(2)
+			defer unsubscribe()
+			repo.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return([]entity.OfferUpdate{update}, nil)
+			broadcaster.EXPECT().Broadcast(ctx, gomock.Any()).Return(nil)
+
+			Expect(bus.Publish(ctx, event)).To(Succeed())
+
+			Expect(updates).NotTo(Receive())
+		})
+
+		It("falls back to local delivery when broadcasting fails", func() {
+			bus := NewBus(repo, broadcaster, zap.NewNop())
+			updates, unsubscribe := bus.Subscribe(1)
+			defer unsubscribe()
+			repo.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return([]entity.OfferUpdate{update}, nil)
+			broadcaster.EXPECT().Broadcast(ctx, gomock.Any()).Return(errors.New("connection refused"))
+
+			Expect(bus.Publish(ctx, event)).To(Succeed())
+
+			Expect(updates).To(Receive())
+		})
+
+		It("returns the error when the offers cannot be loaded", func() {
+			bus := NewBus(repo, broadcaster, zap.NewNop())
+			repo.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return(nil, errors.New("db is down"))
+
+			Expect(bus.Publish(ctx, event)).To(MatchError("db is down"))
+		})
+	})
+})
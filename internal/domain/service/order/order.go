@@ -0,0 +1,100 @@
+package order
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=order_mock_test.go -package=order Repository
+
+type Repository interface {
+	GetOrderByID(ctx context.Context, orderID, userID uint) (entity.Order, error)
+	SelectUserOrders(ctx context.Context, userID uint, limit, offset int) ([]entity.Order, int, error)
+	SelectShopOrders(ctx context.Context, userID uint, limit, offset int) ([]entity.Order, int, error)
+	UpdateOrderStatus(
+		ctx context.Context,
+		orderID, userID uint,
+		isStore bool,
+		status string,
+		fromStatuses []string,
+	) (entity.Order, error)
+}
+
+const (
+	StatusAwaitingPayment = "awaiting_payment"
+	StatusPaid            = "paid"
+	StatusShipped         = "shipped"
+	StatusCompleted       = "completed"
+	StatusCancelled       = "cancelled"
+)
+
+// Допустимые переходы заказа: целевой статус -> статусы, из которых в него можно перейти.
+// Магазин подтверждает оплату, получив деньги, и отправляет заказ, покупатель подтверждает получение.
+// Отменить неоплаченный заказ могут обе стороны, оплаченный - только магазин.
+var (
+	buyerTransitions = map[string][]string{
+		StatusCompleted: {StatusShipped},
+		StatusCancelled: {StatusAwaitingPayment},
+	}
+	shopTransitions = map[string][]string{
+		StatusPaid:      {StatusAwaitingPayment},
+		StatusShipped:   {StatusPaid},
+		StatusCancelled: {StatusAwaitingPayment, StatusPaid},
+	}
+)
+
+type Service struct {
+	orderRepository Repository
+}
+
+func NewService(orderRepository Repository) *Service {
+	return &Service{orderRepository: orderRepository}
+}
+
+func (s *Service) GetOrder(ctx context.Context, orderID, userID uint) (entity.Order, error) {
+	return s.orderRepository.GetOrderByID(ctx, orderID, userID)
+}
+
+func (s *Service) GetUserOrders(
+	ctx context.Context,
+	userID uint,
+	page,
+	limit int,
+) ([]entity.Order, int, error) {
+	offset := (page - 1) * limit
+
+	return s.orderRepository.SelectUserOrders(ctx, userID, limit, offset)
+}
+
+func (s *Service) GetShopOrders(
+	ctx context.Context,
+	userID uint,
+	page,
+	limit int,
+) ([]entity.Order, int, error) {
+	offset := (page - 1) * limit
+
+	return s.orderRepository.SelectShopOrders(ctx, userID, limit, offset)
+}
+
+// UpdateOrderStatus двигает заказ по жизненному циклу от имени покупателя или магазина
+func (s *Service) UpdateOrderStatus(
+	ctx context.Context,
+	orderID, userID uint,
+	isStore bool,
+	status string,
+) (entity.Order, error) {
+	transitions := buyerTransitions
+	if isStore {
+		transitions = shopTransitions
+	}
+
+	fromStatuses, ok := transitions[status]
+	if !ok {
+		return entity.Order{}, apperror.New(apperror.BadRequest, "invalid status field value", nil)
+	}
+
+	return s.orderRepository.UpdateOrderStatus(ctx, orderID, userID, isStore, status, fromStatuses)
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: order.go
+//
+// Generated by this command:
+//
+//	mockgen -source=order.go -destination=order_mock_test.go -package=order Repository
+//
+
+// Package order is a generated GoMock package.
+package order
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetOrderByID mocks base method.
+func (m *MockRepository) GetOrderByID(ctx context.Context, orderID, userID uint) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByID", ctx, orderID, userID)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockRepositoryMockRecorder) GetOrderByID(ctx, orderID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockRepository)(nil).GetOrderByID), ctx, orderID, userID)
+}
+
+// SelectShopOrders mocks base method.
+func (m *MockRepository) SelectShopOrders(ctx context.Context, userID uint, limit, offset int) ([]entity.Order, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopOrders", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectShopOrders indicates an expected call of SelectShopOrders.
+func (mr *MockRepositoryMockRecorder) SelectShopOrders(ctx, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopOrders", reflect.TypeOf((*MockRepository)(nil).SelectShopOrders), ctx, userID, limit, offset)
+}
+
+// SelectUserOrders mocks base method.
+func (m *MockRepository) SelectUserOrders(ctx context.Context, userID uint, limit, offset int) ([]entity.Order, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserOrders", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectUserOrders indicates an expected call of SelectUserOrders.
+func (mr *MockRepositoryMockRecorder) SelectUserOrders(ctx, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserOrders", reflect.TypeOf((*MockRepository)(nil).SelectUserOrders), ctx, userID, limit, offset)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockRepository) UpdateOrderStatus(ctx context.Context, orderID, userID uint, isStore bool, status string, fromStatuses []string) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, orderID, userID, isStore, status, fromStatuses)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockRepositoryMockRecorder) UpdateOrderStatus(ctx, orderID, userID, isStore, status, fromStatuses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockRepository)(nil).UpdateOrderStatus), ctx, orderID, userID, isStore, status, fromStatuses)
+}
@@ -0,0 +1,82 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestOrderServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Order Service Suite")
+}
+
+var _ = Describe("OrderService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		service = NewService(repo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("UpdateOrderStatus", func() {
+		DescribeTable("passes the allowed source statuses to the repository",
+			func(isStore bool, status string, from []string) {
+				repo.EXPECT().UpdateOrderStatus(ctx, uint(1), uint(2), isStore, status, from).
+					Return(entity.Order{ID: 1, Status: status}, nil)
+
+				order, err := service.UpdateOrderStatus(ctx, 1, 2, isStore, status)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(order.Status).To(Equal(status))
+			},
+			Entry("buyer confirms delivery", false, StatusCompleted, []string{StatusShipped}),
+			Entry("buyer cancels an unpaid order", false, StatusCancelled, []string{StatusAwaitingPayment}),
+			Entry("shop confirms payment", true, StatusPaid, []string{StatusAwaitingPayment}),
+			Entry("shop ships", true, StatusShipped, []string{StatusPaid}),
+			Entry("shop cancels", true, StatusCancelled, []string{StatusAwaitingPayment, StatusPaid}),
+		)
+
+		DescribeTable("rejects transitions the role may not perform",
+			func(isStore bool, status string) {
+				_, err := service.UpdateOrderStatus(ctx, 1, 2, isStore, status)
+
+				var appErr *apperror.Error
+				Expect(err).To(BeAssignableToTypeOf(appErr))
+				Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("buyer ships", false, StatusShipped),
+			Entry("buyer marks as paid", false, StatusPaid),
+			Entry("shop completes", true, StatusCompleted),
+			Entry("anyone resets to awaiting payment", false, StatusAwaitingPayment),
+			Entry("unknown status", true, "lost"),
+		)
+	})
+
+	Describe("GetUserOrders", func() {
+		It("converts the page into an offset", func() {
+			repo.EXPECT().SelectUserOrders(ctx, uint(2), 10, 20).Return([]entity.Order{}, 0, nil)
+
+			_, _, err := service.GetUserOrders(ctx, 2, 3, 10)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
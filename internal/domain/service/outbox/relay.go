@@ -0,0 +1,151 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/worker"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=relay_mock_test.go -package=outbox Repository,Sink
+
+type Repository interface {
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.OutboxMessage, error)
+	MarkSinkDone(ctx context.Context, messageID uint, sink string) error
+	SaveResult(ctx context.Context, result entity.OutboxResult, now time.Time) error
+}
+
+// Sink получатель сообщений одного топика. Сообщение может прийти повторно,
+// поэтому обработка должна это допускать.
+type Sink interface {
+	Handle(ctx context.Context, message entity.OutboxMessage) error
+}
+
+const (
+	// на это время взятое сообщение скрыто от других экземпляров
+	claimLease = 2 * time.Minute
+	// максимальная задержка между попытками, до которой растёт экспоненциальная
+	maxRetryBackoff = time.Hour
+)
+
+type namedSink struct {
+	name string
+	sink Sink
+}
+
+// Relay доставляет сообщения outbox зарегистрированным получателям с гарантией at-least-once.
+// Сообщение считается обработанным, когда его обработали все получатели топика; при
+// частичной ошибке повторяется доставка только тем, кто её не обработал.
+// Wake запускает внеочередной проход, чтобы только что записанные сообщения
+// были доставлены без ожидания следующего опроса.
+type Relay struct {
+	*worker.Runner
+
+	repo         Repository
+	sinks        map[string][]namedSink
+	batchSize    int
+	maxAttempts  int
+	retryBackoff time.Duration
+	log          *zap.Logger
+}
+
+func NewRelay(repo Repository, cfg *config.OutboxConfig, log *zap.Logger) *Relay {
+	r := &Relay{
+		repo:         repo,
+		sinks:        make(map[string][]namedSink),
+		batchSize:    cfg.BatchSize,
+		maxAttempts:  cfg.MaxAttempts,
+		retryBackoff: cfg.RetryBackoff,
+		log:          log,
+	}
+	r.Runner = worker.NewRunner("outbox relay", cfg.PollInterval, func(ctx context.Context) error {
+		_, err := r.RelayDue(ctx)
+		return err
+	}, log)
+	return r
+}
+
+// Register подписывает получателя name на сообщения топика. Вызывается до Start;
+// имя сохраняется в done_sinks, поэтому его нельзя менять между релизами.
+func (r *Relay) Register(topic, name string, sink Sink) {
+	r.sinks[topic] = append(r.sinks[topic], namedSink{name: name, sink: sink})
+}
+
+// RelayDue обрабатывает наступившие сообщения пакетами, пока они не закончатся,
+// и возвращает количество обработанных. Сообщения пакета обрабатываются по порядку,
+// чтобы события одного оффера доходили в том порядке, в котором произошли.
+func (r *Relay) RelayDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		now := time.Now()
+		messages, err := r.repo.ClaimDue(ctx, now, now.Add(claimLease), r.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, message := range messages {
+			r.process(ctx, message)
+		}
+		total += len(messages)
+
+		if len(messages) < r.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+func (r *Relay) process(ctx context.Context, message entity.OutboxMessage) {
+	// итог записывается и при остановке релея, иначе сообщение будет доставлено повторно
+	saveCtx := context.WithoutCancel(ctx)
+
+	sinks := r.sinks[message.Topic]
+	errs := make([]error, 0, len(sinks))
+	if len(sinks) == 0 {
+		errs = append(errs, fmt.Errorf("no sinks registered for topic %q", message.Topic))
+	}
+
+	for _, s := range sinks {
+		if slices.Contains(message.DoneSinks, s.name) {
+			continue
+		}
+
+		if err := s.sink.Handle(ctx, message); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+
+		if len(sinks) > 1 {
+			err := r.repo.MarkSinkDone(saveCtx, message.ID, s.name)
+			if err != nil {
+				r.log.Error("failed to mark outbox sink as done",
+					zap.Uint("message id", message.ID), zap.String("sink", s.name), zap.Error(err))
+			}
+		}
+	}
+
+	result := entity.OutboxResult{MessageID: message.ID, Status: entity.OutboxProcessed}
+	if err := errors.Join(errs...); err != nil {
+		result.Error = err.Error()
+		result.Status = entity.OutboxPending
+		result.NextAttemptAt = time.Now().Add(worker.Backoff(r.retryBackoff, maxRetryBackoff, message.Attempts))
+		if message.Attempts+1 >= r.maxAttempts {
+			result.Status = entity.OutboxFailed
+			r.log.Error("outbox message failed after all attempts",
+				zap.Uint("message id", message.ID), zap.String("topic", message.Topic), zap.Error(err))
+		}
+	}
+
+	err := r.repo.SaveResult(saveCtx, result, time.Now())
+	if err != nil {
+		r.log.Error("failed to save outbox message result", zap.Uint("message id", message.ID), zap.Error(err))
+	}
+}
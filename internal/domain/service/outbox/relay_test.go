@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestOutboxSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Outbox Suite")
+}
+
+var _ = Describe("Relay", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		stream  *MockSink
+		mail    *MockSink
+		relay   *Relay
+		ctx     context.Context
+		message entity.OutboxMessage
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		stream = NewMockSink(ctrl)
+		mail = NewMockSink(ctrl)
+		relay = NewRelay(repo, &config.OutboxConfig{
+			PollInterval: time.Hour,
+			BatchSize:    2,
+			MaxAttempts:  3,
+			RetryBackoff: time.Minute,
+		}, zap.NewNop())
+		ctx = context.Background()
+		message = entity.OutboxMessage{ID: 4, Topic: entity.OutboxTopicOfferEvent, AggregateID: 7}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectClaim := func(messages ...entity.OutboxMessage) {
+		repo.EXPECT().ClaimDue(ctx, gomock.Any(), gomock.Any(), 2).Return(messages, nil)
+	}
+
+	expectResult := func(check func(entity.OutboxResult)) {
+		repo.EXPECT().SaveResult(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, result entity.OutboxResult, _ time.Time) {
+				Expect(result.MessageID).To(Equal(message.ID))
+				check(result)
+			})
+	}
+
+	It("marks the message processed once its sink handled it", func() {
+		relay.Register(entity.OutboxTopicOfferEvent, "stream", stream)
+		expectClaim(message)
+		stream.EXPECT().Handle(ctx, message).Return(nil)
+		expectResult(func(result entity.OutboxResult) {
+			Expect(result.Status).To(Equal(entity.OutboxProcessed))
+		})
+
+		relayed, err := relay.RelayDue(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(relayed).To(Equal(1))
+	})
+
+	It("processes batches until a partial one", func() {
+		relay.Register(entity.OutboxTopicOfferEvent, "stream", stream)
+		gomock.InOrder(
+			repo.EXPECT().ClaimDue(ctx, gomock.Any(), gomock.Any(), 2).Return([]entity.OutboxMessage{message, message}, nil),
+			repo.EXPECT().ClaimDue(ctx, gomock.Any(), gomock.Any(), 2).Return(nil, nil),
+		)
+		stream.EXPECT().Handle(ctx, message).Return(nil).Times(2)
+		repo.EXPECT().SaveResult(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+		relayed, err := relay.RelayDue(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(relayed).To(Equal(2))
+	})
+
+	It("retries only the sinks that failed", func() {
+		relay.Register(entity.OutboxTopicOfferEvent, "stream", stream)
+		relay.Register(entity.OutboxTopicOfferEvent, "mail", mail)
+		message.Attempts = 1
+		expectClaim(message)
+		stream.EXPECT().Handle(ctx, message).Return(nil)
+		repo.EXPECT().MarkSinkDone(gomock.Any(), message.ID, "stream").Return(nil)
+		mail.EXPECT().Handle(ctx, message).Return(errors.New("smtp is down"))
+		expectResult(func(result entity.OutboxResult) {
+			Expect(result.Status).To(Equal(entity.OutboxPending))
+			Expect(result.Error).To(Equal("mail: smtp is down"))
+			Expect(result.NextAttemptAt).To(BeTemporally("~", time.Now().Add(2*time.Minute), time.Second))
+		})
+
+		_, err := relay.RelayDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("skips sinks that already handled the message", func() {
+		relay.Register(entity.OutboxTopicOfferEvent, "stream", stream)
+		relay.Register(entity.OutboxTopicOfferEvent, "mail", mail)
+		message.DoneSinks = []string{"stream"}
+		expectClaim(message)
+		mail.EXPECT().Handle(ctx, message).Return(nil)
+		repo.EXPECT().MarkSinkDone(gomock.Any(), message.ID, "mail").Return(nil)
+		expectResult(func(result entity.OutboxResult) {
+			Expect(result.Status).To(Equal(entity.OutboxProcessed))
+		})
+
+		_, err := relay.RelayDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("gives up after the last attempt", func() {
+		relay.Register(entity.OutboxTopicOfferEvent, "stream", stream)
+		message.Attempts = 2
+		expectClaim(message)
+		stream.EXPECT().Handle(ctx, message).Return(errors.New("db is down"))
+		expectResult(func(result entity.OutboxResult) {
+			Expect(result.Status).To(Equal(entity.OutboxFailed))
+		})
+
+		_, err := relay.RelayDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports messages of a topic without sinks as errors", func() {
+		expectClaim(message)
+		expectResult(func(result entity.OutboxResult) {
+			Expect(result.Status).To(Equal(entity.OutboxPending))
+			Expect(result.Error).To(ContainSubstring("no sinks"))
+		})
+
+		_, err := relay.RelayDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=sinks_mock_test.go -package=outbox OfferPublisher,Webhooks,Mailer
+
+// OfferPublisher рассылает события офферов подписчикам
+type OfferPublisher interface {
+	Publish(ctx context.Context, update entity.OfferUpdate) error
+}
+
+// OfferEventSink передаёт события из журнала офферов в шину offerstream
+type OfferEventSink struct {
+	publisher OfferPublisher
+}
+
+func NewOfferEventSink(publisher OfferPublisher) *OfferEventSink {
+	return &OfferEventSink{publisher: publisher}
+}
+
+func (s *OfferEventSink) Handle(ctx context.Context, message entity.OutboxMessage) error {
+	update, err := decodeOfferEvent(message)
+	if err != nil {
+		return err
+	}
+
+	return s.publisher.Publish(ctx, update)
+}
+
+// decodeOfferEvent собирает событие оффера из сообщения OutboxTopicOfferEvent.
+// Событие произошло в транзакции, записавшей сообщение, поэтому его время - время сообщения.
+func decodeOfferEvent(message entity.OutboxMessage) (entity.OfferUpdate, error) {
+	var payload entity.OfferEventPayload
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		return entity.OfferUpdate{}, fmt.Errorf("invalid offer event payload: %w", err)
+	}
+
+	return entity.OfferUpdate{
+		Type:       payload.Type,
+		EventID:    payload.EventID,
+		OfferID:    message.AggregateID,
+		Status:     payload.Status,
+		Price:      payload.Price,
+		OccurredAt: message.CreatedAt,
+	}, nil
+}
+
+// Webhooks ставит события офферов в очередь на вебхуки магазинов
+type Webhooks interface {
+	EnqueueOfferEvent(ctx context.Context, update entity.OfferUpdate) error
+	EnqueueGuestOffer(ctx context.Context, offerID uint, offer entity.GuestOfferPayload, occurredAt time.Time) error
+}
+
+// WebhookSink ставит в очередь вебхуков события из журнала офферов (OutboxTopicOfferEvent)
+// и новые офферы гостей (OutboxTopicGuestOffer)
+type WebhookSink struct {
+	webhooks Webhooks
+}
+
+func NewWebhookSink(webhooks Webhooks) *WebhookSink {
+	return &WebhookSink{webhooks: webhooks}
+}
+
+func (s *WebhookSink) Handle(ctx context.Context, message entity.OutboxMessage) error {
+	switch message.Topic {
+	case entity.OutboxTopicOfferEvent:
+		update, err := decodeOfferEvent(message)
+		if err != nil {
+			return err
+		}
+		return s.webhooks.EnqueueOfferEvent(ctx, update)
+	case entity.OutboxTopicGuestOffer:
+		var offer entity.GuestOfferPayload
+		if err := json.Unmarshal(message.Payload, &offer); err != nil {
+			return fmt.Errorf("invalid guest offer payload: %w", err)
+		}
+		return s.webhooks.EnqueueGuestOffer(ctx, message.AggregateID, offer, message.CreatedAt)
+	default:
+		return fmt.Errorf("unexpected topic %q", message.Topic)
+	}
+}
+
+type Mailer interface {
+	Send(ctx context.Context, userMail string, subject string, body string) error
+}
+
+// MailSink отправляет письма-уведомления по офферам
+type MailSink struct {
+	mailer Mailer
+}
+
+func NewMailSink(mailer Mailer) *MailSink {
+	return &MailSink{mailer: mailer}
+}
+
+func (s *MailSink) Handle(ctx context.Context, message entity.OutboxMessage) error {
+	var notification entity.EmailNotification
+	if err := json.Unmarshal(message.Payload, &notification); err != nil {
+		return fmt.Errorf("invalid email payload: %w", err)
+	}
+
+	var subject, body string
+	switch notification.Kind {
+	case entity.EmailRegistered:
+		subject, body = email.RegisteredMessage(notification.Name)
+	case entity.EmailStatusUpdate:
+		subject, body = email.StatusUpdateMessage(message.AggregateID, notification.Status)
+	case entity.EmailOfferMessage:
+		subject, body = email.OfferMessageReceivedMessage(message.AggregateID)
+	case entity.EmailGuestOfferReceived:
+		guest := notification.Guest
+		if guest == nil {
+			return fmt.Errorf("email %q without guest offer", notification.Kind)
+		}
+		subject, body = email.GuestOfferReceivedMessage(message.AggregateID, guest.ProductID, guest.ShopID,
+			guest.Price, guest.Currency, guest.GuestName, guest.GuestEmail, guest.GuestPhone)
+	case entity.EmailGuestOfferSent:
+		guest := notification.Guest
+		if guest == nil {
+			return fmt.Errorf("email %q without guest offer", notification.Kind)
+		}
+		subject, body = email.GuestOfferSentMessage(message.AggregateID, guest.Price, guest.Currency,
+			notification.Link)
+	default:
+		return fmt.Errorf("unknown email kind %q", notification.Kind)
+	}
+
+	return s.mailer.Send(ctx, notification.To, subject, body)
+}
@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sinks.go
+//
+// Generated by this command:
+//
+//	mockgen -source=sinks.go -destination=sinks_mock_test.go -package=outbox OfferPublisher,Webhooks,Mailer
+//
+
+// Package outbox is a generated GoMock package.
+package outbox
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOfferPublisher is a mock of OfferPublisher interface.
+type MockOfferPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockOfferPublisherMockRecorder is the mock recorder for MockOfferPublisher.
+type MockOfferPublisherMockRecorder struct {
+	mock *MockOfferPublisher
+}
+
+// NewMockOfferPublisher creates a new mock instance.
+func NewMockOfferPublisher(ctrl *gomock.Controller) *MockOfferPublisher {
+	mock := &MockOfferPublisher{ctrl: ctrl}
+	mock.recorder = &MockOfferPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferPublisher) EXPECT() *MockOfferPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockOfferPublisher) Publish(ctx context.Context, update entity.OfferUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockOfferPublisherMockRecorder) Publish(ctx, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockOfferPublisher)(nil).Publish), ctx, update)
+}
+
+// MockWebhooks is a mock of Webhooks interface.
+type MockWebhooks struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhooksMockRecorder is the mock recorder for MockWebhooks.
+type MockWebhooksMockRecorder struct {
+	mock *MockWebhooks
+}
+
+// NewMockWebhooks creates a new mock instance.
+func NewMockWebhooks(ctrl *gomock.Controller) *MockWebhooks {
+	mock := &MockWebhooks{ctrl: ctrl}
+	mock.recorder = &MockWebhooksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooks) EXPECT() *MockWebhooksMockRecorder {
+	return m.recorder
+}
+
+// EnqueueGuestOffer mocks base method.
+func (m *MockWebhooks) EnqueueGuestOffer(ctx context.Context, offerID uint, offer entity.GuestOfferPayload, occurredAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueGuestOffer", ctx, offerID, offer, occurredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueGuestOffer indicates an expected call of EnqueueGuestOffer.
+func (mr *MockWebhooksMockRecorder) EnqueueGuestOffer(ctx, offerID, offer, occurredAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueGuestOffer", reflect.TypeOf((*MockWebhooks)(nil).EnqueueGuestOffer), ctx, offerID, offer, occurredAt)
+}
+
+// EnqueueOfferEvent mocks base method.
+func (m *MockWebhooks) EnqueueOfferEvent(ctx context.Context, update entity.OfferUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueOfferEvent", ctx, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueOfferEvent indicates an expected call of EnqueueOfferEvent.
+func (mr *MockWebhooksMockRecorder) EnqueueOfferEvent(ctx, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueOfferEvent", reflect.TypeOf((*MockWebhooks)(nil).EnqueueOfferEvent), ctx, update)
+}
+
+// MockMailer is a mock of Mailer interface.
+type MockMailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailerMockRecorder
+	isgomock struct{}
+}
+
+// MockMailerMockRecorder is the mock recorder for MockMailer.
+type MockMailerMockRecorder struct {
+	mock *MockMailer
+}
+
+// NewMockMailer creates a new mock instance.
+func NewMockMailer(ctrl *gomock.Controller) *MockMailer {
+	mock := &MockMailer{ctrl: ctrl}
+	mock.recorder = &MockMailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailer) EXPECT() *MockMailerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockMailer) Send(ctx context.Context, userMail, subject, body string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, userMail, subject, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMailerMockRecorder) Send(ctx, userMail, subject, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMailer)(nil).Send), ctx, userMail, subject, body)
+}
@@ -0,0 +1,187 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+var _ = Describe("Sinks", func() {
+	var (
+		ctrl *gomock.Controller
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("OfferEventSink", func() {
+		It("publishes the event as it was logged", func() {
+			createdAt := time.Now().Add(-time.Hour)
+			publisher := NewMockOfferPublisher(ctrl)
+			publisher.EXPECT().Publish(ctx, entity.OfferUpdate{
+				Type:       entity.OfferUpdateExpired,
+				EventID:    40,
+				OfferID:    7,
+				Status:     "expired",
+				Price:      90,
+				OccurredAt: createdAt,
+			}).Return(errors.New("db is down"))
+
+			err := NewOfferEventSink(publisher).Handle(ctx, entity.OutboxMessage{
+				AggregateID: 7,
+				Payload:     []byte(`{"type":"expired","event_id":40,"status":"expired","price":90}`),
+				CreatedAt:   createdAt,
+			})
+
+			Expect(err).To(MatchError("db is down"))
+		})
+	})
+
+	Describe("WebhookSink", func() {
+		var (
+			webhooks *MockWebhooks
+			sink     *WebhookSink
+		)
+
+		BeforeEach(func() {
+			webhooks = NewMockWebhooks(ctrl)
+			sink = NewWebhookSink(webhooks)
+		})
+
+		It("enqueues offer events and returns the error", func() {
+			webhooks.EXPECT().EnqueueOfferEvent(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, update entity.OfferUpdate) error {
+					Expect(update.EventID).To(Equal(uint(40)))
+					Expect(update.OfferID).To(Equal(uint(7)))
+					Expect(update.Status).To(Equal("countered"))
+					return errors.New("db is down")
+				})
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				Topic:       entity.OutboxTopicOfferEvent,
+				AggregateID: 7,
+				Payload:     []byte(`{"type":"status_changed","event_id":40,"status":"countered","price":90}`),
+			})
+
+			Expect(err).To(MatchError("db is down"))
+		})
+
+		It("enqueues new guest offers", func() {
+			webhooks.EXPECT().EnqueueGuestOffer(ctx, uint(9), entity.GuestOfferPayload{
+				ShopID: 5, Price: 10, GuestEmail: "guest@example.com",
+			}, gomock.Any()).Return(nil)
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				Topic:       entity.OutboxTopicGuestOffer,
+				AggregateID: 9,
+				Payload:     []byte(`{"shop_id":5,"price":10,"guest_email":"guest@example.com"}`),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects other topics", func() {
+			err := sink.Handle(ctx, entity.OutboxMessage{Topic: entity.OutboxTopicEmail, Payload: []byte(`{}`)})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("MailSink", func() {
+		var (
+			mailer *MockMailer
+			sink   *MailSink
+		)
+
+		BeforeEach(func() {
+			mailer = NewMockMailer(ctrl)
+			sink = NewMailSink(mailer)
+		})
+
+		It("sends the status update for the offer", func() {
+			mailer.EXPECT().Send(ctx, "buyer@example.com",
+				"Stawberry: Offer Status Update (ID 7)",
+				"The status of your offer (7) has been changed to: expired").Return(nil)
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				AggregateID: 7,
+				Payload:     []byte(`{"kind":"status_update","to":"buyer@example.com","status":"expired"}`),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends the new message notice for the offer", func() {
+			mailer.EXPECT().Send(ctx, "owner@example.com",
+				"Stawberry: New Message on Offer (ID 7)",
+				"You have a new message about offer (7). Open the offer to read and reply.").Return(nil)
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				AggregateID: 7,
+				Payload:     []byte(`{"kind":"offer_message","to":"owner@example.com"}`),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends the welcome email", func() {
+			mailer.EXPECT().Send(ctx, "buyer@example.com", "Welcome to Strawberry!",
+				"Thank you for registering, buyer.").Return(nil)
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				Payload: []byte(`{"kind":"registered","to":"buyer@example.com","name":"buyer"}`),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends the guest the link to the offer", func() {
+			mailer.EXPECT().Send(ctx, "guest@example.com", "Your offer has been sent",
+				"Your offer (7) of 10.50 USD has been sent to the store.\n\n"+
+					"Use this link to track or cancel it: https://example.com/guest/offers/token").Return(nil)
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				AggregateID: 7,
+				Payload: []byte(`{"kind":"guest_offer_sent","to":"guest@example.com",` +
+					`"guest":{"price":10.5,"currency":"USD"},"link":"https://example.com/guest/offers/token"}`),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends the shop owner the guest's contacts", func() {
+			mailer.EXPECT().Send(ctx, "owner@example.com", "New guest offer received", gomock.Any()).
+				Do(func(_ context.Context, _, _, body string) {
+					Expect(body).To(ContainSubstring("Offer ID: 7"))
+					Expect(body).To(ContainSubstring("Guest Phone: 555"))
+				}).Return(nil)
+
+			err := sink.Handle(ctx, entity.OutboxMessage{
+				AggregateID: 7,
+				Payload: []byte(`{"kind":"guest_offer_received","to":"owner@example.com",` +
+					`"guest":{"product_id":1,"shop_id":2,"price":10.5,"currency":"USD","guest_phone":"555"}}`),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects unknown emails", func() {
+			err := sink.Handle(ctx, entity.OutboxMessage{Payload: []byte(`{"kind":"spam"}`)})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
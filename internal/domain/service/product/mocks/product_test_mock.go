@@ -36,6 +36,21 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ArchiveProduct mocks base method.
+func (m *MockRepository) ArchiveProduct(ctx context.Context, shopID, productID, userID uint) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveProduct", ctx, shopID, productID, userID)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveProduct indicates an expected call of ArchiveProduct.
+func (mr *MockRepositoryMockRecorder) ArchiveProduct(ctx, shopID, productID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveProduct", reflect.TypeOf((*MockRepository)(nil).ArchiveProduct), ctx, shopID, productID, userID)
+}
+
 // GetAttributesByID mocks base method.
 func (m *MockRepository) GetAttributesByID(ctx context.Context, productID string) (map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -98,9 +113,9 @@ func (mr *MockRepositoryMockRecorder) GetFilteredProductsCount(ctx, filter inter
 }
 
 // GetPriceRangeByProductID mocks base method.
-func (m *MockRepository) GetPriceRangeByProductID(ctx context.Context, productID int) (int, int, error) {
+func (m *MockRepository) GetPriceRangeByProductID(ctx context.Context, productID int, currency string) (int, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPriceRangeByProductID", ctx, productID)
+	ret := m.ctrl.Call(m, "GetPriceRangeByProductID", ctx, productID, currency)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -108,9 +123,9 @@ func (m *MockRepository) GetPriceRangeByProductID(ctx context.Context, productID
 }
 
 // GetPriceRangeByProductID indicates an expected call of GetPriceRangeByProductID.
-func (mr *MockRepositoryMockRecorder) GetPriceRangeByProductID(ctx, productID interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetPriceRangeByProductID(ctx, productID, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceRangeByProductID", reflect.TypeOf((*MockRepository)(nil).GetPriceRangeByProductID), ctx, productID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceRangeByProductID", reflect.TypeOf((*MockRepository)(nil).GetPriceRangeByProductID), ctx, productID, currency)
 }
 
 // GetProductByID mocks base method.
@@ -127,3 +142,49 @@ func (mr *MockRepositoryMockRecorder) GetProductByID(ctx, id interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByID", reflect.TypeOf((*MockRepository)(nil).GetProductByID), ctx, id)
 }
+
+// GetShopProducts mocks base method.
+func (m *MockRepository) GetShopProducts(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.Product, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopProducts", ctx, shopID, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.Product)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetShopProducts indicates an expected call of GetShopProducts.
+func (mr *MockRepositoryMockRecorder) GetShopProducts(ctx, shopID, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopProducts", reflect.TypeOf((*MockRepository)(nil).GetShopProducts), ctx, shopID, userID, limit, offset)
+}
+
+// InsertProduct mocks base method.
+func (m *MockRepository) InsertProduct(ctx context.Context, shopID, userID uint, product entity.Product) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProduct", ctx, shopID, userID, product)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProduct indicates an expected call of InsertProduct.
+func (mr *MockRepositoryMockRecorder) InsertProduct(ctx, shopID, userID, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProduct", reflect.TypeOf((*MockRepository)(nil).InsertProduct), ctx, shopID, userID, product)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockRepository) UpdateProduct(ctx context.Context, shopID, productID, userID uint, update entity.ProductUpdate) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", ctx, shopID, productID, userID, update)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockRepositoryMockRecorder) UpdateProduct(ctx, shopID, productID, userID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockRepository)(nil).UpdateProduct), ctx, shopID, productID, userID, update)
+}
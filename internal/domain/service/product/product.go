@@ -3,7 +3,10 @@ package product
 import (
 	"context"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
@@ -14,22 +17,40 @@ type Repository interface {
 	GetFilteredProductsCount(ctx context.Context, filter model.ProductFilter) (int, error)
 	GetProductByID(ctx context.Context, id string) (entity.Product, error)
 	GetAttributesByID(ctx context.Context, productID string) (map[string]interface{}, error)
-	GetPriceRangeByProductID(ctx context.Context, productID int) (int, int, error)
+	GetPriceRangeByProductID(ctx context.Context, productID int, currency string) (int, int, error)
 	GetAverageRatingByProductID(ctx context.Context, productID int) (float64, int, error)
+	InsertProduct(ctx context.Context, shopID, userID uint, product entity.Product) (entity.Product, error)
+	UpdateProduct(
+		ctx context.Context,
+		shopID, productID, userID uint,
+		update entity.ProductUpdate,
+	) (entity.Product, error)
+	ArchiveProduct(ctx context.Context, shopID, productID, userID uint) (entity.Product, error)
+	GetShopProducts(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.Product, int, error)
 }
 
+const (
+	maxNameLength      = 255
+	maxAttributes      = 50
+	maxAttributeKeyLen = 64
+)
+
 type Service struct {
 	ProductRepository Repository
+	// DisplayCurrency валюта диапазона цен, если клиент не запросил другую
+	DisplayCurrency string
 }
 
-func NewService(productRepo Repository) *Service {
-	return &Service{ProductRepository: productRepo}
+func NewService(productRepo Repository, displayCurrency string) *Service {
+	return &Service{ProductRepository: productRepo, DisplayCurrency: displayCurrency}
 }
 
-// GetProductByID получает продукт по его ID
+// GetProductByID получает продукт по его ID с диапазоном цен в валюте currency
+// (или валюте отображения по умолчанию, если она не задана)
 func (ps *Service) GetProductByID(
 	ctx context.Context,
 	id string,
+	currency string,
 ) (entity.Product, error) {
 	product, err := ps.ProductRepository.GetProductByID(ctx, id)
 	if err != nil {
@@ -41,7 +62,7 @@ func (ps *Service) GetProductByID(
 	}
 	product.Attributes = attrs
 
-	enrichedProduct, err := ps.enrichProducts(ctx, product)
+	enrichedProduct, err := ps.enrichProducts(ctx, product, ps.displayCurrency(currency))
 	if err != nil {
 		return entity.Product{}, err
 	}
@@ -52,6 +73,9 @@ func (ps *Service) GetProductByID(
 func (ps *Service) GetFilteredProducts(ctx context.Context,
 	filter model.ProductFilter,
 	limit, offset int) ([]entity.Product, int, error) {
+	filter.Currency = ps.displayCurrency(filter.Currency)
+	filter.Query = searchQuery(filter.Query)
+
 	products, err := ps.ProductRepository.GetFilteredProducts(ctx, filter, limit, offset)
 	if err != nil {
 		fmt.Println("Ошибка при получении продуктов")
@@ -64,7 +88,7 @@ func (ps *Service) GetFilteredProducts(ctx context.Context,
 		return nil, 0, err
 	}
 	for i := range products {
-		products[i], err = ps.enrichProducts(ctx, products[i])
+		products[i], err = ps.enrichProducts(ctx, products[i], filter.Currency)
 		if err != nil {
 			fmt.Println("Ошибка при обогащении продуктов")
 			return nil, 0, err
@@ -74,13 +98,36 @@ func (ps *Service) GetFilteredProducts(ctx context.Context,
 	return products, count, nil
 }
 
-// EnrichProducts выполняет обогащение продукта информацией о диапазоне цены, средней оценке и количестве отзывов
+// displayCurrency возвращает запрошенную валюту отображения в верхнем регистре или валюту по умолчанию
+func (ps *Service) displayCurrency(requested string) string {
+	if requested == "" {
+		return ps.DisplayCurrency
+	}
+	return strings.ToUpper(requested)
+}
+
+// searchQuery обрезает пробелы в поисковом запросе, пустой запрос не ищет, а выключает поиск
+func searchQuery(query *string) *string {
+	if query == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*query)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// EnrichProducts выполняет обогащение продукта информацией о диапазоне цены в валюте currency,
+// средней оценке и количестве отзывов
 func (ps *Service) enrichProducts(
 	ctx context.Context,
 	product entity.Product,
+	currency string,
 ) (entity.Product, error) {
 
-	minPrice, maxPrice, err := ps.ProductRepository.GetPriceRangeByProductID(ctx, product.ID)
+	minPrice, maxPrice, err := ps.ProductRepository.GetPriceRangeByProductID(ctx, product.ID, currency)
 	if err != nil {
 		return entity.Product{}, err
 	}
@@ -92,8 +139,98 @@ func (ps *Service) enrichProducts(
 
 	product.MinimalPrice = minPrice
 	product.MaximalPrice = maxPrice
+	product.Currency = currency
 	product.AverageRating = avgRating
 	product.CountReviews = countReviews
 
 	return product, nil
 }
+
+// CreateProduct заводит товар магазина shopID после проверки названия и атрибутов
+func (ps *Service) CreateProduct(
+	ctx context.Context,
+	shopID, userID uint,
+	product entity.Product,
+) (entity.Product, error) {
+	product.Name = strings.TrimSpace(product.Name)
+	if err := validateName(product.Name); err != nil {
+		return entity.Product{}, err
+	}
+	if err := validateAttributes(product.Attributes); err != nil {
+		return entity.Product{}, err
+	}
+
+	return ps.ProductRepository.InsertProduct(ctx, shopID, userID, product)
+}
+
+// UpdateProduct меняет заданные поля товара магазина, хотя бы одно поле должно быть задано
+func (ps *Service) UpdateProduct(
+	ctx context.Context,
+	shopID, productID, userID uint,
+	update entity.ProductUpdate,
+) (entity.Product, error) {
+	if update.Name == nil && update.Description == nil && update.CategoryID == nil && update.Attributes == nil {
+		return entity.Product{}, apperror.New(apperror.BadRequest, "nothing to update", nil)
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if err := validateName(name); err != nil {
+			return entity.Product{}, err
+		}
+		update.Name = &name
+	}
+	if err := validateAttributes(update.Attributes); err != nil {
+		return entity.Product{}, err
+	}
+
+	return ps.ProductRepository.UpdateProduct(ctx, shopID, productID, userID, update)
+}
+
+func (ps *Service) ArchiveProduct(ctx context.Context, shopID, productID, userID uint) (entity.Product, error) {
+	return ps.ProductRepository.ArchiveProduct(ctx, shopID, productID, userID)
+}
+
+func (ps *Service) GetShopProducts(
+	ctx context.Context,
+	shopID, userID uint,
+	limit, offset int,
+) ([]entity.Product, int, error) {
+	return ps.ProductRepository.GetShopProducts(ctx, shopID, userID, limit, offset)
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return apperror.New(apperror.BadRequest, "product name must not be blank", nil)
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return apperror.New(apperror.BadRequest,
+			fmt.Sprintf("product name must not exceed %d characters", maxNameLength), nil)
+	}
+	return nil
+}
+
+// validateAttributes проверяет атрибуты товара: фильтр каталога сравнивает их как строки,
+// поэтому значениями могут быть только строки, числа и логические значения
+func validateAttributes(attributes map[string]interface{}) error {
+	if len(attributes) > maxAttributes {
+		return apperror.New(apperror.BadRequest,
+			fmt.Sprintf("product must not have more than %d attributes", maxAttributes), nil)
+	}
+
+	for key, value := range attributes {
+		if strings.TrimSpace(key) == "" || utf8.RuneCountInString(key) > maxAttributeKeyLen {
+			return apperror.New(apperror.BadRequest,
+				fmt.Sprintf("attribute names must be 1-%d characters long", maxAttributeKeyLen), nil)
+		}
+
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return apperror.New(apperror.BadRequest,
+				fmt.Sprintf("attribute %q must be a string, a number or a boolean", key), nil)
+		}
+	}
+
+	return nil
+}
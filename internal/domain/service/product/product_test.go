@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/product/mocks"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -36,29 +38,157 @@ var _ = Describe("EnrichProducts", func() {
 	})
 
 	It("successfully enriches products", func() {
-		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1).Return(1000, 2000, nil)
+		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1, "EUR").Return(1000, 2000, nil)
 		mockRepo.EXPECT().GetAverageRatingByProductID(ctx, 1).Return(4.5, 10, nil)
 
-		result, err := svc.enrichProducts(ctx, product)
+		result, err := svc.enrichProducts(ctx, product, "EUR")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(result.MinimalPrice).To(Equal(1000))
 		Expect(result.MaximalPrice).To(Equal(2000))
+		Expect(result.Currency).To(Equal("EUR"))
 		Expect(result.AverageRating).To(Equal(4.5))
 		Expect(result.CountReviews).To(Equal(10))
 	})
 
 	It("returns error if GetPriceRangeByProductID fails", func() {
-		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1).Return(0, 0, errors.New("db error"))
+		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1, "EUR").Return(0, 0, errors.New("db error"))
 
-		_, err := svc.enrichProducts(ctx, product)
+		_, err := svc.enrichProducts(ctx, product, "EUR")
 		Expect(err).To(MatchError("db error"))
 	})
 
 	It("returns error if GetAverageRatingByProductID fails", func() {
-		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1).Return(1000, 2000, nil)
+		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1, "EUR").Return(1000, 2000, nil)
 		mockRepo.EXPECT().GetAverageRatingByProductID(ctx, 1).Return(0.0, 0, errors.New("rating error"))
 
-		_, err := svc.enrichProducts(ctx, product)
+		_, err := svc.enrichProducts(ctx, product, "EUR")
 		Expect(err).To(MatchError("rating error"))
 	})
 })
+
+var _ = Describe("Shop product management", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockRepository
+		svc      *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		svc = NewService(mockRepo, "USD")
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	expectBadRequest := func(err error) {
+		var appErr *apperror.Error
+		Expect(errors.As(err, &appErr)).To(BeTrue())
+		Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+	}
+
+	Describe("CreateProduct", func() {
+		It("saves the product with a trimmed name", func() {
+			product := entity.Product{
+				Name:       "  Phone ",
+				CategoryID: 3,
+				Attributes: map[string]interface{}{"color": "black", "memory": 128.0, "nfc": true},
+			}
+			mockRepo.EXPECT().InsertProduct(ctx, uint(1), uint(7), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ uint, p entity.Product) (entity.Product, error) {
+					Expect(p.Name).To(Equal("Phone"))
+					p.ID = 10
+					return p, nil
+				})
+
+			created, err := svc.CreateProduct(ctx, 1, 7, product)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created.ID).To(Equal(10))
+		})
+
+		DescribeTable("rejects invalid products without saving them",
+			func(product entity.Product) {
+				_, err := svc.CreateProduct(ctx, 1, 7, product)
+
+				expectBadRequest(err)
+			},
+			Entry("blank name", entity.Product{Name: "   ", CategoryID: 3}),
+			Entry("nested attribute", entity.Product{Name: "Phone", CategoryID: 3,
+				Attributes: map[string]interface{}{"size": map[string]interface{}{"w": 1.0}}}),
+			Entry("list attribute", entity.Product{Name: "Phone", CategoryID: 3,
+				Attributes: map[string]interface{}{"colors": []interface{}{"red"}}}),
+			Entry("blank attribute name", entity.Product{Name: "Phone", CategoryID: 3,
+				Attributes: map[string]interface{}{" ": "x"}}),
+		)
+	})
+
+	Describe("UpdateProduct", func() {
+		It("rejects an empty update", func() {
+			_, err := svc.UpdateProduct(ctx, 1, 10, 7, entity.ProductUpdate{})
+
+			expectBadRequest(err)
+		})
+
+		It("passes the changes to the repository", func() {
+			category := 4
+			update := entity.ProductUpdate{CategoryID: &category, Attributes: map[string]interface{}{}}
+			mockRepo.EXPECT().UpdateProduct(ctx, uint(1), uint(10), uint(7), update).
+				Return(entity.Product{ID: 10, CategoryID: 4}, nil)
+
+			updated, err := svc.UpdateProduct(ctx, 1, 10, 7, update)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.CategoryID).To(Equal(4))
+		})
+	})
+})
+
+var _ = Describe("GetFilteredProducts", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockRepository
+		svc      *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		svc = NewService(mockRepo, "USD")
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	expectFilter := func(query *string) {
+		filter := model.ProductFilter{Query: query, Currency: "USD"}
+		mockRepo.EXPECT().GetFilteredProducts(ctx, filter, 10, 0).Return([]entity.Product{}, nil)
+		mockRepo.EXPECT().GetFilteredProductsCount(ctx, filter).Return(0, nil)
+	}
+
+	It("trims the search query", func() {
+		expected := "электрический чайник"
+		expectFilter(&expected)
+
+		query := "  электрический чайник "
+		_, _, err := svc.GetFilteredProducts(ctx, model.ProductFilter{Query: &query}, 10, 0)
+
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("drops a blank search query", func() {
+		expectFilter(nil)
+
+		query := "   "
+		_, _, err := svc.GetFilteredProducts(ctx, model.ProductFilter{Query: &query}, 10, 0)
+
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -6,6 +6,9 @@ type User struct {
 	Email    string
 	Phone    string
 	IsStore  bool
+	// GuestToken токен ссылки из письма гостю. Он подтверждает доступ к почте гостя,
+	// поэтому офферы, отправленные с неё, переходят к пользователю. Пустой - ничего не передаётся.
+	GuestToken string
 }
 
 type UpdateUser struct {
@@ -2,6 +2,8 @@ package user
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -14,7 +16,7 @@ import (
 //go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=user Repository, TokenService
 
 type Repository interface {
-	InsertUser(ctx context.Context, user User) (uint, error)
+	InsertUser(ctx context.Context, user User) (uint, int, error)
 	GetUser(ctx context.Context, email string) (entity.User, error)
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
 }
@@ -55,37 +57,43 @@ func NewService(userRepo Repository,
 }
 
 // CreateUser создает пользователя, хэшируя его пароль, используя HashArgon2id
-// генерирует access токен и uuid refresh uuid.
+// генерирует access токен и uuid refresh uuid. Если передан токен из письма гостю,
+// возвращает также количество офферов, отправленных гостем с той почты и перешедших к пользователю.
 func (us *Service) CreateUser(
 	ctx context.Context,
 	user User,
 	fingerprint string,
-) (string, string, error) {
+) (string, string, int, error) {
 	hash, err := us.passwordManager.Hash(user.Password)
 	if err != nil {
 		err := apperror.ErrFailedToGeneratePassword
 		err.WrappedErr = fmt.Errorf("failed to generate password %w", err)
-		return "", "", err
+		return "", "", 0, err
 	}
 	user.Password = hash
+	if user.GuestToken != "" {
+		// в guest_offers хранится только хеш токена
+		tokenHash := sha256.Sum256([]byte(user.GuestToken))
+		user.GuestToken = hex.EncodeToString(tokenHash[:])
+	}
 
-	id, err := us.userRepository.InsertUser(ctx, user)
+	id, claimed, err := us.userRepository.InsertUser(ctx, user)
 	if err != nil {
-		return "", "", err
+		return "", "", 0, err
 	}
 
 	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, fingerprint, id)
 	if err != nil {
-		return "", "", err
+		return "", "", 0, err
 	}
 
 	if err = us.tokenService.InsertToken(ctx, refreshToken); err != nil {
-		return "", "", err
+		return "", "", 0, err
 	}
 
 	us.mailer.Registered(user.Name, user.Email)
 
-	return accessToken, refreshToken.UUID.String(), nil
+	return accessToken, refreshToken.UUID.String(), claimed, nil
 }
 
 // Authenticate аутентифицирует пользователя по email и паролю, создавая новые токены.
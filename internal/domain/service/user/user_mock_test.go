@@ -72,12 +72,13 @@ func (mr *MockRepositoryMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
 }
 
 // InsertUser mocks base method.
-func (m *MockRepository) InsertUser(ctx context.Context, user User) (uint, error) {
+func (m *MockRepository) InsertUser(ctx context.Context, user User) (uint, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "InsertUser", ctx, user)
 	ret0, _ := ret[0].(uint)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // InsertUser indicates an expected call of InsertUser.
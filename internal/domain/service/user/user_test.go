@@ -2,6 +2,8 @@ package user
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -60,14 +62,14 @@ var _ = Describe("UserService", func() {
 		Context("when user creation is successful", func() {
 			It("should create user and return tokens", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), 0, nil)
 				mockTokenService.EXPECT().
 					GenerateTokens(ctx, fingerprint, uint(1)).
 					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
 				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
 				mockEmailService.EXPECT().Registered(testUser.Name, testUser.Email)
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, _, err := userService.CreateUser(ctx, testUser, fingerprint)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(accessToken).ToNot(BeEmpty())
@@ -75,11 +77,35 @@ var _ = Describe("UserService", func() {
 			})
 		})
 
+		Context("when the user presents a guest token", func() {
+			It("should pass only the token hash and report the claimed offers", func() {
+				guestUser := testUser
+				guestUser.GuestToken = "guest-token"
+				tokenHash := sha256.Sum256([]byte("guest-token"))
+
+				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, u User) (uint, int, error) {
+					Expect(u.GuestToken).To(Equal(hex.EncodeToString(tokenHash[:])))
+					return 1, 2, nil
+				})
+				mockTokenService.EXPECT().
+					GenerateTokens(ctx, fingerprint, uint(1)).
+					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
+				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+				mockEmailService.EXPECT().Registered(testUser.Name, testUser.Email)
+
+				_, _, claimed, err := userService.CreateUser(ctx, guestUser, fingerprint)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(claimed).To(Equal(2))
+			})
+		})
+
 		Context("when password hashing fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return("", errors.New("failed to generate password"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, _, err := userService.CreateUser(ctx, testUser, fingerprint)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -90,9 +116,9 @@ var _ = Describe("UserService", func() {
 		Context("when user insertion fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(0), errors.New("db error"))
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(0), 0, errors.New("db error"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, _, err := userService.CreateUser(ctx, testUser, fingerprint)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -103,12 +129,12 @@ var _ = Describe("UserService", func() {
 		Context("when token generation fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), 0, nil)
 				mockTokenService.EXPECT().
 					GenerateTokens(ctx, fingerprint, uint(1)).
 					Return("", entity.RefreshToken{}, errors.New("token generation error"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, _, err := userService.CreateUser(ctx, testUser, fingerprint)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -119,7 +145,7 @@ var _ = Describe("UserService", func() {
 		Context("when token insertion fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), 0, nil)
 				mockTokenService.EXPECT().
 					GenerateTokens(ctx, fingerprint, uint(1)).
 					Return("access-token", entity.RefreshToken{}, nil)
@@ -127,7 +153,7 @@ var _ = Describe("UserService", func() {
 					InsertToken(ctx, gomock.Any()).
 					Return(errors.New("token insertion error"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, _, err := userService.CreateUser(ctx, testUser, fingerprint)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -0,0 +1,60 @@
+package wanted
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/pkg/worker"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=close_mock_test.go -package=wanted CloseRepository
+
+type CloseRepository interface {
+	ExpireRequests(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// CloseWorker периодически закрывает запросы, дедлайн которых прошёл без выбранной ставки.
+// Ставки по ним переходят в lost.
+type CloseWorker struct {
+	*worker.Runner
+
+	repo      CloseRepository
+	batchSize int
+	log       *zap.Logger
+}
+
+func NewCloseWorker(repo CloseRepository, cfg *config.WantedConfig, log *zap.Logger) *CloseWorker {
+	w := &CloseWorker{repo: repo, batchSize: cfg.CloseBatchSize, log: log}
+	w.Runner = worker.NewRunner("wanted request close worker", cfg.CloseInterval, func(ctx context.Context) error {
+		_, err := w.CloseExpired(ctx)
+		return err
+	}, log)
+	return w
+}
+
+// CloseExpired закрывает просроченные запросы пакетами, пока не разберёт все,
+// и возвращает общее количество закрытых.
+func (w *CloseWorker) CloseExpired(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		expired, err := w.repo.ExpireRequests(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += expired
+
+		if expired < w.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.log.Info("wanted requests closed", zap.Int("count", total))
+	}
+
+	return total, nil
+}
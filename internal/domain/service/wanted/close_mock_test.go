@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: close.go
+//
+// Generated by this command:
+//
+//	mockgen -source=close.go -destination=close_mock_test.go -package=wanted CloseRepository
+//
+
+// Package wanted is a generated GoMock package.
+package wanted
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCloseRepository is a mock of CloseRepository interface.
+type MockCloseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloseRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCloseRepositoryMockRecorder is the mock recorder for MockCloseRepository.
+type MockCloseRepositoryMockRecorder struct {
+	mock *MockCloseRepository
+}
+
+// NewMockCloseRepository creates a new mock instance.
+func NewMockCloseRepository(ctrl *gomock.Controller) *MockCloseRepository {
+	mock := &MockCloseRepository{ctrl: ctrl}
+	mock.recorder = &MockCloseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloseRepository) EXPECT() *MockCloseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExpireRequests mocks base method.
+func (m *MockCloseRepository) ExpireRequests(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireRequests", ctx, now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireRequests indicates an expected call of ExpireRequests.
+func (mr *MockCloseRepositoryMockRecorder) ExpireRequests(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireRequests", reflect.TypeOf((*MockCloseRepository)(nil).ExpireRequests), ctx, now, limit)
+}
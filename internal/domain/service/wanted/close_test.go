@@ -0,0 +1,67 @@
+package wanted
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+)
+
+func TestWantedServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Wanted Service Suite")
+}
+
+var _ = Describe("CloseWorker", func() {
+	var (
+		ctrl   *gomock.Controller
+		repo   *MockCloseRepository
+		worker *CloseWorker
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockCloseRepository(ctrl)
+		worker = NewCloseWorker(repo, &config.WantedConfig{CloseInterval: time.Hour, CloseBatchSize: 2}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CloseExpired", func() {
+		It("processes batches until a partial one", func() {
+			gomock.InOrder(
+				repo.EXPECT().ExpireRequests(ctx, gomock.Any(), 2).Return(2, nil),
+				repo.EXPECT().ExpireRequests(ctx, gomock.Any(), 2).Return(1, nil),
+			)
+
+			total, err := worker.CloseExpired(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(3))
+		})
+
+		It("stops on a repository error and reports what was already closed", func() {
+			repoErr := errors.New("db is down")
+			gomock.InOrder(
+				repo.EXPECT().ExpireRequests(ctx, gomock.Any(), 2).Return(2, nil),
+				repo.EXPECT().ExpireRequests(ctx, gomock.Any(), 2).Return(0, repoErr),
+			)
+
+			total, err := worker.CloseExpired(ctx)
+
+			Expect(err).To(MatchError(repoErr))
+			Expect(total).To(Equal(2))
+		})
+	})
+})
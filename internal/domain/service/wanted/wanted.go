@@ -0,0 +1,114 @@
+package wanted
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=wanted_mock_test.go -package=wanted Repository,Relay
+
+type Repository interface {
+	InsertRequest(ctx context.Context, request entity.WantedRequest) (entity.WantedRequest, error)
+	SelectUserRequests(ctx context.Context, userID uint, limit, offset int) ([]entity.WantedRequest, int, error)
+	GetRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error)
+	CancelRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error)
+	AwardBid(ctx context.Context, requestID, bidID, userID uint) (entity.WantedRequest, error)
+	SelectShopRequests(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.WantedRequest, int, error)
+	UpsertBid(ctx context.Context, bid entity.WantedBid, userID uint) (entity.WantedBid, error)
+	WithdrawBid(ctx context.Context, requestID, shopID, userID uint) error
+}
+
+// Relay будит релей outbox, чтобы события оффера, созданного по ставке, ушли сразу
+type Relay interface {
+	Wake()
+}
+
+// Service ведёт обратные аукционы: покупатель публикует запрос с целевой ценой и дедлайном,
+// магазины делают ставки, покупатель выбирает победителя до дедлайна.
+// Запросы без победителя закрывает CloseWorker.
+type Service struct {
+	repo        Repository
+	relay       Relay
+	maxDuration time.Duration
+}
+
+func NewService(repo Repository, relay Relay, cfg *config.WantedConfig) *Service {
+	return &Service{repo: repo, relay: relay, maxDuration: cfg.MaxDuration}
+}
+
+// CreateRequest публикует запрос покупателя. Задан должен быть ровно один из товара и категории,
+// дедлайн - в будущем, но не дальше максимального срока аукциона.
+func (s *Service) CreateRequest(ctx context.Context, request entity.WantedRequest) (entity.WantedRequest, error) {
+	if (request.ProductID == nil) == (request.CategoryID == nil) {
+		return entity.WantedRequest{}, apperror.New(apperror.BadRequest,
+			"exactly one of product_id or category_id must be set", nil)
+	}
+
+	now := time.Now()
+	if !request.Deadline.After(now) {
+		return entity.WantedRequest{}, apperror.New(apperror.BadRequest, "deadline must be in the future", nil)
+	}
+	if request.Deadline.After(now.Add(s.maxDuration)) {
+		return entity.WantedRequest{}, apperror.New(apperror.BadRequest,
+			"deadline must be within "+s.maxDuration.String(), nil)
+	}
+
+	request.Currency = strings.ToUpper(request.Currency)
+
+	return s.repo.InsertRequest(ctx, request)
+}
+
+func (s *Service) GetUserRequests(
+	ctx context.Context,
+	userID uint,
+	page,
+	limit int,
+) ([]entity.WantedRequest, int, error) {
+	offset := (page - 1) * limit
+
+	return s.repo.SelectUserRequests(ctx, userID, limit, offset)
+}
+
+func (s *Service) GetRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error) {
+	return s.repo.GetRequest(ctx, requestID, userID)
+}
+
+func (s *Service) CancelRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error) {
+	return s.repo.CancelRequest(ctx, requestID, userID)
+}
+
+// AwardBid выбирает победившую ставку: по ней создаётся принятый оффер и заказ
+func (s *Service) AwardBid(ctx context.Context, requestID, bidID, userID uint) (entity.WantedRequest, error) {
+	request, err := s.repo.AwardBid(ctx, requestID, bidID, userID)
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	s.relay.Wake()
+
+	return request, nil
+}
+
+func (s *Service) GetShopRequests(
+	ctx context.Context,
+	shopID, userID uint,
+	page,
+	limit int,
+) ([]entity.WantedRequest, int, error) {
+	offset := (page - 1) * limit
+
+	return s.repo.SelectShopRequests(ctx, shopID, userID, limit, offset)
+}
+
+func (s *Service) PlaceBid(ctx context.Context, bid entity.WantedBid, userID uint) (entity.WantedBid, error) {
+	return s.repo.UpsertBid(ctx, bid, userID)
+}
+
+func (s *Service) WithdrawBid(ctx context.Context, requestID, shopID, userID uint) error {
+	return s.repo.WithdrawBid(ctx, requestID, shopID, userID)
+}
@@ -0,0 +1,199 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wanted.go
+//
+// Generated by this command:
+//
+//	mockgen -source=wanted.go -destination=wanted_mock_test.go -package=wanted Repository,Relay
+//
+
+// Package wanted is a generated GoMock package.
+package wanted
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AwardBid mocks base method.
+func (m *MockRepository) AwardBid(ctx context.Context, requestID, bidID, userID uint) (entity.WantedRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AwardBid", ctx, requestID, bidID, userID)
+	ret0, _ := ret[0].(entity.WantedRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AwardBid indicates an expected call of AwardBid.
+func (mr *MockRepositoryMockRecorder) AwardBid(ctx, requestID, bidID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwardBid", reflect.TypeOf((*MockRepository)(nil).AwardBid), ctx, requestID, bidID, userID)
+}
+
+// CancelRequest mocks base method.
+func (m *MockRepository) CancelRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelRequest", ctx, requestID, userID)
+	ret0, _ := ret[0].(entity.WantedRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelRequest indicates an expected call of CancelRequest.
+func (mr *MockRepositoryMockRecorder) CancelRequest(ctx, requestID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRequest", reflect.TypeOf((*MockRepository)(nil).CancelRequest), ctx, requestID, userID)
+}
+
+// GetRequest mocks base method.
+func (m *MockRepository) GetRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequest", ctx, requestID, userID)
+	ret0, _ := ret[0].(entity.WantedRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRequest indicates an expected call of GetRequest.
+func (mr *MockRepositoryMockRecorder) GetRequest(ctx, requestID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequest", reflect.TypeOf((*MockRepository)(nil).GetRequest), ctx, requestID, userID)
+}
+
+// InsertRequest mocks base method.
+func (m *MockRepository) InsertRequest(ctx context.Context, request entity.WantedRequest) (entity.WantedRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRequest", ctx, request)
+	ret0, _ := ret[0].(entity.WantedRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertRequest indicates an expected call of InsertRequest.
+func (mr *MockRepositoryMockRecorder) InsertRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRequest", reflect.TypeOf((*MockRepository)(nil).InsertRequest), ctx, request)
+}
+
+// SelectShopRequests mocks base method.
+func (m *MockRepository) SelectShopRequests(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.WantedRequest, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopRequests", ctx, shopID, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.WantedRequest)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectShopRequests indicates an expected call of SelectShopRequests.
+func (mr *MockRepositoryMockRecorder) SelectShopRequests(ctx, shopID, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopRequests", reflect.TypeOf((*MockRepository)(nil).SelectShopRequests), ctx, shopID, userID, limit, offset)
+}
+
+// SelectUserRequests mocks base method.
+func (m *MockRepository) SelectUserRequests(ctx context.Context, userID uint, limit, offset int) ([]entity.WantedRequest, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserRequests", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.WantedRequest)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectUserRequests indicates an expected call of SelectUserRequests.
+func (mr *MockRepositoryMockRecorder) SelectUserRequests(ctx, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserRequests", reflect.TypeOf((*MockRepository)(nil).SelectUserRequests), ctx, userID, limit, offset)
+}
+
+// UpsertBid mocks base method.
+func (m *MockRepository) UpsertBid(ctx context.Context, bid entity.WantedBid, userID uint) (entity.WantedBid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertBid", ctx, bid, userID)
+	ret0, _ := ret[0].(entity.WantedBid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertBid indicates an expected call of UpsertBid.
+func (mr *MockRepositoryMockRecorder) UpsertBid(ctx, bid, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBid", reflect.TypeOf((*MockRepository)(nil).UpsertBid), ctx, bid, userID)
+}
+
+// WithdrawBid mocks base method.
+func (m *MockRepository) WithdrawBid(ctx context.Context, requestID, shopID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawBid", ctx, requestID, shopID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawBid indicates an expected call of WithdrawBid.
+func (mr *MockRepositoryMockRecorder) WithdrawBid(ctx, requestID, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawBid", reflect.TypeOf((*MockRepository)(nil).WithdrawBid), ctx, requestID, shopID, userID)
+}
+
+// MockRelay is a mock of Relay interface.
+type MockRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayMockRecorder
+	isgomock struct{}
+}
+
+// MockRelayMockRecorder is the mock recorder for MockRelay.
+type MockRelayMockRecorder struct {
+	mock *MockRelay
+}
+
+// NewMockRelay creates a new mock instance.
+func NewMockRelay(ctrl *gomock.Controller) *MockRelay {
+	mock := &MockRelay{ctrl: ctrl}
+	mock.recorder = &MockRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelay) EXPECT() *MockRelayMockRecorder {
+	return m.recorder
+}
+
+// Wake mocks base method.
+func (m *MockRelay) Wake() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Wake")
+}
+
+// Wake indicates an expected call of Wake.
+func (mr *MockRelayMockRecorder) Wake() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wake", reflect.TypeOf((*MockRelay)(nil).Wake))
+}
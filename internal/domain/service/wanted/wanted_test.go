@@ -0,0 +1,115 @@
+package wanted
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+var _ = Describe("WantedService", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		relay   *MockRelay
+		service *Service
+		ctx     context.Context
+		request entity.WantedRequest
+	)
+
+	id := func(v uint) *uint {
+		return &v
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		relay = NewMockRelay(ctrl)
+		service = NewService(repo, relay, &config.WantedConfig{MaxDuration: 7 * 24 * time.Hour})
+		ctx = context.Background()
+		request = entity.WantedRequest{
+			UserID:      2,
+			ProductID:   id(3),
+			TargetPrice: 80,
+			Currency:    "usd",
+			Deadline:    time.Now().Add(24 * time.Hour),
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateRequest", func() {
+		It("saves the request with an upper-case currency", func() {
+			repo.EXPECT().InsertRequest(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, r entity.WantedRequest) (entity.WantedRequest, error) {
+					r.ID = 1
+					r.Status = entity.WantedOpen
+					return r, nil
+				})
+
+			created, err := service.CreateRequest(ctx, request)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.ID).To(Equal(uint(1)))
+			Expect(created.Currency).To(Equal("USD"))
+		})
+
+		DescribeTable("rejects invalid requests without saving them",
+			func(change func(r *entity.WantedRequest)) {
+				change(&request)
+
+				_, err := service.CreateRequest(ctx, request)
+
+				var appErr *apperror.Error
+				Expect(err).To(BeAssignableToTypeOf(appErr))
+				Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("both product and category", func(r *entity.WantedRequest) { r.CategoryID = id(4) }),
+			Entry("neither product nor category", func(r *entity.WantedRequest) { r.ProductID = nil }),
+			Entry("deadline in the past", func(r *entity.WantedRequest) { r.Deadline = time.Now().Add(-time.Minute) }),
+			Entry("deadline beyond the maximal duration", func(r *entity.WantedRequest) {
+				r.Deadline = time.Now().Add(8 * 24 * time.Hour)
+			}),
+		)
+	})
+
+	Describe("AwardBid", func() {
+		It("wakes the relay to publish the new offer", func() {
+			repo.EXPECT().AwardBid(ctx, uint(1), uint(5), uint(2)).
+				Return(entity.WantedRequest{ID: 1, Status: entity.WantedAwarded, OfferID: id(9)}, nil)
+			relay.EXPECT().Wake()
+
+			awarded, err := service.AwardBid(ctx, 1, 5, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*awarded.OfferID).To(Equal(uint(9)))
+		})
+
+		It("does not wake the relay when the award fails", func() {
+			repo.EXPECT().AwardBid(ctx, uint(1), uint(5), uint(2)).
+				Return(entity.WantedRequest{}, apperror.ErrWantedBidNotFound)
+
+			_, err := service.AwardBid(ctx, 1, 5, 2)
+
+			Expect(err).To(MatchError(apperror.ErrWantedBidNotFound))
+		})
+	})
+
+	Describe("GetShopRequests", func() {
+		It("converts the page into an offset", func() {
+			repo.EXPECT().SelectShopRequests(ctx, uint(1), uint(7), 10, 20).Return([]entity.WantedRequest{}, 0, nil)
+
+			_, _, err := service.GetShopRequests(ctx, 1, 7, 3, 10)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
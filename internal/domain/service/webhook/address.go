@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// isPublicIP сообщает, можно ли отправлять вебхук на адрес. Внутренние адреса запрещены,
+// иначе владелец магазина может заставить сервер обращаться к сервисам внутри сети
+// и к метаданным облака (169.254.169.254).
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsUnspecified()
+}
+
+// dialControl проверяет адрес непосредственно перед соединением: DNS-имя вебхука
+// могло начать указывать на внутренний адрес уже после проверки при создании
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// newDeliveryClient создаёт клиент для доставки вебхуков. Перенаправления не выполняются:
+// ответ 3xx считается неудачной попыткой, иначе через редирект можно попасть на внутренний адрес.
+func newDeliveryClient(timeout time.Duration, allowPrivateHosts bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateHosts {
+		dialer.Control = dialControl
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// через прокси проверялся бы адрес прокси, а не получателя
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
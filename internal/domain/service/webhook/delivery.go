@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/worker"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=delivery_mock_test.go -package=webhook DeliveryRepository
+
+type DeliveryRepository interface {
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.WebhookAttempt, error)
+	SaveAttemptResult(ctx context.Context, result entity.WebhookAttemptResult, now time.Time) error
+}
+
+const (
+	EventHeader     = "X-Stawberry-Event"
+	DeliveryHeader  = "X-Stawberry-Delivery"
+	TimestampHeader = "X-Stawberry-Timestamp"
+	SignatureHeader = "X-Stawberry-Signature"
+
+	// максимальная задержка между попытками, до которой растёт экспоненциальная
+	maxRetryBackoff = 6 * time.Hour
+)
+
+// Sign подписывает тело запроса: HMAC-SHA256 от "<timestamp>.<body>" с секретом вебхука.
+// Получатель вычисляет подпись так же и сравнивает её с заголовком X-Stawberry-Signature.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliveryWorker периодически отправляет наступившие доставки вебхуков.
+// Неудачные попытки повторяются с экспоненциальной задержкой, после MaxAttempts
+// доставка помечается failed и может быть отправлена заново только вручную.
+type DeliveryWorker struct {
+	*worker.Runner
+
+	repo         DeliveryRepository
+	client       *http.Client
+	batchSize    int
+	maxAttempts  int
+	retryBackoff time.Duration
+	log          *zap.Logger
+}
+
+func NewDeliveryWorker(repo DeliveryRepository, cfg *config.WebhookConfig, log *zap.Logger) *DeliveryWorker {
+	w := &DeliveryWorker{
+		repo:         repo,
+		client:       newDeliveryClient(cfg.Timeout, cfg.AllowPrivateHosts),
+		batchSize:    cfg.BatchSize,
+		maxAttempts:  cfg.MaxAttempts,
+		retryBackoff: cfg.RetryBackoff,
+		log:          log,
+	}
+	w.Runner = worker.NewRunner("webhook delivery worker", cfg.PollInterval, func(ctx context.Context) error {
+		_, err := w.DeliverDue(ctx)
+		return err
+	}, log)
+	return w
+}
+
+// DeliverDue отправляет наступившие доставки пакетами, пока они не закончатся,
+// и возвращает количество выполненных попыток
+func (w *DeliveryWorker) DeliverDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		now := time.Now()
+		// доставка не вернётся в очередь, пока идёт попытка
+		attempts, err := w.repo.ClaimDueDeliveries(ctx, now, now.Add(2*w.client.Timeout), w.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		var wg sync.WaitGroup
+		for _, attempt := range attempts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.deliver(ctx, attempt)
+			}()
+		}
+		wg.Wait()
+		total += len(attempts)
+
+		if len(attempts) < w.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+func (w *DeliveryWorker) deliver(ctx context.Context, attempt entity.WebhookAttempt) {
+	result := entity.WebhookAttemptResult{DeliveryID: attempt.ID, Status: entity.WebhookDeliveryDelivered}
+
+	statusCode, err := w.send(ctx, attempt)
+	result.StatusCode = statusCode
+	if err != nil {
+		result.Error = err.Error()
+		result.Status = entity.WebhookDeliveryPending
+		result.NextAttemptAt = time.Now().Add(worker.Backoff(w.retryBackoff, maxRetryBackoff, attempt.Attempts))
+		if attempt.Attempts+1 >= w.maxAttempts {
+			result.Status = entity.WebhookDeliveryFailed
+		}
+	}
+
+	// результат записывается и при остановке воркера, иначе попытка повторится
+	err = w.repo.SaveAttemptResult(context.WithoutCancel(ctx), result, time.Now())
+	if err != nil {
+		w.log.Error("failed to save webhook delivery attempt", zap.Uint("delivery id", attempt.ID), zap.Error(err))
+	}
+}
+
+func (w *DeliveryWorker) send(ctx context.Context, attempt entity.WebhookAttempt) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, attempt.URL, bytes.NewReader(attempt.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, attempt.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(attempt.ID), 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(attempt.Secret, timestamp, attempt.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
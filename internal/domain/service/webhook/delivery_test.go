@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+var _ = Describe("DeliveryWorker", func() {
+	var (
+		ctrl     *gomock.Controller
+		repo     *MockDeliveryRepository
+		worker   *DeliveryWorker
+		receiver *httptest.Server
+		status   int
+		received chan *http.Request
+		bodies   chan []byte
+		ctx      context.Context
+		attempt  entity.WebhookAttempt
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockDeliveryRepository(ctrl)
+		worker = NewDeliveryWorker(repo, &config.WebhookConfig{
+			PollInterval: time.Second,
+			BatchSize:    10,
+			MaxAttempts:  3,
+			RetryBackoff: time.Minute,
+			Timeout:      time.Second,
+			// получатель в тестах слушает на 127.0.0.1
+			AllowPrivateHosts: true,
+		}, zap.NewNop())
+		ctx = context.Background()
+
+		status = http.StatusNoContent
+		received = make(chan *http.Request, 1)
+		bodies = make(chan []byte, 1)
+		receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- r
+			bodies <- body
+			w.WriteHeader(status)
+		}))
+
+		attempt = entity.WebhookAttempt{
+			WebhookDelivery: entity.WebhookDelivery{
+				ID:        11,
+				EventType: entity.WebhookEventOfferCreated,
+				Payload:   []byte(`{"event":"offer.created"}`),
+			},
+			URL:    receiver.URL,
+			Secret: "0123456789abcdef",
+		}
+	})
+
+	AfterEach(func() {
+		receiver.Close()
+		ctrl.Finish()
+	})
+
+	expectAttempt := func() {
+		repo.EXPECT().ClaimDueDeliveries(ctx, gomock.Any(), gomock.Any(), 10).
+			Return([]entity.WebhookAttempt{attempt}, nil)
+	}
+
+	It("sends a signed request and marks the delivery as delivered", func() {
+		expectAttempt()
+		repo.EXPECT().SaveAttemptResult(gomock.Any(), entity.WebhookAttemptResult{
+			DeliveryID: 11, Status: entity.WebhookDeliveryDelivered, StatusCode: http.StatusNoContent,
+		}, gomock.Any()).Return(nil)
+
+		delivered, err := worker.DeliverDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivered).To(Equal(1))
+
+		var req *http.Request
+		Expect(received).To(Receive(&req))
+		body := <-bodies
+		Expect(body).To(Equal(attempt.Payload))
+		Expect(req.Header.Get(EventHeader)).To(Equal(entity.WebhookEventOfferCreated))
+		Expect(req.Header.Get(DeliveryHeader)).To(Equal("11"))
+
+		timestamp, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.Header.Get(SignatureHeader)).To(Equal(Sign(attempt.Secret, timestamp, body)))
+	})
+
+	It("schedules a retry with exponential backoff after an error response", func() {
+		status = http.StatusInternalServerError
+		attempt.Attempts = 1
+		expectAttempt()
+		repo.EXPECT().SaveAttemptResult(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, result entity.WebhookAttemptResult, _ time.Time) {
+				Expect(result.Status).To(Equal(entity.WebhookDeliveryPending))
+				Expect(result.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(result.Error).To(ContainSubstring("500"))
+				Expect(result.NextAttemptAt).To(BeTemporally("~", time.Now().Add(2*time.Minute), time.Second))
+			})
+
+		_, err := worker.DeliverDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("gives up after the last attempt", func() {
+		status = http.StatusBadGateway
+		attempt.Attempts = 2
+		expectAttempt()
+		repo.EXPECT().SaveAttemptResult(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, result entity.WebhookAttemptResult, _ time.Time) {
+				Expect(result.Status).To(Equal(entity.WebhookDeliveryFailed))
+			})
+
+		_, err := worker.DeliverDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("does not follow redirects", func() {
+		redirected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Fail("the redirect must not be followed")
+		}))
+		defer redirected.Close()
+		receiver.Config.Handler = http.RedirectHandler(redirected.URL, http.StatusFound)
+		expectAttempt()
+		repo.EXPECT().SaveAttemptResult(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, result entity.WebhookAttemptResult, _ time.Time) {
+				Expect(result.Status).To(Equal(entity.WebhookDeliveryPending))
+				Expect(result.StatusCode).To(Equal(http.StatusFound))
+			})
+
+		_, err := worker.DeliverDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("refuses to connect to a private address", func() {
+		worker = NewDeliveryWorker(repo, &config.WebhookConfig{
+			PollInterval: time.Second,
+			BatchSize:    10,
+			MaxAttempts:  3,
+			RetryBackoff: time.Minute,
+			Timeout:      time.Second,
+		}, zap.NewNop())
+		expectAttempt()
+		repo.EXPECT().SaveAttemptResult(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, result entity.WebhookAttemptResult, _ time.Time) {
+				Expect(result.Status).To(Equal(entity.WebhookDeliveryPending))
+				Expect(result.StatusCode).To(BeZero())
+				Expect(result.Error).To(ContainSubstring("is not public"))
+			})
+
+		_, err := worker.DeliverDue(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(received).NotTo(Receive())
+	})
+})
+
+var _ = Describe("Sign", func() {
+	It("produces the documented HMAC-SHA256 signature", func() {
+		// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+		Expect(Sign("secret", 1700000000, []byte("{}"))).
+			To(Equal("sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"))
+	})
+})
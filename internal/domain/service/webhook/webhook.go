@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=webhook_mock_test.go -package=webhook Repository,OfferRepository
+
+type Repository interface {
+	InsertWebhook(ctx context.Context, webhook entity.Webhook, userID uint) (entity.Webhook, error)
+	SelectShopWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, shopID, webhookID, userID uint) error
+	SelectDeliveries(
+		ctx context.Context,
+		shopID, webhookID, userID uint,
+		limit, offset int,
+	) ([]entity.WebhookDelivery, int, error)
+	Redeliver(
+		ctx context.Context,
+		shopID, webhookID, deliveryID, userID uint,
+		now time.Time,
+	) (entity.WebhookDelivery, error)
+	EnqueueDeliveries(ctx context.Context, shopID uint, eventType, dedupeKey string, payload []byte) (int, error)
+}
+
+// OfferRepository возвращает магазин, покупателя и валюту оффера для события
+type OfferRepository interface {
+	SelectOfferUpdates(ctx context.Context, offerIDs []uint) ([]entity.OfferUpdate, error)
+}
+
+const (
+	// длина сгенерированного секрета в байтах, в hex он вдвое длиннее
+	secretBytes     = 32
+	minSecretLength = 16
+)
+
+// Service управляет вебхуками магазинов и ставит события в очередь на доставку.
+// События приходят из outbox, саму доставку выполняет DeliveryWorker.
+type Service struct {
+	repo              Repository
+	offers            OfferRepository
+	allowPrivateHosts bool
+	lookupIP          func(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+func NewService(repo Repository, offers OfferRepository, cfg *config.WebhookConfig) *Service {
+	return &Service{
+		repo:              repo,
+		offers:            offers,
+		allowPrivateHosts: cfg.AllowPrivateHosts,
+		lookupIP:          net.DefaultResolver.LookupIP,
+	}
+}
+
+// CreateWebhook регистрирует вебхук магазина. Если секрет не задан, он генерируется;
+// секрет возвращается только здесь, чтобы магазин сохранил его для проверки подписи.
+func (s *Service) CreateWebhook(ctx context.Context, webhook entity.Webhook, userID uint) (entity.Webhook, error) {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return entity.Webhook{}, apperror.New(apperror.BadRequest, "url must be an absolute http(s) URL", err)
+	}
+	if err := s.checkHost(ctx, u.Hostname()); err != nil {
+		return entity.Webhook{}, err
+	}
+
+	if len(webhook.Events) == 0 {
+		return entity.Webhook{}, apperror.New(apperror.BadRequest, "at least one event must be selected", nil)
+	}
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		if !slices.Contains(entity.WebhookEvents, event) {
+			return entity.Webhook{}, apperror.New(apperror.BadRequest, "unknown webhook event: "+event, nil)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	webhook.Events = events
+
+	if webhook.Secret == "" {
+		secret := make([]byte, secretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return entity.Webhook{}, apperror.New(apperror.InternalError, "failed to generate webhook secret", err)
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	} else if len(webhook.Secret) < minSecretLength {
+		return entity.Webhook{}, apperror.New(apperror.BadRequest, "secret must be at least 16 characters long", nil)
+	}
+
+	return s.repo.InsertWebhook(ctx, webhook, userID)
+}
+
+// checkHost отклоняет хост, который указывает на внутренний адрес. При доставке адрес
+// проверяется ещё раз, так как DNS-запись могла измениться.
+func (s *Service) checkHost(ctx context.Context, host string) error {
+	if s.allowPrivateHosts {
+		return nil
+	}
+
+	ips, err := s.lookupIP(ctx, "ip", host)
+	if err != nil {
+		return apperror.New(apperror.BadRequest, "url host cannot be resolved", err)
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return apperror.New(apperror.BadRequest, "url must point to a public address", nil)
+		}
+	}
+	return nil
+}
+
+func (s *Service) GetShopWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error) {
+	return s.repo.SelectShopWebhooks(ctx, shopID, userID)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, shopID, webhookID, userID uint) error {
+	return s.repo.DeleteWebhook(ctx, shopID, webhookID, userID)
+}
+
+func (s *Service) GetDeliveries(
+	ctx context.Context,
+	shopID, webhookID, userID uint,
+	page, limit int,
+) ([]entity.WebhookDelivery, int, error) {
+	offset := (page - 1) * limit
+
+	return s.repo.SelectDeliveries(ctx, shopID, webhookID, userID, limit, offset)
+}
+
+// Redeliver отправляет доставку заново при следующем проходе воркера
+func (s *Service) Redeliver(
+	ctx context.Context,
+	shopID, webhookID, deliveryID, userID uint,
+) (entity.WebhookDelivery, error) {
+	return s.repo.Redeliver(ctx, shopID, webhookID, deliveryID, userID, time.Now())
+}
+
+// envelope тело запроса вебхука. ID одинаков у всех доставок одного события,
+// по нему магазин может отбросить повтор.
+type envelope struct {
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+type offerData struct {
+	OfferID  uint    `json:"offer_id"`
+	Status   string  `json:"status"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+	ShopID   uint    `json:"shop_id"`
+	UserID   uint    `json:"user_id"`
+	// Reason уточняет offer.status_changed: status_changed или expired
+	Reason string `json:"reason"`
+}
+
+type guestOfferData struct {
+	OfferID    uint    `json:"offer_id"`
+	ProductID  uint    `json:"product_id"`
+	ShopID     uint    `json:"shop_id"`
+	Price      float64 `json:"price"`
+	Currency   string  `json:"currency"`
+	GuestName  string  `json:"guest_name"`
+	GuestEmail string  `json:"guest_email"`
+	GuestPhone string  `json:"guest_phone"`
+}
+
+// EnqueueOfferEvent ставит событие из журнала оффера в очередь на вебхуки магазина-получателя.
+// Статус и цена берутся из события, поэтому запоздавшая доставка описывает само событие.
+// Повторный вызов для того же события новых доставок не создаёт.
+func (s *Service) EnqueueOfferEvent(ctx context.Context, update entity.OfferUpdate) error {
+	offers, err := s.offers.SelectOfferUpdates(ctx, []uint{update.OfferID})
+	if err != nil {
+		return err
+	}
+	if len(offers) == 0 {
+		return apperror.ErrOfferNotFound
+	}
+	offer := offers[0]
+
+	event := entity.WebhookEventOfferStatusChanged
+	if update.Type == entity.OfferUpdateCreated {
+		event = entity.WebhookEventOfferCreated
+	}
+
+	return s.enqueue(ctx, offer.ShopID, event, fmt.Sprintf("offer_event:%d", update.EventID),
+		update.OccurredAt, offerData{
+			OfferID:  update.OfferID,
+			Status:   update.Status,
+			Price:    update.Price,
+			Currency: offer.Currency,
+			ShopID:   offer.ShopID,
+			UserID:   offer.BuyerID,
+			Reason:   update.Type,
+		})
+}
+
+// EnqueueGuestOffer ставит новый оффер гостя в очередь на вебхуки магазина.
+// Повторный вызов для того же оффера новых доставок не создаёт.
+func (s *Service) EnqueueGuestOffer(
+	ctx context.Context,
+	offerID uint,
+	offer entity.GuestOfferPayload,
+	occurredAt time.Time,
+) error {
+	return s.enqueue(ctx, offer.ShopID, entity.WebhookEventGuestOfferReceived,
+		fmt.Sprintf("guest_offer:%d", offerID), occurredAt, guestOfferData{
+			OfferID:    offerID,
+			ProductID:  offer.ProductID,
+			ShopID:     offer.ShopID,
+			Price:      offer.Price,
+			Currency:   offer.Currency,
+			GuestName:  offer.GuestName,
+			GuestEmail: offer.GuestEmail,
+			GuestPhone: offer.GuestPhone,
+		})
+}
+
+func (s *Service) enqueue(
+	ctx context.Context,
+	shopID uint,
+	event, eventKey string,
+	occurredAt time.Time,
+	data any,
+) error {
+	payload, err := json.Marshal(envelope{ID: eventKey, Event: event, OccurredAt: occurredAt, Data: data})
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to encode webhook payload", err)
+	}
+
+	_, err = s.repo.EnqueueDeliveries(ctx, shopID, event, eventKey, payload)
+	return err
+}
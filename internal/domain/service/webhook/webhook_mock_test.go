@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook.go -destination=webhook_mock_test.go -package=webhook Repository,OfferRepository
+//
+
+// Package webhook is a generated GoMock package.
+package webhook
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockRepository) DeleteWebhook(ctx context.Context, shopID, webhookID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, shopID, webhookID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockRepositoryMockRecorder) DeleteWebhook(ctx, shopID, webhookID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockRepository)(nil).DeleteWebhook), ctx, shopID, webhookID, userID)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockRepository) EnqueueDeliveries(ctx context.Context, shopID uint, eventType, dedupeKey string, payload []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, shopID, eventType, dedupeKey, payload)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockRepositoryMockRecorder) EnqueueDeliveries(ctx, shopID, eventType, dedupeKey, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockRepository)(nil).EnqueueDeliveries), ctx, shopID, eventType, dedupeKey, payload)
+}
+
+// InsertWebhook mocks base method.
+func (m *MockRepository) InsertWebhook(ctx context.Context, webhook entity.Webhook, userID uint) (entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", ctx, webhook, userID)
+	ret0, _ := ret[0].(entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockRepositoryMockRecorder) InsertWebhook(ctx, webhook, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockRepository)(nil).InsertWebhook), ctx, webhook, userID)
+}
+
+// Redeliver mocks base method.
+func (m *MockRepository) Redeliver(ctx context.Context, shopID, webhookID, deliveryID, userID uint, now time.Time) (entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, shopID, webhookID, deliveryID, userID, now)
+	ret0, _ := ret[0].(entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockRepositoryMockRecorder) Redeliver(ctx, shopID, webhookID, deliveryID, userID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockRepository)(nil).Redeliver), ctx, shopID, webhookID, deliveryID, userID, now)
+}
+
+// SelectDeliveries mocks base method.
+func (m *MockRepository) SelectDeliveries(ctx context.Context, shopID, webhookID, userID uint, limit, offset int) ([]entity.WebhookDelivery, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDeliveries", ctx, shopID, webhookID, userID, limit, offset)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectDeliveries indicates an expected call of SelectDeliveries.
+func (mr *MockRepositoryMockRecorder) SelectDeliveries(ctx, shopID, webhookID, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDeliveries", reflect.TypeOf((*MockRepository)(nil).SelectDeliveries), ctx, shopID, webhookID, userID, limit, offset)
+}
+
+// SelectShopWebhooks mocks base method.
+func (m *MockRepository) SelectShopWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopWebhooks", ctx, shopID, userID)
+	ret0, _ := ret[0].([]entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectShopWebhooks indicates an expected call of SelectShopWebhooks.
+func (mr *MockRepositoryMockRecorder) SelectShopWebhooks(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopWebhooks", reflect.TypeOf((*MockRepository)(nil).SelectShopWebhooks), ctx, shopID, userID)
+}
+
+// MockOfferRepository is a mock of OfferRepository interface.
+type MockOfferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOfferRepositoryMockRecorder is the mock recorder for MockOfferRepository.
+type MockOfferRepositoryMockRecorder struct {
+	mock *MockOfferRepository
+}
+
+// NewMockOfferRepository creates a new mock instance.
+func NewMockOfferRepository(ctrl *gomock.Controller) *MockOfferRepository {
+	mock := &MockOfferRepository{ctrl: ctrl}
+	mock.recorder = &MockOfferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferRepository) EXPECT() *MockOfferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SelectOfferUpdates mocks base method.
+func (m *MockOfferRepository) SelectOfferUpdates(ctx context.Context, offerIDs []uint) ([]entity.OfferUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOfferUpdates", ctx, offerIDs)
+	ret0, _ := ret[0].([]entity.OfferUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOfferUpdates indicates an expected call of SelectOfferUpdates.
+func (mr *MockOfferRepositoryMockRecorder) SelectOfferUpdates(ctx, offerIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOfferUpdates", reflect.TypeOf((*MockOfferRepository)(nil).SelectOfferUpdates), ctx, offerIDs)
+}
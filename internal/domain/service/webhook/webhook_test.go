@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+func TestWebhookServiceSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Service Suite")
+}
+
+var _ = Describe("Service", func() {
+	var (
+		ctrl    *gomock.Controller
+		repo    *MockRepository
+		offers  *MockOfferRepository
+		service *Service
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repo = NewMockRepository(ctrl)
+		offers = NewMockOfferRepository(ctrl)
+		service = NewService(repo, offers, &config.WebhookConfig{})
+		service.lookupIP = func(_ context.Context, _, host string) ([]net.IP, error) {
+			switch host {
+			case "shop.example.com":
+				return []net.IP{net.ParseIP("203.0.113.10")}, nil
+			case "localhost":
+				return []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}, nil
+			case "rebind.example.com":
+				return []net.IP{net.ParseIP("203.0.113.10"), net.ParseIP("10.0.0.5")}, nil
+			}
+			if ip := net.ParseIP(host); ip != nil {
+				return []net.IP{ip}, nil
+			}
+			return nil, errors.New("no such host")
+		}
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateWebhook", func() {
+		It("generates a secret and removes duplicate events", func() {
+			repo.EXPECT().InsertWebhook(ctx, gomock.Any(), uint(1)).
+				DoAndReturn(func(_ context.Context, w entity.Webhook, _ uint) (entity.Webhook, error) {
+					w.ID = 3
+					return w, nil
+				})
+
+			webhook, err := service.CreateWebhook(ctx, entity.Webhook{
+				ShopID: 1,
+				URL:    "https://shop.example.com/hooks",
+				Events: []string{entity.WebhookEventOfferCreated, entity.WebhookEventOfferCreated},
+			}, 1)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhook.ID).To(Equal(uint(3)))
+			Expect(webhook.Secret).To(HaveLen(2 * secretBytes))
+			Expect(webhook.Events).To(Equal([]string{entity.WebhookEventOfferCreated}))
+		})
+
+		It("keeps a secret chosen by the shop", func() {
+			repo.EXPECT().InsertWebhook(ctx, gomock.Any(), uint(1)).
+				DoAndReturn(func(_ context.Context, w entity.Webhook, _ uint) (entity.Webhook, error) {
+					return w, nil
+				})
+
+			webhook, err := service.CreateWebhook(ctx, entity.Webhook{
+				URL:    "http://shop.example.com:8081/hooks",
+				Events: []string{entity.WebhookEventGuestOfferReceived},
+				Secret: "0123456789abcdef",
+			}, 1)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhook.Secret).To(Equal("0123456789abcdef"))
+		})
+
+		It("accepts a private address when private hosts are allowed", func() {
+			service.allowPrivateHosts = true
+			repo.EXPECT().InsertWebhook(ctx, gomock.Any(), uint(1)).Return(entity.Webhook{ID: 4}, nil)
+
+			_, err := service.CreateWebhook(ctx, entity.Webhook{
+				URL: "http://localhost:8081/hooks", Events: []string{entity.WebhookEventOfferCreated},
+			}, 1)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		webhookTo := func(url string) entity.Webhook {
+			return entity.Webhook{URL: url, Events: []string{entity.WebhookEventOfferCreated}}
+		}
+
+		DescribeTable("rejects invalid webhooks",
+			func(webhook entity.Webhook) {
+				_, err := service.CreateWebhook(ctx, webhook, 1)
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("relative url", entity.Webhook{URL: "/hooks", Events: []string{entity.WebhookEventOfferCreated}}),
+			Entry("ftp url", entity.Webhook{URL: "ftp://shop.example.com", Events: []string{entity.WebhookEventOfferCreated}}),
+			Entry("no events", entity.Webhook{URL: "https://shop.example.com"}),
+			Entry("unknown event", entity.Webhook{URL: "https://shop.example.com", Events: []string{"offer.deleted"}}),
+			Entry("short secret", entity.Webhook{
+				URL: "https://shop.example.com", Events: []string{entity.WebhookEventOfferCreated}, Secret: "short",
+			}),
+			Entry("unresolvable host", webhookTo("https://missing.example.com")),
+			Entry("localhost", webhookTo("http://localhost:8081")),
+			Entry("loopback address", webhookTo("http://127.0.0.1")),
+			Entry("ipv6 loopback", webhookTo("http://[::1]/hooks")),
+			Entry("private address", webhookTo("http://192.168.1.10")),
+			Entry("cloud metadata", webhookTo("http://169.254.169.254/latest/meta-data")),
+			Entry("unspecified address", webhookTo("http://0.0.0.0")),
+			Entry("host with a private address among public ones", webhookTo("https://rebind.example.com")),
+		)
+	})
+
+	Describe("EnqueueOfferEvent", func() {
+		// оффер уже принят, а событие - о встречном предложении, доставленное с опозданием
+		current := entity.OfferUpdate{OfferID: 7, Status: "accepted", Price: 95, Currency: "USD", BuyerID: 2, ShopID: 4}
+		update := entity.OfferUpdate{EventID: 40, OfferID: 7, Status: "countered", Price: 90}
+
+		DescribeTable("enqueues the event as it happened for the offer's shop",
+			func(updateType, event string) {
+				update.Type = updateType
+				offers.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return([]entity.OfferUpdate{current}, nil)
+				repo.EXPECT().EnqueueDeliveries(ctx, uint(4), event, "offer_event:40", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uint, _, _ string, payload []byte) (int, error) {
+						var body struct {
+							ID    string `json:"id"`
+							Event string `json:"event"`
+							Data  struct {
+								OfferID uint    `json:"offer_id"`
+								Status  string  `json:"status"`
+								Price   float64 `json:"price"`
+								UserID  uint    `json:"user_id"`
+								Reason  string  `json:"reason"`
+							} `json:"data"`
+						}
+						Expect(json.Unmarshal(payload, &body)).To(Succeed())
+						Expect(body.ID).To(Equal("offer_event:40"))
+						Expect(body.Event).To(Equal(event))
+						Expect(body.Data.OfferID).To(Equal(uint(7)))
+						Expect(body.Data.Status).To(Equal("countered"))
+						Expect(body.Data.Price).To(Equal(90.0))
+						Expect(body.Data.UserID).To(Equal(uint(2)))
+						Expect(body.Data.Reason).To(Equal(updateType))
+						return 1, nil
+					})
+
+				Expect(service.EnqueueOfferEvent(ctx, update)).To(Succeed())
+			},
+			Entry("created", entity.OfferUpdateCreated, entity.WebhookEventOfferCreated),
+			Entry("status changed", entity.OfferUpdateStatusChanged, entity.WebhookEventOfferStatusChanged),
+			Entry("expired", entity.OfferUpdateExpired, entity.WebhookEventOfferStatusChanged),
+		)
+
+		It("returns the error so that the outbox retries the event", func() {
+			offers.EXPECT().SelectOfferUpdates(ctx, []uint{7}).Return([]entity.OfferUpdate{current}, nil)
+			repo.EXPECT().EnqueueDeliveries(ctx, uint(4), gomock.Any(), "offer_event:40", gomock.Any()).
+				Return(0, errors.New("db is down"))
+
+			Expect(service.EnqueueOfferEvent(ctx, update)).To(MatchError("db is down"))
+		})
+	})
+
+	Describe("EnqueueGuestOffer", func() {
+		It("enqueues guest_offer.received for the shop and returns the error", func() {
+			repo.EXPECT().EnqueueDeliveries(ctx, uint(5), entity.WebhookEventGuestOfferReceived,
+				"guest_offer:9", gomock.Any()).
+				Return(0, errors.New("db is down"))
+
+			err := service.EnqueueGuestOffer(ctx, 9, entity.GuestOfferPayload{ProductID: 1, ShopID: 5, Price: 10},
+				time.Now())
+			Expect(err).To(MatchError("db is down"))
+		})
+	})
+})
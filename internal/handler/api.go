@@ -26,6 +26,18 @@ import (
 	guesthandler "github.com/EM-Stawberry/Stawberry/internal/handler/guestoffer"
 )
 
+// NewEngine создаёт роутер, который берёт адрес клиента из X-Forwarded-For
+// только у запросов от trustedProxies. Без прокси ClientIP это адрес соединения,
+// иначе клиент подставит любой адрес и обойдёт лимиты по IP
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	return router, nil
+}
+
 // @Summary		Получить статус сервера
 // @Description	Возвращает статус сервера и текущее время
 // @Tags			health
@@ -35,7 +47,17 @@ import (
 func SetupRouter(
 	healthH *HealthHandler,
 	productH *ProductHandler,
+	inventoryH *InventoryHandler,
+	categoryH *CategoryHandler,
+	currencyH *CurrencyHandler,
 	offerH *OfferHandler,
+	offerStreamH *OfferStreamHandler,
+	offerMessageH *OfferMessageHandler,
+	orderH *OrderHandler,
+	autoResponseH *AutoResponseHandler,
+	pricingPolicyH *PricingPolicyHandler,
+	wantedH *WantedHandler,
+	webhookH *WebhookHandler,
 	userH *UserHandler,
 	notificationH *NotificationHandler,
 	productReviewH *reviews.ProductReviewsHandler,
@@ -43,12 +65,17 @@ func SetupRouter(
 	guestOfferH *guesthandler.Handler,
 	userS middleware.UserGetter,
 	tokenS middleware.TokenValidator,
+	idempotencyS middleware.IdempotencyService,
 	basePath string,
+	trustedProxies []string,
 	logger *zap.Logger,
 	auditMiddleware *middleware.AuditMiddleware,
 	auditH *AuditHandler,
 ) *gin.Engine {
-	router := gin.New()
+	router, err := NewEngine(trustedProxies)
+	if err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
 
 	// Добавляет кастомные валидаторы для использования в json-тегах
 	setupValitators()
@@ -58,7 +85,7 @@ func SetupRouter(
 	router.Use(middleware.ZapRecovery(logger))
 	router.Use(middleware.CORS())
 	router.Use(middleware.Errors())
-	router.Use(middleware.Timeout())
+	router.Use(middleware.Timeout(basePath+OfferEventsPath, basePath+InventoryExportPath))
 
 	// Swagger UI эндпоинт
 	docs.SwaggerInfo.BasePath = basePath
@@ -92,18 +119,97 @@ func SetupRouter(
 	{
 		public.GET("/products", productH.GetProducts)
 		public.GET("/products/:id", productH.GetProductByID)
+		public.GET("/currency-rates", currencyH.GetRates)
+	}
+
+	// эндпойнты дерева категорий
+	{
+		public.GET("/categories", categoryH.GetTree)
+		public.GET("/categories/:categoryID", categoryH.GetSubtree)
+		public.GET("/categories/:categoryID/path", categoryH.GetPath)
 	}
 
 	// эндпойнты для гостевых заявок
 	{
-		base.POST("/guest/offers", guestOfferH.PostGuestOffer)
+		base.POST("/guest/offers", middleware.Idempotency(idempotencyS), guestOfferH.PostGuestOffer)
+		base.GET("/guest/offers/:token", guestOfferH.GetGuestOffer)
+		base.POST("/guest/offers/:token/cancel", guestOfferH.CancelGuestOffer)
 	}
 
 	// эндпойнты запросов на покупку
 	{
+		secured.GET(OfferEventsPath, offerStreamH.StreamOfferUpdates)
+		secured.GET("offers/:offerID", offerH.GetOffer)
 		secured.PATCH("offers/:offerID", offerH.PatchOfferStatus)
+		secured.GET("offers/:offerID/history", offerH.GetOfferHistory)
+		secured.GET("offers/:offerID/messages", offerMessageH.GetMessages)
+		secured.POST("offers/:offerID/messages", offerMessageH.PostMessage)
+		secured.GET("messages/unread", offerMessageH.GetUnread)
 		secured.GET("offers", offerH.GetUserOffers)
-		secured.POST("offers", offerH.PostOffer)
+		secured.POST("offers", middleware.Idempotency(idempotencyS), offerH.PostOffer)
+		secured.GET("shop/offers", offerH.GetShopOffers)
+		secured.PATCH("shop/offers", offerH.PatchShopOffers)
+	}
+
+	// эндпойнты заказов
+	{
+		secured.GET("orders", orderH.GetUserOrders)
+		secured.GET("orders/:orderID", orderH.GetOrder)
+		secured.PATCH("orders/:orderID", orderH.PatchOrderStatus)
+		secured.GET("shop/orders", orderH.GetShopOrders)
+	}
+
+	// эндпойнты каталога товаров магазина
+	{
+		secured.GET("shops/:shopID/products", productH.GetShopProducts)
+		secured.POST("shops/:shopID/products", productH.PostProduct)
+		secured.PATCH("shops/:shopID/products/:productID", productH.PatchProduct)
+		secured.POST("shops/:shopID/products/:productID/archive", productH.ArchiveProduct)
+	}
+
+	// эндпойнты инвентаря магазина: цены, валюта и доступность товаров
+	{
+		secured.GET("shops/:shopID/inventory", inventoryH.GetInventory)
+		secured.POST("shops/:shopID/inventory", inventoryH.PostItem)
+		secured.POST("shops/:shopID/inventory/import", inventoryH.ImportInventory)
+		secured.GET(InventoryExportPath, inventoryH.ExportInventory)
+		secured.PATCH("shops/:shopID/inventory/:productID", inventoryH.PatchItem)
+		secured.DELETE("shops/:shopID/inventory/:productID", inventoryH.DeleteItem)
+		secured.GET("shops/:shopID/inventory/:productID/history", inventoryH.GetItemHistory)
+	}
+
+	// эндпойнты правил автоответа магазина
+	{
+		secured.GET("shops/:shopID/auto-rules", autoResponseH.GetRules)
+		secured.PUT("shops/:shopID/auto-rules", autoResponseH.PutRule)
+		secured.DELETE("shops/:shopID/auto-rules/:ruleID", autoResponseH.DeleteRule)
+	}
+
+	// эндпойнты ценовой политики магазина
+	{
+		secured.GET("shops/:shopID/pricing-policy", pricingPolicyH.GetPolicy)
+		secured.PUT("shops/:shopID/pricing-policy", pricingPolicyH.PutPolicy)
+	}
+
+	// эндпойнты обратных аукционов: запросы покупателей и ставки магазинов
+	{
+		secured.POST("wanted", wantedH.PostRequest)
+		secured.GET("wanted", wantedH.GetUserRequests)
+		secured.GET("wanted/:requestID", wantedH.GetRequest)
+		secured.POST("wanted/:requestID/cancel", wantedH.CancelRequest)
+		secured.POST("wanted/:requestID/bids/:bidID/award", wantedH.AwardBid)
+		secured.GET("shops/:shopID/wanted", wantedH.GetShopRequests)
+		secured.PUT("shops/:shopID/wanted/:requestID/bid", wantedH.PutBid)
+		secured.DELETE("shops/:shopID/wanted/:requestID/bid", wantedH.DeleteBid)
+	}
+
+	// эндпойнты вебхуков магазина
+	{
+		secured.GET("shops/:shopID/webhooks", webhookH.GetWebhooks)
+		secured.POST("shops/:shopID/webhooks", webhookH.PostWebhook)
+		secured.DELETE("shops/:shopID/webhooks/:webhookID", webhookH.DeleteWebhook)
+		secured.GET("shops/:shopID/webhooks/:webhookID/deliveries", webhookH.GetDeliveries)
+		secured.POST("shops/:shopID/webhooks/:webhookID/deliveries/:deliveryID/redeliver", webhookH.Redeliver)
 	}
 
 	// эндпойнты отзывов
@@ -114,8 +220,14 @@ func SetupRouter(
 		secured.POST("/sellers/:id/reviews", sellerReviewH.AddReview)
 	}
 
-	// заглушка эндпоинта админа
-	// admin := secured.Group("/admin", middleware.Admin)
+	// admin это эндпойнты, доступные только администраторам
+	admin := public.Group("/admin", middleware.AuthMiddleware(userS, tokenS), middleware.Admin())
+	{
+		admin.POST("/categories", categoryH.PostCategory)
+		admin.POST("/categories/:categoryID/move", categoryH.MoveCategory)
+		admin.DELETE("/categories/:categoryID", categoryH.DeleteCategory)
+	}
+
 	secured.GET("/audit", auditH.DisplayLogs)
 
 	// Эндпоинты для бд
@@ -124,8 +236,7 @@ func SetupRouter(
 		secured.POST("/dev/clear-db", clearDB)
 	}
 
-	// Эти заглушки можно убрать после реализации соответствующих хендлеров
-	_ = productH
+	// Эту заглушку можно убрать после реализации соответствующего хендлера
 	_ = notificationH
 
 	return router
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	guestofferservice "github.com/EM-Stawberry/Stawberry/internal/domain/service/guestoffer"
+	guesthandler "github.com/EM-Stawberry/Stawberry/internal/handler/guestoffer"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("NewEngine", func() {
+	const guestOfferBody = `{"product_id": 1, "store_id": 1, "offer_price": 10, "currency": "USD",
+		"guest_name": "guest", "guest_email": "guest@example.com", "guest_phone": "guestphone"}`
+
+	var (
+		ctrl        *gomock.Controller
+		mockService *guestofferservice.MockService
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockService = guestofferservice.NewMockService(ctrl)
+		gin.SetMode(gin.TestMode)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// postGuestOffer отправляет гостевое предложение с подделанным X-Forwarded-For
+	// и возвращает адрес, который дошёл до сервиса
+	postGuestOffer := func(trustedProxies []string) string {
+		router, err := NewEngine(trustedProxies)
+		Expect(err).NotTo(HaveOccurred())
+		router.Use(middleware.Errors())
+		router.POST("/guest/offers", guesthandler.NewHandler(mockService, zap.NewNop()).PostGuestOffer)
+
+		var guestIP string
+		mockService.EXPECT().ProcessGuestOffer(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, data entity.GuestOfferData) error {
+				guestIP = data.GuestIP
+				return nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/guest/offers", bytes.NewBufferString(guestOfferBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+		return guestIP
+	}
+
+	It("ignores X-Forwarded-For when no proxy is trusted", func() {
+		Expect(postGuestOffer(nil)).To(Equal("192.0.2.1"))
+	})
+
+	It("ignores X-Forwarded-For from a client that is not a trusted proxy", func() {
+		Expect(postGuestOffer([]string{"10.0.0.0/8"})).To(Equal("192.0.2.1"))
+	})
+
+	It("takes the client address from a trusted proxy", func() {
+		Expect(postGuestOffer([]string{"192.0.2.1"})).To(Equal("203.0.113.7"))
+	})
+
+	It("rejects an invalid proxy address", func() {
+		_, err := NewEngine([]string{"not-an-ip"})
+		Expect(err).To(HaveOccurred())
+	})
+})
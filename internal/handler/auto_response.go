@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type AutoResponseService interface {
+	GetShopRules(ctx context.Context, shopID, userID uint) ([]entity.AutoResponseRule, error)
+	SaveRule(ctx context.Context, rule entity.AutoResponseRule, userID uint) (entity.AutoResponseRule, error)
+	DeleteRule(ctx context.Context, shopID, ruleID, userID uint) error
+}
+
+type AutoResponseHandler struct {
+	autoResponseService AutoResponseService
+}
+
+func NewAutoResponseHandler(autoResponseService AutoResponseService) *AutoResponseHandler {
+	return &AutoResponseHandler{autoResponseService: autoResponseService}
+}
+
+// shopOwnerParams достаёт из запроса ID магазина и ID пользователя-магазина
+func shopOwnerParams(c *gin.Context) (shopID, userID uint, ok bool) {
+	store, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return 0, 0, false
+	}
+	if !store {
+		_ = c.Error(apperror.New(apperror.Forbidden, "only store accounts can manage shops", nil))
+		return 0, 0, false
+	}
+
+	userID, ok = helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return 0, 0, false
+	}
+
+	id, err := strconv.Atoi(c.Param("shopID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "shopID must be a positive number", err))
+		return 0, 0, false
+	}
+
+	return uint(id), userID, true
+}
+
+// @summary	Get shop auto-response rules
+// @description	Returns the shop-wide rule (without product_id) and per-product rules.
+// @tags		auto-response
+// @produce	json
+// @param		shopID	path		int	true	"Shop ID"
+// @success	200		{object}	dto.GetAutoResponseRulesResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/auto-rules [get]
+func (h *AutoResponseHandler) GetRules(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	rules, err := h.autoResponseService.GetShopRules(c.Request.Context(), shopID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormAutoResponseRules(rules))
+}
+
+// @summary	Save shop auto-response rule
+// @description	Creates or replaces the rule for the whole shop or, with product_id, for one inventory item.
+// @description	Offers at or above accept_from are accepted, below decline_below declined,
+// @description	otherwise countered at counter_price if the offer is lower.
+// @tags		auto-response
+// @accept		json
+// @produce	json
+// @param		shopID	path		int							true	"Shop ID"
+// @param		body	body		dto.PutAutoResponseRuleReq	true	"Rule"
+// @success	200		{object}	dto.AutoResponseRuleResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/auto-rules [put]
+func (h *AutoResponseHandler) PutRule(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutAutoResponseRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid rule data", err))
+		return
+	}
+
+	rule, err := h.autoResponseService.SaveRule(c.Request.Context(), req.ConvertToEntity(shopID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToAutoResponseRuleResp(rule))
+}
+
+// @summary	Delete shop auto-response rule
+// @tags		auto-response
+// @param		shopID	path	int	true	"Shop ID"
+// @param		ruleID	path	int	true	"Rule ID"
+// @success	204
+// @failure	400	{object}	apperror.Error
+// @failure	403	{object}	apperror.Error
+// @failure	404	{object}	apperror.Error
+// @failure	500	{object}	apperror.Error
+// @Router		/shops/{shopID}/auto-rules/{ruleID} [delete]
+func (h *AutoResponseHandler) DeleteRule(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	ruleID, err := strconv.Atoi(c.Param("ruleID"))
+	if err != nil || ruleID <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "ruleID must be a positive number", err))
+		return
+	}
+
+	err = h.autoResponseService.DeleteRule(c.Request.Context(), shopID, uint(ruleID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
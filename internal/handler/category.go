@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryService interface {
+	GetTree(ctx context.Context) ([]entity.Category, error)
+	GetSubtree(ctx context.Context, categoryID uint) (entity.Category, error)
+	GetPath(ctx context.Context, categoryID uint) ([]entity.Category, error)
+	CreateCategory(ctx context.Context, name string, parentID *uint) (entity.Category, error)
+	MoveCategory(ctx context.Context, categoryID uint, parentID *uint) (entity.Category, error)
+	DeleteCategory(ctx context.Context, categoryID uint) error
+}
+
+type CategoryHandler struct {
+	categoryService CategoryService
+}
+
+func NewCategoryHandler(categoryService CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+func categoryIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("categoryID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "categoryID must be a positive number", err))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// @summary	Get category tree
+// @description	All root categories with their subcategories nested in children.
+// @tags		category
+// @produce	json
+// @success	200	{array}		dto.CategoryResp
+// @failure	500	{object}	apperror.Error
+// @Router		/categories [get]
+func (h *CategoryHandler) GetTree(c *gin.Context) {
+	tree, err := h.categoryService.GetTree(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToCategoriesResp(tree))
+}
+
+// @summary	Get category subtree
+// @description	The category with its subcategories nested in children.
+// @tags		category
+// @produce	json
+// @param		categoryID	path		int	true	"Category ID"
+// @success	200			{object}	dto.CategoryResp
+// @failure	400			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/categories/{categoryID} [get]
+func (h *CategoryHandler) GetSubtree(c *gin.Context) {
+	categoryID, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.GetSubtree(c.Request.Context(), categoryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToCategoryResp(category))
+}
+
+// @summary	Get category path
+// @description	Categories from the root down to the requested one, for breadcrumbs.
+// @tags		category
+// @produce	json
+// @param		categoryID	path		int	true	"Category ID"
+// @success	200			{array}		dto.CategoryResp
+// @failure	400			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/categories/{categoryID}/path [get]
+func (h *CategoryHandler) GetPath(c *gin.Context) {
+	categoryID, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	path, err := h.categoryService.GetPath(c.Request.Context(), categoryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToCategoriesResp(path))
+}
+
+// @summary	Create a category
+// @description	The category becomes the last child of parent_id, or the last root category without it.
+// @description	Admin only.
+// @tags		category
+// @accept		json
+// @produce	json
+// @param		body	body		dto.PostCategoryReq	true	"Category"
+// @success	201		{object}	dto.CategoryResp
+// @failure	400		{object}	apperror.Error
+// @failure	401		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error	"Parent category not found"
+// @failure	409		{object}	apperror.Error	"Category name is already taken"
+// @failure	500		{object}	apperror.Error
+// @Router		/admin/categories [post]
+func (h *CategoryHandler) PostCategory(c *gin.Context) {
+	var req dto.PostCategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid category data", err))
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(c.Request.Context(), req.Name, req.ParentID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ConvertToCategoryResp(category))
+}
+
+// @summary	Move a category
+// @description	Moves the category with its subcategories to the end of parent_id's children,
+// @description	or to the end of the root categories without it. Admin only.
+// @tags		category
+// @accept		json
+// @produce	json
+// @param		categoryID	path		int						true	"Category ID"
+// @param		body		body		dto.MoveCategoryReq	true	"New parent"
+// @success	200			{object}	dto.CategoryResp
+// @failure	400			{object}	apperror.Error
+// @failure	401			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	409			{object}	apperror.Error	"Category cannot be moved into its own subtree"
+// @failure	500			{object}	apperror.Error
+// @Router		/admin/categories/{categoryID}/move [post]
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	categoryID, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MoveCategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid category data", err))
+		return
+	}
+
+	category, err := h.categoryService.MoveCategory(c.Request.Context(), categoryID, req.ParentID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToCategoryResp(category))
+}
+
+// @summary	Delete a category
+// @description	Only categories without subcategories, products and wanted requests can be deleted. Admin only.
+// @tags		category
+// @param		categoryID	path	int	true	"Category ID"
+// @success	204
+// @failure	400	{object}	apperror.Error
+// @failure	401	{object}	apperror.Error
+// @failure	403	{object}	apperror.Error
+// @failure	404	{object}	apperror.Error
+// @failure	409	{object}	apperror.Error	"Category has subcategories, products or wanted requests"
+// @failure	500	{object}	apperror.Error
+// @Router		/admin/categories/{categoryID} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	categoryID, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.categoryService.DeleteCategory(c.Request.Context(), categoryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
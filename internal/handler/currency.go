@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyService interface {
+	GetRates(ctx context.Context) ([]entity.CurrencyRate, error)
+}
+
+type CurrencyHandler struct {
+	currencyService CurrencyService
+}
+
+func NewCurrencyHandler(currencyService CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{currencyService: currencyService}
+}
+
+// @summary	Get currency rates
+// @description	Returns the local exchange rates used to convert prices to a display currency.
+// @description	A rate is the number of currency units per 1 EUR. Rates are loaded with cmd/rates.
+// @tags		currency
+// @produce	json
+// @success	200	{object}	dto.GetCurrencyRatesResp
+// @failure	500	{object}	apperror.Error
+// @Router		/currency-rates [get]
+func (h *CurrencyHandler) GetRates(c *gin.Context) {
+	rates, err := h.currencyService.GetRates(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormCurrencyRates(rates))
+}
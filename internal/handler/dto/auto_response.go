@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// PutAutoResponseRuleReq правило автоответа. Без product_id правило действует на весь магазин.
+type PutAutoResponseRuleReq struct {
+	ProductID    *uint    `json:"product_id" binding:"omitempty,gt=0"`
+	AcceptFrom   *float64 `json:"accept_from" binding:"omitempty,gt=0"`
+	DeclineBelow *float64 `json:"decline_below" binding:"omitempty,gt=0"`
+	CounterPrice *float64 `json:"counter_price" binding:"omitempty,gt=0"`
+}
+
+func (r *PutAutoResponseRuleReq) ConvertToEntity(shopID uint) entity.AutoResponseRule {
+	return entity.AutoResponseRule{
+		ShopID:       shopID,
+		ProductID:    r.ProductID,
+		AcceptFrom:   r.AcceptFrom,
+		DeclineBelow: r.DeclineBelow,
+		CounterPrice: r.CounterPrice,
+	}
+}
+
+type AutoResponseRuleResp struct {
+	ID           uint      `json:"id"`
+	ShopID       uint      `json:"shop_id"`
+	ProductID    *uint     `json:"product_id"`
+	AcceptFrom   *float64  `json:"accept_from"`
+	DeclineBelow *float64  `json:"decline_below"`
+	CounterPrice *float64  `json:"counter_price"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func ConvertToAutoResponseRuleResp(r entity.AutoResponseRule) AutoResponseRuleResp {
+	return AutoResponseRuleResp{
+		ID:           r.ID,
+		ShopID:       r.ShopID,
+		ProductID:    r.ProductID,
+		AcceptFrom:   r.AcceptFrom,
+		DeclineBelow: r.DeclineBelow,
+		CounterPrice: r.CounterPrice,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+type GetAutoResponseRulesResp struct {
+	Data []AutoResponseRuleResp `json:"data"`
+}
+
+func FormAutoResponseRules(rules []entity.AutoResponseRule) GetAutoResponseRulesResp {
+	data := make([]AutoResponseRuleResp, 0, len(rules))
+	for _, r := range rules {
+		data = append(data, ConvertToAutoResponseRuleResp(r))
+	}
+
+	return GetAutoResponseRulesResp{Data: data}
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type PostCategoryReq struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	ParentID *uint  `json:"parent_id" binding:"omitempty,gt=0"`
+}
+
+// MoveCategoryReq новый родитель категории, без parent_id категория становится корнем
+type MoveCategoryReq struct {
+	ParentID *uint `json:"parent_id" binding:"omitempty,gt=0"`
+}
+
+type CategoryResp struct {
+	ID       uint           `json:"id"`
+	Name     string         `json:"name"`
+	ParentID *uint          `json:"parent_id"`
+	Children []CategoryResp `json:"children,omitempty"`
+}
+
+func ConvertToCategoryResp(c entity.Category) CategoryResp {
+	resp := CategoryResp{
+		ID:       c.ID,
+		Name:     c.Name,
+		ParentID: c.ParentID,
+	}
+	if len(c.Children) > 0 {
+		resp.Children = ConvertToCategoriesResp(c.Children)
+	}
+
+	return resp
+}
+
+func ConvertToCategoriesResp(categories []entity.Category) []CategoryResp {
+	resp := make([]CategoryResp, 0, len(categories))
+	for _, c := range categories {
+		resp = append(resp, ConvertToCategoryResp(c))
+	}
+
+	return resp
+}
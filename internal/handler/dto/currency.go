@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type CurrencyRateResp struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GetCurrencyRatesResp struct {
+	Base string             `json:"base"`
+	Data []CurrencyRateResp `json:"data"`
+}
+
+func FormCurrencyRates(rates []entity.CurrencyRate) GetCurrencyRatesResp {
+	data := make([]CurrencyRateResp, 0, len(rates))
+	for _, r := range rates {
+		data = append(data, CurrencyRateResp{
+			Currency:  r.Currency,
+			Rate:      r.Rate,
+			UpdatedAt: r.UpdatedAt,
+		})
+	}
+
+	return GetCurrencyRatesResp{Base: entity.BaseCurrency, Data: data}
+}
@@ -0,0 +1,184 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type PostInventoryItemReq struct {
+	ProductID   uint    `json:"product_id" binding:"required,gt=0"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"required,iso4217"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+// ConvertToEntity переводит запрос в позицию инвентаря. Без is_available товар сразу в продаже.
+func (r *PostInventoryItemReq) ConvertToEntity(shopID uint) entity.InventoryItem {
+	item := entity.InventoryItem{
+		ShopID:      shopID,
+		ProductID:   r.ProductID,
+		Price:       r.Price,
+		Currency:    r.Currency,
+		IsAvailable: true,
+	}
+	if r.IsAvailable != nil {
+		item.IsAvailable = *r.IsAvailable
+	}
+
+	return item
+}
+
+type PatchInventoryItemReq struct {
+	Price       *float64 `json:"price" binding:"omitempty,gt=0"`
+	Currency    *string  `json:"currency" binding:"omitempty,iso4217"`
+	IsAvailable *bool    `json:"is_available"`
+}
+
+func (r *PatchInventoryItemReq) ConvertToEntity() entity.InventoryUpdate {
+	return entity.InventoryUpdate{
+		Price:       r.Price,
+		Currency:    r.Currency,
+		IsAvailable: r.IsAvailable,
+	}
+}
+
+type InventoryItemResp struct {
+	ShopID      uint    `json:"shop_id"`
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
+	IsAvailable bool    `json:"is_available"`
+}
+
+func ConvertToInventoryItemResp(i entity.InventoryItem) InventoryItemResp {
+	return InventoryItemResp{
+		ShopID:      i.ShopID,
+		ProductID:   i.ProductID,
+		ProductName: i.ProductName,
+		Price:       i.Price,
+		Currency:    i.Currency,
+		IsAvailable: i.IsAvailable,
+	}
+}
+
+// PatchInventoryItemResp позиция после изменения. DeclinedOffers - сколько открытых офферов
+// отклонено, потому что товар снят с продажи.
+type PatchInventoryItemResp struct {
+	InventoryItemResp
+	DeclinedOffers int `json:"declined_offers"`
+}
+
+type GetInventoryResp struct {
+	Data []InventoryItemResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormInventory(items []entity.InventoryItem, page, limit, total, totalPages int) GetInventoryResp {
+	resp := GetInventoryResp{Data: make([]InventoryItemResp, 0, len(items))}
+	for _, i := range items {
+		resp.Data = append(resp.Data, ConvertToInventoryItemResp(i))
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+
+	return resp
+}
+
+type InventoryChangeResp struct {
+	ID          uint      `json:"id"`
+	Change      string    `json:"change"`
+	Price       float64   `json:"price"`
+	Currency    string    `json:"currency"`
+	IsAvailable bool      `json:"is_available"`
+	ChangedBy   *uint     `json:"changed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetInventoryHistoryResp struct {
+	Data []InventoryChangeResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormInventoryHistory(
+	changes []entity.InventoryChange,
+	page, limit, total, totalPages int,
+) GetInventoryHistoryResp {
+	resp := GetInventoryHistoryResp{Data: make([]InventoryChangeResp, 0, len(changes))}
+	for _, c := range changes {
+		resp.Data = append(resp.Data, InventoryChangeResp{
+			ID:          c.ID,
+			Change:      c.Change,
+			Price:       c.Price,
+			Currency:    c.Currency,
+			IsAvailable: c.IsAvailable,
+			ChangedBy:   c.ChangedBy,
+			CreatedAt:   c.CreatedAt,
+		})
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+
+	return resp
+}
+
+type ImportInventoryQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ExportInventoryQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+}
+
+type InventoryImportErrorResp struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// InventoryImportResp отчёт импорта. Failed - число строк из Errors, они не применены.
+type InventoryImportResp struct {
+	DryRun         bool                       `json:"dry_run"`
+	Rows           int                        `json:"rows"`
+	Created        int                        `json:"created"`
+	Updated        int                        `json:"updated"`
+	Unchanged      int                        `json:"unchanged"`
+	Failed         int                        `json:"failed"`
+	DeclinedOffers int                        `json:"declined_offers"`
+	Errors         []InventoryImportErrorResp `json:"errors"`
+}
+
+func ConvertToInventoryImportResp(r entity.InventoryImportReport) InventoryImportResp {
+	resp := InventoryImportResp{
+		DryRun:         r.DryRun,
+		Rows:           r.Rows,
+		Created:        r.Created,
+		Updated:        r.Updated,
+		Unchanged:      r.Unchanged,
+		Failed:         len(r.Errors),
+		DeclinedOffers: r.DeclinedOffers,
+		Errors:         make([]InventoryImportErrorResp, 0, len(r.Errors)),
+	}
+	for _, e := range r.Errors {
+		resp.Errors = append(resp.Errors, InventoryImportErrorResp{Line: e.Line, Error: e.Error})
+	}
+
+	return resp
+}
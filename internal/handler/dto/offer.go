@@ -1,8 +1,10 @@
 package dto
 
 import (
+	"errors"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
@@ -14,7 +16,9 @@ type PostOfferReq struct {
 }
 
 type PostOfferResp struct {
-	ID uint `json:"id"`
+	ID     uint    `json:"id"`
+	Status string  `json:"status"`
+	Price  float64 `json:"price"`
 }
 
 func (po *PostOfferReq) ConvertToEntity() entity.Offer {
@@ -27,36 +31,77 @@ func (po *PostOfferReq) ConvertToEntity() entity.Offer {
 }
 
 func ConvertToPostOfferResp(o entity.Offer) PostOfferResp {
-	return PostOfferResp{ID: o.ID}
+	return PostOfferResp{ID: o.ID, Status: o.Status, Price: o.Price}
+}
+
+type GetOfferResp struct {
+	ID          uint      `json:"id"`
+	Price       float64   `json:"price"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ShopID      uint      `json:"shop_id"`
+	ShopName    string    `json:"shop_name"`
+	ProductID   uint      `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	UserID      uint      `json:"user_id"`
+}
+
+func ConvertToGetOfferResp(o entity.OfferDetails) GetOfferResp {
+	return GetOfferResp{
+		ID:          o.ID,
+		Price:       o.Price,
+		Currency:    o.Currency,
+		Status:      o.Status,
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+		ExpiresAt:   o.ExpiresAt,
+		ShopID:      o.ShopID,
+		ShopName:    o.ShopName,
+		ProductID:   o.ProductID,
+		ProductName: o.ProductName,
+		UserID:      o.UserID,
+	}
 }
 
 type PatchOfferStatusReq struct {
 	Status string `json:"status" binding:"required"`
+	// Price встречная цена, обязательна для статуса countered
+	Price *float64 `json:"price" binding:"omitempty,gt=0"`
 }
 
 type PatchOfferStatusResp struct {
-	NewStatus string `json:"new_status"`
+	NewStatus string  `json:"new_status"`
+	Price     float64 `json:"price,omitempty"`
 }
 
 func (p *PatchOfferStatusReq) ConvertToEntity() entity.Offer {
-	return entity.Offer{
+	offer := entity.Offer{
 		Status: p.Status,
 	}
+	if p.Price != nil {
+		offer.Price = *p.Price
+	}
+	return offer
 }
 
 func ConvertToPatchOfferStatusResp(o entity.Offer) PatchOfferStatusResp {
-	return PatchOfferStatusResp{NewStatus: o.Status}
+	return PatchOfferStatusResp{NewStatus: o.Status, Price: o.Price}
 }
 
 type OfferResp struct {
-	ID        uint
-	Price     float64
-	Currency  string
-	Status    string
-	CreatedAt time.Time
-	ExpiresAt time.Time
-	ShopID    uint
-	ProductID uint
+	ID              uint
+	Price           float64
+	Currency        string
+	DisplayPrice    *float64 `json:",omitempty"`
+	DisplayCurrency string   `json:",omitempty"`
+	Status          string
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	ShopID          uint
+	ProductID       uint
 }
 
 type GetUserOffersResp struct {
@@ -74,14 +119,16 @@ func FormUserOffers(ofrs []entity.Offer, page, limit, total, totalPages int) Get
 
 	for _, ofr := range ofrs {
 		data = append(data, OfferResp{
-			ID:        ofr.ID,
-			Price:     ofr.Price,
-			Currency:  ofr.Currency,
-			Status:    ofr.Status,
-			CreatedAt: ofr.CreatedAt,
-			ExpiresAt: ofr.ExpiresAt,
-			ShopID:    ofr.ShopID,
-			ProductID: ofr.ProductID,
+			ID:              ofr.ID,
+			Price:           ofr.Price,
+			Currency:        ofr.Currency,
+			DisplayPrice:    ofr.DisplayPrice,
+			DisplayCurrency: ofr.DisplayCurrency,
+			Status:          ofr.Status,
+			CreatedAt:       ofr.CreatedAt,
+			ExpiresAt:       ofr.ExpiresAt,
+			ShopID:          ofr.ShopID,
+			ProductID:       ofr.ProductID,
 		})
 	}
 
@@ -100,3 +147,201 @@ func FormUserOffers(ofrs []entity.Offer, page, limit, total, totalPages int) Get
 		},
 	}
 }
+
+type OfferEventResp struct {
+	ID         uint      `json:"id"`
+	EventType  string    `json:"event_type"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Price      float64   `json:"price"`
+	ActorID    uint      `json:"actor_id,omitempty"`
+	ActorRole  string    `json:"actor_role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetOfferHistoryResp struct {
+	Data []OfferEventResp `json:"data"`
+}
+
+func FormOfferHistory(events []entity.OfferEvent) GetOfferHistoryResp {
+	data := make([]OfferEventResp, 0, len(events))
+
+	for _, event := range events {
+		data = append(data, OfferEventResp{
+			ID:         event.ID,
+			EventType:  event.EventType,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Price:      event.Price,
+			ActorID:    event.ActorID,
+			ActorRole:  event.ActorRole,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return GetOfferHistoryResp{Data: data}
+}
+
+type GetShopOffersReq struct {
+	Status    []string  `form:"status"`
+	ShopID    uint      `form:"shop_id"`
+	ProductID uint      `form:"product_id"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MinPrice  *float64  `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice  *float64  `form:"max_price" binding:"omitempty,gte=0"`
+	Sort      string    `form:"sort" binding:"omitempty,oneof=created_at expires_at price"`
+	Order     string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page      int       `form:"page,default=1" binding:"min=1"`
+	Limit     int       `form:"limit,default=10" binding:"min=5,max=100"`
+	Currency  string    `form:"currency" binding:"omitempty,iso4217"`
+}
+
+func (r *GetShopOffersReq) ConvertToEntity() entity.ShopOfferFilter {
+	return entity.ShopOfferFilter{
+		Statuses:  r.Status,
+		ShopID:    r.ShopID,
+		ProductID: r.ProductID,
+		From:      r.From,
+		To:        r.To,
+		MinPrice:  r.MinPrice,
+		MaxPrice:  r.MaxPrice,
+		SortBy:    r.Sort,
+		SortDesc:  r.Order != "asc",
+	}
+}
+
+type ShopOfferResp struct {
+	ID              uint      `json:"id"`
+	Price           float64   `json:"price"`
+	Currency        string    `json:"currency"`
+	DisplayPrice    *float64  `json:"display_price,omitempty"`
+	DisplayCurrency string    `json:"display_currency,omitempty"`
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	ShopID          uint      `json:"shop_id"`
+	ProductID       uint      `json:"product_id"`
+	UserID          uint      `json:"user_id"`
+}
+
+type GetShopOffersResp struct {
+	Data         []ShopOfferResp `json:"data"`
+	StatusCounts map[string]int  `json:"status_counts"`
+	Meta         struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormShopOffers(ofrs []entity.Offer, statusCounts map[string]int,
+	page, limit, total, totalPages int) GetShopOffersResp {
+	resp := GetShopOffersResp{
+		Data:         make([]ShopOfferResp, 0, len(ofrs)),
+		StatusCounts: statusCounts,
+	}
+
+	for _, ofr := range ofrs {
+		resp.Data = append(resp.Data, ShopOfferResp{
+			ID:              ofr.ID,
+			Price:           ofr.Price,
+			Currency:        ofr.Currency,
+			DisplayPrice:    ofr.DisplayPrice,
+			DisplayCurrency: ofr.DisplayCurrency,
+			Status:          ofr.Status,
+			CreatedAt:       ofr.CreatedAt,
+			UpdatedAt:       ofr.UpdatedAt,
+			ExpiresAt:       ofr.ExpiresAt,
+			ShopID:          ofr.ShopID,
+			ProductID:       ofr.ProductID,
+			UserID:          ofr.UserID,
+		})
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+
+	return resp
+}
+
+type PatchShopOffersReq struct {
+	OfferIDs []uint `json:"offer_ids" binding:"required,min=1,max=100,dive,gt=0"`
+	Status   string `json:"status" binding:"required,oneof=accepted declined"`
+	// Mode atomic - все офферы или ни одного, per_item - каждый оффер независимо
+	Mode string `json:"mode" binding:"omitempty,oneof=atomic per_item"`
+}
+
+type BulkOfferError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type BulkOfferResultResp struct {
+	OfferID uint            `json:"offer_id"`
+	OK      bool            `json:"ok"`
+	Status  string          `json:"status,omitempty"`
+	Error   *BulkOfferError `json:"error,omitempty"`
+}
+
+type PatchShopOffersResp struct {
+	Mode      string                `json:"mode"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BulkOfferResultResp `json:"results"`
+}
+
+func FormBulkOfferResults(mode string, results []entity.BulkOfferResult) PatchShopOffersResp {
+	resp := PatchShopOffersResp{
+		Mode:    mode,
+		Results: make([]BulkOfferResultResp, 0, len(results)),
+	}
+
+	for _, r := range results {
+		item := BulkOfferResultResp{OfferID: r.OfferID, OK: r.Err == nil, Status: r.Status}
+		if r.Err != nil {
+			resp.Failed++
+			item.Error = &BulkOfferError{Code: apperror.InternalError, Message: "internal error"}
+
+			var appErr apperror.AppError
+			if errors.As(r.Err, &appErr) && appErr.Code() != apperror.DatabaseError {
+				item.Error.Code = appErr.Code()
+				item.Error.Message = appErr.Message()
+			}
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+
+	return resp
+}
+
+// OfferUpdateResp данные SSE-события об изменении оффера. Имя события совпадает с Type.
+type OfferUpdateResp struct {
+	Type       string    `json:"type"`
+	OfferID    uint      `json:"offer_id"`
+	Status     string    `json:"status"`
+	Price      float64   `json:"price"`
+	Currency   string    `json:"currency"`
+	ShopID     uint      `json:"shop_id"`
+	UserID     uint      `json:"user_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func ConvertToOfferUpdateResp(u entity.OfferUpdate) OfferUpdateResp {
+	return OfferUpdateResp{
+		Type:       u.Type,
+		OfferID:    u.OfferID,
+		Status:     u.Status,
+		Price:      u.Price,
+		Currency:   u.Currency,
+		ShopID:     u.ShopID,
+		UserID:     u.BuyerID,
+		OccurredAt: u.OccurredAt,
+	}
+}
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type PostOfferMessageReq struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+func (r *PostOfferMessageReq) ConvertToEntity(offerID, senderID uint) entity.OfferMessage {
+	return entity.OfferMessage{
+		OfferID:  offerID,
+		SenderID: senderID,
+		Body:     r.Body,
+	}
+}
+
+type OfferMessageResp struct {
+	ID         uint       `json:"id"`
+	OfferID    uint       `json:"offer_id"`
+	SenderID   uint       `json:"sender_id"`
+	SenderRole string     `json:"sender_role"`
+	Body       string     `json:"body"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReadAt     *time.Time `json:"read_at"`
+}
+
+func ConvertToOfferMessageResp(m entity.OfferMessage) OfferMessageResp {
+	return OfferMessageResp{
+		ID:         m.ID,
+		OfferID:    m.OfferID,
+		SenderID:   m.SenderID,
+		SenderRole: m.SenderRole,
+		Body:       m.Body,
+		CreatedAt:  m.CreatedAt,
+		ReadAt:     m.ReadAt,
+	}
+}
+
+// GetOfferMessagesResp страница переписки. Unread - сколько сообщений второй стороны на странице
+// было непрочитано до этого запроса, теперь они прочитаны.
+type GetOfferMessagesResp struct {
+	Data []OfferMessageResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+		Unread      int `json:"unread"`
+	} `json:"meta"`
+}
+
+func FormOfferMessages(
+	messages []entity.OfferMessage,
+	page, limit, total, totalPages, unread int,
+) GetOfferMessagesResp {
+	resp := GetOfferMessagesResp{Data: make([]OfferMessageResp, 0, len(messages))}
+	for _, m := range messages {
+		resp.Data = append(resp.Data, ConvertToOfferMessageResp(m))
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+	resp.Meta.Unread = unread
+
+	return resp
+}
+
+type OfferUnreadResp struct {
+	OfferID uint `json:"offer_id"`
+	Unread  int  `json:"unread"`
+}
+
+type GetUnreadMessagesResp struct {
+	Total  int               `json:"total"`
+	Offers []OfferUnreadResp `json:"offers"`
+}
+
+func FormUnreadMessages(unread []entity.OfferUnread) GetUnreadMessagesResp {
+	resp := GetUnreadMessagesResp{Offers: make([]OfferUnreadResp, 0, len(unread))}
+	for _, u := range unread {
+		resp.Total += u.Unread
+		resp.Offers = append(resp.Offers, OfferUnreadResp{OfferID: u.OfferID, Unread: u.Unread})
+	}
+
+	return resp
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type OrderResp struct {
+	ID        uint      `json:"id"`
+	OfferID   uint      `json:"offer_id"`
+	UserID    uint      `json:"user_id"`
+	ShopID    uint      `json:"shop_id"`
+	ProductID uint      `json:"product_id"`
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ConvertToOrderResp(o entity.Order) OrderResp {
+	return OrderResp{
+		ID:        o.ID,
+		OfferID:   o.OfferID,
+		UserID:    o.UserID,
+		ShopID:    o.ShopID,
+		ProductID: o.ProductID,
+		Price:     o.Price,
+		Currency:  o.Currency,
+		Status:    o.Status,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+type GetOrdersResp struct {
+	Data []OrderResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormOrders(orders []entity.Order, page, limit, total, totalPages int) GetOrdersResp {
+	resp := GetOrdersResp{Data: make([]OrderResp, 0, len(orders))}
+	for _, o := range orders {
+		resp.Data = append(resp.Data, ConvertToOrderResp(o))
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+
+	return resp
+}
+
+type PatchOrderStatusReq struct {
+	Status string `json:"status" binding:"required"`
+}
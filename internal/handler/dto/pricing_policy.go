@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// PutPricingPolicyReq ценовая политика магазина. min_price_percent = 0 отключает проверку цены.
+type PutPricingPolicyReq struct {
+	MinPricePercent *float64 `json:"min_price_percent" binding:"required,gte=0,lte=100"`
+}
+
+func (r *PutPricingPolicyReq) ConvertToEntity(shopID uint) entity.PricingPolicy {
+	return entity.PricingPolicy{
+		ShopID:          shopID,
+		MinPricePercent: *r.MinPricePercent,
+	}
+}
+
+type PricingPolicyResp struct {
+	ShopID          uint       `json:"shop_id"`
+	MinPricePercent float64    `json:"min_price_percent"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+// ConvertToPricingPolicyResp у политики по умолчанию нет updated_at
+func ConvertToPricingPolicyResp(p entity.PricingPolicy) PricingPolicyResp {
+	resp := PricingPolicyResp{
+		ShopID:          p.ShopID,
+		MinPricePercent: p.MinPricePercent,
+	}
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &p.UpdatedAt
+	}
+
+	return resp
+}
@@ -1,47 +1,38 @@
 package dto
 
-import "github.com/EM-Stawberry/Stawberry/internal/domain/service/product"
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
+// PostProductReq новый товар магазина. Значения атрибутов - строки, числа или логические значения.
 type PostProductReq struct {
-	StoreID     uint    `json:"store_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
-	InStock     bool    `json:"in_stock"`
+	Name        string                 `json:"name" binding:"required"`
+	Description string                 `json:"description"`
+	CategoryID  int                    `json:"category_id" binding:"required,gt=0"`
+	Attributes  map[string]interface{} `json:"product_attributes"`
 }
 
-type PostProductResp struct {
-	ID uint `json:"id"`
-}
-
-/* func (pp *PostProductReq) ConvertToSvc() product.Product {
-	return product.Product{
-		StoreID:     pp.StoreID,
+func (pp *PostProductReq) ConvertToEntity() entity.Product {
+	return entity.Product{
 		Name:        pp.Name,
 		Description: pp.Description,
-		Price:       pp.Price,
-		Category:    pp.Category,
-		InStock:     pp.InStock,
+		CategoryID:  pp.CategoryID,
+		Attributes:  pp.Attributes,
 	}
-} */
+}
 
+// PatchProductReq изменения товара: незаданные поля не меняются,
+// product_attributes заменяют атрибуты товара целиком
 type PatchProductReq struct {
-	StoreID     *uint    `json:"store_id,omitempty"`
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	Category    *string  `json:"category,omitempty"`
-	InStock     *bool    `json:"in_stock,omitempty"`
+	Name        *string                `json:"name,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	CategoryID  *int                   `json:"category_id,omitempty" binding:"omitempty,gt=0"`
+	Attributes  map[string]interface{} `json:"product_attributes,omitempty"`
 }
 
-func (pp *PatchProductReq) ConvertToSvc() product.UpdateProduct {
-	return product.UpdateProduct{
-		StoreID:     pp.StoreID,
+func (pp *PatchProductReq) ConvertToEntity() entity.ProductUpdate {
+	return entity.ProductUpdate{
 		Name:        pp.Name,
 		Description: pp.Description,
-		Price:       pp.Price,
-		Category:    pp.Category,
-		InStock:     pp.InStock,
+		CategoryID:  pp.CategoryID,
+		Attributes:  pp.Attributes,
 	}
 }
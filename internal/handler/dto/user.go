@@ -10,19 +10,24 @@ type RegistrationUserReq struct {
 	Email       string `json:"email" binding:"required"`
 	Phone       string `json:"phone" binding:"required"`
 	Fingerprint string `json:"fingerprint" binding:"required"`
+	// GuestToken токен из ссылки в письме гостю. Офферы, отправленные гостем с той почты, переходят к пользователю
+	GuestToken string `json:"guest_token"`
 }
 
 type RegistrationUserResp struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	// ClaimedOffers количество гостевых офферов, перешедших к пользователю по guest_token
+	ClaimedOffers int `json:"claimed_offers"`
 }
 
 func (ru *RegistrationUserReq) ConvertToSvc() user.User {
 	return user.User{
-		Name:     ru.Name,
-		Password: ru.Password,
-		Email:    ru.Email,
-		Phone:    ru.Phone,
+		Name:       ru.Name,
+		Password:   ru.Password,
+		Email:      ru.Email,
+		Phone:      ru.Phone,
+		GuestToken: ru.GuestToken,
 	}
 }
 
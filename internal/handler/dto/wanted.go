@@ -0,0 +1,128 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// PostWantedRequestReq запрос на покупку: ровно одно из product_id и category_id
+type PostWantedRequestReq struct {
+	ProductID   *uint     `json:"product_id" binding:"omitempty,gt=0"`
+	CategoryID  *uint     `json:"category_id" binding:"omitempty,gt=0"`
+	TargetPrice float64   `json:"target_price" binding:"required,gt=0"`
+	Currency    string    `json:"currency" binding:"required,iso4217"`
+	Deadline    time.Time `json:"deadline" binding:"required"`
+}
+
+func (r *PostWantedRequestReq) ConvertToEntity(userID uint) entity.WantedRequest {
+	return entity.WantedRequest{
+		UserID:      userID,
+		ProductID:   r.ProductID,
+		CategoryID:  r.CategoryID,
+		TargetPrice: r.TargetPrice,
+		Currency:    r.Currency,
+		Deadline:    r.Deadline,
+	}
+}
+
+// PutWantedBidReq ставка магазина: товар из его инвентаря и цена в валюте запроса
+type PutWantedBidReq struct {
+	ProductID uint    `json:"product_id" binding:"required,gt=0"`
+	Price     float64 `json:"price" binding:"required,gt=0"`
+}
+
+func (r *PutWantedBidReq) ConvertToEntity(requestID, shopID uint) entity.WantedBid {
+	return entity.WantedBid{
+		RequestID: requestID,
+		ShopID:    shopID,
+		ProductID: r.ProductID,
+		Price:     r.Price,
+	}
+}
+
+type WantedBidResp struct {
+	ID          uint      `json:"id"`
+	RequestID   uint      `json:"request_id"`
+	ShopID      uint      `json:"shop_id"`
+	ShopName    string    `json:"shop_name"`
+	ProductID   uint      `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Price       float64   `json:"price"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func ConvertToWantedBidResp(b entity.WantedBid) WantedBidResp {
+	return WantedBidResp{
+		ID:          b.ID,
+		RequestID:   b.RequestID,
+		ShopID:      b.ShopID,
+		ShopName:    b.ShopName,
+		ProductID:   b.ProductID,
+		ProductName: b.ProductName,
+		Price:       b.Price,
+		Status:      b.Status,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+	}
+}
+
+type WantedRequestResp struct {
+	ID          uint            `json:"id"`
+	ProductID   *uint           `json:"product_id"`
+	CategoryID  *uint           `json:"category_id"`
+	TargetPrice float64         `json:"target_price"`
+	Currency    string          `json:"currency"`
+	Status      string          `json:"status"`
+	Deadline    time.Time       `json:"deadline"`
+	OfferID     *uint           `json:"offer_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Bids        []WantedBidResp `json:"bids,omitempty"`
+}
+
+func ConvertToWantedRequestResp(w entity.WantedRequest) WantedRequestResp {
+	resp := WantedRequestResp{
+		ID:          w.ID,
+		ProductID:   w.ProductID,
+		CategoryID:  w.CategoryID,
+		TargetPrice: w.TargetPrice,
+		Currency:    w.Currency,
+		Status:      w.Status,
+		Deadline:    w.Deadline,
+		OfferID:     w.OfferID,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+	for _, b := range w.Bids {
+		resp.Bids = append(resp.Bids, ConvertToWantedBidResp(b))
+	}
+
+	return resp
+}
+
+type GetWantedRequestsResp struct {
+	Data []WantedRequestResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormWantedRequests(requests []entity.WantedRequest, page, limit, total, totalPages int) GetWantedRequestsResp {
+	resp := GetWantedRequestsResp{Data: make([]WantedRequestResp, 0, len(requests))}
+	for _, w := range requests {
+		resp.Data = append(resp.Data, ConvertToWantedRequestResp(w))
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+
+	return resp
+}
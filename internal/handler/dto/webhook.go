@@ -0,0 +1,124 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// PostWebhookReq вебхук магазина. Без secret секрет генерируется и возвращается в ответе.
+type PostWebhookReq struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Secret string   `json:"secret"`
+}
+
+func (r *PostWebhookReq) ConvertToEntity(shopID uint) entity.Webhook {
+	return entity.Webhook{
+		ShopID: shopID,
+		URL:    r.URL,
+		Events: r.Events,
+		Secret: r.Secret,
+	}
+}
+
+type WebhookResp struct {
+	ID        uint      `json:"id"`
+	ShopID    uint      `json:"shop_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ConvertToWebhookResp(w entity.Webhook) WebhookResp {
+	return WebhookResp{
+		ID:        w.ID,
+		ShopID:    w.ShopID,
+		URL:       w.URL,
+		Events:    w.Events,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+// CreatedWebhookResp ответ на создание вебхука, единственный, в котором есть секрет
+type CreatedWebhookResp struct {
+	WebhookResp
+	Secret string `json:"secret"`
+}
+
+func ConvertToCreatedWebhookResp(w entity.Webhook) CreatedWebhookResp {
+	return CreatedWebhookResp{WebhookResp: ConvertToWebhookResp(w), Secret: w.Secret}
+}
+
+type GetWebhooksResp struct {
+	Data []WebhookResp `json:"data"`
+}
+
+func FormWebhooks(webhooks []entity.Webhook) GetWebhooksResp {
+	data := make([]WebhookResp, 0, len(webhooks))
+	for _, w := range webhooks {
+		data = append(data, ConvertToWebhookResp(w))
+	}
+
+	return GetWebhooksResp{Data: data}
+}
+
+type WebhookDeliveryResp struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+func ConvertToWebhookDeliveryResp(d entity.WebhookDelivery) WebhookDeliveryResp {
+	return WebhookDeliveryResp{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+type GetWebhookDeliveriesResp struct {
+	Data []WebhookDeliveryResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormWebhookDeliveries(
+	deliveries []entity.WebhookDelivery,
+	page, limit, total, totalPages int,
+) GetWebhookDeliveriesResp {
+	resp := GetWebhookDeliveriesResp{Data: make([]WebhookDeliveryResp, 0, len(deliveries))}
+	for _, d := range deliveries {
+		resp.Data = append(resp.Data, ConvertToWebhookDeliveryResp(d))
+	}
+
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = totalPages
+
+	return resp
+}
@@ -1,12 +1,61 @@
 package guestoffer
 
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
 // GuestPostOfferReq DTO for the guest offer creation request
 type GuestPostOfferReq struct {
 	ProductID  uint    `json:"product_id" binding:"required"`
 	StoreID    uint    `json:"store_id" binding:"required"`
-	Price      float64 `json:"offer_price" binding:"required"`
-	Currency   string  `json:"currency" binding:"required"`
+	Price      float64 `json:"offer_price" binding:"required,gte=0"`
+	Currency   string  `json:"currency" binding:"required,iso4217"`
 	GuestName  string  `json:"guest_name" binding:"required"`
 	GuestEmail string  `json:"guest_email" binding:"required,email"`
 	GuestPhone string  `json:"guest_phone" binding:"required"`
 }
+
+// GuestOfferResp DTO for the guest offer opened by the magic link
+type GuestOfferResp struct {
+	ID          uint      `json:"id"`
+	Price       float64   `json:"price"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ShopID      uint      `json:"shop_id"`
+	ShopName    string    `json:"shop_name"`
+	ProductID   uint      `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	GuestName   string    `json:"guest_name"`
+	GuestEmail  string    `json:"guest_email"`
+	GuestPhone  string    `json:"guest_phone"`
+}
+
+// CancelGuestOfferResp DTO for the guest offer cancellation response
+type CancelGuestOfferResp struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+}
+
+func convertToGuestOfferResp(o entity.GuestOffer) GuestOfferResp {
+	return GuestOfferResp{
+		ID:          o.ID,
+		Price:       o.Price,
+		Currency:    o.Currency,
+		Status:      o.Status,
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+		ExpiresAt:   o.ExpiresAt,
+		ShopID:      o.ShopID,
+		ShopName:    o.ShopName,
+		ProductID:   o.ProductID,
+		ProductName: o.ProductName,
+		GuestName:   o.GuestName,
+		GuestEmail:  o.GuestEmail,
+		GuestPhone:  o.GuestPhone,
+	}
+}
@@ -32,15 +32,18 @@ func NewHandler(service guestofferservice.Service, log *zap.Logger) *Handler {
 // @Accept json
 // @Produce json
 // @Param offer body GuestPostOfferReq true "Guest offer data"
+// @Param Idempotency-Key header string false "Repeating a request with the same key returns the first response"
 // @Success 202 {object} map[string]string "Offer accepted and forwarded"
 // @Failure 400 {object} apperror.Error "Invalid guest offer data"
 // @Failure 404 {object} apperror.Error "Store or product not found"
+// @Failure 409 {object} apperror.Error "Product is not available or request with this Idempotency-Key is in progress"
+// @Failure 429 {object} apperror.Error "Too many offers from this email or IP"
 // @Failure 500 {object} apperror.Error "Internal server error"
 // @Router /guest/offers [post]
 func (h *Handler) PostGuestOffer(c *gin.Context) {
 	var guestOfferReq GuestPostOfferReq
 	if err := c.ShouldBindJSON(&guestOfferReq); err != nil {
-		_ = c.Error(apperror.NewGuestOfferError(apperror.GuestOfferInvalidData, "invalid guest offer data"))
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid guest offer data", err))
 		return
 	}
 
@@ -52,11 +55,18 @@ func (h *Handler) PostGuestOffer(c *gin.Context) {
 		GuestName:  guestOfferReq.GuestName,
 		GuestEmail: guestOfferReq.GuestEmail,
 		GuestPhone: guestOfferReq.GuestPhone,
+		GuestIP:    c.ClientIP(),
 	}
 
 	err := h.service.ProcessGuestOffer(c.Request.Context(), offerData)
 	if err != nil {
 		h.log.Error("Failed to process guest offer", zap.Error(err))
+		var appErr apperror.AppError
+		if errors.As(err, &appErr) {
+			_ = c.Error(err)
+			return
+		}
+
 		var guestOfferErr *apperror.GuestOfferError
 		if errors.As(err, &guestOfferErr) {
 			switch guestOfferErr.Code {
@@ -76,3 +86,44 @@ func (h *Handler) PostGuestOffer(c *gin.Context) {
 
 	c.JSON(http.StatusAccepted, gin.H{"message": "Offer accepted and forwarded"})
 }
+
+// GetGuestOffer returns the guest offer by the magic link token
+// @Summary Get a guest offer
+// @Description Returns the guest offer the token from the guest's email belongs to
+// @Tags guest
+// @Produce json
+// @Param token path string true "Token from the guest's email"
+// @Success 200 {object} GuestOfferResp
+// @Failure 404 {object} apperror.Error "Offer not found"
+// @Failure 500 {object} apperror.Error "Internal server error"
+// @Router /guest/offers/{token} [get]
+func (h *Handler) GetGuestOffer(c *gin.Context) {
+	offer, err := h.service.GetGuestOffer(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, convertToGuestOfferResp(offer))
+}
+
+// CancelGuestOffer cancels the guest offer by the magic link token
+// @Summary Cancel a guest offer
+// @Description Cancels the guest offer the token from the guest's email belongs to, until the store answers it
+// @Tags guest
+// @Produce json
+// @Param token path string true "Token from the guest's email"
+// @Success 200 {object} CancelGuestOfferResp
+// @Failure 404 {object} apperror.Error "Offer not found"
+// @Failure 409 {object} apperror.Error "Offer is no longer open"
+// @Failure 500 {object} apperror.Error "Internal server error"
+// @Router /guest/offers/{token}/cancel [post]
+func (h *Handler) CancelGuestOffer(c *gin.Context) {
+	offer, err := h.service.CancelGuestOffer(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CancelGuestOfferResp{ID: offer.ID, Status: offer.Status})
+}
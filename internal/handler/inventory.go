@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/inventory"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type InventoryService interface {
+	GetShopInventory(ctx context.Context, shopID, userID uint, page, limit int) ([]entity.InventoryItem, int, error)
+	AddItem(ctx context.Context, item entity.InventoryItem, userID uint) (entity.InventoryItem, error)
+	UpdateItem(
+		ctx context.Context,
+		shopID, productID, userID uint,
+		update entity.InventoryUpdate,
+	) (item entity.InventoryItem, declined int, err error)
+	RemoveItem(ctx context.Context, shopID, productID, userID uint) error
+	GetItemHistory(
+		ctx context.Context,
+		shopID, productID, userID uint,
+		page, limit int,
+	) ([]entity.InventoryChange, int, error)
+	ImportInventory(
+		ctx context.Context,
+		shopID, userID uint,
+		format string,
+		file io.Reader,
+		dryRun bool,
+	) (entity.InventoryImportReport, error)
+	ExportInventory(ctx context.Context, shopID, userID uint, format string, w io.Writer) error
+}
+
+// InventoryExportPath путь выгрузки инвентаря относительно версии API. Выгрузка большого
+// магазина идёт потоком и может длиться дольше общего таймаута запроса.
+const InventoryExportPath = "/shops/:shopID/inventory/export"
+
+// inventoryContentTypes типы содержимого файлов инвентаря по формату
+var inventoryContentTypes = map[string]string{
+	inventory.FormatCSV:    "text/csv; charset=utf-8",
+	inventory.FormatNDJSON: "application/x-ndjson",
+}
+
+type InventoryHandler struct {
+	inventoryService InventoryService
+	log              *zap.Logger
+}
+
+func NewInventoryHandler(inventoryService InventoryService, log *zap.Logger) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService, log: log}
+}
+
+// @summary	Get shop inventory
+// @description	Lists the shop's products with price, currency and availability, ordered by product ID.
+// @tags		inventory
+// @produce	json
+// @param		shopID	path		int	true	"Shop ID"
+// @param		page	query		int	false	"Page number for pagination"	default(1)
+// @param		limit	query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200		{object}	dto.GetInventoryResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory [get]
+func (h *InventoryHandler) GetInventory(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	page, limit, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	items, total, err := h.inventoryService.GetShopInventory(c.Request.Context(), shopID, userID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormInventory(items, page, limit, total, totalPages))
+}
+
+// @summary	Add a product to shop inventory
+// @description	The product is available for sale unless is_available is false. Archived products cannot be added.
+// @tags		inventory
+// @accept		json
+// @produce	json
+// @param		shopID	path		int							true	"Shop ID"
+// @param		body	body		dto.PostInventoryItemReq	true	"Inventory item"
+// @success	201		{object}	dto.InventoryItemResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	409		{object}	apperror.Error	"Product is already in the inventory or archived"
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory [post]
+func (h *InventoryHandler) PostItem(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostInventoryItemReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid inventory item data", err))
+		return
+	}
+
+	item, err := h.inventoryService.AddItem(c.Request.Context(), req.ConvertToEntity(shopID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ConvertToInventoryItemResp(item))
+}
+
+// @summary	Update a shop inventory item
+// @description	Changes price, currency or availability. Making the item unavailable declines
+// @description	its pending and countered offers and emails the buyers; declined_offers is their number.
+// @tags		inventory
+// @accept		json
+// @produce	json
+// @param		shopID		path		int							true	"Shop ID"
+// @param		productID	path		int							true	"Product ID"
+// @param		body		body		dto.PatchInventoryItemReq	true	"Fields to change"
+// @success	200			{object}	dto.PatchInventoryItemResp
+// @failure	400			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	409			{object}	apperror.Error	"Archived product cannot be made available"
+// @failure	500			{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory/{productID} [patch]
+func (h *InventoryHandler) PatchItem(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PatchInventoryItemReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid inventory item data", err))
+		return
+	}
+
+	item, declined, err := h.inventoryService.UpdateItem(c.Request.Context(), shopID, productID, userID,
+		req.ConvertToEntity())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PatchInventoryItemResp{
+		InventoryItemResp: dto.ConvertToInventoryItemResp(item),
+		DeclinedOffers:    declined,
+	})
+}
+
+// @summary	Remove a product from shop inventory
+// @description	Items that have offers cannot be removed, make them unavailable instead.
+// @tags		inventory
+// @param		shopID		path	int	true	"Shop ID"
+// @param		productID	path	int	true	"Product ID"
+// @success	204
+// @failure	400	{object}	apperror.Error
+// @failure	403	{object}	apperror.Error
+// @failure	404	{object}	apperror.Error
+// @failure	409	{object}	apperror.Error	"Item has offers"
+// @failure	500	{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory/{productID} [delete]
+func (h *InventoryHandler) DeleteItem(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.inventoryService.RemoveItem(c.Request.Context(), shopID, productID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @summary	Get shop inventory item history
+// @description	Every add, update and removal of the item with the resulting state, newest first.
+// @description	History stays available after the item is removed.
+// @tags		inventory
+// @produce	json
+// @param		shopID		path		int	true	"Shop ID"
+// @param		productID	path		int	true	"Product ID"
+// @param		page		query		int	false	"Page number for pagination"	default(1)
+// @param		limit		query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200			{object}	dto.GetInventoryHistoryResp
+// @failure	400			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory/{productID}/history [get]
+func (h *InventoryHandler) GetItemHistory(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	page, limit, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	changes, total, err := h.inventoryService.GetItemHistory(c.Request.Context(), shopID, productID, userID,
+		page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormInventoryHistory(changes, page, limit, total, totalPages))
+}
+
+// @summary	Import shop inventory from a file
+// @description	The request body is a CSV file with a header or JSON Lines, one item per line.
+// @description	Fields: product_id, price, currency and optional is_available (true by default), other
+// @description	columns are ignored, so an export can be uploaded back. Products missing from the inventory
+// @description	are added, the rest are updated. Rows with errors are skipped and listed in the report with
+// @description	their line numbers. With dry_run nothing is saved, but the report shows what would change.
+// @tags		inventory
+// @accept		plain
+// @produce	json
+// @param		shopID	path		int		true	"Shop ID"
+// @param		format	query		string	false	"File format"	Enums(csv, ndjson)	default(csv)
+// @param		dry_run	query		bool	false	"Only validate the file and report the changes"
+// @param		body	body		string	true	"Inventory file"
+// @success	200		{object}	dto.InventoryImportResp
+// @failure	400		{object}	apperror.Error	"Unreadable file, missing columns or too many rows"
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory/import [post]
+func (h *InventoryHandler) ImportInventory(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	query := dto.ImportInventoryQuery{Format: inventory.FormatCSV}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid import parameters", err))
+		return
+	}
+
+	report, err := h.inventoryService.ImportInventory(c.Request.Context(), shopID, userID, query.Format,
+		c.Request.Body, query.DryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToInventoryImportResp(report))
+}
+
+// @summary	Export shop inventory to a file
+// @description	Streams the shop's inventory ordered by product ID in the format the import accepts,
+// @description	with an extra product_name field.
+// @tags		inventory
+// @produce	plain
+// @param		shopID	path		int		true	"Shop ID"
+// @param		format	query		string	false	"File format"	Enums(csv, ndjson)	default(csv)
+// @success	200		{string}	string	"Inventory file"
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/inventory/export [get]
+func (h *InventoryHandler) ExportInventory(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	query := dto.ExportInventoryQuery{Format: inventory.FormatCSV}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid export parameters", err))
+		return
+	}
+
+	w := &exportWriter{
+		c:           c,
+		contentType: inventoryContentTypes[query.Format],
+		filename:    fmt.Sprintf("shop-%d-inventory.%s", shopID, query.Format),
+	}
+
+	err := h.inventoryService.ExportInventory(c.Request.Context(), shopID, userID, query.Format, w)
+	if err != nil {
+		if w.started {
+			// заголовки и часть файла уже отправлены, ответить ошибкой нельзя, клиент получит обрезанный файл
+			h.log.Error("Inventory export interrupted", zap.Uint("shop_id", shopID), zap.Error(err))
+			c.Abort()
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	w.start()
+}
+
+// exportWriter отправляет заголовки выгрузки только с первыми данными, чтобы ошибка
+// до начала выгрузки, например чужой магазин, ушла обычным JSON-ответом
+type exportWriter struct {
+	c           *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+
+	w.c.Header("Content-Type", w.contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+	w.c.Status(http.StatusOK)
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.c.Writer.Write(p)
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
 )
 
@@ -37,18 +39,27 @@ type AuditMiddleware struct {
 	backupBuffer    []entity.AuditEntry // Buffer being flushed, then reused
 }
 
-// bodyLogWriter это обертка над ResponseWriter, которая записывает тело запроса в буфер
-// для сохранения в логах.
+// bodyLogWriter это обертка над ResponseWriter, которая записывает тело ответа в буфер
+// для сохранения в логах. В буфер попадает не больше maxBodySize+1 байт, по лишнему байту
+// видно, что тело обрезано.
 type bodyLogWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
 }
 
 func (w bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	if room := maxBodySize + 1 - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
 	return w.ResponseWriter.Write(b)
 }
 
+// prefixedBody тело запроса, начало которого уже прочитано для аудита
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
 type AuditService interface {
 	Log(entries []entity.AuditEntry) error
 }
@@ -168,13 +179,15 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 
 		receivedAt := time.Now()
 
-		bodyBytes, _ := c.GetRawData()
-		if len(bodyBytes) > maxBodySize {
-			bodyBytes = append(bodyBytes[:maxBodySize], []byte("... [TRUNCATED]")...)
+		// сохраняются только JSON-тела, файлы импорта и формы не читаются. Обработчик получает
+		// тело целиком: прочитанное начало склеивается с остатком.
+		var bodyBytes []byte
+		if c.ContentType() == binding.MIMEJSON && c.Request.Body != nil {
+			body := c.Request.Body
+			bodyBytes, _ = io.ReadAll(io.LimitReader(body, maxBodySize+1))
+			c.Request.Body = prefixedBody{Reader: io.MultiReader(bytes.NewReader(bodyBytes), body), Closer: body}
 		}
 
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
 		blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
 		c.Writer = blw
 
@@ -182,23 +195,11 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 
 		usrID, _ := helpers.UserIDContext(c)
 
-		reqBody := make(map[string]interface{})
-		if len(bodyBytes) > 0 {
-			if err := json.Unmarshal(bodyBytes, &reqBody); err != nil {
-				am.log.Error("Failed to unmarshal request body", zap.Error(err))
-			}
-		}
-
-		respBodyBytes := blw.body.Bytes()
-		if len(respBodyBytes) > maxBodySize {
-			respBodyBytes = append(respBodyBytes[:maxBodySize], []byte("... [TRUNCATED]")...)
-		}
+		reqBody := am.parseBody(bodyBytes, "request")
 
 		respBody := make(map[string]interface{})
-		if len(respBodyBytes) > 0 {
-			if err := json.Unmarshal(respBodyBytes, &respBody); err != nil {
-				am.log.Error("Failed to unmarshal response body", zap.Error(err))
-			}
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), binding.MIMEJSON) {
+			respBody = am.parseBody(blw.body.Bytes(), "response")
 		}
 
 		logE := entity.AuditEntry{
@@ -223,6 +224,24 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 	}
 }
 
+// parseBody разбирает JSON-тело для аудита. Тело больше maxBodySize не разбирается,
+// обрезанный JSON всё равно невалиден, вместо него сохраняется отметка truncated.
+func (am *AuditMiddleware) parseBody(body []byte, kind string) map[string]interface{} {
+	parsed := make(map[string]interface{})
+	if len(body) == 0 {
+		return parsed
+	}
+	if len(body) > maxBodySize {
+		parsed["truncated"] = true
+		return parsed
+	}
+
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		am.log.Error("Failed to unmarshal "+kind+" body", zap.Error(err))
+	}
+	return parsed
+}
+
 func getRole(c *gin.Context) string {
 	isStore, _ := helpers.UserIsStoreContext(c)
 	if isStore {
@@ -239,7 +258,8 @@ func sanitizeSensitiveData(data map[string]interface{}) {
 	if data == nil {
 		return
 	}
-	sensitiveFields := []string{"password", "fingerprint", "refresh_token", "access_token"}
+	// secret - ключ подписи вебхуков, который отдаётся один раз при создании
+	sensitiveFields := []string{"password", "fingerprint", "refresh_token", "access_token", "secret"}
 	for _, field := range sensitiveFields {
 		if _, ok := data[field]; ok {
 			data[field] = "[REDACTED]"
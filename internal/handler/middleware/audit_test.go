@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// auditLogStub запоминает записанные записи аудита
+type auditLogStub struct {
+	mu      sync.Mutex
+	entries []entity.AuditEntry
+}
+
+func (s *auditLogStub) Log(entries []entity.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+var _ = Describe("AuditMiddleware", func() {
+	var (
+		stub     *auditLogStub
+		audit    *AuditMiddleware
+		router   *gin.Engine
+		received []byte
+	)
+
+	BeforeEach(func() {
+		stub = &auditLogStub{}
+		audit = NewAuditMiddleware(&config.AuditConfig{WorkerPoolSize: 1, QueueSize: 10, BatchSize: 10},
+			stub, zap.NewNop())
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(audit.Middleware())
+		router.POST("/upload", func(c *gin.Context) {
+			received, _ = io.ReadAll(c.Request.Body)
+			c.JSON(http.StatusCreated, gin.H{"id": 1, "secret": "0123456789abcdef"})
+		})
+	})
+
+	// post отправляет запрос и возвращает записанную запись аудита
+	post := func(contentType string, body []byte) entity.AuditEntry {
+		req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		audit.Close()
+		Expect(stub.entries).To(HaveLen(1))
+		return stub.entries[0]
+	}
+
+	It("stores the JSON bodies without secrets", func() {
+		entry := post("application/json", []byte(`{"url": "https://shop.example.com", "secret": "0123456789abcdef"}`))
+
+		Expect(entry.ReqBody).To(Equal(map[string]interface{}{
+			"url": "https://shop.example.com", "secret": "[REDACTED]",
+		}))
+		Expect(entry.RespBody).To(Equal(map[string]interface{}{"id": float64(1), "secret": "[REDACTED]"}))
+	})
+
+	It("passes a large body to the handler whole and stores only a mark", func() {
+		body := []byte(`{"note": "` + strings.Repeat("a", 3*maxBodySize) + `"}`)
+
+		entry := post("application/json", body)
+
+		Expect(received).To(Equal(body))
+		Expect(entry.ReqBody).To(Equal(map[string]interface{}{"truncated": true}))
+	})
+
+	It("does not read file uploads", func() {
+		body := []byte("product_id,price,currency,is_available\n1,10,USD,true\n")
+
+		entry := post("text/csv", body)
+
+		Expect(received).To(Equal(body))
+		Expect(entry.ReqBody).To(BeEmpty())
+	})
+})
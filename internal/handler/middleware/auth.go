@@ -61,7 +61,26 @@ func AuthMiddleware(userGetter UserGetter, validator TokenValidator) gin.Handler
 		c.Set(helpers.UserName, user.Name)
 		c.Set(helpers.UserEmail, user.Email)
 
-		c.Set(helpers.UserIsAdminKey, false)
+		c.Set(helpers.UserIsAdminKey, user.IsAdmin)
+		c.Next()
+	}
+}
+
+// Admin пропускает только администраторов. Ставится после AuthMiddleware.
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		isAdmin, ok := helpers.UserIsAdminContext(c)
+		if !ok {
+			_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+			c.Abort()
+			return
+		}
+		if !isAdmin {
+			_ = c.Error(apperror.New(apperror.Forbidden, "admin access required", nil))
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -17,30 +17,40 @@ func Errors() gin.HandlerFunc {
 			err := c.Errors.Last().Err
 
 			var appErr apperror.AppError
-			if errors.As(err, &appErr) {
-				statusCode := errorStatus(appErr.Code())
-				resp := gin.H{
-					"code":    appErr.Code(),
-					"message": appErr.Message(),
-				}
-				if appErr.Code() == apperror.BadRequest {
-					resp["details"] = appErr.Error()
-				}
-				c.AbortWithStatusJSON(statusCode, resp)
-				return
+			if !errors.As(err, &appErr) {
+				log.Printf("Unhandled error: %v", err)
 			}
 
-			log.Printf("Unhandled error: %v", err)
-
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"code":    apperror.InternalError,
-				"message": "An unexpected internal error occurred",
-			})
+			c.AbortWithStatusJSON(errorResponse(err))
 			return
 		}
 	}
 }
 
+// errorResponse статус и тело ответа на ошибку хендлера
+func errorResponse(err error) (int, gin.H) {
+	var appErr apperror.AppError
+	if errors.As(err, &appErr) {
+		resp := gin.H{
+			"code":    appErr.Code(),
+			"message": appErr.Message(),
+		}
+		if appErr.Code() == apperror.BadRequest {
+			resp["details"] = appErr.Error()
+		}
+		var validationErr *apperror.ValidationError
+		if errors.As(err, &validationErr) {
+			resp["violations"] = validationErr.Violations
+		}
+		return errorStatus(appErr.Code()), resp
+	}
+
+	return http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected internal error occurred",
+	}
+}
+
 func errorStatus(code string) int {
 	switch code {
 	case apperror.NotFound:
@@ -57,6 +67,8 @@ func errorStatus(code string) int {
 		return http.StatusConflict
 	case apperror.Forbidden:
 		return http.StatusForbidden
+	case apperror.TooManyRequests:
+		return http.StatusTooManyRequests
 	case apperror.InternalError:
 		fallthrough
 
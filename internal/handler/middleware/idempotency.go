@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotency-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (*entity.IdempotencyRecord, string, error)
+	Complete(ctx context.Context, scope, key, lease string, statusCode int, body []byte) error
+	Release(ctx context.Context, scope, key, lease string) error
+}
+
+// Idempotency повторяет сохранённый ответ на запрос с уже использованным Idempotency-Key.
+// Ключ действует в пределах маршрута и пользователя, для гостей - маршрута и IP клиента.
+// Сохраняется первый ответ, в том числе с ошибкой клиента (4xx): повтор того же запроса получит
+// тот же ответ. После ошибки сервера (5xx), 429 или паники ключ освобождается, и запрос можно повторить.
+// Запросы без заголовка обрабатываются как обычно.
+func Idempotency(is IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			_ = c.Error(apperror.New(apperror.BadRequest, "Idempotency-Key is too long", nil))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			_ = c.Error(apperror.New(apperror.BadRequest, "failed to read request body", err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		hash := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(hash[:])
+
+		scope := c.Request.Method + " " + c.FullPath()
+		if userID, ok := helpers.UserIDContext(c); ok {
+			scope = fmt.Sprintf("%s user:%d", scope, userID)
+		} else {
+			scope = fmt.Sprintf("%s guest:%s", scope, c.ClientIP())
+		}
+
+		ctx := c.Request.Context()
+
+		saved, lease, err := is.Begin(ctx, scope, key, requestHash)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if saved != nil {
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(saved.StatusCode, "application/json; charset=utf-8", saved.Body)
+			c.Abort()
+			return
+		}
+
+		// ключ освобождается при любом выходе без сохранённого ответа, в том числе при панике хендлера
+		completed := false
+		defer func() {
+			if !completed {
+				_ = is.Release(context.WithoutCancel(ctx), scope, key, lease)
+			}
+		}()
+
+		writer := bodyLogWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		status, body := writer.Status(), writer.body.Bytes()
+		// ошибки хендлера пишет в ответ middleware.Errors уже после нас, поэтому их ответ
+		// формируется здесь так же, как это сделает Errors
+		if len(c.Errors) > 0 {
+			var resp gin.H
+			status, resp = errorResponse(c.Errors.Last().Err)
+			if body, err = json.Marshal(resp); err != nil {
+				return
+			}
+		}
+		if status < http.StatusOK || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			return
+		}
+
+		err = is.Complete(context.WithoutCancel(ctx), scope, key, lease, status, body)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		completed = true
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,8 +12,15 @@ import (
 // change this to change request timeout
 const to = 10 * time.Second
 
-func Timeout() gin.HandlerFunc {
+// Timeout ограничивает время обработки запроса. На маршруты из skipPaths
+// (долгоживущие соединения, например SSE) ограничение не действует.
+func Timeout(skipPaths ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if slices.Contains(skipPaths, c.FullPath()) {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), to)
 		defer cancel()
 
@@ -15,13 +15,31 @@ import (
 
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/text/currency"
 )
 
+//go:generate mockgen -source=$GOFILE -destination=offer_mock_test.go -package=handler OfferService
+
 type OfferService interface {
-	CreateOffer(ctx context.Context, offer entity.Offer, usr entity.User) (uint, error)
-	GetUserOffers(ctx context.Context, userID uint, page, limit int) ([]entity.Offer, int, error)
-	GetOffer(ctx context.Context, offerID uint) (entity.Offer, error)
+	CreateOffer(ctx context.Context, offer entity.Offer, usr entity.User) (entity.Offer, error)
+	GetUserOffers(ctx context.Context, userID uint, page, limit int, displayCurrency string) ([]entity.Offer, int, error)
+	GetOffer(ctx context.Context, offerID uint, userID uint) (entity.OfferDetails, error)
 	UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
+	GetOfferHistory(ctx context.Context, offerID uint, userID uint) ([]entity.OfferEvent, error)
+	GetShopOffers(
+		ctx context.Context,
+		userID uint,
+		filter entity.ShopOfferFilter,
+		page, limit int,
+		displayCurrency string,
+	) ([]entity.Offer, int, map[string]int, error)
+	BulkUpdateOfferStatus(
+		ctx context.Context,
+		offerIDs []uint,
+		status string,
+		userID uint,
+		atomic bool,
+	) ([]entity.BulkOfferResult, error)
 	DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error)
 }
 
@@ -34,14 +52,24 @@ func NewOfferHandler(offerService OfferService) *OfferHandler {
 }
 
 // @summary Create offer
+// @description The shop's auto-response rule, if any, is applied at once:
+// @description the returned status may already be accepted, declined or countered.
+// @description An offer below the shop's pricing policy or in an inconvertible currency is rejected
+// @description with 400 and the list of violated rules in violations.
+// @description A buyer over the open or hourly offer limit gets 429; after a decline the same product
+// @description of the same shop can be offered again only when the cooldown is over (409).
+// @description A product the shop does not sell at the moment or an archived one can't be offered (409).
 // @tags offer
 // @accept json
 // @produce json
 // @param body body dto.PostOfferReq true "Offer creation request"
+// @param Idempotency-Key header string false "Repeating a request with the same key returns the first response"
 // @success 201 {object} dto.PostOfferResp
 // @failure 400 {object} apperror.Error
 // @failure 401 {object} apperror.Error
 // @failure 403 {object} apperror.Error
+// @failure 409 {object} apperror.Error "Product not available, decline cooldown or Idempotency-Key in progress"
+// @failure 429 {object} apperror.Error "Offer quota exceeded"
 // @failure 500 {object} apperror.Error
 // @Router /offers [post]
 func (h *OfferHandler) PostOffer(c *gin.Context) {
@@ -86,13 +114,13 @@ func (h *OfferHandler) PostOffer(c *gin.Context) {
 	offerEnt := offerPost.ConvertToEntity()
 	offerEnt.UserID = userID
 
-	offerID, err := h.offerService.CreateOffer(c.Request.Context(), offerEnt, usr)
+	createdOffer, err := h.offerService.CreateOffer(c.Request.Context(), offerEnt, usr)
 	if err != nil {
-		_ = c.Error(apperror.New(apperror.InternalError, "Failed to create offer", err))
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.PostOfferResp{ID: offerID})
+	c.JSON(http.StatusCreated, dto.ConvertToPostOfferResp(createdOffer))
 }
 
 // @summary Get user's offers
@@ -101,6 +129,7 @@ func (h *OfferHandler) PostOffer(c *gin.Context) {
 // @produce json
 // @param page query int false "Page number for pagination" default(1)
 // @param limit query int false "Number of items per page (5-100)" default(10)
+// @param currency query string false "Display currency: adds display_price converted by the local rates"
 // @success 200 {object} dto.GetUserOffersResp
 // @failure 400 {object} apperror.Error
 // @failure 500 {object} apperror.Error
@@ -124,7 +153,15 @@ func (h *OfferHandler) GetUserOffers(c *gin.Context) {
 		return
 	}
 
-	offersEnt, total, err := h.offerService.GetUserOffers(c.Request.Context(), userID, page, limit)
+	displayCurrency := c.Query("currency")
+	if displayCurrency != "" {
+		if _, err = currency.ParseISO(displayCurrency); err != nil {
+			_ = c.Error(apperror.New(apperror.BadRequest, "currency must be an ISO 4217 code", err))
+			return
+		}
+	}
+
+	offersEnt, total, err := h.offerService.GetUserOffers(c.Request.Context(), userID, page, limit, displayCurrency)
 	if err != nil {
 		_ = c.Error(apperror.New(apperror.InternalError,
 			fmt.Sprintf("failed to get user (userID: %d) offers", userID), err))
@@ -138,25 +175,106 @@ func (h *OfferHandler) GetUserOffers(c *gin.Context) {
 	c.JSON(http.StatusOK, offersResp)
 }
 
+// @summary	Get shop's incoming offers
+// @description	Lists offers received by all shops of the current user, with per-status counts.
+// @tags		offer
+// @produce	json
+// @param		status		query		[]string	false	"Filter by status (repeatable)"	collectionFormat(multi)
+// @param		shop_id		query		int			false	"Filter by shop"
+// @param		product_id	query		int			false	"Filter by product"
+// @param		from		query		string		false	"Created at or after (RFC3339)"	Format(date-time)
+// @param		to			query		string		false	"Created at or before (RFC3339)"	Format(date-time)
+// @param		min_price	query		number		false	"Minimal offer price"
+// @param		max_price	query		number		false	"Maximal offer price"
+// @param		sort		query		string		false	"Sort field"	Enums(created_at, expires_at, price)
+// @param		order		query		string		false	"Sort order"	Enums(asc, desc)	default(desc)
+// @param		page		query		int			false	"Page number for pagination"	default(1)
+// @param		limit		query		int			false	"Number of items per page (5-100)"	default(10)
+// @param		currency	query		string		false	"Display currency: adds display_price converted by the local rates"
+// @success	200			{object}	dto.GetShopOffersResp
+// @failure	400			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/shop/offers [get]
+func (h *OfferHandler) GetShopOffers(c *gin.Context) {
+	store, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	if !store {
+		_ = c.Error(apperror.New(apperror.Forbidden,
+			"only store accounts have an offer inbox", nil))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	var req dto.GetShopOffersReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid query parameters", err))
+		return
+	}
+
+	offersEnt, total, statusCounts, err := h.offerService.GetShopOffers(c.Request.Context(), userID,
+		req.ConvertToEntity(), req.Page, req.Limit, req.Currency)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
+
+	c.JSON(http.StatusOK, dto.FormShopOffers(offersEnt, statusCounts, req.Page, req.Limit, total, totalPages))
+}
+
+// @summary	Get offer
+// @description	Returns the offer with its product and shop names.
+// @description	Available to the buyer and the owner of the offer's shop.
+// @tags		offer
+// @produce	json
+// @param		offerID	path		int	true	"Offer ID"
+// @success	200		{object}	dto.GetOfferResp
+// @failure	400		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/offers/{offerID} [get]
 func (h *OfferHandler) GetOffer(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, err := strconv.Atoi(c.Param("offerID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid non digit offer id"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "offerID must be numeric", err))
+		return
+	}
+	if id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "offerID must be positive", nil))
+		return
+	}
+
+	usrID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError,
+			"user id key not found in ctx", nil))
 		return
 	}
 
-	offerEntity, err := h.offerService.GetOffer(c.Request.Context(), uint(id))
+	offerDetails, err := h.offerService.GetOffer(c.Request.Context(), uint(id), usrID)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": offerEntity,
-	})
+	c.JSON(http.StatusOK, dto.ConvertToGetOfferResp(offerDetails))
 }
 
 // @summary	Update offer status
+// @description	Shop may accept, decline or counter a pending offer.
+// @description	Buyer may cancel an open offer, accept or counter the shop's counter-offer.
+// @description	Counter-offers require a price; a buyer's counter returns the offer to pending.
+// @description	A buyer's counter is checked against the shop's pricing policy like a new offer.
 // @tags		offer
 // @accept		json
 // @produce	json
@@ -207,7 +325,94 @@ func (h *OfferHandler) PatchOfferStatus(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.PatchOfferStatusResp{NewStatus: updatedOffer.Status})
+	c.JSON(http.StatusOK, dto.ConvertToPatchOfferStatusResp(updatedOffer))
+}
+
+// @summary	Accept or decline several offers
+// @description	Applies the same answer to a list of pending offers of the user's shops.
+// @description	In atomic mode (default) either every offer is updated or none;
+// @description	in per_item mode each offer is processed on its own. The report lists the outcome per offer.
+// @tags		offer
+// @accept		json
+// @produce	json
+// @param		body	body		dto.PatchShopOffersReq	true	"Offers and the target status"
+// @success	200		{object}	dto.PatchShopOffersResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shop/offers [patch]
+func (h *OfferHandler) PatchShopOffers(c *gin.Context) {
+	store, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	if !store {
+		_ = c.Error(apperror.New(apperror.Forbidden,
+			"only store accounts can answer offers in bulk", nil))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	var req dto.PatchShopOffersReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid request body", err))
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = "atomic"
+	}
+
+	results, err := h.offerService.BulkUpdateOfferStatus(c.Request.Context(), req.OfferIDs, req.Status, userID,
+		req.Mode == "atomic")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormBulkOfferResults(req.Mode, results))
+}
+
+// @summary	Get offer history
+// @description	Returns the log of offer changes. Available to the buyer and the owner of the offer's shop.
+// @tags		offer
+// @produce	json
+// @param		offerID	path		int	true	"Offer ID"
+// @success	200		{object}	dto.GetOfferHistoryResp
+// @failure	400		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/offers/{offerID}/history [get]
+func (h *OfferHandler) GetOfferHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("offerID"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "offerID must be numeric", err))
+		return
+	}
+	if id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "offerID must be positive", nil))
+		return
+	}
+
+	usrID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError,
+			"user id key not found in ctx", nil))
+		return
+	}
+
+	events, err := h.offerService.GetOfferHistory(c.Request.Context(), uint(id), usrID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormOfferHistory(events))
 }
 
 func (h *OfferHandler) DeleteOffer(c *gin.Context) {
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type OfferMessageService interface {
+	SendMessage(ctx context.Context, message entity.OfferMessage) (entity.OfferMessage, error)
+	GetThread(
+		ctx context.Context,
+		offerID, userID uint,
+		page, limit int,
+	) (messages []entity.OfferMessage, total, unread int, err error)
+	GetUnreadCounts(ctx context.Context, userID uint) ([]entity.OfferUnread, error)
+}
+
+type OfferMessageHandler struct {
+	messageService OfferMessageService
+}
+
+func NewOfferMessageHandler(messageService OfferMessageService) *OfferMessageHandler {
+	return &OfferMessageHandler{messageService: messageService}
+}
+
+// offerIDParam достаёт из пути ID оффера
+func offerIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("offerID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "offerID must be a positive number", err))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// @summary	Send a message on an offer
+// @description	Available to the buyer and the owner of the offer's shop while the offer is open
+// @description	(pending or countered).
+// @description	The other side is notified by email unless it already has unread messages from the sender.
+// @tags		offer
+// @accept		json
+// @produce	json
+// @param		offerID	path		int							true	"Offer ID"
+// @param		body	body		dto.PostOfferMessageReq		true	"Message"
+// @success	201		{object}	dto.OfferMessageResp
+// @failure	400		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	409		{object}	apperror.Error	"Offer is closed or is a guest offer"
+// @failure	500		{object}	apperror.Error
+// @Router		/offers/{offerID}/messages [post]
+func (h *OfferMessageHandler) PostMessage(c *gin.Context) {
+	offerID, ok := offerIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	var req dto.PostOfferMessageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid message data", err))
+		return
+	}
+
+	message, err := h.messageService.SendMessage(c.Request.Context(), req.ConvertToEntity(offerID, userID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ConvertToOfferMessageResp(message))
+}
+
+// @summary	Get offer message thread
+// @description	Returns the messages oldest first and marks the other side's messages on the page as read.
+// @description	meta.unread is the number of messages on the page that were unread before this request.
+// @tags		offer
+// @produce	json
+// @param		offerID	path		int	true	"Offer ID"
+// @param		page	query		int	false	"Page number for pagination"	default(1)
+// @param		limit	query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200		{object}	dto.GetOfferMessagesResp
+// @failure	400		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/offers/{offerID}/messages [get]
+func (h *OfferMessageHandler) GetMessages(c *gin.Context) {
+	offerID, ok := offerIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	page, limit, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	messages, total, unread, err := h.messageService.GetThread(c.Request.Context(), offerID, userID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormOfferMessages(messages, page, limit, total, totalPages, unread))
+}
+
+// @summary	Get unread message counts
+// @description	Unread messages per offer where the user is the buyer or the shop owner.
+// @description	Offers without unread messages are omitted.
+// @tags		offer
+// @produce	json
+// @success	200	{object}	dto.GetUnreadMessagesResp
+// @failure	500	{object}	apperror.Error
+// @Router		/messages/unread [get]
+func (h *OfferMessageHandler) GetUnread(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	unread, err := h.messageService.GetUnreadCounts(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormUnreadMessages(unread))
+}
@@ -0,0 +1,165 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: offer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=offer.go -destination=offer_mock_test.go -package=handler OfferService
+//
+
+// Package handler is a generated GoMock package.
+package handler
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOfferService is a mock of OfferService interface.
+type MockOfferService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockOfferServiceMockRecorder is the mock recorder for MockOfferService.
+type MockOfferServiceMockRecorder struct {
+	mock *MockOfferService
+}
+
+// NewMockOfferService creates a new mock instance.
+func NewMockOfferService(ctrl *gomock.Controller) *MockOfferService {
+	mock := &MockOfferService{ctrl: ctrl}
+	mock.recorder = &MockOfferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferService) EXPECT() *MockOfferServiceMockRecorder {
+	return m.recorder
+}
+
+// BulkUpdateOfferStatus mocks base method.
+func (m *MockOfferService) BulkUpdateOfferStatus(ctx context.Context, offerIDs []uint, status string, userID uint, atomic bool) ([]entity.BulkOfferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateOfferStatus", ctx, offerIDs, status, userID, atomic)
+	ret0, _ := ret[0].([]entity.BulkOfferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateOfferStatus indicates an expected call of BulkUpdateOfferStatus.
+func (mr *MockOfferServiceMockRecorder) BulkUpdateOfferStatus(ctx, offerIDs, status, userID, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateOfferStatus", reflect.TypeOf((*MockOfferService)(nil).BulkUpdateOfferStatus), ctx, offerIDs, status, userID, atomic)
+}
+
+// CreateOffer mocks base method.
+func (m *MockOfferService) CreateOffer(ctx context.Context, offer entity.Offer, usr entity.User) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOffer", ctx, offer, usr)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOffer indicates an expected call of CreateOffer.
+func (mr *MockOfferServiceMockRecorder) CreateOffer(ctx, offer, usr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOffer", reflect.TypeOf((*MockOfferService)(nil).CreateOffer), ctx, offer, usr)
+}
+
+// DeleteOffer mocks base method.
+func (m *MockOfferService) DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOffer", ctx, offerID)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOffer indicates an expected call of DeleteOffer.
+func (mr *MockOfferServiceMockRecorder) DeleteOffer(ctx, offerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOffer", reflect.TypeOf((*MockOfferService)(nil).DeleteOffer), ctx, offerID)
+}
+
+// GetOffer mocks base method.
+func (m *MockOfferService) GetOffer(ctx context.Context, offerID, userID uint) (entity.OfferDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOffer", ctx, offerID, userID)
+	ret0, _ := ret[0].(entity.OfferDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOffer indicates an expected call of GetOffer.
+func (mr *MockOfferServiceMockRecorder) GetOffer(ctx, offerID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOffer", reflect.TypeOf((*MockOfferService)(nil).GetOffer), ctx, offerID, userID)
+}
+
+// GetOfferHistory mocks base method.
+func (m *MockOfferService) GetOfferHistory(ctx context.Context, offerID, userID uint) ([]entity.OfferEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOfferHistory", ctx, offerID, userID)
+	ret0, _ := ret[0].([]entity.OfferEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOfferHistory indicates an expected call of GetOfferHistory.
+func (mr *MockOfferServiceMockRecorder) GetOfferHistory(ctx, offerID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOfferHistory", reflect.TypeOf((*MockOfferService)(nil).GetOfferHistory), ctx, offerID, userID)
+}
+
+// GetShopOffers mocks base method.
+func (m *MockOfferService) GetShopOffers(ctx context.Context, userID uint, filter entity.ShopOfferFilter, page, limit int, displayCurrency string) ([]entity.Offer, int, map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopOffers", ctx, userID, filter, page, limit, displayCurrency)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(map[string]int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetShopOffers indicates an expected call of GetShopOffers.
+func (mr *MockOfferServiceMockRecorder) GetShopOffers(ctx, userID, filter, page, limit, displayCurrency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopOffers", reflect.TypeOf((*MockOfferService)(nil).GetShopOffers), ctx, userID, filter, page, limit, displayCurrency)
+}
+
+// GetUserOffers mocks base method.
+func (m *MockOfferService) GetUserOffers(ctx context.Context, userID uint, page, limit int, displayCurrency string) ([]entity.Offer, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOffers", ctx, userID, page, limit, displayCurrency)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserOffers indicates an expected call of GetUserOffers.
+func (mr *MockOfferServiceMockRecorder) GetUserOffers(ctx, userID, page, limit, displayCurrency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOffers", reflect.TypeOf((*MockOfferService)(nil).GetUserOffers), ctx, userID, page, limit, displayCurrency)
+}
+
+// UpdateOfferStatus mocks base method.
+func (m *MockOfferService) UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOfferStatus", ctx, offer, userID, isStore)
+	ret0, _ := ret[0].(entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOfferStatus indicates an expected call of UpdateOfferStatus.
+func (mr *MockOfferServiceMockRecorder) UpdateOfferStatus(ctx, offer, userID, isStore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOfferStatus", reflect.TypeOf((*MockOfferService)(nil).UpdateOfferStatus), ctx, offer, userID, isStore)
+}
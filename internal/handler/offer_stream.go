@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// OfferEventsPath путь SSE-потока событий офферов относительно версии API.
+// Соединение долгоживущее, поэтому на него не действует middleware.Timeout.
+const OfferEventsPath = "/offers/events"
+
+// как часто в поток пишется комментарий, чтобы прокси не закрывали простаивающее соединение
+const offerStreamHeartbeat = 25 * time.Second
+
+type OfferStreamService interface {
+	Subscribe(userID uint) (<-chan entity.OfferUpdate, func())
+}
+
+type OfferStreamHandler struct {
+	offerStream OfferStreamService
+	heartbeat   time.Duration
+}
+
+func NewOfferStreamHandler(offerStream OfferStreamService) *OfferStreamHandler {
+	return &OfferStreamHandler{offerStream: offerStream, heartbeat: offerStreamHeartbeat}
+}
+
+// @summary	Stream offer updates
+// @description	Server-Sent Events stream of offer lifecycle events for the current user:
+// @description	offers they made as a buyer and offers sent to their shops.
+// @description	Event names are created, status_changed and expired; data is a JSON object.
+// @tags		offer
+// @produce	text/event-stream
+// @success	200	{object}	dto.OfferUpdateResp
+// @failure	401	{object}	apperror.Error
+// @Router		/offers/events [get]
+func (h *OfferStreamHandler) StreamOfferUpdates(c *gin.Context) {
+	usrID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError,
+			"user id key not found in ctx", nil))
+		return
+	}
+
+	updates, unsubscribe := h.offerStream.Subscribe(usrID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			c.SSEvent(update.Type, dto.ConvertToOfferUpdateResp(update))
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
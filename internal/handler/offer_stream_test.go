@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offerstream"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("OfferStreamHandler", func() {
+	var (
+		bus    *offerstream.Bus
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		bus = offerstream.NewBus(nil, nil, zap.NewNop())
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Errors())
+		router.Use(middleware.Timeout(OfferEventsPath))
+		router.Use(func(c *gin.Context) {
+			c.Set(helpers.UserIDKey, uint(2))
+		})
+		router.GET(OfferEventsPath, NewOfferStreamHandler(bus).StreamOfferUpdates)
+		router.GET("/offers/:offerID", func(c *gin.Context) {})
+
+		server = httptest.NewServer(router)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("streams the user's offer updates as server-sent events", func() {
+		resp, err := http.Get(server.URL + OfferEventsPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+		bus.Dispatch(entity.OfferUpdate{
+			Type: entity.OfferUpdateStatusChanged, OfferID: 7, Status: "accepted", BuyerID: 2, ShopOwnerID: 1,
+		})
+
+		reader := bufio.NewReader(resp.Body)
+		event, err := reader.ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		Expect(event).To(Equal("event:status_changed\n"))
+
+		data, err := reader.ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimPrefix(data, "data:")).To(MatchJSON(`{
+			"type": "status_changed", "offer_id": 7, "status": "accepted", "price": 0, "currency": "",
+			"shop_id": 0, "user_id": 2, "occurred_at": "0001-01-01T00:00:00Z"
+		}`))
+	})
+
+	It("ends the stream when the bus stops", func() {
+		resp, err := http.Get(server.URL + OfferEventsPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		bus.Stop(resp.Request.Context())
+
+		_, err = bufio.NewReader(resp.Body).ReadString('\n')
+		Expect(err).To(HaveOccurred())
+	})
+})
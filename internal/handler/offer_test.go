@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("OfferHandler", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockService *MockOfferService
+		router      *gin.Engine
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockService = NewMockOfferService(ctrl)
+		setupValitators()
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(middleware.Errors())
+		router.Use(func(c *gin.Context) {
+			c.Set(helpers.UserIDKey, uint(2))
+			c.Set(helpers.UserIsStoreKey, false)
+			c.Set(helpers.UserName, "buyer")
+			c.Set(helpers.UserEmail, "buyer@example.com")
+		})
+		router.POST("/offers", NewOfferHandler(mockService).PostOffer)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("PostOffer", func() {
+		postOffer := func() *httptest.ResponseRecorder {
+			body := `{"product_id": 3, "shop_id": 1, "price": 10, "currency": "USD"}`
+			req := httptest.NewRequest(http.MethodPost, "/offers", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("returns the violated pricing rules with 400", func() {
+			mockService.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(entity.Offer{}, apperror.NewValidationError("offer violates the shop pricing policy",
+					apperror.Violation{Field: "price", Rule: "min_price_percent", Message: "price is too low"}))
+
+			w := postOffer()
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var resp struct {
+				Code       string               `json:"code"`
+				Violations []apperror.Violation `json:"violations"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.Code).To(Equal(apperror.BadRequest))
+			Expect(resp.Violations).To(ConsistOf(
+				apperror.Violation{Field: "price", Rule: "min_price_percent", Message: "price is too low"}))
+		})
+
+		It("returns 429 when the buyer is over the offer quota", func() {
+			mockService.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(entity.Offer{}, apperror.New(apperror.TooManyRequests, "too many open offers (limit 3)", nil))
+
+			w := postOffer()
+
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(w.Body.String()).To(ContainSubstring("too many open offers"))
+		})
+
+		It("returns 409 during the decline cooldown", func() {
+			mockService.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(entity.Offer{}, apperror.New(apperror.Conflict, "the shop declined your offer", nil))
+
+			Expect(postOffer().Code).To(Equal(http.StatusConflict))
+		})
+	})
+})
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderService interface {
+	GetOrder(ctx context.Context, orderID, userID uint) (entity.Order, error)
+	GetUserOrders(ctx context.Context, userID uint, page, limit int) ([]entity.Order, int, error)
+	GetShopOrders(ctx context.Context, userID uint, page, limit int) ([]entity.Order, int, error)
+	UpdateOrderStatus(ctx context.Context, orderID, userID uint, isStore bool, status string) (entity.Order, error)
+}
+
+type OrderHandler struct {
+	orderService OrderService
+}
+
+func NewOrderHandler(orderService OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+// @summary	Get buyer's orders
+// @tags		order
+// @produce	json
+// @param		page	query		int	false	"Page number for pagination"	default(1)
+// @param		limit	query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200		{object}	dto.GetOrdersResp
+// @failure	400		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/orders [get]
+func (h *OrderHandler) GetUserOrders(c *gin.Context) {
+	h.listOrders(c, h.orderService.GetUserOrders)
+}
+
+// @summary	Get shop's orders
+// @description	Lists orders of all shops of the current user.
+// @tags		order
+// @produce	json
+// @param		page	query		int	false	"Page number for pagination"	default(1)
+// @param		limit	query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200		{object}	dto.GetOrdersResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shop/orders [get]
+func (h *OrderHandler) GetShopOrders(c *gin.Context) {
+	store, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	if !store {
+		_ = c.Error(apperror.New(apperror.Forbidden, "only store accounts have shop orders", nil))
+		return
+	}
+
+	h.listOrders(c, h.orderService.GetShopOrders)
+}
+
+func (h *OrderHandler) listOrders(
+	c *gin.Context,
+	getOrders func(ctx context.Context, userID uint, page, limit int) ([]entity.Order, int, error),
+) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 5 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 5-100)", err))
+		return
+	}
+
+	orders, total, err := getOrders(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormOrders(orders, page, limit, total, totalPages))
+}
+
+// @summary	Get order
+// @description	Available to the buyer and the owner of the order's shop.
+// @tags		order
+// @produce	json
+// @param		orderID	path		int	true	"Order ID"
+// @success	200		{object}	dto.OrderResp
+// @failure	400		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/orders/{orderID} [get]
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("orderID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "orderID must be a positive number", err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToOrderResp(order))
+}
+
+// @summary	Update order status
+// @description	Buyer may cancel an order awaiting payment or complete a shipped order.
+// @description	Shop may confirm payment of an order awaiting payment, ship a paid order
+// @description	or cancel an order that has not been shipped yet.
+// @tags		order
+// @accept		json
+// @produce	json
+// @param		orderID	path		int							true	"Order ID"
+// @param		body	body		dto.PatchOrderStatusReq		true	"Order status update request"
+// @success	200		{object}	dto.OrderResp
+// @failure	400		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	409		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/orders/{orderID} [patch]
+func (h *OrderHandler) PatchOrderStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("orderID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "orderID must be a positive number", err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	isStore, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user is store key not found in context", nil))
+		return
+	}
+
+	var req dto.PatchOrderStatusReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid request body", err))
+		return
+	}
+
+	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), uint(id), userID, isStore, req.Status)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToOrderResp(order))
+}
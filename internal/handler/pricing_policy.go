@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type PricingPolicyService interface {
+	GetShopPolicy(ctx context.Context, shopID, userID uint) (entity.PricingPolicy, error)
+	SaveShopPolicy(ctx context.Context, policy entity.PricingPolicy, userID uint) (entity.PricingPolicy, error)
+}
+
+type PricingPolicyHandler struct {
+	pricingPolicyService PricingPolicyService
+}
+
+func NewPricingPolicyHandler(pricingPolicyService PricingPolicyService) *PricingPolicyHandler {
+	return &PricingPolicyHandler{pricingPolicyService: pricingPolicyService}
+}
+
+// @summary	Get shop pricing policy
+// @description	Returns the shop's pricing policy or the default one if the shop hasn't set its own.
+// @tags		pricing-policy
+// @produce	json
+// @param		shopID	path		int	true	"Shop ID"
+// @success	200		{object}	dto.PricingPolicyResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/pricing-policy [get]
+func (h *PricingPolicyHandler) GetPolicy(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.pricingPolicyService.GetShopPolicy(c.Request.Context(), shopID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToPricingPolicyResp(policy))
+}
+
+// @summary	Save shop pricing policy
+// @description	Offers and buyer counter-offers below min_price_percent of the inventory list price
+// @description	are rejected with the violated rules. 0 disables the price check.
+// @tags		pricing-policy
+// @accept		json
+// @produce	json
+// @param		shopID	path		int						true	"Shop ID"
+// @param		body	body		dto.PutPricingPolicyReq	true	"Policy"
+// @success	200		{object}	dto.PricingPolicyResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/pricing-policy [put]
+func (h *PricingPolicyHandler) PutPolicy(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutPricingPolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid pricing policy data", err))
+		return
+	}
+
+	policy, err := h.pricingPolicyService.SaveShopPolicy(c.Request.Context(), req.ConvertToEntity(shopID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToPricingPolicyResp(policy))
+}
@@ -10,15 +10,25 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
 
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/text/currency"
 )
 
 type ProductService interface {
 	GetFilteredProducts(ctx context.Context, filter model.ProductFilter, limit, offset int) ([]entity.Product, int, error)
-	GetProductByID(ctx context.Context, id string) (entity.Product, error)
+	GetProductByID(ctx context.Context, id string, currency string) (entity.Product, error)
+	CreateProduct(ctx context.Context, shopID, userID uint, product entity.Product) (entity.Product, error)
+	UpdateProduct(
+		ctx context.Context,
+		shopID, productID, userID uint,
+		update entity.ProductUpdate,
+	) (entity.Product, error)
+	ArchiveProduct(ctx context.Context, shopID, productID, userID uint) (entity.Product, error)
+	GetShopProducts(ctx context.Context, shopID, userID uint, limit, offset int) ([]entity.Product, int, error)
 }
 
 type ProductHandler struct {
@@ -33,7 +43,8 @@ func NewProductHandler(productService ProductService) *ProductHandler {
 // @Summary      Получить продукт по его ID
 // @Description  Возвращает один продукт по его идентификатору
 // @Tags         products
-// @Param        id   path      int  true  "ID продукта"
+// @Param        id        path      int     true   "ID продукта"
+// @Param        currency  query     string  false  "Валюта диапазона цен (ISO 4217), по умолчанию из конфига"
 // @Success      200  {object}  entity.Product
 // @Failure      400  {object}  apperror.Error "Некорректный ID"
 // @Failure      500  {object}  apperror.Error "Ошибка сервера при получении продукта"
@@ -50,7 +61,18 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productService.GetProductByID(context.Background(), id)
+	displayCurrency := c.Query("currency")
+	if displayCurrency != "" {
+		if _, err := currency.ParseISO(displayCurrency); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "currency must be an ISO 4217 code",
+			})
+			return
+		}
+	}
+
+	product, err := h.productService.GetProductByID(context.Background(), id, displayCurrency)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    apperror.DatabaseError,
@@ -65,14 +87,17 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 // GetProducts godoc
 // @Summary      Получить список продуктов с фильтрацией и пагинацией
 // @Description  Возвращает список продуктов по фильтру (категория, цена, магазин, имя, атрибуты) с поддержкой пагинации
+// @Description  С параметром q товары упорядочены по релевантности, а в snippet слова запроса обёрнуты в <mark>.
 // @Tags         products
 // @Accept       json
 // @Produce      json
 // @Param        page         query     int     false  "Номер страницы (по умолчанию 1)"
 // @Param        limit        query     int     false  "Размер страницы (по умолчанию 10, максимум 100)"
 // @Param        name         query     string  false  "Фильтр по названию продукта (поиск по подстроке)"
-// @Param        min_price    query     int     false  "Минимальная цена (в копейках)"
-// @Param        max_price    query     int     false  "Максимальная цена (в копейках)"
+// @Param        q            query     string  false  "Полнотекстовый поиск по названию и описанию (до 200 символов)"
+// @Param        min_price    query     int     false  "Минимальная цена (в копейках валюты currency)"
+// @Param        max_price    query     int     false  "Максимальная цена (в копейках валюты currency)"
+// @Param        currency     query     string  false  "Валюта отображения (ISO 4217): цены пересчитываются по курсам"
 // @Param        category_id  query     int     false  "ID категории (с учетом подкатегорий)"
 // @Param        shop_id      query     int     false  "ID магазина"
 // @Param        attributes   query     string  false  "JSON-строка с фильтрами по атрибутам (exmpl: {"color":"Black"})"
@@ -135,3 +160,183 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		},
 	})
 }
+
+// productIDParam достаёт из пути ID товара
+func productIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("productID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "productID must be a positive number", err))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// GetShopProducts godoc
+// @Summary      Получить товары магазина
+// @Description  Возвращает товары, заведённые магазином, включая архивные, новые первыми.
+// @Description  Доступно только владельцу магазина.
+// @Tags         products
+// @Produce      json
+// @Param        shopID  path      int  true   "ID магазина"
+// @Param        page    query     int  false  "Номер страницы (по умолчанию 1)"
+// @Param        limit   query     int  false  "Размер страницы (по умолчанию 10, максимум 100)"
+// @Success      200  {object}  map[string]interface{} "Список товаров и метаинформация"
+// @Failure      400  {object}  apperror.Error "Некорректный запрос"
+// @Failure      403  {object}  apperror.Error "Аккаунт не является магазином"
+// @Failure      404  {object}  apperror.Error "Магазин не найден"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера"
+// @Router       /shops/{shopID}/products [get]
+func (h *ProductHandler) GetShopProducts(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid limit value (should be between 1 and 100)", err))
+		return
+	}
+
+	products, total, err := h.productService.GetShopProducts(c.Request.Context(), shopID, userID,
+		limit, (page-1)*limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": products,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+// PostProduct godoc
+// @Summary      Завести товар магазина
+// @Description  Создаёт товар в каталоге от имени магазина. Значения атрибутов - строки, числа
+// @Description  или логические значения, не более 50 атрибутов.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        shopID  path      int                 true  "ID магазина"
+// @Param        body    body      dto.PostProductReq  true  "Товар"
+// @Success      201  {object}  entity.Product
+// @Failure      400  {object}  apperror.Error "Некорректные данные товара"
+// @Failure      403  {object}  apperror.Error "Аккаунт не является магазином"
+// @Failure      404  {object}  apperror.Error "Магазин или категория не найдены"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера"
+// @Router       /shops/{shopID}/products [post]
+func (h *ProductHandler) PostProduct(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostProductReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid product data", err))
+		return
+	}
+
+	product, err := h.productService.CreateProduct(c.Request.Context(), shopID, userID, req.ConvertToEntity())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, product)
+}
+
+// PatchProduct godoc
+// @Summary      Изменить товар магазина
+// @Description  Меняет заданные поля товара. product_attributes заменяют атрибуты целиком.
+// @Description  Архивный товар изменить нельзя.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        shopID     path      int                  true  "ID магазина"
+// @Param        productID  path      int                  true  "ID товара"
+// @Param        body       body      dto.PatchProductReq  true  "Изменения товара"
+// @Success      200  {object}  entity.Product
+// @Failure      400  {object}  apperror.Error "Некорректные данные товара"
+// @Failure      403  {object}  apperror.Error "Аккаунт не является магазином"
+// @Failure      404  {object}  apperror.Error "Магазин, товар или категория не найдены"
+// @Failure      409  {object}  apperror.Error "Товар в архиве"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера"
+// @Router       /shops/{shopID}/products/{productID} [patch]
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PatchProductReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid product data", err))
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(c.Request.Context(), shopID, productID, userID,
+		req.ConvertToEntity())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// ArchiveProduct godoc
+// @Summary      Архивировать товар магазина
+// @Description  Скрывает товар из каталога и снимает его с продажи в магазине.
+// @Description  Пока товар продают другие магазины, архивировать его нельзя.
+// @Description  Открытые офферы магазина на товар отклоняются, заказы не затрагиваются.
+// @Tags         products
+// @Produce      json
+// @Param        shopID     path      int  true  "ID магазина"
+// @Param        productID  path      int  true  "ID товара"
+// @Success      200  {object}  entity.Product
+// @Failure      400  {object}  apperror.Error "Некорректный запрос"
+// @Failure      403  {object}  apperror.Error "Аккаунт не является магазином"
+// @Failure      404  {object}  apperror.Error "Магазин или товар не найдены"
+// @Failure      409  {object}  apperror.Error "Товар уже в архиве или его продают другие магазины"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера"
+// @Router       /shops/{shopID}/products/{productID}/archive [post]
+func (h *ProductHandler) ArchiveProduct(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.ArchiveProduct(c.Request.Context(), shopID, productID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
@@ -15,7 +15,7 @@ import (
 //go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=handler UserService
 
 type UserService interface {
-	CreateUser(ctx context.Context, user user.User, fingerprint string) (string, string, error)
+	CreateUser(ctx context.Context, user user.User, fingerprint string) (string, string, int, error)
 	Authenticate(ctx context.Context, email, password, fingerprint string) (string, string, error)
 	Refresh(ctx context.Context, refreshToken, fingerprint string) (string, string, error)
 	Logout(ctx context.Context, refreshToken, fingerprint string) error
@@ -43,7 +43,9 @@ func NewUserHandler(
 // Registration godoc
 //
 //	@Summary		Регистрация нового пользователя
-//	@Description	Регистрирует нового пользователя и возвращает токены доступа/обновления
+//	@Description	Регистрирует нового пользователя и возвращает токены доступа/обновления.
+//	@Description	Если передан guest_token из письма гостю, офферы, отправленные гостем с той почты,
+//	@Description	переходят к пользователю.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
@@ -58,7 +60,7 @@ func (h *UserHandler) Registration(c *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.userService.CreateUser(
+	accessToken, refreshToken, claimedOffers, err := h.userService.CreateUser(
 		c.Request.Context(),
 		regUserDTO.ConvertToSvc(),
 		regUserDTO.Fingerprint,
@@ -68,8 +70,9 @@ func (h *UserHandler) Registration(c *gin.Context) {
 		return
 	}
 	response := dto.RegistrationUserResp{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		ClaimedOffers: claimedOffers,
 	}
 
 	setRefreshCookie(c, refreshToken, h.basePath, h.domain, h.refreshLife)
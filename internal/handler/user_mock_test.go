@@ -59,13 +59,14 @@ func (mr *MockUserServiceMockRecorder) Authenticate(ctx, email, password, finger
 }
 
 // CreateUser mocks base method.
-func (m *MockUserService) CreateUser(ctx context.Context, arg1 user.User, fingerprint string) (string, string, error) {
+func (m *MockUserService) CreateUser(ctx context.Context, arg1 user.User, fingerprint string) (string, string, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateUser", ctx, arg1, fingerprint)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret2, _ := ret[2].(int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
 }
 
 // CreateUser indicates an expected call of CreateUser.
@@ -58,7 +58,7 @@ var _ = Describe("UserHandler", func() {
 
 				mockService.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any(), "fp123").
-					Return("access_token", "refresh_token", nil)
+					Return("access_token", "refresh_token", 2, nil)
 
 				jsonData, _ := json.Marshal(input)
 				req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonData))
@@ -73,6 +73,7 @@ var _ = Describe("UserHandler", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(response.AccessToken).To(Equal("access_token"))
 				Expect(response.RefreshToken).To(Equal("refresh_token"))
+				Expect(response.ClaimedOffers).To(Equal(2))
 			})
 		})
 
@@ -106,7 +107,7 @@ var _ = Describe("UserHandler", func() {
 
 				mockService.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any(), "fp123").
-					Return("", "", apperror.New(apperror.InternalError, "service error", nil))
+					Return("", "", 0, apperror.New(apperror.InternalError, "service error", nil))
 
 				jsonData, _ := json.Marshal(input)
 				req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonData))
@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type WantedService interface {
+	CreateRequest(ctx context.Context, request entity.WantedRequest) (entity.WantedRequest, error)
+	GetUserRequests(ctx context.Context, userID uint, page, limit int) ([]entity.WantedRequest, int, error)
+	GetRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error)
+	CancelRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error)
+	AwardBid(ctx context.Context, requestID, bidID, userID uint) (entity.WantedRequest, error)
+	GetShopRequests(ctx context.Context, shopID, userID uint, page, limit int) ([]entity.WantedRequest, int, error)
+	PlaceBid(ctx context.Context, bid entity.WantedBid, userID uint) (entity.WantedBid, error)
+	WithdrawBid(ctx context.Context, requestID, shopID, userID uint) error
+}
+
+type WantedHandler struct {
+	wantedService WantedService
+}
+
+func NewWantedHandler(wantedService WantedService) *WantedHandler {
+	return &WantedHandler{wantedService: wantedService}
+}
+
+// pageParams достаёт из запроса номер страницы и размер страницы (5-100)
+func pageParams(c *gin.Context) (page, limit int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
+		return 0, 0, false
+	}
+
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 5 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 5-100)", err))
+		return 0, 0, false
+	}
+
+	return page, limit, true
+}
+
+// requestIDParam достаёт из пути ID запроса на покупку
+func requestIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("requestID"))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "requestID must be a positive number", err))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// @summary	Publish a wanted request
+// @description	Buyer asks shops for a product or, with category_id, any product of the category
+// @description	and its subcategories. Shops stocking a matching product bid until the deadline.
+// @tags		wanted
+// @accept		json
+// @produce	json
+// @param		body	body		dto.PostWantedRequestReq	true	"Wanted request"
+// @success	201		{object}	dto.WantedRequestResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/wanted [post]
+func (h *WantedHandler) PostRequest(c *gin.Context) {
+	store, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	if store {
+		_ = c.Error(apperror.New(apperror.Forbidden, "store accounts are not allowed to publish wanted requests", nil))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	var req dto.PostWantedRequestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid wanted request data", err))
+		return
+	}
+
+	request, err := h.wantedService.CreateRequest(c.Request.Context(), req.ConvertToEntity(userID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ConvertToWantedRequestResp(request))
+}
+
+// @summary	Get buyer's wanted requests
+// @tags		wanted
+// @produce	json
+// @param		page	query		int	false	"Page number for pagination"	default(1)
+// @param		limit	query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200		{object}	dto.GetWantedRequestsResp
+// @failure	400		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/wanted [get]
+func (h *WantedHandler) GetUserRequests(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	page, limit, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	requests, total, err := h.wantedService.GetUserRequests(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormWantedRequests(requests, page, limit, total, totalPages))
+}
+
+// @summary	Get wanted request
+// @description	Returns the buyer's request with all bids that were not withdrawn, lowest first.
+// @tags		wanted
+// @produce	json
+// @param		requestID	path		int	true	"Wanted request ID"
+// @success	200			{object}	dto.WantedRequestResp
+// @failure	400			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/wanted/{requestID} [get]
+func (h *WantedHandler) GetRequest(c *gin.Context) {
+	requestID, ok := requestIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	request, err := h.wantedService.GetRequest(c.Request.Context(), requestID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToWantedRequestResp(request))
+}
+
+// @summary	Cancel wanted request
+// @description	Closes an open request without a winner, all active bids lose.
+// @tags		wanted
+// @produce	json
+// @param		requestID	path		int	true	"Wanted request ID"
+// @success	200			{object}	dto.WantedRequestResp
+// @failure	400			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	409			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/wanted/{requestID}/cancel [post]
+func (h *WantedHandler) CancelRequest(c *gin.Context) {
+	requestID, ok := requestIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	request, err := h.wantedService.CancelRequest(c.Request.Context(), requestID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToWantedRequestResp(request))
+}
+
+// @summary	Award a bid
+// @description	Buyer picks the winning bid before the deadline. The bid becomes an accepted offer
+// @description	at the bid price with an order awaiting payment; the other bids lose.
+// @description	A bid whose product the shop has withdrawn from sale cannot be awarded (409).
+// @tags		wanted
+// @produce	json
+// @param		requestID	path		int	true	"Wanted request ID"
+// @param		bidID		path		int	true	"Bid ID"
+// @success	200			{object}	dto.WantedRequestResp
+// @failure	400			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	409			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/wanted/{requestID}/bids/{bidID}/award [post]
+func (h *WantedHandler) AwardBid(c *gin.Context) {
+	requestID, ok := requestIDParam(c)
+	if !ok {
+		return
+	}
+
+	bidID, err := strconv.Atoi(c.Param("bidID"))
+	if err != nil || bidID <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "bidID must be a positive number", err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.InternalError, "user ID not found in context", nil))
+		return
+	}
+
+	request, err := h.wantedService.AwardBid(c.Request.Context(), requestID, uint(bidID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToWantedRequestResp(request))
+}
+
+// @summary	Get wanted requests for a shop
+// @description	Lists open requests the shop can bid on with an available inventory product,
+// @description	nearest deadline first. Each request carries only the shop's own bid.
+// @tags		wanted
+// @produce	json
+// @param		shopID	path		int	true	"Shop ID"
+// @param		page	query		int	false	"Page number for pagination"	default(1)
+// @param		limit	query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200		{object}	dto.GetWantedRequestsResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/wanted [get]
+func (h *WantedHandler) GetShopRequests(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	page, limit, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	requests, total, err := h.wantedService.GetShopRequests(c.Request.Context(), shopID, userID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormWantedRequests(requests, page, limit, total, totalPages))
+}
+
+// @summary	Place a bid
+// @description	Creates or replaces the shop's bid on an open request. The product must match
+// @description	the request and be available in the shop; the price is in the request's currency.
+// @tags		wanted
+// @accept		json
+// @produce	json
+// @param		shopID		path		int					true	"Shop ID"
+// @param		requestID	path		int					true	"Wanted request ID"
+// @param		body		body		dto.PutWantedBidReq	true	"Bid"
+// @success	200			{object}	dto.WantedBidResp
+// @failure	400			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	409			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/shops/{shopID}/wanted/{requestID}/bid [put]
+func (h *WantedHandler) PutBid(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	requestID, ok := requestIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutWantedBidReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid bid data", err))
+		return
+	}
+
+	bid, err := h.wantedService.PlaceBid(c.Request.Context(), req.ConvertToEntity(requestID, shopID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ConvertToWantedBidResp(bid))
+}
+
+// @summary	Withdraw a bid
+// @tags		wanted
+// @param		shopID		path	int	true	"Shop ID"
+// @param		requestID	path	int	true	"Wanted request ID"
+// @success	204
+// @failure	400	{object}	apperror.Error
+// @failure	403	{object}	apperror.Error
+// @failure	404	{object}	apperror.Error
+// @failure	500	{object}	apperror.Error
+// @Router		/shops/{shopID}/wanted/{requestID}/bid [delete]
+func (h *WantedHandler) DeleteBid(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	requestID, ok := requestIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.wantedService.WithdrawBid(c.Request.Context(), requestID, shopID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, webhook entity.Webhook, userID uint) (entity.Webhook, error)
+	GetShopWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, shopID, webhookID, userID uint) error
+	GetDeliveries(
+		ctx context.Context,
+		shopID, webhookID, userID uint,
+		page, limit int,
+	) ([]entity.WebhookDelivery, int, error)
+	Redeliver(ctx context.Context, shopID, webhookID, deliveryID, userID uint) (entity.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	webhookService WebhookService
+}
+
+func NewWebhookHandler(webhookService WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// @summary	Get shop webhooks
+// @tags		webhook
+// @produce	json
+// @param		shopID	path		int	true	"Shop ID"
+// @success	200		{object}	dto.GetWebhooksResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.GetShopWebhooks(c.Request.Context(), shopID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormWebhooks(webhooks))
+}
+
+// @summary	Create shop webhook
+// @description	Subscribes the URL to offer.created, offer.status_changed and guest_offer.received events.
+// @description	Each delivery is a POST signed with HMAC-SHA256 of "<X-Stawberry-Timestamp>.<body>"
+// @description	in the X-Stawberry-Signature header. The secret is returned only in this response.
+// @description	The body "id" identifies the event: a redelivered event keeps its id.
+// @tags		webhook
+// @accept		json
+// @produce	json
+// @param		shopID	path		int					true	"Shop ID"
+// @param		body	body		dto.PostWebhookReq	true	"Webhook"
+// @success	201		{object}	dto.CreatedWebhookResp
+// @failure	400		{object}	apperror.Error
+// @failure	403		{object}	apperror.Error
+// @failure	404		{object}	apperror.Error
+// @failure	500		{object}	apperror.Error
+// @Router		/shops/{shopID}/webhooks [post]
+func (h *WebhookHandler) PostWebhook(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid webhook data", err))
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), req.ConvertToEntity(shopID), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ConvertToCreatedWebhookResp(webhook))
+}
+
+// @summary	Delete shop webhook
+// @tags		webhook
+// @param		shopID		path	int	true	"Shop ID"
+// @param		webhookID	path	int	true	"Webhook ID"
+// @success	204
+// @failure	400	{object}	apperror.Error
+// @failure	403	{object}	apperror.Error
+// @failure	404	{object}	apperror.Error
+// @failure	500	{object}	apperror.Error
+// @Router		/shops/{shopID}/webhooks/{webhookID} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	webhookID, ok := positiveParam(c, "webhookID")
+	if !ok {
+		return
+	}
+
+	err := h.webhookService.DeleteWebhook(c.Request.Context(), shopID, webhookID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @summary	Get webhook delivery log
+// @description	Lists deliveries of the webhook, newest first.
+// @tags		webhook
+// @produce	json
+// @param		shopID		path		int	true	"Shop ID"
+// @param		webhookID	path		int	true	"Webhook ID"
+// @param		page		query		int	false	"Page number for pagination"	default(1)
+// @param		limit		query		int	false	"Number of items per page (5-100)"	default(10)
+// @success	200			{object}	dto.GetWebhookDeliveriesResp
+// @failure	400			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/shops/{shopID}/webhooks/{webhookID}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	webhookID, ok := positiveParam(c, "webhookID")
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 5 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 5-100)", err))
+		return
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(
+		c.Request.Context(), shopID, webhookID, userID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, dto.FormWebhookDeliveries(deliveries, page, limit, total, totalPages))
+}
+
+// @summary	Redeliver webhook delivery
+// @description	Queues the delivery again with a fresh attempt counter, regardless of its status.
+// @tags		webhook
+// @produce	json
+// @param		shopID		path		int	true	"Shop ID"
+// @param		webhookID	path		int	true	"Webhook ID"
+// @param		deliveryID	path		int	true	"Delivery ID"
+// @success	202			{object}	dto.WebhookDeliveryResp
+// @failure	400			{object}	apperror.Error
+// @failure	403			{object}	apperror.Error
+// @failure	404			{object}	apperror.Error
+// @failure	500			{object}	apperror.Error
+// @Router		/shops/{shopID}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	shopID, userID, ok := shopOwnerParams(c)
+	if !ok {
+		return
+	}
+
+	webhookID, ok := positiveParam(c, "webhookID")
+	if !ok {
+		return
+	}
+
+	deliveryID, ok := positiveParam(c, "deliveryID")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), shopID, webhookID, deliveryID, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.ConvertToWebhookDeliveryResp(delivery))
+}
+
+// positiveParam достаёт из пути положительный ID
+func positiveParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, name+" must be a positive number", err))
+		return 0, false
+	}
+
+	return uint(id), true
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var autoResponseRuleColumns = []string{"id", "shop_id", "product_id", "accept_from", "decline_below",
+	"counter_price", "updated_at"}
+
+type AutoResponseRepository struct {
+	db *sqlx.DB
+}
+
+func NewAutoResponseRepository(db *sqlx.DB) *AutoResponseRepository {
+	return &AutoResponseRepository{db: db}
+}
+
+// isShopOwner проверяет, что магазин принадлежит пользователю. Чужой магазин "не существует".
+func isShopOwner(ctx context.Context, shopID, userID uint, q sqlx.QueryerContext) error {
+	checkShopOwnerQuery, args := squirrel.Select("count(*)").
+		From("shops").
+		Where(squirrel.Eq{"id": shopID, "user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err := sqlx.GetContext(ctx, q, &count, checkShopOwnerQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error checking shop owner", err)
+	}
+
+	if count == 0 {
+		return apperror.ErrStoreNotFound
+	}
+
+	return nil
+}
+
+// SelectShopRules возвращает правила автоответа магазина: сначала общее, затем по товарам
+func (r *AutoResponseRepository) SelectShopRules(
+	ctx context.Context,
+	shopID, userID uint,
+) ([]entity.AutoResponseRule, error) {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	selectRulesQuery, args := squirrel.Select(autoResponseRuleColumns...).
+		From("shop_inventory_rules").
+		Where(squirrel.Eq{"shop_id": shopID}).
+		OrderBy("product_id nulls first").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var ruleModels []model.AutoResponseRule
+	err = r.db.SelectContext(ctx, &ruleModels, selectRulesQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting auto-response rules", err)
+	}
+
+	rules := make([]entity.AutoResponseRule, len(ruleModels))
+	for i, ruleModel := range ruleModels {
+		rules[i] = ruleModel.ConvertToEntity()
+	}
+
+	return rules, nil
+}
+
+// UpsertRule создаёт или заменяет правило магазина (или позиции инвентаря, если задан ProductID)
+func (r *AutoResponseRepository) UpsertRule(
+	ctx context.Context,
+	rule entity.AutoResponseRule,
+	userID uint,
+) (entity.AutoResponseRule, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.AutoResponseRule{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isShopOwner(ctx, rule.ShopID, userID, tx)
+	if err != nil {
+		return entity.AutoResponseRule{}, err
+	}
+
+	if rule.ProductID != nil {
+		checkInventoryQuery, args := squirrel.Select("count(*)").
+			From("shop_inventory").
+			Where(squirrel.Eq{"shop_id": rule.ShopID, "product_id": *rule.ProductID}).
+			PlaceholderFormat(squirrel.Dollar).
+			MustSql()
+
+		var count int
+		err = tx.GetContext(ctx, &count, checkInventoryQuery, args...)
+		if err != nil {
+			return entity.AutoResponseRule{}, apperror.New(apperror.DatabaseError,
+				"error checking shop inventory", err)
+		}
+		if count == 0 {
+			return entity.AutoResponseRule{}, apperror.New(apperror.NotFound,
+				"product is not in the shop inventory", nil)
+		}
+	}
+
+	upsertRuleQuery, args := squirrel.Insert("shop_inventory_rules").
+		Columns("shop_id", "product_id", "accept_from", "decline_below", "counter_price", "updated_at").
+		Values(rule.ShopID, rule.ProductID, rule.AcceptFrom, rule.DeclineBelow, rule.CounterPrice, time.Now()).
+		Suffix("on conflict (shop_id, COALESCE(product_id, 0)) do update set " +
+			"accept_from = excluded.accept_from, decline_below = excluded.decline_below, " +
+			"counter_price = excluded.counter_price, updated_at = excluded.updated_at " +
+			"returning id, shop_id, product_id, accept_from, decline_below, counter_price, updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var ruleModel model.AutoResponseRule
+	err = tx.QueryRowxContext(ctx, upsertRuleQuery, args...).StructScan(&ruleModel)
+	if err != nil {
+		return entity.AutoResponseRule{}, apperror.New(apperror.DatabaseError,
+			"error saving auto-response rule", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.AutoResponseRule{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return ruleModel.ConvertToEntity(), nil
+}
+
+// DeleteRule удаляет правило магазина, принадлежащего пользователю
+func (r *AutoResponseRepository) DeleteRule(ctx context.Context, shopID, ruleID, userID uint) error {
+	deleteRuleQuery, args := squirrel.Delete("shop_inventory_rules using shops").
+		Where("shops.id = shop_inventory_rules.shop_id").
+		Where(squirrel.Eq{
+			"shop_inventory_rules.id":      ruleID,
+			"shop_inventory_rules.shop_id": shopID,
+			"shops.user_id":                userID,
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, deleteRuleQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error deleting auto-response rule", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error deleting auto-response rule", err)
+	}
+	if affected == 0 {
+		return apperror.New(apperror.NotFound, "auto-response rule not found", nil)
+	}
+
+	return nil
+}
+
+// GetAutoResponseRule возвращает правило, действующее для позиции инвентаря:
+// правило позиции, а если его нет - общее правило магазина. false, если правил нет.
+func (r *OfferRepository) GetAutoResponseRule(
+	ctx context.Context,
+	shopID, productID uint,
+) (entity.AutoResponseRule, bool, error) {
+	selectRuleQuery, args := squirrel.Select(autoResponseRuleColumns...).
+		From("shop_inventory_rules").
+		Where(squirrel.Eq{"shop_id": shopID}).
+		Where(squirrel.Or{squirrel.Eq{"product_id": productID}, squirrel.Eq{"product_id": nil}}).
+		OrderBy("product_id nulls last").
+		Limit(1).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var ruleModel model.AutoResponseRule
+	err := r.db.GetContext(ctx, &ruleModel, selectRuleQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.AutoResponseRule{}, false, nil
+		}
+		return entity.AutoResponseRule{}, false, apperror.New(apperror.DatabaseError,
+			"error selecting auto-response rule", err)
+	}
+
+	return ruleModel.ConvertToEntity(), true, nil
+}
@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+var categoryColumns = []string{"categories.id", "categories.name", "categories.parent_id",
+	"categories.lft", "categories.rgt"}
+
+// CategoryRepository хранит дерево категорий вложенными множествами. Изменения дерева
+// сдвигают lft и rgt у многих строк, поэтому выполняются под блокировкой таблицы по одному.
+type CategoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// SelectTree возвращает все категории в порядке обхода дерева (по lft)
+func (r *CategoryRepository) SelectTree(ctx context.Context) ([]entity.Category, error) {
+	selectTreeQuery, args := squirrel.Select(categoryColumns...).
+		From("categories").
+		OrderBy("categories.lft").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	return r.selectCategories(ctx, selectTreeQuery, args...)
+}
+
+// SelectSubtree возвращает категорию и всех её потомков в порядке обхода дерева
+func (r *CategoryRepository) SelectSubtree(ctx context.Context, categoryID uint) ([]entity.Category, error) {
+	selectSubtreeQuery, args := squirrel.Select(categoryColumns...).
+		From("categories").
+		Join("categories root on categories.lft between root.lft and root.rgt").
+		Where(squirrel.Eq{"root.id": categoryID}).
+		OrderBy("categories.lft").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	subtree, err := r.selectCategories(ctx, selectSubtreeQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(subtree) == 0 {
+		return nil, apperror.ErrCategoryNotFound
+	}
+
+	return subtree, nil
+}
+
+// SelectPath возвращает цепочку категорий от корня дерева до категории включительно
+func (r *CategoryRepository) SelectPath(ctx context.Context, categoryID uint) ([]entity.Category, error) {
+	selectPathQuery, args := squirrel.Select(categoryColumns...).
+		From("categories").
+		Join("categories node on node.lft between categories.lft and categories.rgt").
+		Where(squirrel.Eq{"node.id": categoryID}).
+		OrderBy("categories.lft").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	path, err := r.selectCategories(ctx, selectPathQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, apperror.ErrCategoryNotFound
+	}
+
+	return path, nil
+}
+
+func (r *CategoryRepository) selectCategories(
+	ctx context.Context,
+	query string,
+	args ...interface{},
+) ([]entity.Category, error) {
+	var categoryModels []model.Category
+	err := r.db.SelectContext(ctx, &categoryModels, query, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting categories", err)
+	}
+
+	categories := make([]entity.Category, len(categoryModels))
+	for i, categoryModel := range categoryModels {
+		categories[i] = categoryModel.ConvertToEntity()
+	}
+
+	return categories, nil
+}
+
+// InsertCategory добавляет категорию последним потомком parentID или, если он nil, последним корнем
+func (r *CategoryRepository) InsertCategory(
+	ctx context.Context,
+	name string,
+	parentID *uint,
+) (entity.Category, error) {
+	tx, err := r.beginTreeChange(ctx)
+	if err != nil {
+		return entity.Category{}, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	position, err := childPosition(ctx, tx, parentID)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	err = openGap(ctx, tx, position, 2)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	insertCategoryQuery, args := squirrel.Insert("categories").
+		Columns("name", "lft", "rgt", "parent_id").
+		Values(name, position, position+1, parentID).
+		Suffix("returning id, name, parent_id, lft, rgt").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var categoryModel model.Category
+	err = tx.GetContext(ctx, &categoryModel, insertCategoryQuery, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return entity.Category{}, apperror.New(apperror.Conflict, "category name is already taken", nil)
+		}
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "error inserting category", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return categoryModel.ConvertToEntity(), nil
+}
+
+// MoveCategory переносит категорию вместе с потомками последним потомком parentID
+// или, если он nil, последним корнем. Категорию нельзя перенести внутрь неё самой.
+func (r *CategoryRepository) MoveCategory(
+	ctx context.Context,
+	categoryID uint,
+	parentID *uint,
+) (entity.Category, error) {
+	tx, err := r.beginTreeChange(ctx)
+	if err != nil {
+		return entity.Category{}, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	moved, err := selectCategory(ctx, tx, categoryID)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	if parentID != nil {
+		parent, err := selectCategory(ctx, tx, *parentID)
+		if err != nil {
+			return entity.Category{}, err
+		}
+		if parent.Lft >= moved.Lft && parent.Lft <= moved.Rgt {
+			return entity.Category{}, apperror.New(apperror.Conflict,
+				"category cannot be moved into its own subtree", nil)
+		}
+	}
+
+	width := moved.Rgt - moved.Lft + 1
+
+	// поддерево временно уходит в отрицательные lft и rgt, чтобы сдвиги остального дерева его не задели
+	hideSubtreeQuery, args := squirrel.Update("categories").
+		Set("lft", squirrel.Expr("-lft")).
+		Set("rgt", squirrel.Expr("-rgt")).
+		Where(squirrel.GtOrEq{"lft": moved.Lft}).
+		Where(squirrel.LtOrEq{"rgt": moved.Rgt}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, hideSubtreeQuery, args...)
+	if err != nil {
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "error moving category", err)
+	}
+
+	err = closeGap(ctx, tx, moved.Rgt, width)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	position, err := childPosition(ctx, tx, parentID)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	err = openGap(ctx, tx, position, width)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	placeSubtreeQuery, args := squirrel.Update("categories").
+		Set("lft", squirrel.Expr("-lft + ?", position-moved.Lft)).
+		Set("rgt", squirrel.Expr("-rgt + ?", position-moved.Lft)).
+		Where(squirrel.Lt{"lft": 0}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, placeSubtreeQuery, args...)
+	if err != nil {
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "error moving category", err)
+	}
+
+	setParentQuery, args := squirrel.Update("categories").
+		Set("parent_id", parentID).
+		Where(squirrel.Eq{"id": categoryID}).
+		Suffix("returning id, name, parent_id, lft, rgt").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var categoryModel model.Category
+	err = tx.GetContext(ctx, &categoryModel, setParentQuery, args...)
+	if err != nil {
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "error moving category", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return categoryModel.ConvertToEntity(), nil
+}
+
+// DeleteCategory удаляет категорию без подкатегорий и товаров. Запросы на покупку
+// по этой категории удаляются вместе с ней.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, categoryID uint) error {
+	tx, err := r.beginTreeChange(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	category, err := selectCategory(ctx, tx, categoryID)
+	if err != nil {
+		return err
+	}
+	if category.Rgt-category.Lft > 1 {
+		return apperror.New(apperror.Conflict, "category has subcategories", nil)
+	}
+
+	deleteCategoryQuery, args := squirrel.Delete("categories").
+		Where(squirrel.Eq{"id": categoryID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, deleteCategoryQuery, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			if pgErr.TableName == "wanted_requests" {
+				return apperror.New(apperror.Conflict, "category is used by wanted requests", nil)
+			}
+			return apperror.New(apperror.Conflict, "category has products", nil)
+		}
+		return apperror.New(apperror.DatabaseError, "error deleting category", err)
+	}
+
+	err = closeGap(ctx, tx, category.Rgt, 2)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// beginTreeChange начинает транзакцию изменения дерева. Блокировка не мешает чтению,
+// но не даёт двум изменениям дерева сдвигать lft и rgt одновременно.
+func (r *CategoryRepository) beginTreeChange(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "lock table categories in share row exclusive mode")
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, apperror.New(apperror.DatabaseError, "error locking categories", err)
+	}
+
+	return tx, nil
+}
+
+func selectCategory(ctx context.Context, tx *sqlx.Tx, categoryID uint) (entity.Category, error) {
+	selectCategoryQuery, args := squirrel.Select(categoryColumns...).
+		From("categories").
+		Where(squirrel.Eq{"id": categoryID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var categoryModel model.Category
+	err := tx.GetContext(ctx, &categoryModel, selectCategoryQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Category{}, apperror.ErrCategoryNotFound
+		}
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "error selecting category", err)
+	}
+
+	return categoryModel.ConvertToEntity(), nil
+}
+
+// childPosition возвращает lft для нового последнего потомка parentID: rgt родителя,
+// а для корня - место после всего дерева
+func childPosition(ctx context.Context, tx *sqlx.Tx, parentID *uint) (int, error) {
+	if parentID != nil {
+		parent, err := selectCategory(ctx, tx, *parentID)
+		if err != nil {
+			return 0, err
+		}
+		return parent.Rgt, nil
+	}
+
+	lastRgtQuery, args := squirrel.Select("coalesce(max(rgt), 0)").
+		From("categories").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var lastRgt int
+	err := tx.GetContext(ctx, &lastRgt, lastRgtQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error selecting categories", err)
+	}
+
+	return lastRgt + 1, nil
+}
+
+// openGap освобождает в дереве width номеров начиная с position
+func openGap(ctx context.Context, tx *sqlx.Tx, position, width int) error {
+	return shiftTree(ctx, tx, position, width)
+}
+
+// closeGap убирает из дерева width номеров, освободившихся слева от after включительно
+func closeGap(ctx context.Context, tx *sqlx.Tx, after, width int) error {
+	return shiftTree(ctx, tx, after+1, -width)
+}
+
+// shiftTree сдвигает на delta все lft и rgt, не меньшие from
+func shiftTree(ctx context.Context, tx *sqlx.Tx, from, delta int) error {
+	for _, column := range []string{"lft", "rgt"} {
+		shiftQuery, args := squirrel.Update("categories").
+			Set(column, squirrel.Expr(column+" + ?", delta)).
+			Where(squirrel.GtOrEq{column: from}).
+			PlaceholderFormat(squirrel.Dollar).
+			MustSql()
+
+		_, err := tx.ExecContext(ctx, shiftQuery, args...)
+		if err != nil {
+			return apperror.New(apperror.DatabaseError, "error updating category tree", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type CurrencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewCurrencyRepository(db *sqlx.DB) *CurrencyRepository {
+	return &CurrencyRepository{db: db}
+}
+
+// SelectRates возвращает все известные курсы валют к EUR
+func (r *CurrencyRepository) SelectRates(ctx context.Context) ([]entity.CurrencyRate, error) {
+	selectRatesQuery, args := squirrel.Select("currency", "rate", "updated_at").
+		From("currency_rates").
+		OrderBy("currency").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rateModels []model.CurrencyRate
+	err := r.db.SelectContext(ctx, &rateModels, selectRatesQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting currency rates", err)
+	}
+
+	rates := make([]entity.CurrencyRate, len(rateModels))
+	for i, rateModel := range rateModels {
+		rates[i] = rateModel.ConvertToEntity()
+	}
+
+	return rates, nil
+}
+
+// UpsertRates добавляет курсы одним запросом, заменяя уже известные.
+// Валюты, которых нет в загрузке, сохраняют прежний курс.
+func (r *CurrencyRepository) UpsertRates(ctx context.Context, rates []entity.CurrencyRate) error {
+	now := time.Now()
+	upsertRatesQuery := squirrel.Insert("currency_rates").
+		Columns("currency", "rate", "updated_at")
+	for _, rate := range rates {
+		upsertRatesQuery = upsertRatesQuery.Values(rate.Currency, rate.Rate, now)
+	}
+
+	query, args := upsertRatesQuery.
+		Suffix("on conflict (currency) do update set rate = excluded.rate, updated_at = excluded.updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error saving currency rates", err)
+	}
+
+	return nil
+}
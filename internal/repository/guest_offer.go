@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertGuestOfferEvents пишет в журнал созданного оффера гостя событие created от имени
+// покупателя, а в outbox - оффер с контактами гостя для вебхуков магазина и сообщения outbox
+// с AggregateID оффера. Вызывается в транзакции репозитория guestoffer: журнал у офферов
+// гостей общий с остальными.
+func InsertGuestOfferEvents(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	offerID uint,
+	offer entity.Offer,
+	guest entity.GuestOfferData,
+	outbox ...entity.OutboxMessage,
+) error {
+	err := insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:   offerID,
+		EventType: offerEventCreated,
+		ToStatus:  offer.Status,
+		Price:     offer.Price,
+		ActorRole: actorRoleBuyer,
+	})
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(entity.GuestOfferPayload{
+		ProductID:  offer.ProductID,
+		ShopID:     offer.ShopID,
+		Price:      offer.Price,
+		Currency:   offer.Currency,
+		GuestName:  guest.GuestName,
+		GuestEmail: guest.GuestEmail,
+		GuestPhone: guest.GuestPhone,
+	})
+	if err != nil {
+		return apperror.New(apperror.InternalError, "error encoding guest offer", err)
+	}
+
+	for i := range outbox {
+		outbox[i].AggregateID = offerID
+	}
+
+	return insertOutboxMessages(ctx, tx, append(outbox, entity.OutboxMessage{
+		Topic:       entity.OutboxTopicGuestOffer,
+		AggregateID: offerID,
+		Payload:     payload,
+	})...)
+}
+
+// CancelGuestOffer отменяет оффер гостя в транзакции репозитория guestoffer, пока по нему
+// идёт торг, и пишет событие в журнал от имени покупателя
+func CancelGuestOffer(ctx context.Context, tx *sqlx.Tx, offerID uint) (entity.Offer, error) {
+	err := isOpenOffer(ctx, offerID, tx)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	cancelQuery, args := squirrel.Update("offers").
+		Set("status", statusCancelled).
+		Set("updated_at", time.Now()).
+		From("offers prev").
+		Where("prev.id = offers.id").
+		Where(squirrel.Eq{"offers.id": offerID}).
+		Suffix("returning offers.id, offers.offer_price, offers.currency, offers.status, " +
+			"offers.created_at, offers.updated_at, offers.expires_at, " +
+			"offers.shop_id, offers.user_id, offers.product_id, " +
+			"prev.status as previous_status, prev.offer_price as previous_price").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerResp model.OfferStatusChange
+	err = tx.QueryRowxContext(ctx, cancelQuery, args...).StructScan(&offerResp)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error cancelling guest offer", err)
+	}
+
+	err = insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:    offerResp.ID,
+		EventType:  offerEventStatusChanged,
+		FromStatus: &offerResp.PreviousStatus,
+		ToStatus:   offerResp.Status,
+		Price:      offerResp.Price,
+		ActorRole:  actorRoleBuyer,
+	})
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	return offerResp.ConvertToEntity(), nil
+}
+
+// claimGuestOffers передаёт пользователю офферы гостя, предъявившего токен из письма:
+// токен подтверждает доступ к почте, поэтому переходят все офферы с неё (без учёта регистра).
+// Офферы становятся обычными: контакты гостя и токены удаляются, ссылки из писем перестают
+// работать. По уже принятым офферам создаются заказы. Неизвестный токен ничего не передаёт.
+func claimGuestOffers(ctx context.Context, tx *sqlx.Tx, userID uint, tokenHash string) (int, error) {
+	claimQuery, args := squirrel.Update("offers").
+		Prefix("with claimed as (delete from guest_offers where lower(guest_email) = "+
+			"(select lower(guest_email) from guest_offers where token_hash = ?) returning offer_id)", tokenHash).
+		Set("user_id", userID).
+		Set("updated_at", time.Now()).
+		From("claimed").
+		Where("offers.id = claimed.offer_id").
+		Suffix("returning offers.id, offers.status").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var claimed []model.Offer
+	err := sqlx.SelectContext(ctx, tx, &claimed, claimQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error claiming guest offers", err)
+	}
+
+	for _, offer := range claimed {
+		if offer.Status == statusAccepted {
+			err = insertOrderFromOffer(ctx, tx, offer.ID)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return len(claimed), nil
+}
+
+// insertGuestStatusEmail ставит в outbox письмо гостю о новом статусе его оффера.
+// Для оффера зарегистрированного покупателя ничего не записывается.
+func insertGuestStatusEmail(ctx context.Context, tx *sqlx.Tx, offerID uint, status string) error {
+	insertEmailQuery, args := squirrel.Insert("outbox").
+		Columns("topic", "aggregate_id", "payload").
+		Select(squirrel.Select().
+			Column(squirrel.Expr("?::text", entity.OutboxTopicEmail)).
+			Column("offer_id").
+			Column(squirrel.Expr("json_build_object('kind', ?::text, 'to', guest_email, 'status', ?::text)::jsonb",
+				entity.EmailStatusUpdate, status)).
+			From("guest_offers").
+			Where(squirrel.Eq{"offer_id": offerID})).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, insertEmailQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error writing guest status email", err)
+	}
+
+	return nil
+}
+
+// isNotGuestOffer проверяет, что оффер сделан зарегистрированным покупателем:
+// гость отвечать на встречные предложения не может, он может только отменить оффер.
+func isNotGuestOffer(ctx context.Context, offerID uint, tx *sqlx.Tx) error {
+	countGuestQuery, args := squirrel.Select("count(*)").
+		From("guest_offers").
+		Where(squirrel.Eq{"offer_id": offerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err := tx.QueryRowxContext(ctx, countGuestQuery, args...).Scan(&count)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error checking guest offer", err)
+	}
+
+	if count > 0 {
+		return apperror.New(apperror.Conflict, "guest offers can only be accepted or declined", nil)
+	}
+
+	return nil
+}
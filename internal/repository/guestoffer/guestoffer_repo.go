@@ -4,9 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -15,12 +21,21 @@ type StoreInfoGetter interface {
 	GetStoreOwnerEmailByStoreID(ctx context.Context, storeID uint) (string, error)
 }
 
+// classes of the two-key advisory locks on a guest's email and IP, the key space is separate
+// from the one-key locks on registered buyers
+const (
+	guestEmailLockClass = 1
+	guestIPLockClass    = 2
+)
+
+// Repository stores guest offers. A guest offer is a regular offers row without a buyer,
+// so the shop answers it through the common offer flow.
 type Repository struct {
 	db *sqlx.DB
 }
 
-// NewRepository creates a new instance of Repository and returns the StoreInfoGetter interface
-func NewRepository(db *sqlx.DB) StoreInfoGetter {
+// NewRepository creates a new instance of Repository
+func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{db: db}
 }
 
@@ -51,3 +66,209 @@ func (r *Repository) GetStoreOwnerEmailByStoreID(ctx context.Context, storeID ui
 
 	return email, nil
 }
+
+// InsertGuestOffer stores a pending guest offer together with the guest's contacts and
+// the hash of the magic link token. The created event is logged on behalf of the buyer,
+// the outbox messages are written in the same transaction with the offer ID as AggregateID.
+// Offers from one email or one IP are inserted one at a time: checkLimits, if set, gets
+// their counts since limitsSince in the same transaction and may reject the offer.
+func (r *Repository) InsertGuestOffer(
+	ctx context.Context,
+	offer entity.Offer,
+	guest entity.GuestOfferData,
+	tokenHash string,
+	limitsSince time.Time,
+	checkLimits func(byEmail, byIP int) error,
+	outbox []entity.OutboxMessage,
+) (uint, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if checkLimits != nil {
+		// the locks are held until the end of the transaction, so a concurrent request
+		// from the same guest counts this offer too. Email is always locked first.
+		lockGuestQuery, args := squirrel.Select().
+			Column(squirrel.Expr("pg_advisory_xact_lock(?, hashtext(lower(?)))", guestEmailLockClass, guest.GuestEmail)).
+			Column(squirrel.Expr("pg_advisory_xact_lock(?, hashtext(?))", guestIPLockClass, guest.GuestIP)).
+			PlaceholderFormat(squirrel.Dollar).
+			MustSql()
+
+		_, err = tx.ExecContext(ctx, lockGuestQuery, args...)
+		if err != nil {
+			return 0, apperror.New(apperror.DatabaseError, "error locking guest offers", err)
+		}
+
+		byEmail, byIP, err := countRecentGuestOffers(ctx, tx, guest.GuestEmail, guest.GuestIP, limitsSince)
+		if err != nil {
+			return 0, err
+		}
+
+		err = checkLimits(byEmail, byIP)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	insertOfferQuery, args := squirrel.Insert("offers").
+		Columns("offer_price", "currency", "status", "created_at", "updated_at", "expires_at",
+			"shop_id", "product_id").
+		Values(offer.Price, offer.Currency, offer.Status, offer.CreatedAt, offer.UpdatedAt, offer.ExpiresAt,
+			offer.ShopID, offer.ProductID).
+		Suffix("returning id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerID uint
+	err = tx.QueryRowxContext(ctx, insertOfferQuery, args...).Scan(&offerID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return 0, apperror.ErrProductNotFound
+		}
+		return 0, apperror.New(apperror.DatabaseError, "error inserting guest offer into database", err)
+	}
+
+	insertGuestQuery, args := squirrel.Insert("guest_offers").
+		Columns("offer_id", "guest_name", "guest_email", "guest_phone", "guest_ip", "token_hash").
+		Values(offerID, guest.GuestName, guest.GuestEmail, guest.GuestPhone, guest.GuestIP, tokenHash).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, insertGuestQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error inserting guest contacts", err)
+	}
+
+	err = repository.InsertGuestOfferEvents(ctx, tx, offerID, offer, guest, outbox...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return offerID, nil
+}
+
+// IsProductAvailable checks that the shop sells the product and it can be ordered.
+// ErrProductNotFound is returned when the shop does not sell the product.
+func (r *Repository) IsProductAvailable(ctx context.Context, shopID, productID uint) (bool, error) {
+	selectAvailabilityQuery, args := squirrel.Select("is_available").
+		From("shop_inventory").
+		Where(squirrel.Eq{"shop_id": shopID, "product_id": productID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var available bool
+	err := r.db.GetContext(ctx, &available, selectAvailabilityQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, apperror.ErrProductNotFound
+		}
+		return false, apperror.New(apperror.DatabaseError, "error checking product availability", err)
+	}
+
+	return available, nil
+}
+
+// countRecentGuestOffers counts guest offers sent after since from the same email
+// (case-insensitive) and from the same address
+func countRecentGuestOffers(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	email, ip string,
+	since time.Time,
+) (int, int, error) {
+	countQuery, args := squirrel.Select().
+		Column(squirrel.Expr("count(*) filter (where lower(guest_email) = lower(?))", email)).
+		Column(squirrel.Expr("count(*) filter (where guest_ip = ?)", ip)).
+		From("guest_offers").
+		Where(squirrel.Gt{"created_at": since}).
+		Where(squirrel.Or{
+			squirrel.Expr("lower(guest_email) = lower(?)", email),
+			squirrel.Eq{"guest_ip": ip},
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var byEmail, byIP int
+	err := tx.QueryRowxContext(ctx, countQuery, args...).Scan(&byEmail, &byIP)
+	if err != nil {
+		return 0, 0, apperror.New(apperror.DatabaseError, "error counting recent guest offers", err)
+	}
+
+	return byEmail, byIP, nil
+}
+
+// GetGuestOfferByToken returns the guest offer by the hash of the magic link token
+func (r *Repository) GetGuestOfferByToken(ctx context.Context, tokenHash string) (entity.GuestOffer, error) {
+	selectOfferQuery, args := squirrel.Select("offers.id", "offers.offer_price",
+		"offers.currency", "offers.status", "offers.created_at", "offers.updated_at", "offers.expires_at",
+		"offers.shop_id", "offers.product_id", "offers.user_id",
+		"products.name as product_name", "shops.name as shop_name",
+		"guest_offers.guest_name", "guest_offers.guest_email", "guest_offers.guest_phone").
+		From("offers").
+		InnerJoin("shops on shops.id = offers.shop_id").
+		InnerJoin("guest_offers on guest_offers.offer_id = offers.id").
+		InnerJoin("products on products.id = offers.product_id").
+		Where(squirrel.Eq{"guest_offers.token_hash": tokenHash}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerModel model.GuestOffer
+	err := r.db.GetContext(ctx, &offerModel, selectOfferQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.GuestOffer{}, apperror.ErrOfferNotFound
+		}
+		return entity.GuestOffer{}, apperror.New(apperror.DatabaseError, "error selecting guest offer", err)
+	}
+
+	return offerModel.ConvertToEntity(), nil
+}
+
+// CancelGuestOffer cancels the guest offer found by the hash of the magic link token
+// while it is still being negotiated
+func (r *Repository) CancelGuestOffer(ctx context.Context, tokenHash string) (entity.Offer, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	selectOfferIDQuery, args := squirrel.Select("offer_id").
+		From("guest_offers").
+		Where(squirrel.Eq{"token_hash": tokenHash}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerID uint
+	err = tx.QueryRowxContext(ctx, selectOfferIDQuery, args...).Scan(&offerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Offer{}, apperror.ErrOfferNotFound
+		}
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error selecting guest offer", err)
+	}
+
+	offer, err := repository.CancelGuestOffer(ctx, tx, offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return offer, nil
+}
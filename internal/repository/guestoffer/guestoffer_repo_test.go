@@ -17,7 +17,7 @@ var _ = Describe("GuestOfferRepository", func() {
 	var (
 		db      *sql.DB
 		mock    go_sqlmock.Sqlmock
-		repo    guestofferrepo.StoreInfoGetter
+		repo    *guestofferrepo.Repository
 		ctx     context.Context
 		storeID uint
 	)
@@ -87,4 +87,40 @@ var _ = Describe("GuestOfferRepository", func() {
 			})
 		})
 	})
+
+	Describe("IsProductAvailable", func() {
+		It("should return the availability of the product in the shop", func() {
+			mock.ExpectQuery("SELECT is_available FROM shop_inventory WHERE product_id = \\$1 AND shop_id = \\$2").
+				WithArgs(7, storeID).
+				WillReturnRows(go_sqlmock.NewRows([]string{"is_available"}).AddRow(false))
+
+			available, err := repo.IsProductAvailable(ctx, storeID, 7)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(available).To(BeFalse())
+		})
+
+		It("should return ErrProductNotFound when the shop does not sell the product", func() {
+			mock.ExpectQuery("SELECT is_available FROM shop_inventory").
+				WillReturnError(sql.ErrNoRows)
+
+			_, err := repo.IsProductAvailable(ctx, storeID, 7)
+
+			Expect(err).To(MatchError(apperror.ErrProductNotFound))
+		})
+	})
+
+	Describe("CancelGuestOffer", func() {
+		It("should return ErrOfferNotFound for an unknown token", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT offer_id FROM guest_offers WHERE token_hash = \\$1").
+				WithArgs("unknown").
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectRollback()
+
+			_, err := repo.CancelGuestOffer(ctx, "unknown")
+
+			Expect(err).To(MatchError(apperror.ErrOfferNotFound))
+		})
+	})
 })
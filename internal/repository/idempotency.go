@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Claim занимает ключ под новый запрос до record.ExpiresAt. Истёкший ключ (в том числе брошенный
+// упавшим запросом) занимается заново. Если ключ занят и не истёк, возвращается сохранённая запись и false.
+func (r *IdempotencyRepository) Claim(
+	ctx context.Context,
+	record entity.IdempotencyRecord,
+) (entity.IdempotencyRecord, bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.IdempotencyRecord{}, false, apperror.New(apperror.DatabaseError,
+			"failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	claimQuery, args := squirrel.Insert("idempotency_keys").
+		Columns("scope", "key", "request_hash", "lease_token", "expires_at").
+		Values(record.Scope, record.Key, record.RequestHash, record.LeaseToken, record.ExpiresAt).
+		Suffix("on conflict (scope, key) do update set request_hash = excluded.request_hash, " +
+			"lease_token = excluded.lease_token, status_code = null, response_body = null, " +
+			"created_at = now(), expires_at = excluded.expires_at " +
+			"where idempotency_keys.expires_at < now() returning scope").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var scope string
+	err = tx.GetContext(ctx, &scope, claimQuery, args...)
+	if err == nil {
+		if err = tx.Commit(); err != nil {
+			return entity.IdempotencyRecord{}, false, apperror.New(apperror.DatabaseError,
+				"failed to commit transaction", err)
+		}
+		return record, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return entity.IdempotencyRecord{}, false, apperror.New(apperror.DatabaseError,
+			"error claiming idempotency key", err)
+	}
+
+	selectRecordQuery, args := squirrel.Select("scope", "key", "request_hash", "status_code",
+		"response_body", "expires_at").
+		From("idempotency_keys").
+		Where(squirrel.Eq{"scope": record.Scope, "key": record.Key}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var existing model.IdempotencyRecord
+	err = tx.GetContext(ctx, &existing, selectRecordQuery, args...)
+	if err != nil {
+		return entity.IdempotencyRecord{}, false, apperror.New(apperror.DatabaseError,
+			"error selecting idempotency key", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.IdempotencyRecord{}, false, apperror.New(apperror.DatabaseError,
+			"failed to commit transaction", err)
+	}
+
+	return existing.ConvertToEntity(), false, nil
+}
+
+// Complete сохраняет ответ на запрос, занявший ключ с арендой lease, и продлевает хранение ключа до expiresAt
+func (r *IdempotencyRepository) Complete(
+	ctx context.Context,
+	scope, key, lease string,
+	statusCode int,
+	body []byte,
+	expiresAt time.Time,
+) error {
+	completeQuery, args := squirrel.Update("idempotency_keys").
+		Set("status_code", statusCode).
+		Set("response_body", body).
+		Set("expires_at", expiresAt).
+		Where(squirrel.Eq{"scope": scope, "key": key, "lease_token": lease, "status_code": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := r.db.ExecContext(ctx, completeQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error saving idempotent response", err)
+	}
+
+	return nil
+}
+
+// Release освобождает ключ, если запрос с арендой lease завершился без сохранённого ответа.
+// Ключ, который после истечения аренды занял другой запрос, остаётся за ним.
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key, lease string) error {
+	releaseQuery, args := squirrel.Delete("idempotency_keys").
+		Where(squirrel.Eq{"scope": scope, "key": key, "lease_token": lease, "status_code": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := r.db.ExecContext(ctx, releaseQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error releasing idempotency key", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет ключи, истёкшие к now, и возвращает их количество
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	deleteExpiredQuery, args := squirrel.Delete("idempotency_keys").
+		Where(squirrel.Lt{"expires_at": now}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, deleteExpiredQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error deleting expired idempotency keys", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error deleting expired idempotency keys", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,704 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	inventoryColumns = []string{"shop_inventory.shop_id", "shop_inventory.product_id", "products.name as product_name",
+		"shop_inventory.price", "shop_inventory.currency", "shop_inventory.is_available"}
+
+	inventoryChangeColumns = []string{"id", "shop_id", "product_id", "change", "price", "currency",
+		"is_available", "changed_by", "created_at"}
+)
+
+type InventoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryRepository(db *sqlx.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+// SelectShopInventory возвращает позиции инвентаря магазина по ID товара
+func (r *InventoryRepository) SelectShopInventory(
+	ctx context.Context,
+	shopID, userID uint,
+	limit, offset int,
+) ([]entity.InventoryItem, int, error) {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	selectItemsQuery, args := squirrel.Select(inventoryColumns...).
+		Column("COUNT (*) OVER() as total_count").
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID}).
+		OrderBy("shop_inventory.product_id").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	itemsWithCount := make([]model.InventoryItemWithCount, 0, limit)
+	err = r.db.SelectContext(ctx, &itemsWithCount, selectItemsQuery, args...)
+	if err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting shop inventory", err)
+	}
+
+	if len(itemsWithCount) == 0 {
+		return []entity.InventoryItem{}, 0, nil
+	}
+
+	items := make([]entity.InventoryItem, len(itemsWithCount))
+	for i, itemModel := range itemsWithCount {
+		items[i] = itemModel.ConvertToEntity()
+	}
+
+	return items, itemsWithCount[0].TotalCount, nil
+}
+
+// InsertItem добавляет товар в инвентарь магазина. Архивный товар добавить нельзя.
+func (r *InventoryRepository) InsertItem(
+	ctx context.Context,
+	item entity.InventoryItem,
+	userID uint,
+) (entity.InventoryItem, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.InventoryItem{}, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isShopOwner(ctx, item.ShopID, userID, tx)
+	if err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	// блокировка не даёт архивировать товар, пока он добавляется в продажу
+	selectProductQuery, args := squirrel.Select("archived_at is not null").
+		From("products").
+		Where(squirrel.Eq{"id": item.ProductID}).
+		Suffix("for share").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var archived bool
+	err = tx.GetContext(ctx, &archived, selectProductQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.InventoryItem{}, apperror.ErrProductNotFound
+		}
+		return entity.InventoryItem{}, apperror.New(apperror.DatabaseError, "error selecting product", err)
+	}
+	if archived {
+		return entity.InventoryItem{}, apperror.New(apperror.Conflict, "archived products cannot be sold", nil)
+	}
+
+	insertItemQuery, args := squirrel.Insert("shop_inventory").
+		Columns("shop_id", "product_id", "price", "currency", "is_available").
+		Values(item.ShopID, item.ProductID, item.Price, item.Currency, item.IsAvailable).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, insertItemQuery, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return entity.InventoryItem{}, apperror.New(apperror.Conflict,
+				"product is already in the shop inventory", nil)
+		}
+		return entity.InventoryItem{}, apperror.New(apperror.DatabaseError, "error inserting inventory item", err)
+	}
+
+	err = insertInventoryHistory(ctx, tx, entity.InventoryAdded, &userID, squirrel.Eq{
+		"shop_id":    item.ShopID,
+		"product_id": item.ProductID,
+	})
+	if err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	created, err := selectItem(ctx, tx, item.ShopID, item.ProductID)
+	if err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.InventoryItem{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return created, nil
+}
+
+// UpdateItem меняет цену, валюту или доступность позиции. Если позиция становится недоступной,
+// открытые офферы на неё отклоняются от имени магазина, declined - сколько их было.
+// Вернуть в продажу архивный товар нельзя.
+func (r *InventoryRepository) UpdateItem(
+	ctx context.Context,
+	shopID, productID, userID uint,
+	update entity.InventoryUpdate,
+) (item entity.InventoryItem, declined int, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.InventoryItem{}, 0, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	current, archived, err := lockItem(ctx, tx, shopID, productID, userID)
+	if err != nil {
+		return entity.InventoryItem{}, 0, err
+	}
+	if archived && update.IsAvailable != nil && *update.IsAvailable {
+		return entity.InventoryItem{}, 0, apperror.New(apperror.Conflict, "archived products cannot be sold", nil)
+	}
+
+	changes := squirrel.Eq{}
+	if update.Price != nil {
+		changes["price"] = *update.Price
+	}
+	if update.Currency != nil {
+		changes["currency"] = *update.Currency
+	}
+	if update.IsAvailable != nil {
+		changes["is_available"] = *update.IsAvailable
+	}
+
+	itemCondition := squirrel.Eq{"shop_id": shopID, "product_id": productID}
+
+	updateItemQuery, args := squirrel.Update("shop_inventory").
+		SetMap(changes).
+		Where(itemCondition).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, updateItemQuery, args...)
+	if err != nil {
+		return entity.InventoryItem{}, 0, apperror.New(apperror.DatabaseError, "error updating inventory item", err)
+	}
+
+	err = insertInventoryHistory(ctx, tx, entity.InventoryUpdated, &userID, itemCondition)
+	if err != nil {
+		return entity.InventoryItem{}, 0, err
+	}
+
+	if current.IsAvailable && update.IsAvailable != nil && !*update.IsAvailable {
+		declined, err = declineOpenOffers(ctx, tx, itemCondition, &userID, actorRoleShop)
+		if err != nil {
+			return entity.InventoryItem{}, 0, err
+		}
+
+		err = loseItemBids(ctx, tx, itemCondition)
+		if err != nil {
+			return entity.InventoryItem{}, 0, err
+		}
+	}
+
+	item, err = selectItem(ctx, tx, shopID, productID)
+	if err != nil {
+		return entity.InventoryItem{}, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.InventoryItem{}, 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return item, declined, nil
+}
+
+// DeleteItem убирает товар из инвентаря магазина. Позицию, по которой уже были офферы,
+// удалить нельзя: на неё ссылается их история, такую позицию нужно сделать недоступной.
+func (r *InventoryRepository) DeleteItem(ctx context.Context, shopID, productID, userID uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, _, err = lockItem(ctx, tx, shopID, productID, userID)
+	if err != nil {
+		return err
+	}
+
+	itemCondition := squirrel.Eq{"shop_id": shopID, "product_id": productID}
+
+	err = insertInventoryHistory(ctx, tx, entity.InventoryRemoved, &userID, itemCondition)
+	if err != nil {
+		return err
+	}
+
+	deleteItemQuery, args := squirrel.Delete("shop_inventory").
+		Where(itemCondition).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, deleteItemQuery, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return apperror.New(apperror.Conflict,
+				"product has offers in this shop, make it unavailable instead of removing", nil)
+		}
+		return apperror.New(apperror.DatabaseError, "error deleting inventory item", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// SelectItemHistory возвращает историю позиции инвентаря, новые изменения первыми.
+// История доступна и после удаления позиции.
+func (r *InventoryRepository) SelectItemHistory(
+	ctx context.Context,
+	shopID, productID, userID uint,
+	limit, offset int,
+) ([]entity.InventoryChange, int, error) {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	selectHistoryQuery, args := squirrel.Select(inventoryChangeColumns...).
+		Column("COUNT (*) OVER() as total_count").
+		From("shop_inventory_history").
+		Where(squirrel.Eq{"shop_id": shopID, "product_id": productID}).
+		OrderBy("id desc").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	changesWithCount := make([]model.InventoryChangeWithCount, 0, limit)
+	err = r.db.SelectContext(ctx, &changesWithCount, selectHistoryQuery, args...)
+	if err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting inventory history", err)
+	}
+
+	if len(changesWithCount) == 0 {
+		return []entity.InventoryChange{}, 0, nil
+	}
+
+	changes := make([]entity.InventoryChange, len(changesWithCount))
+	for i, changeModel := range changesWithCount {
+		changes[i] = changeModel.ConvertToEntity()
+	}
+
+	return changes, changesWithCount[0].TotalCount, nil
+}
+
+// ImportItems добавляет или обновляет позиции инвентаря магазина по строкам импорта в одной транзакции.
+// Строки с несуществующим или архивным товаром не применяются и попадают в ошибки отчёта.
+// Позиции, которые снимаются с продажи, отклоняют открытые офферы, как при UpdateItem.
+// При dryRun транзакция откатывается, отчёт при этом такой же, как при настоящем импорте.
+func (r *InventoryRepository) ImportItems(
+	ctx context.Context,
+	shopID, userID uint,
+	rows []entity.InventoryImportRow,
+	dryRun bool,
+) (entity.InventoryImportReport, error) {
+	report := entity.InventoryImportReport{DryRun: dryRun}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return report, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isShopOwner(ctx, shopID, userID, tx)
+	if err != nil {
+		return report, err
+	}
+
+	if len(rows) == 0 {
+		return report, nil
+	}
+
+	productIDs := make([]uint, len(rows))
+	for i, row := range rows {
+		productIDs[i] = row.Item.ProductID
+	}
+
+	// товары блокируются раньше позиций, как в lockItem
+	selectProductsQuery, args := squirrel.Select("id", "archived_at is not null as archived").
+		From("products").
+		Where(squirrel.Eq{"id": productIDs}).
+		OrderBy("id").
+		Suffix("for share").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var products []struct {
+		ID       uint `db:"id"`
+		Archived bool `db:"archived"`
+	}
+	err = tx.SelectContext(ctx, &products, selectProductsQuery, args...)
+	if err != nil {
+		return report, apperror.New(apperror.DatabaseError, "error selecting products", err)
+	}
+
+	archived := make(map[uint]bool, len(products))
+	for _, product := range products {
+		archived[product.ID] = product.Archived
+	}
+
+	lockItemsQuery, args := squirrel.Select(inventoryColumns...).
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID, "shop_inventory.product_id": productIDs}).
+		Suffix("for update of shop_inventory").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var itemModels []model.InventoryItem
+	err = tx.SelectContext(ctx, &itemModels, lockItemsQuery, args...)
+	if err != nil {
+		return report, apperror.New(apperror.DatabaseError, "error locking inventory items", err)
+	}
+
+	existing := make(map[uint]entity.InventoryItem, len(itemModels))
+	for _, itemModel := range itemModels {
+		item := itemModel.ConvertToEntity()
+		existing[item.ProductID] = item
+	}
+
+	insertItemsQuery := squirrel.Insert("shop_inventory").
+		Columns("shop_id", "product_id", "price", "currency", "is_available").
+		PlaceholderFormat(squirrel.Dollar)
+
+	var created, updated, withdrawn []uint
+	for _, row := range rows {
+		item := row.Item
+		isArchived, found := archived[item.ProductID]
+		current, listed := existing[item.ProductID]
+
+		switch {
+		case !found:
+			report.Errors = append(report.Errors, entity.InventoryImportError{
+				Line: row.Line, Error: apperror.ErrProductNotFound.Message()})
+			continue
+		case isArchived && item.IsAvailable:
+			report.Errors = append(report.Errors, entity.InventoryImportError{
+				Line: row.Line, Error: "archived products cannot be sold"})
+			continue
+		case !listed:
+			insertItemsQuery = insertItemsQuery.Values(shopID, item.ProductID, item.Price, item.Currency,
+				item.IsAvailable)
+			created = append(created, item.ProductID)
+			continue
+		case sameInventoryItem(current, item):
+			report.Unchanged++
+			continue
+		}
+
+		updateItemQuery, args := squirrel.Update("shop_inventory").
+			Set("price", item.Price).
+			Set("currency", item.Currency).
+			Set("is_available", item.IsAvailable).
+			Where(squirrel.Eq{"shop_id": shopID, "product_id": item.ProductID}).
+			PlaceholderFormat(squirrel.Dollar).
+			MustSql()
+
+		_, err = tx.ExecContext(ctx, updateItemQuery, args...)
+		if err != nil {
+			return report, apperror.New(apperror.DatabaseError, "error updating inventory item", err)
+		}
+
+		updated = append(updated, item.ProductID)
+		if current.IsAvailable && !item.IsAvailable {
+			withdrawn = append(withdrawn, item.ProductID)
+		}
+	}
+
+	if len(created) > 0 {
+		query, args := insertItemsQuery.MustSql()
+		_, err = tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return report, apperror.New(apperror.DatabaseError, "error inserting inventory items", err)
+		}
+
+		err = insertInventoryHistory(ctx, tx, entity.InventoryAdded, &userID,
+			squirrel.Eq{"shop_id": shopID, "product_id": created})
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if len(updated) > 0 {
+		err = insertInventoryHistory(ctx, tx, entity.InventoryUpdated, &userID,
+			squirrel.Eq{"shop_id": shopID, "product_id": updated})
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if len(withdrawn) > 0 {
+		withdrawnCondition := squirrel.Eq{"shop_id": shopID, "product_id": withdrawn}
+
+		report.DeclinedOffers, err = declineOpenOffers(ctx, tx, withdrawnCondition, &userID, actorRoleShop)
+		if err != nil {
+			return report, err
+		}
+
+		err = loseItemBids(ctx, tx, withdrawnCondition)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	report.Created = len(created)
+	report.Updated = len(updated)
+
+	if dryRun {
+		return report, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.InventoryImportReport{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return report, nil
+}
+
+// sameInventoryItem сравнивает позиции с точностью до копейки, как цена хранится в базе
+func sameInventoryItem(current, imported entity.InventoryItem) bool {
+	return math.Round(current.Price*100) == math.Round(imported.Price*100) &&
+		strings.EqualFold(current.Currency, imported.Currency) &&
+		current.IsAvailable == imported.IsAvailable
+}
+
+// ExportItems передаёт в fn позиции инвентаря магазина по ID товара, не загружая весь инвентарь в память.
+// Ошибка fn прерывает выгрузку и возвращается как есть.
+func (r *InventoryRepository) ExportItems(
+	ctx context.Context,
+	shopID, userID uint,
+	fn func(entity.InventoryItem) error,
+) error {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return err
+	}
+
+	selectItemsQuery, args := squirrel.Select(inventoryColumns...).
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID}).
+		OrderBy("shop_inventory.product_id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	rows, err := r.db.QueryxContext(ctx, selectItemsQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error selecting shop inventory", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemModel model.InventoryItem
+		if err = rows.StructScan(&itemModel); err != nil {
+			return apperror.New(apperror.DatabaseError, "error reading shop inventory", err)
+		}
+		if err = fn(itemModel.ConvertToEntity()); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return apperror.New(apperror.DatabaseError, "error reading shop inventory", err)
+	}
+
+	return nil
+}
+
+// lockItem проверяет, что пользователь владеет магазином, и блокирует позицию его инвентаря
+// до конца транзакции. archived - товар позиции в архиве.
+func lockItem(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	shopID, productID, userID uint,
+) (item entity.InventoryItem, archived bool, err error) {
+	err = isShopOwner(ctx, shopID, userID, tx)
+	if err != nil {
+		return entity.InventoryItem{}, false, err
+	}
+
+	// товар блокируется раньше позиции, в том же порядке, что и при архивации:
+	// пока позиция меняется, архивировать товар нельзя
+	lockProductQuery, args := squirrel.Select("archived_at is not null").
+		From("products").
+		Where(squirrel.Eq{"id": productID}).
+		Suffix("for share").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	err = tx.GetContext(ctx, &archived, lockProductQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.InventoryItem{}, false, apperror.ErrInventoryItemNotFound
+		}
+		return entity.InventoryItem{}, false, apperror.New(apperror.DatabaseError, "error locking product", err)
+	}
+
+	lockItemQuery, args := squirrel.Select(inventoryColumns...).
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID, "shop_inventory.product_id": productID}).
+		Suffix("for update of shop_inventory").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var itemModel model.InventoryItem
+	err = tx.GetContext(ctx, &itemModel, lockItemQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.InventoryItem{}, false, apperror.ErrInventoryItemNotFound
+		}
+		return entity.InventoryItem{}, false, apperror.New(apperror.DatabaseError, "error locking inventory item", err)
+	}
+
+	return itemModel.ConvertToEntity(), archived, nil
+}
+
+func selectItem(ctx context.Context, tx *sqlx.Tx, shopID, productID uint) (entity.InventoryItem, error) {
+	selectItemQuery, args := squirrel.Select(inventoryColumns...).
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID, "shop_inventory.product_id": productID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var itemModel model.InventoryItem
+	err := tx.GetContext(ctx, &itemModel, selectItemQuery, args...)
+	if err != nil {
+		return entity.InventoryItem{}, apperror.New(apperror.DatabaseError, "error selecting inventory item", err)
+	}
+
+	return itemModel.ConvertToEntity(), nil
+}
+
+// insertInventoryHistory записывает в историю текущее состояние позиций инвентаря,
+// подходящих под condition
+func insertInventoryHistory(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	change string,
+	changedBy *uint,
+	condition squirrel.Eq,
+) error {
+	insertHistoryQuery, args := squirrel.Insert("shop_inventory_history").
+		Columns("shop_id", "product_id", "change", "price", "currency", "is_available", "changed_by").
+		Select(squirrel.Select("shop_id", "product_id").
+			Column("?::text", change).
+			Columns("price", "currency", "is_available").
+			Column("?::int", changedBy).
+			From("shop_inventory").
+			Where(condition)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, insertHistoryQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error writing inventory history", err)
+	}
+
+	return nil
+}
+
+// declineOpenOffers отклоняет открытые офферы на позиции инвентаря, подходящие под condition
+// (по колонкам offers), и возвращает их число. Как и при ручном отказе, в журнал пишется
+// событие от имени actorRole, а в outbox - это событие и письмо покупателю.
+func declineOpenOffers(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	condition squirrel.Eq,
+	actorID *uint,
+	actorRole string,
+) (int, error) {
+	batchQuery, batchArgs := squirrel.Select("id").
+		From("offers").
+		Where(squirrel.Eq{"status": openOfferStatuses}).
+		Where(condition).
+		Suffix("for update").
+		MustSql()
+
+	updateQuery, updateArgs := squirrel.Update("offers").
+		Set("status", statusDeclined).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		From("batch, offers prev").
+		Where("offers.id = batch.id").
+		Where("prev.id = offers.id").
+		Suffix("returning offers.id, offers.user_id, prev.status as previous_status, " +
+			"offers.status, offers.offer_price").
+		MustSql()
+
+	logQuery, logArgs := squirrel.Insert("offer_events").
+		Columns("offer_id", "event_type", "from_status", "to_status", "price", "actor_id", "actor_role").
+		Select(squirrel.Select("id").
+			Column(squirrel.Expr("?", offerEventStatusChanged)).
+			Columns("previous_status", "status", "offer_price").
+			Column(squirrel.Expr("?::int", actorID)).
+			Column(squirrel.Expr("?", actorRole)).
+			From("declined")).
+		Suffix("returning id, offer_id, to_status, price").
+		MustSql()
+
+	outboxQuery, outboxArgs := squirrel.Insert("outbox").
+		Columns("topic", "aggregate_id", "dedupe_key", "payload").
+		Select(squirrel.Select().
+			Column(squirrel.Expr("?::text", entity.OutboxTopicOfferEvent)).
+			Column("logged.offer_id").
+			Column(squirrel.Expr("?::text || logged.id", offerEventKeyPrefix)).
+			Column(squirrel.Expr(offerEventPayloadJSON, entity.OfferUpdateStatusChanged)).
+			From("logged").
+			Suffix("union all").
+			SuffixExpr(squirrel.Select().
+				Column(squirrel.Expr("?::text", entity.OutboxTopicEmail)).
+				Column("logged.offer_id").
+				Column(squirrel.Expr("?::text || logged.id || ?::text", offerEventKeyPrefix, ":email")).
+				Column(squirrel.Expr("json_build_object('kind', ?::text, 'to', "+buyerEmail+", 'status', ?::text)::jsonb",
+					entity.EmailStatusUpdate, statusDeclined)).
+				From("logged").
+				InnerJoin("declined on declined.id = logged.offer_id").
+				LeftJoin("users on users.id = declined.user_id").
+				LeftJoin("guest_offers on guest_offers.offer_id = declined.id"))).
+		MustSql()
+
+	args := append(append(append(batchArgs, updateArgs...), logArgs...), outboxArgs...)
+	declineQuery, args := squirrel.Select("count(*)").
+		Prefix("with batch as ("+batchQuery+"), declined as ("+updateQuery+"), logged as ("+logQuery+"), "+
+			"outboxed as ("+outboxQuery+")", args...).
+		From("declined").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var declined int
+	err := tx.GetContext(ctx, &declined, declineQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error declining open offers", err)
+	}
+
+	return declined, nil
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type AutoResponseRule struct {
+	ID           uint      `db:"id"`
+	ShopID       uint      `db:"shop_id"`
+	ProductID    *uint     `db:"product_id"`
+	AcceptFrom   *float64  `db:"accept_from"`
+	DeclineBelow *float64  `db:"decline_below"`
+	CounterPrice *float64  `db:"counter_price"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (r *AutoResponseRule) ConvertToEntity() entity.AutoResponseRule {
+	return entity.AutoResponseRule{
+		ID:           r.ID,
+		ShopID:       r.ShopID,
+		ProductID:    r.ProductID,
+		AcceptFrom:   r.AcceptFrom,
+		DeclineBelow: r.DeclineBelow,
+		CounterPrice: r.CounterPrice,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
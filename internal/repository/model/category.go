@@ -0,0 +1,21 @@
+package model
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type Category struct {
+	ID       uint   `db:"id"`
+	Name     string `db:"name"`
+	ParentID *uint  `db:"parent_id"`
+	Lft      int    `db:"lft"`
+	Rgt      int    `db:"rgt"`
+}
+
+func (c *Category) ConvertToEntity() entity.Category {
+	return entity.Category{
+		ID:       c.ID,
+		Name:     c.Name,
+		ParentID: c.ParentID,
+		Lft:      c.Lft,
+		Rgt:      c.Rgt,
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type CurrencyRate struct {
+	Currency  string    `db:"currency"`
+	Rate      float64   `db:"rate"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (r *CurrencyRate) ConvertToEntity() entity.CurrencyRate {
+	return entity.CurrencyRate{
+		Currency:  r.Currency,
+		Rate:      r.Rate,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
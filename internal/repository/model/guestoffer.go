@@ -0,0 +1,19 @@
+package model
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type GuestOffer struct {
+	OfferDetails
+	GuestName  string `db:"guest_name"`
+	GuestEmail string `db:"guest_email"`
+	GuestPhone string `db:"guest_phone"`
+}
+
+func (o *GuestOffer) ConvertToEntity() entity.GuestOffer {
+	return entity.GuestOffer{
+		OfferDetails: o.OfferDetails.ConvertToEntity(),
+		GuestName:    o.GuestName,
+		GuestEmail:   o.GuestEmail,
+		GuestPhone:   o.GuestPhone,
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type IdempotencyRecord struct {
+	Scope       string        `db:"scope"`
+	Key         string        `db:"key"`
+	RequestHash string        `db:"request_hash"`
+	StatusCode  sql.NullInt64 `db:"status_code"`
+	Body        []byte        `db:"response_body"`
+	ExpiresAt   time.Time     `db:"expires_at"`
+}
+
+func (r *IdempotencyRecord) ConvertToEntity() entity.IdempotencyRecord {
+	return entity.IdempotencyRecord{
+		Scope:       r.Scope,
+		Key:         r.Key,
+		RequestHash: r.RequestHash,
+		StatusCode:  int(r.StatusCode.Int64),
+		Body:        r.Body,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
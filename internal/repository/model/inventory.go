@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type InventoryItem struct {
+	ShopID      uint    `db:"shop_id"`
+	ProductID   uint    `db:"product_id"`
+	ProductName string  `db:"product_name"`
+	Price       float64 `db:"price"`
+	Currency    string  `db:"currency"`
+	IsAvailable bool    `db:"is_available"`
+}
+
+type InventoryItemWithCount struct {
+	InventoryItem
+	TotalCount int `db:"total_count"`
+}
+
+func (i *InventoryItem) ConvertToEntity() entity.InventoryItem {
+	return entity.InventoryItem{
+		ShopID:      i.ShopID,
+		ProductID:   i.ProductID,
+		ProductName: i.ProductName,
+		Price:       i.Price,
+		Currency:    i.Currency,
+		IsAvailable: i.IsAvailable,
+	}
+}
+
+type InventoryChange struct {
+	ID          uint      `db:"id"`
+	ShopID      uint      `db:"shop_id"`
+	ProductID   uint      `db:"product_id"`
+	Change      string    `db:"change"`
+	Price       float64   `db:"price"`
+	Currency    string    `db:"currency"`
+	IsAvailable bool      `db:"is_available"`
+	ChangedBy   *uint     `db:"changed_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type InventoryChangeWithCount struct {
+	InventoryChange
+	TotalCount int `db:"total_count"`
+}
+
+func (c *InventoryChange) ConvertToEntity() entity.InventoryChange {
+	return entity.InventoryChange{
+		ID:          c.ID,
+		ShopID:      c.ShopID,
+		ProductID:   c.ProductID,
+		Change:      c.Change,
+		Price:       c.Price,
+		Currency:    c.Currency,
+		IsAvailable: c.IsAvailable,
+		ChangedBy:   c.ChangedBy,
+		CreatedAt:   c.CreatedAt,
+	}
+}
@@ -15,7 +15,7 @@ type Offer struct {
 	UpdatedAt time.Time `db:"updated_at"`
 	ExpiresAt time.Time `db:"expires_at"`
 	ShopID    uint      `db:"shop_id"`
-	UserID    uint      `db:"user_id"`
+	UserID    *uint     `db:"user_id"` // nil у оффера гостя
 	ProductID uint      `db:"product_id"`
 }
 
@@ -25,7 +25,7 @@ type OfferWithCount struct {
 }
 
 func (o *Offer) ConvertToEntity() entity.Offer {
-	return entity.Offer{
+	offer := entity.Offer{
 		ID:        o.ID,
 		Price:     o.Price,
 		Currency:  o.Currency,
@@ -34,13 +34,16 @@ func (o *Offer) ConvertToEntity() entity.Offer {
 		UpdatedAt: o.UpdatedAt,
 		ExpiresAt: o.ExpiresAt,
 		ShopID:    o.ShopID,
-		UserID:    o.UserID,
 		ProductID: o.ProductID,
 	}
+	if o.UserID != nil {
+		offer.UserID = *o.UserID
+	}
+	return offer
 }
 
 func ConvertOfferEntityToModel(offer entity.Offer) Offer {
-	return Offer{
+	offerModel := Offer{
 		ID:        offer.ID,
 		Price:     offer.Price,
 		Currency:  offer.Currency,
@@ -49,7 +52,133 @@ func ConvertOfferEntityToModel(offer entity.Offer) Offer {
 		UpdatedAt: offer.UpdatedAt,
 		ExpiresAt: offer.ExpiresAt,
 		ShopID:    offer.ShopID,
-		UserID:    offer.UserID,
 		ProductID: offer.ProductID,
 	}
+	if offer.UserID != 0 {
+		offerModel.UserID = &offer.UserID
+	}
+	return offerModel
+}
+
+type OfferDetails struct {
+	Offer
+	ProductName string `db:"product_name"`
+	ShopName    string `db:"shop_name"`
+}
+
+func (o *OfferDetails) ConvertToEntity() entity.OfferDetails {
+	return entity.OfferDetails{
+		Offer:       o.Offer.ConvertToEntity(),
+		ProductName: o.ProductName,
+		ShopName:    o.ShopName,
+	}
+}
+
+// OfferStatusChange строка оффера после обновления вместе с его предыдущим состоянием
+type OfferStatusChange struct {
+	Offer
+	PreviousStatus string  `db:"previous_status"`
+	PreviousPrice  float64 `db:"previous_price"`
+}
+
+type OfferEvent struct {
+	ID         uint      `db:"id"`
+	OfferID    uint      `db:"offer_id"`
+	EventType  string    `db:"event_type"`
+	FromStatus *string   `db:"from_status"`
+	ToStatus   string    `db:"to_status"`
+	Price      float64   `db:"price"`
+	ActorID    *uint     `db:"actor_id"`
+	ActorRole  string    `db:"actor_role"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (e *OfferEvent) ConvertToEntity() entity.OfferEvent {
+	event := entity.OfferEvent{
+		ID:        e.ID,
+		OfferID:   e.OfferID,
+		EventType: e.EventType,
+		ToStatus:  e.ToStatus,
+		Price:     e.Price,
+		ActorRole: e.ActorRole,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.FromStatus != nil {
+		event.FromStatus = *e.FromStatus
+	}
+	if e.ActorID != nil {
+		event.ActorID = *e.ActorID
+	}
+	return event
+}
+
+type ExpiredOffer struct {
+	OfferID    uint   `db:"offer_id"`
+	BuyerEmail string `db:"buyer_email"`
+}
+
+func (e *ExpiredOffer) ConvertToEntity() entity.ExpiredOffer {
+	return entity.ExpiredOffer{
+		OfferID:    e.OfferID,
+		BuyerEmail: e.BuyerEmail,
+	}
+}
+
+type OfferQuotaUsage struct {
+	Open           int        `db:"open_offers"`
+	CreatedSince   int        `db:"created_since"`
+	LastDeclinedAt *time.Time `db:"last_declined_at"`
+}
+
+func (u *OfferQuotaUsage) ConvertToEntity() entity.OfferQuotaUsage {
+	return entity.OfferQuotaUsage{
+		Open:           u.Open,
+		CreatedSince:   u.CreatedSince,
+		LastDeclinedAt: u.LastDeclinedAt,
+	}
+}
+
+// OfferUpdate строка оффера с владельцем магазина. Теги json задают формат
+// полезной нагрузки NOTIFY, которой экземпляры приложения обмениваются событиями.
+type OfferUpdate struct {
+	Type        string    `db:"-" json:"type"`
+	EventID     uint      `db:"-" json:"event_id"`
+	OfferID     uint      `db:"offer_id" json:"offer_id"`
+	Status      string    `db:"status" json:"status"`
+	Price       float64   `db:"offer_price" json:"price"`
+	Currency    string    `db:"currency" json:"currency"`
+	BuyerID     uint      `db:"buyer_id" json:"buyer_id"`
+	ShopID      uint      `db:"shop_id" json:"shop_id"`
+	ShopOwnerID uint      `db:"shop_owner_id" json:"shop_owner_id"`
+	OccurredAt  time.Time `db:"-" json:"occurred_at"`
+}
+
+func (u *OfferUpdate) ConvertToEntity() entity.OfferUpdate {
+	return entity.OfferUpdate{
+		Type:        u.Type,
+		EventID:     u.EventID,
+		OfferID:     u.OfferID,
+		Status:      u.Status,
+		Price:       u.Price,
+		Currency:    u.Currency,
+		BuyerID:     u.BuyerID,
+		ShopID:      u.ShopID,
+		ShopOwnerID: u.ShopOwnerID,
+		OccurredAt:  u.OccurredAt,
+	}
+}
+
+func ConvertOfferUpdateEntityToModel(update entity.OfferUpdate) OfferUpdate {
+	return OfferUpdate{
+		Type:        update.Type,
+		EventID:     update.EventID,
+		OfferID:     update.OfferID,
+		Status:      update.Status,
+		Price:       update.Price,
+		Currency:    update.Currency,
+		BuyerID:     update.BuyerID,
+		ShopID:      update.ShopID,
+		ShopOwnerID: update.ShopOwnerID,
+		OccurredAt:  update.OccurredAt,
+	}
 }
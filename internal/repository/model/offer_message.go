@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type OfferMessage struct {
+	ID         uint       `db:"id"`
+	OfferID    uint       `db:"offer_id"`
+	SenderID   uint       `db:"sender_id"`
+	SenderRole string     `db:"sender_role"`
+	Body       string     `db:"body"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ReadAt     *time.Time `db:"read_at"`
+}
+
+type OfferMessageWithCount struct {
+	OfferMessage
+	TotalCount int `db:"total_count"`
+}
+
+func (m *OfferMessage) ConvertToEntity() entity.OfferMessage {
+	return entity.OfferMessage{
+		ID:         m.ID,
+		OfferID:    m.OfferID,
+		SenderID:   m.SenderID,
+		SenderRole: m.SenderRole,
+		Body:       m.Body,
+		CreatedAt:  m.CreatedAt,
+		ReadAt:     m.ReadAt,
+	}
+}
+
+type OfferUnread struct {
+	OfferID uint `db:"offer_id"`
+	Unread  int  `db:"unread"`
+}
+
+func (u *OfferUnread) ConvertToEntity() entity.OfferUnread {
+	return entity.OfferUnread{
+		OfferID: u.OfferID,
+		Unread:  u.Unread,
+	}
+}
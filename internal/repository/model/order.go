@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type Order struct {
+	ID        uint      `db:"id"`
+	OfferID   uint      `db:"offer_id"`
+	UserID    uint      `db:"user_id"`
+	ShopID    uint      `db:"shop_id"`
+	ProductID uint      `db:"product_id"`
+	Price     float64   `db:"price"`
+	Currency  string    `db:"currency"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type OrderWithCount struct {
+	Order
+	TotalCount int `db:"total_count"`
+}
+
+func (o *Order) ConvertToEntity() entity.Order {
+	return entity.Order{
+		ID:        o.ID,
+		OfferID:   o.OfferID,
+		UserID:    o.UserID,
+		ShopID:    o.ShopID,
+		ProductID: o.ProductID,
+		Price:     o.Price,
+		Currency:  o.Currency,
+		Status:    o.Status,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
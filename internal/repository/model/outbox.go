@@ -0,0 +1,39 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type OutboxMessage struct {
+	ID          uint           `db:"id"`
+	Topic       string         `db:"topic"`
+	AggregateID uint           `db:"aggregate_id"`
+	DedupeKey   sql.NullString `db:"dedupe_key"`
+	Payload     []byte         `db:"payload"`
+	Attempts    int            `db:"attempts"`
+	// DoneSinks выбирается через array_to_string(done_sinks, ','): имена получателей не содержат запятых
+	DoneSinks string    `db:"done_sinks"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (m *OutboxMessage) ConvertToEntity() entity.OutboxMessage {
+	var doneSinks []string
+	if m.DoneSinks != "" {
+		doneSinks = strings.Split(m.DoneSinks, ",")
+	}
+
+	return entity.OutboxMessage{
+		ID:          m.ID,
+		Topic:       m.Topic,
+		AggregateID: m.AggregateID,
+		DedupeKey:   m.DedupeKey.String,
+		Payload:     m.Payload,
+		Attempts:    m.Attempts,
+		DoneSinks:   doneSinks,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type PricingPolicy struct {
+	ShopID          uint      `db:"shop_id"`
+	MinPricePercent float64   `db:"min_price_percent"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (p *PricingPolicy) ConvertToEntity() entity.PricingPolicy {
+	return entity.PricingPolicy{
+		ShopID:          p.ShopID,
+		MinPricePercent: p.MinPricePercent,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+type PriceListing struct {
+	Price           float64  `db:"price"`
+	Currency        string   `db:"currency"`
+	MinPricePercent *float64 `db:"min_price_percent"`
+}
+
+func (l *PriceListing) ConvertToEntity() entity.PriceListing {
+	return entity.PriceListing{
+		Price:           l.Price,
+		Currency:        l.Currency,
+		MinPricePercent: l.MinPricePercent,
+	}
+}
@@ -1,14 +1,26 @@
 package model
 
 import (
+	"time"
+
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
 type Product struct {
-	ID          int    `db:"id"`
-	Name        string `db:"name"`
-	Description string `db:"description"`
-	CategoryID  int    `db:"category_id"`
+	ID          int        `db:"id"`
+	Name        string     `db:"name"`
+	Description string     `db:"description"`
+	CategoryID  int        `db:"category_id"`
+	ShopID      *int       `db:"shop_id"`
+	ArchivedAt  *time.Time `db:"archived_at"`
+	// Snippet фрагмент названия и описания, где слова запроса стоят между маркерами подсветки.
+	// Выбирается только при поиске, в entity.Product переводится репозиторием.
+	Snippet *string `db:"snippet"`
+}
+
+type ProductWithCount struct {
+	Product
+	TotalCount int `db:"total_count"`
 }
 
 type ProductFilter struct {
@@ -17,6 +29,10 @@ type ProductFilter struct {
 	MinPrice   *int    `form:"min_price"`
 	MaxPrice   *int    `form:"max_price"`
 	Name       *string `form:"name"`
+	// Query полнотекстовый поиск по названию и описанию, с ним товары упорядочены по релевантности
+	Query *string `form:"q" binding:"omitempty,max=200"`
+	// Currency валюта отображения: в ней задаются min_price и max_price и считается диапазон цен
+	Currency   string `form:"currency" binding:"omitempty,iso4217"`
 	Attributes map[string]string
 }
 
@@ -29,5 +45,7 @@ func ConvertProductToEntity(p Product) entity.Product {
 		MinimalPrice: 0,
 		MaximalPrice: 0,
 		Attributes:   make(map[string]interface{}),
+		ShopID:       p.ShopID,
+		ArchivedAt:   p.ArchivedAt,
 	}
 }
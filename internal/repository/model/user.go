@@ -12,6 +12,7 @@ type User struct {
 	Phone         string `db:"phone_number"`
 	Password      string `db:"password_hash"`
 	IsStore       bool   `db:"is_store"`
+	IsAdmin       bool   `db:"is_admin"`
 	Notifications []Notification
 }
 
@@ -33,5 +34,6 @@ func ConvertUserToEntity(u User) entity.User {
 		Phone:    u.Phone,
 		Password: u.Password,
 		IsStore:  u.IsStore,
+		IsAdmin:  u.IsAdmin,
 	}
 }
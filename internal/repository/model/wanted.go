@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type WantedRequest struct {
+	ID          uint      `db:"id"`
+	UserID      uint      `db:"user_id"`
+	ProductID   *uint     `db:"product_id"`
+	CategoryID  *uint     `db:"category_id"`
+	TargetPrice float64   `db:"target_price"`
+	Currency    string    `db:"currency"`
+	Status      string    `db:"status"`
+	Deadline    time.Time `db:"deadline"`
+	OfferID     *uint     `db:"offer_id"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type WantedRequestWithCount struct {
+	WantedRequest
+	TotalCount int `db:"total_count"`
+}
+
+func (w *WantedRequest) ConvertToEntity() entity.WantedRequest {
+	return entity.WantedRequest{
+		ID:          w.ID,
+		UserID:      w.UserID,
+		ProductID:   w.ProductID,
+		CategoryID:  w.CategoryID,
+		TargetPrice: w.TargetPrice,
+		Currency:    w.Currency,
+		Status:      w.Status,
+		Deadline:    w.Deadline,
+		OfferID:     w.OfferID,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+type WantedBid struct {
+	ID          uint      `db:"id"`
+	RequestID   uint      `db:"request_id"`
+	ShopID      uint      `db:"shop_id"`
+	ShopName    string    `db:"shop_name"`
+	ProductID   uint      `db:"product_id"`
+	ProductName string    `db:"product_name"`
+	Price       float64   `db:"price"`
+	Status      string    `db:"status"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (b *WantedBid) ConvertToEntity() entity.WantedBid {
+	return entity.WantedBid{
+		ID:          b.ID,
+		RequestID:   b.RequestID,
+		ShopID:      b.ShopID,
+		ShopName:    b.ShopName,
+		ProductID:   b.ProductID,
+		ProductName: b.ProductName,
+		Price:       b.Price,
+		Status:      b.Status,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+	}
+}
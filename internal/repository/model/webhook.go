@@ -0,0 +1,81 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type Webhook struct {
+	ID     uint   `db:"id"`
+	ShopID uint   `db:"shop_id"`
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+	// Events выбирается через array_to_string(events, ','): имена событий не содержат запятых
+	Events    string    `db:"events"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (w *Webhook) ConvertToEntity() entity.Webhook {
+	return entity.Webhook{
+		ID:        w.ID,
+		ShopID:    w.ShopID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    strings.Split(w.Events, ","),
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+type WebhookDelivery struct {
+	ID             uint           `db:"id"`
+	WebhookID      uint           `db:"webhook_id"`
+	EventType      string         `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	LastStatusCode sql.NullInt64  `db:"last_status_code"`
+	LastError      sql.NullString `db:"last_error"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+	DeliveredAt    *time.Time     `db:"delivered_at"`
+}
+
+type WebhookDeliveryWithCount struct {
+	WebhookDelivery
+	TotalCount int `db:"total_count"`
+}
+
+func (d *WebhookDelivery) ConvertToEntity() entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: int(d.LastStatusCode.Int64),
+		LastError:      d.LastError.String,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+type WebhookAttempt struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+func (a *WebhookAttempt) ConvertToEntity() entity.WebhookAttempt {
+	return entity.WebhookAttempt{
+		WebhookDelivery: a.WebhookDelivery.ConvertToEntity(),
+		URL:             a.URL,
+		Secret:          a.Secret,
+	}
+}
@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
@@ -15,6 +17,36 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
+const (
+	offerEventCreated       = "created"
+	offerEventStatusChanged = "status_changed"
+	offerEventCountered     = "countered"
+	offerEventExpired       = "expired"
+
+	actorRoleBuyer  = "buyer"
+	actorRoleShop   = "shop"
+	actorRoleSystem = "system"
+
+	statusAccepted  = "accepted"
+	statusCountered = "countered"
+	statusCancelled = "cancelled"
+	statusDeclined  = "declined"
+	statusExpired   = "expired"
+
+	// buyerEmail почта покупателя оффера, к которому присоединены users и guest_offers:
+	// у оффера гостя покупателя нет, письмо уходит на почту из гостевой заявки
+	buyerEmail = "coalesce(users.email, guest_offers.guest_email)"
+)
+
+var (
+	errBulkRolledBack   = apperror.New(apperror.Conflict, "rolled back because another offer in the batch failed", nil)
+	errBulkNotProcessed = apperror.New(apperror.Conflict, "not processed because another offer in the batch failed", nil)
+)
+
+// openOfferStatuses статусы офферов, по которым ещё идёт торг:
+// pending - ход магазина, countered - ход покупателя.
+var openOfferStatuses = []string{"pending", "countered"}
+
 type OfferRepository struct {
 	db *sqlx.DB
 }
@@ -23,15 +55,25 @@ func NewOfferRepository(db *sqlx.DB) *OfferRepository {
 	return &OfferRepository{db: db}
 }
 
+// InsertOffer создаёт оффер в статусе pending на товар, который магазин продаёт, иначе Conflict.
+// Офферы одного покупателя создаются по очереди:
+// checkQuota, если задан, получает его квоты с момента quotaSince в той же транзакции и может
+// отказать в создании. Если задан autoResponse (сработало правило автоответа магазина),
+// решение применяется в той же транзакции от имени системы.
+// Сообщения outbox записываются в той же транзакции с AggregateID созданного оффера.
 func (r *OfferRepository) InsertOffer(
 	ctx context.Context,
 	offer entity.Offer,
+	quotaSince time.Time,
+	checkQuota func(entity.OfferQuotaUsage) error,
+	autoResponse *entity.AutoResponse,
+	outbox []entity.OutboxMessage,
 ) (uint, error) {
 	offerModel := model.ConvertOfferEntityToModel(offer)
 
 	checkExistingOfferQuery, args := squirrel.Select("count(*)").
 		From("offers").
-		Where(squirrel.Eq{"status": "pending",
+		Where(squirrel.Eq{"status": openOfferStatuses,
 			"product_id": offerModel.ProductID,
 			"shop_id":    offerModel.ShopID,
 			"user_id":    offerModel.UserID}).
@@ -45,6 +87,52 @@ func (r *OfferRepository) InsertOffer(
 		_ = tx.Rollback()
 	}()
 
+	// блокировка на покупателя держится до конца транзакции, так что параллельный запрос
+	// посчитает квоты и дубли уже с этим оффером
+	lockBuyerQuery, lockArgs := squirrel.Select().
+		Column(squirrel.Expr("pg_advisory_xact_lock(?)", offer.UserID)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, lockBuyerQuery, lockArgs...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error locking buyer offers", err)
+	}
+
+	// позиция блокируется на чтение, как в AwardBid: снятие товара с продажи дождётся
+	// этой транзакции и отклонит новый оффер вместе с остальными открытыми
+	lockItemQuery, lockArgs := squirrel.Select("shop_inventory.is_available and products.archived_at is null").
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": offer.ShopID, "shop_inventory.product_id": offer.ProductID}).
+		Suffix("for share of shop_inventory").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var available bool
+	err = tx.QueryRowxContext(ctx, lockItemQuery, lockArgs...).Scan(&available)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.ErrProductNotFound
+		}
+		return 0, apperror.New(apperror.DatabaseError, "error locking offer product", err)
+	}
+	if !available {
+		return 0, apperror.New(apperror.Conflict, "product is not available in the shop", nil)
+	}
+
+	if checkQuota != nil {
+		usage, err := selectOfferQuotaUsage(ctx, tx, offer.UserID, offer.ShopID, offer.ProductID, quotaSince)
+		if err != nil {
+			return 0, err
+		}
+
+		err = checkQuota(usage)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	var existingOfferCount int
 	err = tx.QueryRowxContext(ctx, checkExistingOfferQuery, args...).Scan(&existingOfferCount)
 	if err != nil {
@@ -72,6 +160,33 @@ func (r *OfferRepository) InsertOffer(
 		return 0, apperror.New(apperror.DatabaseError, "error inserting offer into database", err)
 	}
 
+	err = insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:   offerID,
+		EventType: offerEventCreated,
+		ToStatus:  offerModel.Status,
+		Price:     offerModel.Price,
+		ActorID:   offerModel.UserID,
+		ActorRole: actorRoleBuyer,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if autoResponse != nil {
+		err = applyAutoResponse(ctx, tx, offerID, *autoResponse)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i := range outbox {
+		outbox[i].AggregateID = offerID
+	}
+	err = insertOutboxMessages(ctx, tx, outbox...)
+	if err != nil {
+		return 0, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
@@ -80,16 +195,66 @@ func (r *OfferRepository) InsertOffer(
 	return offerID, nil
 }
 
+// selectOfferQuotaUsage считает открытые офферы покупателя и созданные им с момента since,
+// а также находит время последнего отказа магазина по тому же товару
+func selectOfferQuotaUsage(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	userID, shopID, productID uint,
+	since time.Time,
+) (entity.OfferQuotaUsage, error) {
+	selectUsageQuery, args := squirrel.Select().
+		Column(squirrel.Expr("count(*) filter (where ?) as open_offers",
+			squirrel.Eq{"status": openOfferStatuses})).
+		Column(squirrel.Expr("count(*) filter (where ?) as created_since",
+			squirrel.GtOrEq{"created_at": since})).
+		Column(squirrel.Expr("max(updated_at) filter (where ?) as last_declined_at",
+			squirrel.Eq{"status": statusDeclined, "shop_id": shopID, "product_id": productID})).
+		From("offers").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var usage model.OfferQuotaUsage
+	err := tx.GetContext(ctx, &usage, selectUsageQuery, args...)
+	if err != nil {
+		return entity.OfferQuotaUsage{}, apperror.New(apperror.DatabaseError, "error counting user offers", err)
+	}
+
+	return usage.ConvertToEntity(), nil
+}
+
+// GetOfferByID возвращает оффер с названиями товара и магазина. Оффер виден только
+// покупателю и владельцу магазина, для остальных возвращается ErrOfferNotFound.
 func (r *OfferRepository) GetOfferByID(
 	ctx context.Context,
 	offerID uint,
-) (entity.Offer, error) {
-	var offer entity.Offer
+	userID uint,
+) (entity.OfferDetails, error) {
+	selectOfferQuery, args := withShopOwner(squirrel.Select("offers.id", "offers.offer_price",
+		"offers.currency", "offers.status", "offers.created_at", "offers.updated_at", "offers.expires_at",
+		"offers.shop_id", "offers.product_id", "offers.user_id",
+		"products.name as product_name", "shops.name as shop_name").
+		From("offers")).
+		InnerJoin("products on products.id = offers.product_id").
+		Where(squirrel.Eq{"offers.id": offerID}).
+		Where(squirrel.Or{
+			squirrel.Eq{"offers.user_id": userID},
+			squirrel.Eq{"shops.user_id": userID},
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
 
-	_ = ctx
-	_ = offerID
+	var offerModel model.OfferDetails
+	err := r.db.GetContext(ctx, &offerModel, selectOfferQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.OfferDetails{}, apperror.ErrOfferNotFound
+		}
+		return entity.OfferDetails{}, apperror.New(apperror.DatabaseError, "error selecting offer", err)
+	}
 
-	return offer, nil
+	return offerModel.ConvertToEntity(), nil
 }
 
 func (r *OfferRepository) SelectUserOffers(
@@ -97,28 +262,103 @@ func (r *OfferRepository) SelectUserOffers(
 	userID uint,
 	limit, offset int,
 ) ([]entity.Offer, int, error) {
-	var total int
+	// просроченные офферы, которые воркер ещё не успел перевести в expired, не показываем
+	selectUserOffersQuery, args := squirrel.Select("id, offer_price, currency, status, " +
+		"created_at, updated_at, expires_at, shop_id, product_id, user_id," +
+		"COUNT (*) OVER() as total_count").
+		From("offers").
+		Where(squirrel.Eq{"status": openOfferStatuses, "user_id": userID}).
+		Where(squirrel.Gt{"expires_at": time.Now()}).
+		OrderBy("created_at desc").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
 
-	tx, err := r.db.BeginTxx(ctx, nil)
+	offersWithCount := make([]model.OfferWithCount, 0, limit)
+
+	err := r.db.SelectContext(ctx, &offersWithCount, selectUserOffersQuery, args...)
 	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError,
-			"failed to begin transaction", err)
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user offers", err)
+	}
+
+	if len(offersWithCount) == 0 {
+		return []entity.Offer{}, 0, nil
+	}
+
+	offers := make([]entity.Offer, len(offersWithCount))
+	for i, offerModel := range offersWithCount {
+		offers[i] = offerModel.ConvertToEntity()
+	}
+
+	return offers, offersWithCount[0].TotalCount, nil
+}
+
+// SelectShopOffers возвращает входящие офферы по всем магазинам пользователя с фильтрацией,
+// а также количество офферов в каждом статусе (с учётом всех фильтров, кроме статуса).
+// Счётчики и страница читаются из одного снимка, чтобы не расходиться друг с другом.
+func (r *OfferRepository) SelectShopOffers(
+	ctx context.Context,
+	userID uint,
+	filter entity.ShopOfferFilter,
+	limit, offset int,
+) ([]entity.Offer, int, map[string]int, error) {
+	conditions := squirrel.And{squirrel.Eq{"shops.user_id": userID}}
+	if filter.ShopID != 0 {
+		conditions = append(conditions, squirrel.Eq{"offers.shop_id": filter.ShopID})
+	}
+	if filter.ProductID != 0 {
+		conditions = append(conditions, squirrel.Eq{"offers.product_id": filter.ProductID})
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, squirrel.GtOrEq{"offers.created_at": filter.From})
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, squirrel.LtOrEq{"offers.created_at": filter.To})
+	}
+	if filter.MinPrice != nil {
+		conditions = append(conditions, squirrel.GtOrEq{"offers.offer_price": *filter.MinPrice})
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, squirrel.LtOrEq{"offers.offer_price": *filter.MaxPrice})
+	}
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, 0, nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
 	}
 	defer func() {
 		_ = tx.Rollback()
 	}()
 
-	// lazy update
-	if err = r.updateExpiredOffers(ctx, userID, tx); err != nil {
-		return nil, 0, err
+	statusCounts, err := countShopOffersByStatus(ctx, tx, conditions)
+	if err != nil {
+		return nil, 0, nil, err
 	}
 
-	selectUserOffersQuery, args := squirrel.Select("id, offer_price, currency, status, " +
-		"created_at, updated_at, expires_at, shop_id, product_id, user_id," +
-		"COUNT (*) OVER() as total_count").
-		From("offers").
-		Where(squirrel.Eq{"status": "pending", "user_id": userID}).
-		OrderBy("created_at desc").
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, squirrel.Eq{"offers.status": filter.Statuses})
+	}
+
+	sortColumn := map[string]string{
+		entity.OfferSortCreatedAt: "offers.created_at",
+		entity.OfferSortExpiresAt: "offers.expires_at",
+		entity.OfferSortPrice:     "offers.offer_price",
+	}[filter.SortBy]
+	if sortColumn == "" {
+		sortColumn = "offers.created_at"
+	}
+	sortOrder := " asc"
+	if filter.SortDesc {
+		sortOrder = " desc"
+	}
+
+	selectShopOffersQuery, args := withShopOwner(squirrel.Select("offers.id", "offers.offer_price",
+		"offers.currency", "offers.status", "offers.created_at", "offers.updated_at", "offers.expires_at",
+		"offers.shop_id", "offers.product_id", "offers.user_id", "COUNT (*) OVER() as total_count").
+		From("offers")).
+		Where(conditions).
+		OrderBy(sortColumn+sortOrder, "offers.id"+sortOrder).
 		Offset(uint64(offset)).
 		Limit(uint64(limit)).
 		PlaceholderFormat(squirrel.Dollar).
@@ -126,46 +366,143 @@ func (r *OfferRepository) SelectUserOffers(
 
 	offersWithCount := make([]model.OfferWithCount, 0, limit)
 
-	err = tx.SelectContext(ctx, &offersWithCount, selectUserOffersQuery, args...)
+	err = tx.SelectContext(ctx, &offersWithCount, selectShopOffersQuery, args...)
 	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user offers after lazy update", err)
+		return nil, 0, nil, apperror.New(apperror.DatabaseError, "error selecting shop offers", err)
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError, "failed to commit transaction for lazy update", err)
+	if err = tx.Commit(); err != nil {
+		return nil, 0, nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
 	}
 
 	if len(offersWithCount) == 0 {
-		return []entity.Offer{}, 0, nil
+		return []entity.Offer{}, 0, statusCounts, nil
 	}
 
-	total = offersWithCount[0].TotalCount
-
 	offers := make([]entity.Offer, len(offersWithCount))
 	for i, offerModel := range offersWithCount {
 		offers[i] = offerModel.ConvertToEntity()
 	}
 
-	return offers, total, nil
+	return offers, offersWithCount[0].TotalCount, statusCounts, nil
 }
 
-// SelectUserOffers lazy update helper function
-func (r *OfferRepository) updateExpiredOffers(ctx context.Context, userID uint, tx *sqlx.Tx) error {
-	updateExpiredQuery, args := squirrel.Update("offers").
-		Set("status", "cancelled").
-		Set("updated_at", time.Now()).
-		Where(squirrel.Lt{"expires_at": time.Now()}).
-		Where(squirrel.Eq{"status": "pending"}).
-		Where(squirrel.Eq{"user_id": userID}).
+// countShopOffersByStatus считает офферы входящих, подходящих под conditions, по статусам
+func countShopOffersByStatus(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	conditions squirrel.And,
+) (map[string]int, error) {
+	countByStatusQuery, args := withShopOwner(squirrel.Select("offers.status", "count(*)").From("offers")).
+		Where(conditions).
+		GroupBy("offers.status").
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
-	_, err := tx.ExecContext(ctx, updateExpiredQuery, args...)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return apperror.New(apperror.DatabaseError, "error updating expired offers", err)
+	rows, err := tx.QueryxContext(ctx, countByStatusQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error counting shop offers by status", err)
 	}
-	return nil
+	defer rows.Close()
+
+	statusCounts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err = rows.Scan(&status, &count); err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "error scanning shop offer counts", err)
+		}
+		statusCounts[status] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error counting shop offers by status", err)
+	}
+
+	return statusCounts, nil
+}
+
+// ExpireOffers переводит в статус expired не более limit открытых офферов с истёкшим сроком.
+// Пакет выбирается по idx_offers_expires_at с skip locked, поэтому параллельные воркеры
+// и пользовательские транзакции друг друга не блокируют. Для каждого оффера в журнал
+// пишется событие expired от имени системы, а в outbox - это событие и письмо покупателю.
+func (r *OfferRepository) ExpireOffers(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]entity.ExpiredOffer, error) {
+	batchQuery, batchArgs := squirrel.Select("id").
+		From("offers").
+		Where(squirrel.Eq{"status": openOfferStatuses}).
+		Where(squirrel.Lt{"expires_at": now}).
+		OrderBy("expires_at").
+		Limit(uint64(limit)).
+		Suffix("for update skip locked").
+		MustSql()
+
+	updateQuery, updateArgs := squirrel.Update("offers").
+		Set("status", statusExpired).
+		Set("updated_at", now).
+		From("batch, offers prev").
+		Where("offers.id = batch.id").
+		Where("prev.id = offers.id").
+		Suffix("returning offers.id, offers.user_id, prev.status as previous_status, " +
+			"offers.status, offers.offer_price").
+		MustSql()
+
+	logQuery, logArgs := squirrel.Insert("offer_events").
+		Columns("offer_id", "event_type", "from_status", "to_status", "price", "actor_role").
+		Select(squirrel.Select("id").
+			Column(squirrel.Expr("?", offerEventExpired)).
+			Columns("previous_status", "status", "offer_price").
+			Column(squirrel.Expr("?", actorRoleSystem)).
+			From("expired")).
+		Suffix("returning id, offer_id, to_status, price").
+		MustSql()
+
+	outboxQuery, outboxArgs := squirrel.Insert("outbox").
+		Columns("topic", "aggregate_id", "dedupe_key", "payload").
+		Select(squirrel.Select().
+			Column(squirrel.Expr("?::text", entity.OutboxTopicOfferEvent)).
+			Column("logged.offer_id").
+			Column(squirrel.Expr("?::text || logged.id", offerEventKeyPrefix)).
+			Column(squirrel.Expr(offerEventPayloadJSON, entity.OfferUpdateExpired)).
+			From("logged").
+			Suffix("union all").
+			SuffixExpr(squirrel.Select().
+				Column(squirrel.Expr("?::text", entity.OutboxTopicEmail)).
+				Column("logged.offer_id").
+				Column(squirrel.Expr("?::text || logged.id || ?::text", offerEventKeyPrefix, ":email")).
+				Column(squirrel.Expr("json_build_object('kind', ?::text, 'to', "+buyerEmail+", 'status', ?::text)::jsonb",
+					entity.EmailStatusUpdate, statusExpired)).
+				From("logged").
+				InnerJoin("expired on expired.id = logged.offer_id").
+				LeftJoin("users on users.id = expired.user_id").
+				LeftJoin("guest_offers on guest_offers.offer_id = expired.id"))).
+		MustSql()
+
+	args := append(append(append(batchArgs, updateArgs...), logArgs...), outboxArgs...)
+	expireQuery, args := squirrel.Select("expired.id as offer_id", buyerEmail+" as buyer_email").
+		Prefix("with batch as ("+batchQuery+"), expired as ("+updateQuery+"), logged as ("+logQuery+"), "+
+			"outboxed as ("+outboxQuery+")", args...).
+		From("expired").
+		LeftJoin("users on users.id = expired.user_id").
+		LeftJoin("guest_offers on guest_offers.offer_id = expired.id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	expiredModels := make([]model.ExpiredOffer, 0, limit)
+
+	err := r.db.SelectContext(ctx, &expiredModels, expireQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error expiring offers", err)
+	}
+
+	expired := make([]entity.ExpiredOffer, len(expiredModels))
+	for i, e := range expiredModels {
+		expired[i] = e.ConvertToEntity()
+	}
+
+	return expired, nil
 }
 
 func (r *OfferRepository) UpdateOfferStatus(
@@ -174,16 +511,6 @@ func (r *OfferRepository) UpdateOfferStatus(
 	userID uint,
 	isStore bool,
 ) (entity.Offer, error) {
-	offer := model.ConvertOfferEntityToModel(offerEntity)
-
-	updateOfferStatusQuery, args := squirrel.Update("offers").
-		Set("status", offer.Status).
-		Set("updated_at", time.Now()).
-		Where(squirrel.Eq{"id": offer.ID}).
-		Suffix("returning status").
-		PlaceholderFormat(squirrel.Dollar).
-		MustSql()
-
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
@@ -192,52 +519,186 @@ func (r *OfferRepository) UpdateOfferStatus(
 		_ = tx.Rollback()
 	}()
 
-	err = isPendingOffer(ctx, offer.ID, tx)
+	offerResp, err := updateOfferStatus(ctx, tx, model.ConvertOfferEntityToModel(offerEntity), userID, isStore)
 	if err != nil {
 		return entity.Offer{}, err
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return entity.Offer{Status: offerResp.Status}, nil
+}
+
+// BulkUpdateOfferStatus отвечает магазином на несколько офферов сразу. В режиме atomic
+// все офферы обновляются в одной транзакции: при любой ошибке не применяется ничего,
+// а успешно проверенные офферы получают в отчёте ошибку об откате. Иначе каждый оффер
+// обновляется в своей транзакции независимо от остальных.
+func (r *OfferRepository) BulkUpdateOfferStatus(
+	ctx context.Context,
+	offerIDs []uint,
+	status string,
+	userID uint,
+	atomic bool,
+) ([]entity.BulkOfferResult, error) {
+	results := make([]entity.BulkOfferResult, len(offerIDs))
+
+	if !atomic {
+		for i, offerID := range offerIDs {
+			offerResp, err := r.UpdateOfferStatus(ctx, entity.Offer{ID: offerID, Status: status}, userID, true)
+			results[i] = entity.BulkOfferResult{OfferID: offerID, Status: offerResp.Status, Err: err}
+		}
+		return results, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	failed := false
+	for i, offerID := range offerIDs {
+		results[i].OfferID = offerID
+		if failed {
+			results[i].Err = errBulkNotProcessed
+			continue
+		}
+
+		offerResp, err := updateOfferStatus(ctx, tx, model.Offer{ID: offerID, Status: status}, userID, true)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		results[i].Status = offerResp.Status
+	}
+
+	if failed {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Status = ""
+				results[i].Err = errBulkRolledBack
+			}
+		}
+		return results, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return results, nil
+}
+
+// updateOfferStatus проверяет право пользователя ответить на оффер и меняет его статус
+// в переданной транзакции. Принятие оффера создаёт заказ.
+func updateOfferStatus(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	offer model.Offer,
+	userID uint,
+	isStore bool,
+) (model.OfferStatusChange, error) {
+	var err error
+
+	actorRole := actorRoleShop
+	ownerCondition := squirrel.Eq{"offers.id": offer.ID}
+
 	if isStore {
+		err = isPendingOffer(ctx, offer.ID, tx)
+		if err != nil {
+			return model.OfferStatusChange{}, err
+		}
+
 		err = isUserShopOwner(ctx, offer.ID, userID, tx)
 		if err != nil {
-			return entity.Offer{}, err
+			return model.OfferStatusChange{}, err
 		}
 
 	} else {
+		// Принять покупатель может только встречное предложение магазина,
+		// отменить - любой оффер, по которому ещё идёт торг.
+		if offer.Status == statusAccepted {
+			err = isCounteredOffer(ctx, offer.ID, tx)
+		} else {
+			err = isOpenOffer(ctx, offer.ID, tx)
+		}
+		if err != nil {
+			return model.OfferStatusChange{}, err
+		}
+
 		// Если запрос на обновление статуса отправляет НЕ магазин, то добавляем проверку user_id
 		// в квери, чтобы убедиться, что пользователь является создателем оффера.
-		updateOfferStatusQuery, args = squirrel.Update("offers").
-			Set("status", offer.Status).
-			Set("updated_at", time.Now()).
-			Where(squirrel.Eq{"id": offer.ID, "user_id": userID}).
-			Suffix("returning status").
-			PlaceholderFormat(squirrel.Dollar).
-			MustSql()
+		actorRole = actorRoleBuyer
+		ownerCondition = squirrel.Eq{"offers.id": offer.ID, "offers.user_id": userID}
 	}
 
-	var offerResp model.Offer
+	updateOfferStatusQuery, args := squirrel.Update("offers").
+		Set("status", offer.Status).
+		Set("updated_at", time.Now()).
+		From("offers prev").
+		Where("prev.id = offers.id").
+		Where(ownerCondition).
+		Suffix("returning offers.id, offers.status, offers.offer_price, prev.status as previous_status").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerResp model.OfferStatusChange
 	err = tx.QueryRowxContext(ctx, updateOfferStatusQuery, args...).StructScan(&offerResp)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return entity.Offer{}, apperror.New(apperror.Unauthorized,
+			return model.OfferStatusChange{}, apperror.New(apperror.Unauthorized,
 				"unauthorized to update offer status", nil)
 		}
-		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error scanning into struct", err)
+		return model.OfferStatusChange{}, apperror.New(apperror.DatabaseError, "error scanning into struct", err)
 	}
 
-	err = tx.Commit()
+	err = insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:    offerResp.ID,
+		EventType:  offerEventStatusChanged,
+		FromStatus: &offerResp.PreviousStatus,
+		ToStatus:   offerResp.Status,
+		Price:      offerResp.Price,
+		ActorID:    &userID,
+		ActorRole:  actorRole,
+	})
 	if err != nil {
-		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+		return model.OfferStatusChange{}, err
 	}
 
-	return offerResp.ConvertToEntity(), nil
+	// гость узнаёт о решении магазина только из письма
+	if isStore {
+		err = insertGuestStatusEmail(ctx, tx, offerResp.ID, offerResp.Status)
+		if err != nil {
+			return model.OfferStatusChange{}, err
+		}
+	}
+
+	// принятый оффер сразу превращается в заказ с зафиксированной ценой
+	if offerResp.Status == statusAccepted {
+		err = insertOrderFromOffer(ctx, tx, offerResp.ID)
+		if err != nil {
+			return model.OfferStatusChange{}, err
+		}
+	}
+
+	return offerResp, nil
+}
+
+// withShopOwner присоединяет к выборке офферов магазин и его владельца (shops.user_id).
+// Соединение идёт по offers.shop_id и использует индекс idx_offers_shop_id.
+func withShopOwner(query squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return query.InnerJoin("shops on shops.id = offers.shop_id")
 }
 
 func isUserShopOwner(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) error {
-	validateShopOwnerIDQuery, args := squirrel.Select("users.id").
-		From("users").
-		InnerJoin("shops on users.id = shops.user_id").
-		InnerJoin("offers on shops.id = offers.shop_id").
+	validateShopOwnerIDQuery, args := withShopOwner(squirrel.Select("shops.user_id").From("offers")).
 		Where(squirrel.Eq{"offers.id": offerID}).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
@@ -258,37 +719,446 @@ func isUserShopOwner(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) err
 	return nil
 }
 
+// isOfferBuyer проверяет, что оффер создан пользователем. Чужой оффер для покупателя
+// "не существует", поэтому его статус не раскрывается.
+func isOfferBuyer(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) error {
+	countOffersQuery, args := squirrel.Select("COUNT(*)").
+		From("offers").
+		Where(squirrel.Eq{"id": offerID, "user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err := tx.GetContext(ctx, &count, countOffersQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error checking offer buyer", err)
+	}
+	if count == 0 {
+		return apperror.ErrOfferNotFound
+	}
+
+	return nil
+}
+
 func isPendingOffer(ctx context.Context, offerID uint, tx *sqlx.Tx) error {
-	getOfferStatusQuery, args := squirrel.Select("offers.status = 'pending'").
+	ok, err := offerStatusIn(ctx, offerID, tx, "pending")
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return apperror.New(apperror.Conflict, "offer is not in a pending status", nil)
+	}
+
+	return nil
+}
+
+func isCounteredOffer(ctx context.Context, offerID uint, tx *sqlx.Tx) error {
+	ok, err := offerStatusIn(ctx, offerID, tx, "countered")
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return apperror.New(apperror.Conflict, "offer has no counter-offer awaiting response", nil)
+	}
+
+	return nil
+}
+
+func isOpenOffer(ctx context.Context, offerID uint, tx *sqlx.Tx) error {
+	ok, err := offerStatusIn(ctx, offerID, tx, openOfferStatuses...)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return apperror.New(apperror.Conflict, "offer is no longer open for negotiation", nil)
+	}
+
+	return nil
+}
+
+// offerStatusIn блокирует строку оффера до конца транзакции и проверяет,
+// что его текущий статус входит в statuses. Открытый оффер с прошедшим сроком, до которого
+// ещё не добрался воркер истечения, считается истёкшим: ответить на него уже нельзя.
+// Срок сравнивается с часами приложения, как и при создании оффера и в воркере истечения.
+func offerStatusIn(ctx context.Context, offerID uint, tx *sqlx.Tx, statuses ...string) (bool, error) {
+	getOfferStatusQuery, args := squirrel.Select("offers.status").
+		Column(squirrel.Expr("offers.expires_at <= ? as expired", time.Now())).
 		From("offers").
 		Where(squirrel.Eq{"offers.id": offerID}).
+		Suffix("for update").
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
-	var ok bool
-	err := tx.QueryRowxContext(ctx, getOfferStatusQuery, args...).Scan(&ok)
+	var (
+		status  string
+		expired bool
+	)
+	err := tx.QueryRowxContext(ctx, getOfferStatusQuery, args...).Scan(&status, &expired)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return apperror.ErrOfferNotFound
+			return false, apperror.ErrOfferNotFound
 		}
-		return apperror.New(apperror.InternalError, "error scanning offer status", err)
+		return false, apperror.New(apperror.InternalError, "error scanning offer status", err)
 	}
 
-	if !ok {
-		return apperror.New(apperror.Conflict, "offer is not in a pending status", nil)
+	if expired && slices.Contains(openOfferStatuses, status) {
+		return false, apperror.ErrOfferExpired
+	}
+
+	return slices.Contains(statuses, status), nil
+}
+
+// CounterOffer сохраняет очередной раунд торга и обновляет текущую цену оффера.
+// Встречное предложение магазина переводит оффер в countered (ход покупателя),
+// встречное предложение покупателя возвращает его в pending (ход магазина).
+func (r *OfferRepository) CounterOffer(
+	ctx context.Context,
+	offerEntity entity.Offer,
+	userID uint,
+	isStore bool,
+) (entity.Offer, error) {
+	offer := model.ConvertOfferEntityToModel(offerEntity)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	proposedBy, newStatus := actorRoleBuyer, "pending"
+	ownerCondition := squirrel.Eq{"offers.id": offer.ID, "offers.user_id": userID}
+
+	if isStore {
+		err = isPendingOffer(ctx, offer.ID, tx)
+		if err != nil {
+			return entity.Offer{}, err
+		}
+
+		err = isUserShopOwner(ctx, offer.ID, userID, tx)
+		if err != nil {
+			return entity.Offer{}, err
+		}
+
+		err = isNotGuestOffer(ctx, offer.ID, tx)
+		if err != nil {
+			return entity.Offer{}, err
+		}
+
+		proposedBy, newStatus = actorRoleShop, "countered"
+		ownerCondition = squirrel.Eq{"offers.id": offer.ID}
+	} else {
+		err = isOfferBuyer(ctx, offer.ID, userID, tx)
+		if err != nil {
+			return entity.Offer{}, err
+		}
+
+		err = isCounteredOffer(ctx, offer.ID, tx)
+		if err != nil {
+			return entity.Offer{}, err
+		}
+	}
+
+	counterOfferQuery, args := squirrel.Update("offers").
+		Set("offer_price", offer.Price).
+		Set("status", newStatus).
+		Set("updated_at", time.Now()).
+		From("offers prev").
+		Where("prev.id = offers.id").
+		Where(ownerCondition).
+		Suffix("returning offers.id, offers.offer_price, offers.currency, offers.status, " +
+			"offers.created_at, offers.updated_at, offers.expires_at, " +
+			"offers.shop_id, offers.user_id, offers.product_id, " +
+			"prev.status as previous_status, prev.offer_price as previous_price").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerResp model.OfferStatusChange
+	err = tx.QueryRowxContext(ctx, counterOfferQuery, args...).StructScan(&offerResp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Offer{}, apperror.New(apperror.Unauthorized,
+				"unauthorized to update offer status", nil)
+		}
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error scanning into struct", err)
+	}
+
+	err = insertOfferRound(ctx, tx, offerResp, proposedBy)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	err = insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:    offerResp.ID,
+		EventType:  offerEventCountered,
+		FromStatus: &offerResp.PreviousStatus,
+		ToStatus:   offerResp.Status,
+		Price:      offerResp.Price,
+		ActorID:    &userID,
+		ActorRole:  proposedBy,
+	})
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return offerResp.ConvertToEntity(), nil
+}
+
+// insertOfferRound сохраняет очередной раунд торга по только что обновлённому офферу
+func insertOfferRound(ctx context.Context, tx *sqlx.Tx, change model.OfferStatusChange, proposedBy string) error {
+	insertRoundQuery, args := squirrel.Insert("offer_rounds").
+		Columns("offer_id", "round", "proposed_by", "price", "previous_price", "currency").
+		Values(change.ID,
+			squirrel.Expr("(SELECT COALESCE(MAX(round), 0) + 1 FROM offer_rounds WHERE offer_id = ?)", change.ID),
+			proposedBy, change.Price, change.PreviousPrice, change.Currency).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, insertRoundQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error inserting offer round", err)
 	}
 
 	return nil
 }
 
+// applyAutoResponse применяет к новому офферу решение правила автоответа магазина.
+// Встречное предложение записывается раундом торга от имени магазина,
+// принятие сразу создаёт заказ, как и при ручном ответе.
+func applyAutoResponse(ctx context.Context, tx *sqlx.Tx, offerID uint, autoResponse entity.AutoResponse) error {
+	eventType := offerEventStatusChanged
+	updateOfferQuery := squirrel.Update("offers").
+		Set("status", autoResponse.Status).
+		Set("updated_at", time.Now())
+	if autoResponse.Status == statusCountered {
+		eventType = offerEventCountered
+		updateOfferQuery = updateOfferQuery.Set("offer_price", autoResponse.CounterPrice)
+	}
+
+	applyQuery, args := updateOfferQuery.
+		From("offers prev").
+		Where("prev.id = offers.id").
+		Where(squirrel.Eq{"offers.id": offerID}).
+		Suffix("returning offers.id, offers.offer_price, offers.currency, offers.status, " +
+			"prev.status as previous_status, prev.offer_price as previous_price").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerResp model.OfferStatusChange
+	err := tx.QueryRowxContext(ctx, applyQuery, args...).StructScan(&offerResp)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error applying auto-response", err)
+	}
+
+	if autoResponse.Status == statusCountered {
+		err = insertOfferRound(ctx, tx, offerResp, actorRoleShop)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:    offerResp.ID,
+		EventType:  eventType,
+		FromStatus: &offerResp.PreviousStatus,
+		ToStatus:   offerResp.Status,
+		Price:      offerResp.Price,
+		ActorRole:  actorRoleSystem,
+	})
+	if err != nil {
+		return err
+	}
+
+	if autoResponse.Status == statusAccepted {
+		return insertOrderFromOffer(ctx, tx, offerID)
+	}
+
+	return nil
+}
+
+// insertOfferEvent дописывает событие в журнал оффера в рамках транзакции, меняющей оффер,
+// и записывает его в outbox для рассылки подписчикам
+func insertOfferEvent(ctx context.Context, tx *sqlx.Tx, event model.OfferEvent) error {
+	insertEventQuery, args := squirrel.Insert("offer_events").
+		Columns("offer_id", "event_type", "from_status", "to_status", "price", "actor_id", "actor_role").
+		Values(event.OfferID, event.EventType, event.FromStatus, event.ToStatus,
+			event.Price, event.ActorID, event.ActorRole).
+		Suffix("returning id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var eventID uint
+	err := tx.QueryRowxContext(ctx, insertEventQuery, args...).Scan(&eventID)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error inserting offer event", err)
+	}
+
+	payload, err := json.Marshal(entity.OfferEventPayload{
+		Type:    offerUpdateType(event.EventType),
+		EventID: eventID,
+		Status:  event.ToStatus,
+		Price:   event.Price,
+	})
+	if err != nil {
+		return apperror.New(apperror.InternalError, "error encoding offer event", err)
+	}
+
+	return insertOutboxMessages(ctx, tx, entity.OutboxMessage{
+		Topic:       entity.OutboxTopicOfferEvent,
+		AggregateID: event.OfferID,
+		DedupeKey:   offerEventDedupeKey(eventID),
+		Payload:     payload,
+	})
+}
+
+// offerUpdateType тип события для подписчиков по типу события журнала: встречное
+// предложение для них - такая же смена статуса
+func offerUpdateType(eventType string) string {
+	switch eventType {
+	case offerEventCreated:
+		return entity.OfferUpdateCreated
+	case offerEventExpired:
+		return entity.OfferUpdateExpired
+	default:
+		return entity.OfferUpdateStatusChanged
+	}
+}
+
+// isOfferParticipant проверяет, что пользователь является покупателем по офферу
+// или владельцем магазина, которому оффер адресован. Для всех остальных оффер "не существует".
+func isOfferParticipant(ctx context.Context, offerID, userID uint, q sqlx.QueryerContext) error {
+	checkParticipantQuery, args := withShopOwner(squirrel.Select("count(*)").From("offers")).
+		Where(squirrel.Eq{"offers.id": offerID}).
+		Where(squirrel.Or{
+			squirrel.Eq{"offers.user_id": userID},
+			squirrel.Eq{"shops.user_id": userID},
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err := sqlx.GetContext(ctx, q, &count, checkParticipantQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error checking offer participant", err)
+	}
+
+	if count == 0 {
+		return apperror.ErrOfferNotFound
+	}
+
+	return nil
+}
+
+// SelectOfferEvents возвращает журнал изменений оффера в хронологическом порядке
+func (r *OfferRepository) SelectOfferEvents(
+	ctx context.Context,
+	offerID uint,
+	userID uint,
+) ([]entity.OfferEvent, error) {
+	err := isOfferParticipant(ctx, offerID, userID, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	selectEventsQuery, args := squirrel.Select("id", "offer_id", "event_type", "from_status", "to_status",
+		"price", "actor_id", "actor_role", "created_at").
+		From("offer_events").
+		Where(squirrel.Eq{"offer_id": offerID}).
+		OrderBy("created_at", "id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var eventModels []model.OfferEvent
+	err = r.db.SelectContext(ctx, &eventModels, selectEventsQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting offer events", err)
+	}
+
+	events := make([]entity.OfferEvent, len(eventModels))
+	for i, eventModel := range eventModels {
+		events[i] = eventModel.ConvertToEntity()
+	}
+
+	return events, nil
+}
+
+// DeleteOffer удаляет оффер вместе с его журналом. Внешний ключ offer_events запрещает
+// удалять оффер с историей напрямую, чтобы журнал не пропадал незаметно, поэтому это
+// единственный способ удаления: журнал и оффер удаляются в одной транзакции.
+// Оффер, по которому создан заказ, не удаляется (Conflict) - его закрывают статусом.
 func (r *OfferRepository) DeleteOffer(
 	ctx context.Context,
 	offerID uint,
 ) (entity.Offer, error) {
-	var offer entity.Offer
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var offer model.Offer
+	selectOfferQuery, args := squirrel.Select("id, offer_price, currency, status, " +
+		"created_at, updated_at, expires_at, shop_id, product_id, user_id").
+		From("offers").
+		Where(squirrel.Eq{"id": offerID}).
+		Suffix("for update").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+	err = tx.GetContext(ctx, &offer, selectOfferQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Offer{}, apperror.ErrOfferNotFound
+		}
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error selecting offer", err)
+	}
 
-	_ = ctx
-	_ = offerID
+	var orders int
+	countOrdersQuery, args := squirrel.Select("COUNT(*)").
+		From("orders").
+		Where(squirrel.Eq{"offer_id": offerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+	err = tx.GetContext(ctx, &orders, countOrdersQuery, args...)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error counting offer orders", err)
+	}
+	if orders > 0 {
+		return entity.Offer{}, apperror.New(apperror.Conflict, "offer has an order and cannot be deleted", nil)
+	}
+
+	deleteEventsQuery, args := squirrel.Delete("offer_events").
+		Where(squirrel.Eq{"offer_id": offerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+	_, err = tx.ExecContext(ctx, deleteEventsQuery, args...)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error deleting offer events", err)
+	}
+
+	deleteOfferQuery, args := squirrel.Delete("offers").
+		Where(squirrel.Eq{"id": offerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+	_, err = tx.ExecContext(ctx, deleteOfferQuery, args...)
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error deleting offer", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
 
-	return offer, nil
+	return offer.ConvertToEntity(), nil
 }
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const offerMessageColumns = "id, offer_id, sender_id, sender_role, body, created_at, read_at"
+
+type OfferMessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewOfferMessageRepository(db *sqlx.DB) *OfferMessageRepository {
+	return &OfferMessageRepository{db: db}
+}
+
+// InsertMessage добавляет сообщение в переписку по офферу. Писать могут только покупатель
+// и владелец магазина и только пока оффер открыт: в pending или countered. Письмо второй стороне ставится в outbox,
+// если у неё ещё нет непрочитанных сообщений от отправителя, чтобы не слать письмо на каждую реплику.
+func (r *OfferMessageRepository) InsertMessage(
+	ctx context.Context,
+	message entity.OfferMessage,
+) (entity.OfferMessage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.OfferMessage{}, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isOfferParticipant(ctx, message.OfferID, message.SenderID, tx)
+	if err != nil {
+		return entity.OfferMessage{}, err
+	}
+
+	// у гостя нет аккаунта, переписываться с ним магазину не с кем
+	err = isNotGuestOffer(ctx, message.OfferID, tx)
+	if err != nil {
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) && appErr.Code() == apperror.Conflict {
+			return entity.OfferMessage{}, apperror.New(apperror.Conflict, "guest offers have no message thread", nil)
+		}
+		return entity.OfferMessage{}, err
+	}
+
+	open, err := offerStatusIn(ctx, message.OfferID, tx, openOfferStatuses...)
+	if err != nil {
+		return entity.OfferMessage{}, err
+	}
+	if !open {
+		return entity.OfferMessage{}, apperror.New(apperror.Conflict,
+			"message thread is read-only once the offer is closed", nil)
+	}
+
+	countUnreadQuery, args := squirrel.Select("count(*)").
+		From("offer_messages").
+		Where(squirrel.Eq{
+			"offer_id":  message.OfferID,
+			"sender_id": message.SenderID,
+			"read_at":   nil,
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var unread int
+	err = tx.GetContext(ctx, &unread, countUnreadQuery, args...)
+	if err != nil {
+		return entity.OfferMessage{}, apperror.New(apperror.DatabaseError, "error counting unread messages", err)
+	}
+
+	insertMessageQuery, args := squirrel.Insert("offer_messages").
+		Columns("offer_id", "sender_id", "sender_role", "body").
+		Select(squirrel.Select().
+			Column("offers.id").
+			Column("?::int", message.SenderID).
+			Column(squirrel.Expr("case when offers.user_id = ? then ?::text else ?::text end",
+				message.SenderID, actorRoleBuyer, actorRoleShop)).
+			Column("?::text", message.Body).
+			From("offers").
+			Where(squirrel.Eq{"offers.id": message.OfferID})).
+		Suffix("returning " + offerMessageColumns).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var messageModel model.OfferMessage
+	err = tx.QueryRowxContext(ctx, insertMessageQuery, args...).StructScan(&messageModel)
+	if err != nil {
+		return entity.OfferMessage{}, apperror.New(apperror.DatabaseError, "error inserting offer message", err)
+	}
+
+	if unread == 0 {
+		err = insertOfferMessageEmail(ctx, tx, message.OfferID, message.SenderID)
+		if err != nil {
+			return entity.OfferMessage{}, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.OfferMessage{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return messageModel.ConvertToEntity(), nil
+}
+
+// insertOfferMessageEmail ставит в outbox письмо о новом сообщении второй стороне переписки:
+// покупателю, если пишет владелец магазина, и владельцу магазина, если пишет покупатель
+func insertOfferMessageEmail(ctx context.Context, tx *sqlx.Tx, offerID, senderID uint) error {
+	insertEmailQuery, args := squirrel.Insert("outbox").
+		Columns("topic", "aggregate_id", "payload").
+		Select(withShopOwner(squirrel.Select().
+			Column(squirrel.Expr("?::text", entity.OutboxTopicEmail)).
+			Column("offers.id").
+			Column(squirrel.Expr("json_build_object('kind', ?::text, 'to', users.email)::jsonb",
+				entity.EmailOfferMessage)).
+			From("offers")).
+			InnerJoin("users on users.id = case when offers.user_id = ? then shops.user_id "+
+				"else offers.user_id end", senderID).
+			Where(squirrel.Eq{"offers.id": offerID})).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, insertEmailQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error writing offer message email", err)
+	}
+
+	return nil
+}
+
+// SelectMessages возвращает страницу переписки по офферу в хронологическом порядке и помечает
+// прочитанными сообщения второй стороны из этой страницы: сообщения на других страницах
+// пользователь ещё не видел. unread - сколько сообщений страницы было непрочитано до этого,
+// в самой странице у них ещё нет read_at, так что клиент может выделить новые.
+func (r *OfferMessageRepository) SelectMessages(
+	ctx context.Context,
+	offerID, userID uint,
+	limit, offset int,
+) (messages []entity.OfferMessage, total, unread int, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, 0, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isOfferParticipant(ctx, offerID, userID, tx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	selectMessagesQuery, args := squirrel.Select(offerMessageColumns).
+		Column("COUNT (*) OVER() as total_count").
+		From("offer_messages").
+		Where(squirrel.Eq{"offer_id": offerID}).
+		OrderBy("created_at", "id").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	messagesWithCount := make([]model.OfferMessageWithCount, 0, limit)
+	err = tx.SelectContext(ctx, &messagesWithCount, selectMessagesQuery, args...)
+	if err != nil {
+		return nil, 0, 0, apperror.New(apperror.DatabaseError, "error selecting offer messages", err)
+	}
+
+	pageIDs := make([]uint, len(messagesWithCount))
+	for i, messageModel := range messagesWithCount {
+		pageIDs[i] = messageModel.ID
+	}
+
+	var marked int64
+	if len(pageIDs) > 0 {
+		markReadQuery, args := squirrel.Update("offer_messages").
+			Set("read_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+			Where(squirrel.Eq{"id": pageIDs, "read_at": nil}).
+			Where(squirrel.NotEq{"sender_id": userID}).
+			PlaceholderFormat(squirrel.Dollar).
+			MustSql()
+
+		res, err := tx.ExecContext(ctx, markReadQuery, args...)
+		if err != nil {
+			return nil, 0, 0, apperror.New(apperror.DatabaseError, "error marking offer messages as read", err)
+		}
+		marked, err = res.RowsAffected()
+		if err != nil {
+			return nil, 0, 0, apperror.New(apperror.DatabaseError, "error marking offer messages as read", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, 0, 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	messages = make([]entity.OfferMessage, len(messagesWithCount))
+	for i, messageModel := range messagesWithCount {
+		messages[i] = messageModel.ConvertToEntity()
+	}
+	if len(messagesWithCount) > 0 {
+		total = messagesWithCount[0].TotalCount
+	}
+
+	return messages, total, int(marked), nil
+}
+
+// SelectUnreadCounts возвращает число непрочитанных пользователем сообщений по каждому офферу,
+// где он покупатель или владелец магазина. Офферы без непрочитанных сообщений не попадают в список.
+func (r *OfferMessageRepository) SelectUnreadCounts(ctx context.Context, userID uint) ([]entity.OfferUnread, error) {
+	selectUnreadQuery, args := withShopOwner(squirrel.Select("offer_messages.offer_id", "count(*) as unread").
+		From("offer_messages").
+		InnerJoin("offers on offers.id = offer_messages.offer_id")).
+		Where(squirrel.Eq{"offer_messages.read_at": nil}).
+		Where(squirrel.NotEq{"offer_messages.sender_id": userID}).
+		Where(squirrel.Or{
+			squirrel.Eq{"offers.user_id": userID},
+			squirrel.Eq{"shops.user_id": userID},
+		}).
+		GroupBy("offer_messages.offer_id").
+		OrderBy("offer_messages.offer_id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var unreadModels []model.OfferUnread
+	err := r.db.SelectContext(ctx, &unreadModels, selectUnreadQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting unread messages", err)
+	}
+
+	unread := make([]entity.OfferUnread, len(unreadModels))
+	for i, unreadModel := range unreadModels {
+		unread[i] = unreadModel.ConvertToEntity()
+	}
+
+	return unread, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// канал LISTEN/NOTIFY, через который экземпляры приложения обмениваются событиями офферов
+const offerUpdatesChannel = "offer_updates"
+
+// время ожидания перед повторным подключением слушателя после обрыва соединения
+const listenerReconnectDelay = 5 * time.Second
+
+// SelectOfferUpdates возвращает текущее состояние офферов вместе с владельцами магазинов
+// для рассылки событий. Тип и время события заполняет вызывающий.
+// У оффера гостя покупателя нет, его BuyerID равен нулю.
+func (r *OfferRepository) SelectOfferUpdates(ctx context.Context, offerIDs []uint) ([]entity.OfferUpdate, error) {
+	selectQuery, args := squirrel.Select("offers.id as offer_id", "offers.status", "offers.offer_price",
+		"offers.currency", "coalesce(offers.user_id, 0) as buyer_id", "offers.shop_id",
+		"shops.user_id as shop_owner_id").
+		From("offers").
+		InnerJoin("shops on shops.id = offers.shop_id").
+		Where(squirrel.Eq{"offers.id": offerIDs}).
+		OrderBy("offers.id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var updateModels []model.OfferUpdate
+
+	err := r.db.SelectContext(ctx, &updateModels, selectQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting offer updates", err)
+	}
+
+	updates := make([]entity.OfferUpdate, len(updateModels))
+	for i, u := range updateModels {
+		updates[i] = u.ConvertToEntity()
+	}
+
+	return updates, nil
+}
+
+// OfferUpdateNotifier рассылает события офферов всем экземплярам приложения через NOTIFY
+type OfferUpdateNotifier struct {
+	db *sqlx.DB
+}
+
+func NewOfferUpdateNotifier(db *sqlx.DB) *OfferUpdateNotifier {
+	return &OfferUpdateNotifier{db: db}
+}
+
+func (n *OfferUpdateNotifier) Broadcast(ctx context.Context, update entity.OfferUpdate) error {
+	payload, err := json.Marshal(model.ConvertOfferUpdateEntityToModel(update))
+	if err != nil {
+		return apperror.New(apperror.InternalError, "error encoding offer update", err)
+	}
+
+	_, err = n.db.ExecContext(ctx, "select pg_notify($1, $2)", offerUpdatesChannel, string(payload))
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error sending offer update notification", err)
+	}
+
+	return nil
+}
+
+type OfferUpdateDispatcher interface {
+	Dispatch(update entity.OfferUpdate)
+}
+
+// OfferUpdateListener держит отдельное соединение с LISTEN на канале событий офферов
+// и передаёт полученные события локальным подписчикам. При обрыве соединения
+// переподключается, события за время переподключения теряются.
+type OfferUpdateListener struct {
+	connString string
+	dispatcher OfferUpdateDispatcher
+	log        *zap.Logger
+
+	ctx     context.Context
+	ctxCanc context.CancelFunc
+	done    chan struct{}
+}
+
+func NewOfferUpdateListener(
+	connString string,
+	dispatcher OfferUpdateDispatcher,
+	log *zap.Logger,
+) *OfferUpdateListener {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OfferUpdateListener{
+		connString: connString,
+		dispatcher: dispatcher,
+		log:        log,
+		ctx:        ctx,
+		ctxCanc:    cancel,
+		done:       make(chan struct{}),
+	}
+}
+
+// Start запускает слушателя в отдельной горутине
+func (l *OfferUpdateListener) Start() {
+	l.log.Info("starting offer update listener", zap.String("channel", offerUpdatesChannel))
+
+	go func() {
+		defer close(l.done)
+
+		for {
+			err := l.listen(l.ctx)
+			if l.ctx.Err() != nil {
+				return
+			}
+			l.log.Error("offer update listener disconnected, reconnecting", zap.Error(err))
+
+			select {
+			case <-l.ctx.Done():
+				return
+			case <-time.After(listenerReconnectDelay):
+			}
+		}
+	}()
+}
+
+// Stop закрывает соединение слушателя, дожидаясь его остановки, но не дольше ctx
+func (l *OfferUpdateListener) Stop(ctx context.Context) {
+	l.log.Info("offer update listener is stopping")
+	l.ctxCanc()
+
+	select {
+	case <-ctx.Done():
+		l.log.Info("offer update listener forcefully stopped (timeout)")
+	case <-l.done:
+		l.log.Info("offer update listener stopped")
+	}
+}
+
+func (l *OfferUpdateListener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.connString)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	_, err = conn.Exec(ctx, "listen "+pgx.Identifier{offerUpdatesChannel}.Sanitize())
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var update model.OfferUpdate
+		if err := json.Unmarshal([]byte(notification.Payload), &update); err != nil {
+			l.log.Error("malformed offer update notification", zap.Error(err))
+			continue
+		}
+
+		l.dispatcher.Dispatch(update.ConvertToEntity())
+	}
+}
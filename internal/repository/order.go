@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var orderColumns = []string{"orders.id", "orders.offer_id", "orders.user_id", "orders.shop_id",
+	"orders.product_id", "orders.price", "orders.currency", "orders.status", "orders.created_at",
+	"orders.updated_at"}
+
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderRepository(db *sqlx.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// insertOrderFromOffer создаёт заказ по принятому офферу в транзакции, которая его принимает.
+// Цена и валюта берутся из оффера и дальше не меняются. По офферу гостя заказ
+// не создаётся, пока гость не зарегистрируется: до этого покупателя у оффера нет.
+func insertOrderFromOffer(ctx context.Context, tx *sqlx.Tx, offerID uint) error {
+	insertOrderQuery, args := squirrel.Insert("orders").
+		Columns("offer_id", "user_id", "shop_id", "product_id", "price", "currency").
+		Select(squirrel.Select("id", "user_id", "shop_id", "product_id", "offer_price", "currency").
+			From("offers").
+			Where(squirrel.Eq{"id": offerID}).
+			Where(squirrel.NotEq{"user_id": nil})).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, insertOrderQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error creating order from offer", err)
+	}
+
+	return nil
+}
+
+// withOrderShopOwner присоединяет к выборке заказов магазин и его владельца (shops.user_id)
+func withOrderShopOwner(query squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return query.InnerJoin("shops on shops.id = orders.shop_id")
+}
+
+// orderParticipant условие видимости заказа: покупатель или владелец магазина
+func orderParticipant(userID uint) squirrel.Or {
+	return squirrel.Or{
+		squirrel.Eq{"orders.user_id": userID},
+		squirrel.Eq{"shops.user_id": userID},
+	}
+}
+
+// GetOrderByID возвращает заказ покупателю или владельцу магазина, остальным ErrOrderNotFound
+func (r *OrderRepository) GetOrderByID(ctx context.Context, orderID, userID uint) (entity.Order, error) {
+	selectOrderQuery, args := withOrderShopOwner(squirrel.Select(orderColumns...).From("orders")).
+		Where(squirrel.Eq{"orders.id": orderID}).
+		Where(orderParticipant(userID)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var order model.Order
+	err := r.db.GetContext(ctx, &order, selectOrderQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Order{}, apperror.ErrOrderNotFound
+		}
+		return entity.Order{}, apperror.New(apperror.DatabaseError, "error selecting order", err)
+	}
+
+	return order.ConvertToEntity(), nil
+}
+
+// SelectUserOrders возвращает заказы покупателя
+func (r *OrderRepository) SelectUserOrders(
+	ctx context.Context,
+	userID uint,
+	limit, offset int,
+) ([]entity.Order, int, error) {
+	return r.selectOrders(ctx, squirrel.Eq{"orders.user_id": userID}, limit, offset)
+}
+
+// SelectShopOrders возвращает заказы по всем магазинам пользователя
+func (r *OrderRepository) SelectShopOrders(
+	ctx context.Context,
+	userID uint,
+	limit, offset int,
+) ([]entity.Order, int, error) {
+	return r.selectOrders(ctx, squirrel.Eq{"shops.user_id": userID}, limit, offset)
+}
+
+func (r *OrderRepository) selectOrders(
+	ctx context.Context,
+	condition squirrel.Sqlizer,
+	limit, offset int,
+) ([]entity.Order, int, error) {
+	selectOrdersQuery, args := withOrderShopOwner(squirrel.Select(orderColumns...).
+		Column("COUNT (*) OVER() as total_count").
+		From("orders")).
+		Where(condition).
+		OrderBy("orders.created_at desc", "orders.id desc").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	ordersWithCount := make([]model.OrderWithCount, 0, limit)
+
+	err := r.db.SelectContext(ctx, &ordersWithCount, selectOrdersQuery, args...)
+	if err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting orders", err)
+	}
+
+	if len(ordersWithCount) == 0 {
+		return []entity.Order{}, 0, nil
+	}
+
+	orders := make([]entity.Order, len(ordersWithCount))
+	for i, orderModel := range ordersWithCount {
+		orders[i] = orderModel.ConvertToEntity()
+	}
+
+	return orders, ordersWithCount[0].TotalCount, nil
+}
+
+// UpdateOrderStatus переводит заказ в статус status, если он сейчас в одном из fromStatuses.
+// Покупатель может менять только свои заказы, магазин - заказы своих магазинов.
+func (r *OrderRepository) UpdateOrderStatus(
+	ctx context.Context,
+	orderID, userID uint,
+	isStore bool,
+	status string,
+	fromStatuses []string,
+) (entity.Order, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var ownerCondition squirrel.Sqlizer = squirrel.Eq{"orders.user_id": userID}
+	if isStore {
+		ownerCondition = squirrel.Eq{"shops.user_id": userID}
+	}
+
+	selectOrderQuery, args := withOrderShopOwner(squirrel.Select("orders.status").From("orders")).
+		Where(squirrel.Eq{"orders.id": orderID}).
+		Where(ownerCondition).
+		Suffix("for update of orders").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var currentStatus string
+	err = tx.GetContext(ctx, &currentStatus, selectOrderQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Order{}, apperror.ErrOrderNotFound
+		}
+		return entity.Order{}, apperror.New(apperror.DatabaseError, "error selecting order", err)
+	}
+
+	if !slices.Contains(fromStatuses, currentStatus) {
+		return entity.Order{}, apperror.New(apperror.Conflict,
+			"order cannot be moved from "+currentStatus+" to "+status, nil)
+	}
+
+	updateOrderQuery, args := squirrel.Update("orders").
+		Set("status", status).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"id": orderID}).
+		Suffix("returning id, offer_id, user_id, shop_id, product_id, price, currency, status, " +
+			"created_at, updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var order model.Order
+	err = tx.QueryRowxContext(ctx, updateOrderQuery, args...).StructScan(&order)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.DatabaseError, "error updating order status", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return order.ConvertToEntity(), nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var outboxColumns = []string{"outbox.id", "outbox.topic", "outbox.aggregate_id", "outbox.dedupe_key",
+	"outbox.payload", "outbox.attempts", "array_to_string(outbox.done_sinks, ',') as done_sinks", "outbox.created_at"}
+
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// insertOutboxMessages записывает сообщения в outbox в транзакции бизнес-изменения.
+// Сообщение с уже записанным dedupe_key пропускается.
+func insertOutboxMessages(ctx context.Context, tx *sqlx.Tx, messages ...entity.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	insert := squirrel.Insert("outbox").
+		Columns("topic", "aggregate_id", "dedupe_key", "payload")
+	for _, m := range messages {
+		var dedupeKey *string
+		if m.DedupeKey != "" {
+			dedupeKey = &m.DedupeKey
+		}
+		insert = insert.Values(m.Topic, m.AggregateID, dedupeKey, squirrel.Expr("?::jsonb", string(m.Payload)))
+	}
+
+	insertQuery, args := insert.
+		Suffix("on conflict (dedupe_key) do nothing").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, insertQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error writing outbox messages", err)
+	}
+
+	return nil
+}
+
+// offerEventKeyPrefix префикс dedupe_key сообщений о событии из журнала оффера,
+// за ним следует ID события: одно сообщение на событие
+const offerEventKeyPrefix = "offer_event:"
+
+// offerEventPayloadJSON собирает entity.OfferEventPayload в SQL из строки offer_events,
+// вставленной в CTE logged; параметр - тип события
+const offerEventPayloadJSON = "json_build_object('type', ?::text, 'event_id', logged.id, " +
+	"'status', logged.to_status, 'price', logged.price)::jsonb"
+
+func offerEventDedupeKey(eventID uint) string {
+	return offerEventKeyPrefix + strconv.FormatUint(uint64(eventID), 10)
+}
+
+// ClaimDue берёт в обработку не более limit сообщений, время которых наступило.
+// Взятые сообщения откладываются до leaseUntil, чтобы другие экземпляры их не обработали
+// параллельно; если результат так и не будет записан, сообщение вернётся в работу.
+func (r *OutboxRepository) ClaimDue(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]entity.OutboxMessage, error) {
+	batchQuery, batchArgs := squirrel.Select("id").
+		From("outbox").
+		Where(squirrel.Eq{"status": entity.OutboxPending}).
+		Where(squirrel.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at", "id").
+		Limit(uint64(limit)).
+		Suffix("for update skip locked").
+		MustSql()
+
+	claimQuery, claimArgs := squirrel.Update("outbox").
+		Set("next_attempt_at", leaseUntil).
+		From("batch").
+		Where("outbox.id = batch.id").
+		Suffix("returning " + strings.Join(outboxColumns, ", ")).
+		MustSql()
+
+	args := append(batchArgs, claimArgs...)
+	selectQuery, args := squirrel.Select("*").
+		Prefix("with batch as ("+batchQuery+"), claimed as ("+claimQuery+")", args...).
+		From("claimed").
+		OrderBy("id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	messageModels := make([]model.OutboxMessage, 0, limit)
+	err := r.db.SelectContext(ctx, &messageModels, selectQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error claiming outbox messages", err)
+	}
+
+	messages := make([]entity.OutboxMessage, len(messageModels))
+	for i, m := range messageModels {
+		messages[i] = m.ConvertToEntity()
+	}
+
+	return messages, nil
+}
+
+// MarkSinkDone отмечает, что получатель sink обработал сообщение, чтобы при повторе
+// сообщение не было доставлено ему ещё раз
+func (r *OutboxRepository) MarkSinkDone(ctx context.Context, messageID uint, sink string) error {
+	markQuery, args := squirrel.Update("outbox").
+		Set("done_sinks", squirrel.Expr("array_append(done_sinks, ?::text)", sink)).
+		Where(squirrel.Eq{"id": messageID}).
+		Where(squirrel.Expr("not (?::text = any(done_sinks))", sink)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := r.db.ExecContext(ctx, markQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error marking outbox sink as done", err)
+	}
+
+	return nil
+}
+
+// SaveResult записывает итог обработки сообщения
+func (r *OutboxRepository) SaveResult(ctx context.Context, result entity.OutboxResult, now time.Time) error {
+	var (
+		lastError   *string
+		processedAt *time.Time
+	)
+	if result.Error != "" {
+		lastError = &result.Error
+	}
+	if result.Status == entity.OutboxProcessed {
+		processedAt = &now
+	}
+
+	saveResult := squirrel.Update("outbox").
+		Set("status", result.Status).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", lastError).
+		Set("processed_at", processedAt).
+		Where(squirrel.Eq{"id": result.MessageID})
+	if result.Status == entity.OutboxPending {
+		saveResult = saveResult.Set("next_attempt_at", result.NextAttemptAt)
+	}
+
+	saveResultQuery, args := saveResult.
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := r.db.ExecContext(ctx, saveResultQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error saving outbox message result", err)
+	}
+
+	return nil
+}
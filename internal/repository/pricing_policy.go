@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PricingPolicyRepository struct {
+	db *sqlx.DB
+}
+
+func NewPricingPolicyRepository(db *sqlx.DB) *PricingPolicyRepository {
+	return &PricingPolicyRepository{db: db}
+}
+
+// GetPriceListing возвращает цену позиции инвентаря вместе с минимумом из политики магазина
+func (r *PricingPolicyRepository) GetPriceListing(
+	ctx context.Context,
+	shopID, productID uint,
+) (entity.PriceListing, error) {
+	selectListingQuery, args := squirrel.Select(
+		"shop_inventory.price",
+		"shop_inventory.currency",
+		"shop_pricing_policies.min_price_percent",
+	).
+		From("shop_inventory").
+		LeftJoin("shop_pricing_policies on shop_pricing_policies.shop_id = shop_inventory.shop_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID, "shop_inventory.product_id": productID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var listingModel model.PriceListing
+	err := r.db.GetContext(ctx, &listingModel, selectListingQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PriceListing{}, apperror.ErrProductNotFound
+		}
+		return entity.PriceListing{}, apperror.New(apperror.DatabaseError, "error selecting inventory price", err)
+	}
+
+	return listingModel.ConvertToEntity(), nil
+}
+
+// SelectPricingPolicy возвращает политику магазина, принадлежащего пользователю.
+// false, если магазин не задавал свою политику.
+func (r *PricingPolicyRepository) SelectPricingPolicy(
+	ctx context.Context,
+	shopID, userID uint,
+) (entity.PricingPolicy, bool, error) {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return entity.PricingPolicy{}, false, err
+	}
+
+	selectPolicyQuery, args := squirrel.Select("shop_id", "min_price_percent", "updated_at").
+		From("shop_pricing_policies").
+		Where(squirrel.Eq{"shop_id": shopID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var policyModel model.PricingPolicy
+	err = r.db.GetContext(ctx, &policyModel, selectPolicyQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PricingPolicy{}, false, nil
+		}
+		return entity.PricingPolicy{}, false, apperror.New(apperror.DatabaseError,
+			"error selecting pricing policy", err)
+	}
+
+	return policyModel.ConvertToEntity(), true, nil
+}
+
+// UpsertPricingPolicy создаёт или заменяет политику магазина, принадлежащего пользователю
+func (r *PricingPolicyRepository) UpsertPricingPolicy(
+	ctx context.Context,
+	policy entity.PricingPolicy,
+	userID uint,
+) (entity.PricingPolicy, error) {
+	err := isShopOwner(ctx, policy.ShopID, userID, r.db)
+	if err != nil {
+		return entity.PricingPolicy{}, err
+	}
+
+	upsertPolicyQuery, args := squirrel.Insert("shop_pricing_policies").
+		Columns("shop_id", "min_price_percent", "updated_at").
+		Values(policy.ShopID, policy.MinPricePercent, time.Now()).
+		Suffix("on conflict (shop_id) do update set " +
+			"min_price_percent = excluded.min_price_percent, updated_at = excluded.updated_at " +
+			"returning shop_id, min_price_percent, updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var policyModel model.PricingPolicy
+	err = r.db.QueryRowxContext(ctx, upsertPolicyQuery, args...).StructScan(&policyModel)
+	if err != nil {
+		return entity.PricingPolicy{}, apperror.New(apperror.DatabaseError, "error saving pricing policy", err)
+	}
+
+	return policyModel.ConvertToEntity(), nil
+}
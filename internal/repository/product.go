@@ -5,8 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
+	"html"
 	"strconv"
+	"strings"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 
@@ -21,6 +22,21 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
+// displayPrice цена позиции инвентаря si в валюте отображения, NULL, если курса нет.
+// Цена в той же валюте не пересчитывается. Требует соединений из withDisplayPrice.
+const displayPrice = "CASE WHEN upper(si.currency) = display.currency THEN si.price " +
+	"ELSE si.price / src.rate * dst.rate END"
+
+// withDisplayPrice добавляет к запросу по shop_inventory si валюту отображения и курсы для displayPrice
+func withDisplayPrice(b sq.SelectBuilder, currency string) sq.SelectBuilder {
+	return b.
+		Join("(SELECT CAST(? AS CHAR(3)) AS currency) display ON true", strings.ToUpper(currency)).
+		LeftJoin("currency_rates src ON src.currency = upper(si.currency)").
+		LeftJoin("currency_rates dst ON dst.currency = display.currency")
+}
+
+var productColumns = []string{"id", "name", "description", "category_id", "shop_id", "archived_at"}
+
 type ProductRepository struct {
 	Db *sqlx.DB
 }
@@ -37,7 +53,7 @@ func (r *ProductRepository) GetProductByID(
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	queryBuilder := psql.
-		Select("id", "name", "description", "category_id").
+		Select(productColumns...).
 		From("products").
 		Where(sq.Eq{"id": id})
 
@@ -61,32 +77,39 @@ func (r *ProductRepository) GetFilteredProducts(
 	ctx context.Context,
 	filter model.ProductFilter,
 	limit, offset int) ([]entity.Product, error) {
-	args := []interface{}{}
-	categoryID := 0
-	if filter.CategoryID != nil {
-		categoryID = *filter.CategoryID
+	columns := make([]string, len(productColumns))
+	for i, column := range productColumns {
+		columns[i] = "p." + column
 	}
-	args = append(args, categoryID)
+	columns[0] = "DISTINCT ON (p.id) " + columns[0]
 
 	selectBuilder := sq.StatementBuilder.
 		PlaceholderFormat(sq.Dollar).
-		Select("DISTINCT ON (p.id) p.*").
+		Select(columns...).
 		From("products p").
 		LeftJoin("shop_inventory si ON si.product_id = p.id").
+		Where("p.archived_at IS NULL").
 		OrderBy("p.id")
 
 	if filter.CategoryID != nil {
-		selectBuilder = selectBuilder.Where("p.category_id IN (SELECT id FROM subcategories)")
+		selectBuilder = withCategorySubtree(selectBuilder, *filter.CategoryID)
+	}
+	if filter.Query != nil {
+		selectBuilder = withSearch(selectBuilder, *filter.Query).
+			Columns("ts_rank(p.search_vector, search.query) AS search_rank", "search.query AS search_query")
 	}
 
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		selectBuilder = withDisplayPrice(selectBuilder, filter.Currency)
+	}
 	if filter.MinPrice != nil {
 		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) >= ?", *filter.MinPrice),
+			sq.Expr("CAST("+displayPrice+" * 100 AS BIGINT) >= ?", *filter.MinPrice),
 		)
 	}
 	if filter.MaxPrice != nil {
 		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) <= ?", *filter.MaxPrice),
+			sq.Expr("CAST("+displayPrice+" * 100 AS BIGINT) <= ?", *filter.MaxPrice),
 		)
 	}
 	if filter.ShopID != nil {
@@ -105,29 +128,23 @@ func (r *ProductRepository) GetFilteredProducts(
 		}
 	}
 
-	selectSQL, queryArgs, err := selectBuilder.ToSql()
+	if filter.Query != nil {
+		selectBuilder = rankedPage(selectBuilder, limit, offset)
+	}
+
+	selectSQL, args, err := selectBuilder.ToSql()
 	if err != nil {
 		fmt.Println("Ошибка в билде запроса")
 		return nil, apperror.New(apperror.DatabaseError, "failed to build SQL", err)
 	}
 
-	selectSQL = shiftPlaceholders(selectSQL, 1)
-
-	recursivePart := `
-		WITH RECURSIVE subcategories AS (
-			SELECT id FROM categories WHERE id = $1
-			UNION ALL
-			SELECT c.id FROM categories c
-			JOIN subcategories sc ON c.parent_id = sc.id
-		)
-	`
-
-	limitStr := fmt.Sprintf(" LIMIT %d ", limit)
-	offsetStr := fmt.Sprintf("OFFSET %d", offset)
-
-	fullSQL := recursivePart + selectSQL + limitStr + offsetStr
+	fullSQL := selectSQL
+	if filter.Query == nil {
+		limitStr := fmt.Sprintf(" LIMIT %d ", limit)
+		offsetStr := fmt.Sprintf("OFFSET %d", offset)
 
-	args = append(args, queryArgs...)
+		fullSQL += limitStr + offsetStr
+	}
 
 	var productModels []model.Product
 	err = r.Db.SelectContext(ctx, &productModels, fullSQL, args...)
@@ -139,6 +156,10 @@ func (r *ProductRepository) GetFilteredProducts(
 	products := make([]entity.Product, len(productModels))
 	for i, pm := range productModels {
 		products[i] = model.ConvertProductToEntity(pm)
+		if pm.Snippet != nil {
+			snippet := highlightSnippet(*pm.Snippet)
+			products[i].Snippet = &snippet
+		}
 	}
 
 	return products, nil
@@ -146,31 +167,31 @@ func (r *ProductRepository) GetFilteredProducts(
 
 func (r *ProductRepository) GetFilteredProductsCount(ctx context.Context,
 	filter model.ProductFilter) (int, error) {
-	args := []interface{}{}
-	categoryID := 0
-	if filter.CategoryID != nil {
-		categoryID = *filter.CategoryID
-	}
-	args = append(args, categoryID)
-
 	selectBuilder := sq.StatementBuilder.
 		PlaceholderFormat(sq.Dollar).
 		Select("COUNT(DISTINCT p.id)").
 		From("products p").
-		LeftJoin("shop_inventory si ON si.product_id = p.id")
+		LeftJoin("shop_inventory si ON si.product_id = p.id").
+		Where("p.archived_at IS NULL")
 
 	if filter.CategoryID != nil {
-		selectBuilder = selectBuilder.Where("p.category_id IN (SELECT id FROM subcategories)")
+		selectBuilder = withCategorySubtree(selectBuilder, *filter.CategoryID)
+	}
+	if filter.Query != nil {
+		selectBuilder = withSearch(selectBuilder, *filter.Query)
 	}
 
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		selectBuilder = withDisplayPrice(selectBuilder, filter.Currency)
+	}
 	if filter.MinPrice != nil {
 		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) >= ?", *filter.MinPrice),
+			sq.Expr("CAST("+displayPrice+" * 100 AS BIGINT) >= ?", *filter.MinPrice),
 		)
 	}
 	if filter.MaxPrice != nil {
 		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) <= ?", *filter.MaxPrice),
+			sq.Expr("CAST("+displayPrice+" * 100 AS BIGINT) <= ?", *filter.MaxPrice),
 		)
 	}
 	if filter.ShopID != nil {
@@ -189,27 +210,12 @@ func (r *ProductRepository) GetFilteredProductsCount(ctx context.Context,
 		}
 	}
 
-	selectSQL, queryArgs, err := selectBuilder.ToSql()
+	fullSQL, args, err := selectBuilder.ToSql()
 	if err != nil {
 		fmt.Println("Ошибка в билде запроса")
 		return 0, apperror.New(apperror.DatabaseError, "failed to build SQL", err)
 	}
 
-	selectSQL = shiftPlaceholders(selectSQL, 1)
-
-	recursivePart := `
-		WITH RECURSIVE subcategories AS (
-			SELECT id FROM categories WHERE id = $1
-			UNION ALL
-			SELECT c.id FROM categories c
-			JOIN subcategories sc ON c.parent_id = sc.id
-		)
-	`
-
-	fullSQL := recursivePart + selectSQL
-
-	args = append(args, queryArgs...)
-
 	var count int
 	err = r.Db.GetContext(ctx, &count, fullSQL, args...)
 	if err != nil {
@@ -254,9 +260,10 @@ func (r *ProductRepository) GetAttributesByID(ctx context.Context,
 	return attributes, nil
 }
 
-// GetPriceRangeByProductID получает минимальную и максимальную цену на продукт
+// GetPriceRangeByProductID получает минимальную и максимальную цену на продукт в валюте отображения.
+// Предложения в валютах без курса не учитываются.
 func (r *ProductRepository) GetPriceRangeByProductID(ctx context.Context,
-	productID int) (int, int, error) {
+	productID int, currency string) (int, int, error) {
 	var priceRange struct {
 		Min sql.NullInt64 `db:"min"`
 		Max sql.NullInt64 `db:"max"`
@@ -264,13 +271,13 @@ func (r *ProductRepository) GetPriceRangeByProductID(ctx context.Context,
 
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	queryBuilder := psql.
+	queryBuilder := withDisplayPrice(psql.
 		Select(
-			"CAST(MIN(price) * 100 AS BIGINT) AS min",
-			"CAST(MAX(price) * 100 AS BIGINT) AS max",
+			"CAST(MIN("+displayPrice+") * 100 AS BIGINT) AS min",
+			"CAST(MAX("+displayPrice+") * 100 AS BIGINT) AS max",
 		).
-		From("shop_inventory").
-		Where(sq.Eq{"product_id": productID})
+		From("shop_inventory si"), currency).
+		Where(sq.Eq{"si.product_id": productID})
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -330,10 +337,374 @@ func (r *ProductRepository) GetAverageRatingByProductID(ctx context.Context,
 	return avg, count, nil
 }
 
-func shiftPlaceholders(sql string, offset int) string {
-	re := regexp.MustCompile(`\$(\d+)`)
-	return re.ReplaceAllStringFunc(sql, func(match string) string {
-		num, _ := strconv.Atoi(match[1:])
-		return "$" + strconv.Itoa(num+offset)
-	})
+// withCategorySubtree оставляет товары категории categoryID и всех её подкатегорий:
+// lft их категории лежит между lft и rgt категории categoryID
+func withCategorySubtree(selectBuilder sq.SelectBuilder, categoryID int) sq.SelectBuilder {
+	return selectBuilder.
+		Join("categories filter_category ON filter_category.id = ?", categoryID).
+		Join("categories pc ON pc.id = p.category_id " +
+			"AND pc.lft BETWEEN filter_category.lft AND filter_category.rgt")
+}
+
+// Маркеры подсветки в snippet из ts_headline. Управляющие символы не встречаются в тексте товаров,
+// поэтому после экранирования текста их можно заменить на теги.
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+var snippetHighlighter = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+// withSearch оставляет товары, подходящие под поисковый запрос, и добавляет в запрос search.query.
+// Запрос разбирается как в поисковиках (кавычки, or, минус) русской и английской конфигурациями.
+func withSearch(selectBuilder sq.SelectBuilder, query string) sq.SelectBuilder {
+	return selectBuilder.
+		Join("(SELECT websearch_to_tsquery('russian', ?) || websearch_to_tsquery('english', ?) AS query) search "+
+			"ON p.search_vector @@ search.query", query, query)
+}
+
+// rankedPage упорядочивает результаты поиска по релевантности и выбирает страницу. Snippet считается
+// только для товаров страницы: ts_headline заново разбирает текст и дорог на всей выборке.
+func rankedPage(selectBuilder sq.SelectBuilder, limit, offset int) sq.SelectBuilder {
+	page := sq.Select("*").
+		FromSelect(selectBuilder, "ranked").
+		OrderBy("search_rank DESC", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	return sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select(productColumns...).
+		Column("ts_headline('russian', concat_ws(' ', name, description), search_query, ?) AS snippet",
+			fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=2, MinWords=5, MaxWords=20`,
+				snippetStartSel, snippetStopSel)).
+		FromSelect(page, "page").
+		OrderBy("search_rank DESC", "id")
+}
+
+// highlightSnippet экранирует snippet для HTML и заменяет маркеры подсветки на <mark>
+func highlightSnippet(snippet string) string {
+	return snippetHighlighter.Replace(html.EscapeString(snippet))
+}
+
+// InsertProduct заводит товар магазина вместе с атрибутами.
+// Завести товар может только владелец магазина, категория должна существовать.
+func (r *ProductRepository) InsertProduct(
+	ctx context.Context,
+	shopID, userID uint,
+	product entity.Product,
+) (entity.Product, error) {
+	tx, err := r.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isShopOwner(ctx, shopID, userID, tx)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	err = categoryExists(ctx, product.CategoryID, tx)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	query, args := sq.Insert("products").
+		Columns("name", "description", "category_id", "shop_id").
+		Values(product.Name, product.Description, product.CategoryID, shopID).
+		Suffix("RETURNING " + strings.Join(productColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var productModel model.Product
+	if err := tx.GetContext(ctx, &productModel, query, args...); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to insert product", err)
+	}
+
+	created := model.ConvertProductToEntity(productModel)
+	created.Attributes = product.Attributes
+	if created.Attributes == nil {
+		created.Attributes = make(map[string]interface{})
+	}
+
+	err = upsertAttributes(ctx, created.ID, created.Attributes, tx)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return created, nil
+}
+
+// UpdateProduct меняет заданные поля товара магазина. Чужой товар "не существует",
+// архивный товар не меняется.
+func (r *ProductRepository) UpdateProduct(
+	ctx context.Context,
+	shopID, productID, userID uint,
+	update entity.ProductUpdate,
+) (entity.Product, error) {
+	tx, err := r.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	productModel, err := lockShopProduct(ctx, shopID, productID, userID, tx)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	if productModel.ArchivedAt != nil {
+		return entity.Product{}, apperror.New(apperror.Conflict, "archived products cannot be changed", nil)
+	}
+
+	if update.CategoryID != nil {
+		err = categoryExists(ctx, *update.CategoryID, tx)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	}
+
+	changes := sq.Eq{}
+	if update.Name != nil {
+		changes["name"] = *update.Name
+	}
+	if update.Description != nil {
+		changes["description"] = *update.Description
+	}
+	if update.CategoryID != nil {
+		changes["category_id"] = *update.CategoryID
+	}
+
+	if len(changes) > 0 {
+		query, args := sq.Update("products").
+			SetMap(changes).
+			Where(sq.Eq{"id": productID}).
+			Suffix("RETURNING " + strings.Join(productColumns, ", ")).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+
+		if err := tx.GetContext(ctx, &productModel, query, args...); err != nil {
+			return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to update product", err)
+		}
+	}
+
+	if update.Attributes != nil {
+		err = upsertAttributes(ctx, productModel.ID, update.Attributes, tx)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	updated := model.ConvertProductToEntity(productModel)
+	updated.Attributes, err = r.GetAttributesByID(ctx, strconv.Itoa(updated.ID))
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return updated, nil
+}
+
+// ArchiveProduct убирает товар магазина из каталога и снимает его с продажи в этом магазине.
+// Пока товар продают другие магазины, архивировать его нельзя: их офферы и ставки
+// решает не владелец товара. Открытые офферы магазина на товар отклоняются, заказы не затрагиваются.
+func (r *ProductRepository) ArchiveProduct(
+	ctx context.Context,
+	shopID, productID, userID uint,
+) (entity.Product, error) {
+	tx, err := r.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to start transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	productModel, err := lockShopProduct(ctx, shopID, productID, userID, tx)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	if productModel.ArchivedAt != nil {
+		return entity.Product{}, apperror.New(apperror.Conflict, "product is already archived", nil)
+	}
+
+	// позиции других магазинов меняются под блокировкой товара на чтение (см. lockItem),
+	// так что после блокировки товара на запись результат проверки не устареет
+	query, args := sq.Select("COUNT(*)").
+		From("shop_inventory").
+		Where(sq.Eq{"product_id": productID, "is_available": true}).
+		Where(sq.NotEq{"shop_id": shopID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var otherShops int
+	if err := tx.GetContext(ctx, &otherShops, query, args...); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to check product inventory", err)
+	}
+	if otherShops > 0 {
+		return entity.Product{}, apperror.New(apperror.Conflict, "product is still sold by other shops", nil)
+	}
+
+	query, args = sq.Update("products").
+		Set("archived_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": productID}).
+		Suffix("RETURNING " + strings.Join(productColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if err := tx.GetContext(ctx, &productModel, query, args...); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to archive product", err)
+	}
+
+	query, args = sq.Update("shop_inventory").
+		Set("is_available", false).
+		Where(sq.Eq{"product_id": productID, "shop_id": shopID, "is_available": true}).
+		Suffix("RETURNING shop_id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var withdrawnShopIDs []uint
+	if err := tx.SelectContext(ctx, &withdrawnShopIDs, query, args...); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to withdraw product from sale", err)
+	}
+
+	if len(withdrawnShopIDs) > 0 {
+		withdrawn := sq.Eq{"product_id": productID, "shop_id": withdrawnShopIDs}
+
+		err = insertInventoryHistory(ctx, tx, entity.InventoryUpdated, &userID, withdrawn)
+		if err != nil {
+			return entity.Product{}, err
+		}
+
+		// письма покупателям уйдут со следующим проходом outbox relay
+		_, err = declineOpenOffers(ctx, tx, withdrawn, &userID, actorRoleShop)
+		if err != nil {
+			return entity.Product{}, err
+		}
+
+		err = loseItemBids(ctx, tx, withdrawn)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Product{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return model.ConvertProductToEntity(productModel), nil
+}
+
+// GetShopProducts возвращает товары, которыми управляет магазин, включая архивные, новые первыми
+func (r *ProductRepository) GetShopProducts(
+	ctx context.Context,
+	shopID, userID uint,
+	limit, offset int,
+) ([]entity.Product, int, error) {
+	err := isShopOwner(ctx, shopID, userID, r.Db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := sq.Select(productColumns...).
+		Column("COUNT(*) OVER() AS total_count").
+		From("products").
+		Where(sq.Eq{"shop_id": shopID}).
+		OrderBy("id DESC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var productModels []model.ProductWithCount
+	if err := r.Db.SelectContext(ctx, &productModels, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "failed to fetch shop products", err)
+	}
+
+	if len(productModels) == 0 {
+		return []entity.Product{}, 0, nil
+	}
+
+	products := make([]entity.Product, len(productModels))
+	for i, pm := range productModels {
+		products[i] = model.ConvertProductToEntity(pm.Product)
+	}
+
+	return products, productModels[0].TotalCount, nil
+}
+
+// lockShopProduct блокирует товар магазина до конца транзакции. Менять товары магазина
+// может только его владелец, товары других магазинов и товары из сидов "не существуют".
+func lockShopProduct(ctx context.Context, shopID, productID, userID uint, tx *sqlx.Tx) (model.Product, error) {
+	err := isShopOwner(ctx, shopID, userID, tx)
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	query, args := sq.Select(productColumns...).
+		From("products").
+		Where(sq.Eq{"id": productID, "shop_id": shopID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var productModel model.Product
+	if err := tx.GetContext(ctx, &productModel, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Product{}, apperror.ErrProductNotFound
+		}
+		return model.Product{}, apperror.New(apperror.DatabaseError, "failed to fetch product", err)
+	}
+
+	return productModel, nil
+}
+
+func categoryExists(ctx context.Context, categoryID int, q sqlx.QueryerContext) error {
+	query, args := sq.Select("COUNT(*)").
+		From("categories").
+		Where(sq.Eq{"id": categoryID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var count int
+	if err := sqlx.GetContext(ctx, q, &count, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to check category", err)
+	}
+	if count == 0 {
+		return apperror.New(apperror.NotFound, "category not found", nil)
+	}
+
+	return nil
+}
+
+// upsertAttributes заменяет атрибуты товара целиком
+func upsertAttributes(ctx context.Context, productID int, attributes map[string]interface{}, tx *sqlx.Tx) error {
+	attributesJSON, err := json.Marshal(attributes)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to marshal product attributes", err)
+	}
+
+	query, args := sq.Insert("product_attributes").
+		Columns("product_id", "attributes").
+		Values(productID, sq.Expr("?::jsonb", string(attributesJSON))).
+		Suffix("ON CONFLICT (product_id) DO UPDATE SET attributes = EXCLUDED.attributes").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save product attributes", err)
+	}
+
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"regexp"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/EM-Stawberry/Stawberry/internal/repository"
@@ -86,17 +87,25 @@ var _ = Describe("ProductRepository", func() {
 	})
 
 	Describe("GetPriceRangeByProductID", func() {
+		// цены переводятся в валюту отображения по курсам из currency_rates
+		priceRangeQuery := regexp.QuoteMeta(`SELECT CAST(MIN(CASE WHEN upper(si.currency) = display.currency ` +
+			`THEN si.price ELSE si.price / src.rate * dst.rate END) * 100 AS BIGINT) AS min, ` +
+			`CAST(MAX(CASE WHEN upper(si.currency) = display.currency ` +
+			`THEN si.price ELSE si.price / src.rate * dst.rate END) * 100 AS BIGINT) AS max ` +
+			`FROM shop_inventory si JOIN (SELECT CAST($1 AS CHAR(3)) AS currency) display ON true ` +
+			`LEFT JOIN currency_rates src ON src.currency = upper(si.currency) ` +
+			`LEFT JOIN currency_rates dst ON dst.currency = display.currency WHERE si.product_id = $2`)
+
 		It("should return min and max price when data exists", func() {
 			productID := 123
 			rows := sqlmock.NewRows([]string{"min", "max"}).
 				AddRow(1005, 9909)
 
-			mock.ExpectQuery(`SELECT CAST\(MIN\(price\) \* 100 AS BIGINT\) AS min,` +
-				` CAST\(MAX\(price\) \* 100 AS BIGINT\) AS max FROM shop_inventory WHERE product_id = \$1`).
-				WithArgs(productID).
+			mock.ExpectQuery(priceRangeQuery).
+				WithArgs("USD", productID).
 				WillReturnRows(rows)
 
-			min, max, err := repo.GetPriceRangeByProductID(ctx, productID)
+			min, max, err := repo.GetPriceRangeByProductID(ctx, productID, "usd")
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(min).To(Equal(1005))
@@ -109,12 +118,11 @@ var _ = Describe("ProductRepository", func() {
 			rows := sqlmock.NewRows([]string{"min", "max"}).
 				AddRow(nil, nil)
 
-			mock.ExpectQuery(`SELECT CAST\(MIN\(price\) \* 100 AS BIGINT\) AS min,` +
-				` CAST\(MAX\(price\) \* 100 AS BIGINT\) AS max FROM shop_inventory WHERE product_id = \$1`).
-				WithArgs(productID).
+			mock.ExpectQuery(priceRangeQuery).
+				WithArgs("USD", productID).
 				WillReturnRows(rows)
 
-			min, max, err := repo.GetPriceRangeByProductID(ctx, productID)
+			min, max, err := repo.GetPriceRangeByProductID(ctx, productID, "usd")
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(min).To(Equal(0))
@@ -124,12 +132,11 @@ var _ = Describe("ProductRepository", func() {
 		It("should return error on query failure", func() {
 			productID := 123
 
-			mock.ExpectQuery(`SELECT CAST\(MIN\(price\) \* 100 AS BIGINT\) AS min,` +
-				` CAST\(MAX\(price\) \* 100 AS BIGINT\) AS max FROM shop_inventory WHERE product_id = \$1`).
-				WithArgs(productID).
+			mock.ExpectQuery(priceRangeQuery).
+				WithArgs("USD", productID).
 				WillReturnError(errors.New("some db error"))
 
-			min, max, err := repo.GetPriceRangeByProductID(ctx, productID)
+			min, max, err := repo.GetPriceRangeByProductID(ctx, productID, "usd")
 
 			Expect(err).To(HaveOccurred())
 			Expect(min).To(Equal(0))
@@ -23,11 +23,13 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// InsertUser вставляет пользователя в БД
+// InsertUser вставляет пользователя в БД. Если передан хеш токена из письма гостю,
+// офферы, отправленные с почты этого гостя, в той же транзакции переходят к новому
+// пользователю; возвращается их количество.
 func (r *UserRepository) InsertUser(
 	ctx context.Context,
 	user user.User,
-) (uint, error) {
+) (uint, int, error) {
 	userModel := model.ConvertUserFromSvc(user)
 
 	stmt := sq.Insert("users").
@@ -38,16 +40,37 @@ func (r *UserRepository) InsertUser(
 
 	query, args := stmt.MustSql()
 
-	err := r.db.QueryRowxContext(ctx, query, args...).Scan(&userModel.ID)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&userModel.ID)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr); pgErr.Code == pgerrcode.UniqueViolation {
-			return 0, apperror.New(apperror.DuplicateError, "user with this email already exists", err)
+			return 0, 0, apperror.New(apperror.DuplicateError, "user with this email already exists", err)
 		}
-		return 0, apperror.New(apperror.DuplicateError, "failed to create user", err)
+		return 0, 0, apperror.New(apperror.DuplicateError, "failed to create user", err)
+	}
+
+	claimed := 0
+	if user.GuestToken != "" {
+		claimed, err = claimGuestOffers(ctx, tx, userModel.ID, user.GuestToken)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
 	}
 
-	return userModel.ID, nil
+	return userModel.ID, claimed, nil
 }
 
 // GetUser получает пользователя по почте
@@ -57,7 +80,7 @@ func (r *UserRepository) GetUser(
 ) (entity.User, error) {
 	var userModel model.User
 
-	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store").
+	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store", "is_admin").
 		From("users").
 		Where(sq.Eq{"email": email}).
 		PlaceholderFormat(sq.Dollar)
@@ -82,7 +105,7 @@ func (r *UserRepository) GetUserByID(
 ) (entity.User, error) {
 	var userModel model.User
 
-	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store").
+	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store", "is_admin").
 		From("users").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar)
@@ -0,0 +1,644 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	wantedRequestColumns = []string{"wanted_requests.id", "wanted_requests.user_id", "wanted_requests.product_id",
+		"wanted_requests.category_id", "wanted_requests.target_price", "wanted_requests.currency",
+		"wanted_requests.status", "wanted_requests.deadline", "wanted_requests.offer_id",
+		"wanted_requests.created_at", "wanted_requests.updated_at"}
+
+	wantedBidColumns = []string{"wanted_bids.id", "wanted_bids.request_id", "wanted_bids.shop_id",
+		"shops.name as shop_name", "wanted_bids.product_id", "products.name as product_name",
+		"wanted_bids.price", "wanted_bids.status", "wanted_bids.created_at", "wanted_bids.updated_at"}
+)
+
+// wantedProductMatches условие, что товар products подходит под запрос wanted_requests:
+// это сам запрошенный товар или товар из запрошенной категории либо её подкатегорий
+const wantedProductMatches = "(products.id = wanted_requests.product_id OR EXISTS (" +
+	"SELECT 1 FROM categories requested JOIN categories product_category " +
+	"ON product_category.lft BETWEEN requested.lft AND requested.rgt " +
+	"WHERE requested.id = wanted_requests.category_id AND product_category.id = products.category_id))"
+
+type WantedRepository struct {
+	db *sqlx.DB
+}
+
+func NewWantedRepository(db *sqlx.DB) *WantedRepository {
+	return &WantedRepository{db: db}
+}
+
+// InsertRequest публикует запрос покупателя. Запрошенные товар или категория должны существовать.
+func (r *WantedRepository) InsertRequest(
+	ctx context.Context,
+	request entity.WantedRequest,
+) (entity.WantedRequest, error) {
+	table, id, notFound := "products", request.ProductID, apperror.ErrProductNotFound
+	if request.CategoryID != nil {
+		table, id, notFound = "categories", request.CategoryID,
+			apperror.New(apperror.NotFound, "category not found", nil)
+	}
+
+	checkTargetQuery, args := squirrel.Select("count(*)").
+		From(table).
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err := r.db.GetContext(ctx, &count, checkTargetQuery, args...)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error checking requested item", err)
+	}
+	if count == 0 {
+		return entity.WantedRequest{}, notFound
+	}
+
+	insertRequestQuery, args := squirrel.Insert("wanted_requests").
+		Columns("user_id", "product_id", "category_id", "target_price", "currency", "deadline").
+		Values(request.UserID, request.ProductID, request.CategoryID, request.TargetPrice,
+			request.Currency, request.Deadline).
+		Suffix("returning id, user_id, product_id, category_id, target_price, currency, status, " +
+			"deadline, offer_id, created_at, updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var requestModel model.WantedRequest
+	err = r.db.QueryRowxContext(ctx, insertRequestQuery, args...).StructScan(&requestModel)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error inserting wanted request", err)
+	}
+
+	return requestModel.ConvertToEntity(), nil
+}
+
+// SelectUserRequests возвращает запросы покупателя, новые первыми, без ставок
+func (r *WantedRepository) SelectUserRequests(
+	ctx context.Context,
+	userID uint,
+	limit, offset int,
+) ([]entity.WantedRequest, int, error) {
+	selectRequestsQuery, args := squirrel.Select(wantedRequestColumns...).
+		Column("COUNT (*) OVER() as total_count").
+		From("wanted_requests").
+		Where(squirrel.Eq{"wanted_requests.user_id": userID}).
+		OrderBy("wanted_requests.created_at desc", "wanted_requests.id desc").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	return r.selectRequests(ctx, selectRequestsQuery, args, limit)
+}
+
+// GetRequest возвращает запрос покупателю вместе со всеми не отозванными ставками,
+// самые низкие первыми. Чужой запрос "не существует".
+func (r *WantedRepository) GetRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error) {
+	selectRequestQuery, args := squirrel.Select(wantedRequestColumns...).
+		From("wanted_requests").
+		Where(squirrel.Eq{"wanted_requests.id": requestID, "wanted_requests.user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var requestModel model.WantedRequest
+	err := r.db.GetContext(ctx, &requestModel, selectRequestQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WantedRequest{}, apperror.ErrWantedRequestNotFound
+		}
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error selecting wanted request", err)
+	}
+
+	request := requestModel.ConvertToEntity()
+	request.Bids, err = selectBids(ctx, r.db, squirrel.And{
+		squirrel.Eq{"wanted_bids.request_id": requestID},
+		squirrel.NotEq{"wanted_bids.status": entity.BidWithdrawn},
+	})
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	return request, nil
+}
+
+// SelectShopRequests возвращает открытые запросы, под которые подходит хотя бы одна доступная
+// позиция инвентаря магазина, ближайший дедлайн первым. К каждому запросу приложена
+// только ставка этого магазина, если она есть. Свои же запросы владельцу не показываются.
+func (r *WantedRepository) SelectShopRequests(
+	ctx context.Context,
+	shopID, userID uint,
+	limit, offset int,
+) ([]entity.WantedRequest, int, error) {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matchingInventory := squirrel.Select("1").
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		Where(squirrel.Eq{"shop_inventory.shop_id": shopID, "shop_inventory.is_available": true}).
+		Where(wantedProductMatches).
+		Prefix("EXISTS (").
+		Suffix(")")
+
+	selectRequestsQuery, args := squirrel.Select(wantedRequestColumns...).
+		Column("COUNT (*) OVER() as total_count").
+		From("wanted_requests").
+		Where(squirrel.Eq{"wanted_requests.status": entity.WantedOpen}).
+		Where(squirrel.Gt{"wanted_requests.deadline": time.Now()}).
+		Where(squirrel.NotEq{"wanted_requests.user_id": userID}).
+		Where(matchingInventory).
+		OrderBy("wanted_requests.deadline", "wanted_requests.id").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	requests, total, err := r.selectRequests(ctx, selectRequestsQuery, args, limit)
+	if err != nil || len(requests) == 0 {
+		return requests, total, err
+	}
+
+	requestIDs := make([]uint, len(requests))
+	for i, request := range requests {
+		requestIDs[i] = request.ID
+	}
+
+	bids, err := selectBids(ctx, r.db, squirrel.Eq{
+		"wanted_bids.request_id": requestIDs,
+		"wanted_bids.shop_id":    shopID,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, bid := range bids {
+		for i := range requests {
+			if requests[i].ID == bid.RequestID {
+				requests[i].Bids = append(requests[i].Bids, bid)
+			}
+		}
+	}
+
+	return requests, total, nil
+}
+
+func (r *WantedRepository) selectRequests(
+	ctx context.Context,
+	query string,
+	args []any,
+	limit int,
+) ([]entity.WantedRequest, int, error) {
+	requestsWithCount := make([]model.WantedRequestWithCount, 0, limit)
+
+	err := r.db.SelectContext(ctx, &requestsWithCount, query, args...)
+	if err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting wanted requests", err)
+	}
+
+	if len(requestsWithCount) == 0 {
+		return []entity.WantedRequest{}, 0, nil
+	}
+
+	requests := make([]entity.WantedRequest, len(requestsWithCount))
+	for i, requestModel := range requestsWithCount {
+		requests[i] = requestModel.ConvertToEntity()
+	}
+
+	return requests, requestsWithCount[0].TotalCount, nil
+}
+
+// selectBids возвращает ставки с названиями магазина и товара, самые низкие первыми
+func selectBids(ctx context.Context, q sqlx.QueryerContext, condition squirrel.Sqlizer) ([]entity.WantedBid, error) {
+	selectBidsQuery, args := squirrel.Select(wantedBidColumns...).
+		From("wanted_bids").
+		InnerJoin("shops on shops.id = wanted_bids.shop_id").
+		InnerJoin("products on products.id = wanted_bids.product_id").
+		Where(condition).
+		OrderBy("wanted_bids.price", "wanted_bids.updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var bidModels []model.WantedBid
+	err := sqlx.SelectContext(ctx, q, &bidModels, selectBidsQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting bids", err)
+	}
+
+	bids := make([]entity.WantedBid, len(bidModels))
+	for i, bidModel := range bidModels {
+		bids[i] = bidModel.ConvertToEntity()
+	}
+
+	return bids, nil
+}
+
+// lockOpenRequest блокирует строку запроса до конца транзакции и проверяет,
+// что по нему ещё принимаются ставки. condition сужает выборку до запросов,
+// доступных пользователю; для остальных возвращается ErrWantedRequestNotFound.
+func lockOpenRequest(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	condition squirrel.Sqlizer,
+) (model.WantedRequest, error) {
+	lockRequestQuery, args := squirrel.Select(wantedRequestColumns...).
+		From("wanted_requests").
+		Where(condition).
+		Suffix("for update").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var requestModel model.WantedRequest
+	err := tx.GetContext(ctx, &requestModel, lockRequestQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WantedRequest{}, apperror.ErrWantedRequestNotFound
+		}
+		return model.WantedRequest{}, apperror.New(apperror.DatabaseError, "error selecting wanted request", err)
+	}
+
+	if requestModel.Status != entity.WantedOpen || !requestModel.Deadline.After(time.Now()) {
+		return model.WantedRequest{}, apperror.New(apperror.Conflict, "wanted request is no longer open", nil)
+	}
+
+	return requestModel, nil
+}
+
+// UpsertBid делает или заменяет ставку магазина по открытому запросу. Товар ставки должен
+// подходить под запрос и быть доступен в инвентаре магазина. Отозванная ставка снова становится активной.
+func (r *WantedRepository) UpsertBid(ctx context.Context, bid entity.WantedBid, userID uint) (entity.WantedBid, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.WantedBid{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = isShopOwner(ctx, bid.ShopID, userID, tx)
+	if err != nil {
+		return entity.WantedBid{}, err
+	}
+
+	request, err := lockOpenRequest(ctx, tx, squirrel.Eq{"wanted_requests.id": bid.RequestID})
+	if err != nil {
+		return entity.WantedBid{}, err
+	}
+	if request.UserID == userID {
+		return entity.WantedBid{}, apperror.New(apperror.Forbidden, "cannot bid on your own wanted request", nil)
+	}
+
+	checkProductQuery, args := squirrel.Select("count(*)").
+		From("shop_inventory").
+		InnerJoin("products on products.id = shop_inventory.product_id").
+		InnerJoin("wanted_requests on wanted_requests.id = ?", bid.RequestID).
+		Where(squirrel.Eq{
+			"shop_inventory.shop_id":      bid.ShopID,
+			"shop_inventory.product_id":   bid.ProductID,
+			"shop_inventory.is_available": true,
+		}).
+		Where(wantedProductMatches).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err = tx.GetContext(ctx, &count, checkProductQuery, args...)
+	if err != nil {
+		return entity.WantedBid{}, apperror.New(apperror.DatabaseError, "error checking bid product", err)
+	}
+	if count == 0 {
+		return entity.WantedBid{}, apperror.New(apperror.BadRequest,
+			"product does not match the wanted request or is not available in the shop", nil)
+	}
+
+	upsertBidQuery, args := squirrel.Insert("wanted_bids").
+		Columns("request_id", "shop_id", "product_id", "price", "status", "updated_at").
+		Values(bid.RequestID, bid.ShopID, bid.ProductID, bid.Price, entity.BidActive, time.Now()).
+		Suffix("on conflict (request_id, shop_id) do update set " +
+			"product_id = excluded.product_id, price = excluded.price, " +
+			"status = excluded.status, updated_at = excluded.updated_at " +
+			"returning id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var bidID uint
+	err = tx.QueryRowxContext(ctx, upsertBidQuery, args...).Scan(&bidID)
+	if err != nil {
+		return entity.WantedBid{}, apperror.New(apperror.DatabaseError, "error saving bid", err)
+	}
+
+	bids, err := selectBids(ctx, tx, squirrel.Eq{"wanted_bids.id": bidID})
+	if err != nil {
+		return entity.WantedBid{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.WantedBid{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return bids[0], nil
+}
+
+// WithdrawBid отзывает активную ставку магазина, пока запрос открыт
+func (r *WantedRepository) WithdrawBid(ctx context.Context, requestID, shopID, userID uint) error {
+	withdrawBidQuery, args := squirrel.Update("wanted_bids").
+		Set("status", entity.BidWithdrawn).
+		Set("updated_at", time.Now()).
+		From("wanted_requests, shops").
+		Where("wanted_requests.id = wanted_bids.request_id").
+		Where("shops.id = wanted_bids.shop_id").
+		Where(squirrel.Eq{
+			"wanted_bids.request_id": requestID,
+			"wanted_bids.shop_id":    shopID,
+			"wanted_bids.status":     entity.BidActive,
+			"wanted_requests.status": entity.WantedOpen,
+			"shops.user_id":          userID,
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, withdrawBidQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error withdrawing bid", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error withdrawing bid", err)
+	}
+	if affected == 0 {
+		return apperror.ErrWantedBidNotFound
+	}
+
+	return nil
+}
+
+// CancelRequest отменяет открытый запрос покупателя, активные ставки по нему проигрывают
+func (r *WantedRepository) CancelRequest(ctx context.Context, requestID, userID uint) (entity.WantedRequest, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = lockOpenRequest(ctx, tx, squirrel.Eq{
+		"wanted_requests.id":      requestID,
+		"wanted_requests.user_id": userID,
+	})
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	request, err := closeRequest(ctx, tx, requestID, entity.WantedCancelled, nil)
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return request, nil
+}
+
+// AwardBid выбирает победившую ставку до дедлайна. По ставке создаётся уже принятый оффер
+// покупателя с ценой ставки и заказ по нему, как при обычном принятии оффера.
+// Товар ставки должен всё ещё продаваться магазином. Остальные активные ставки проигрывают.
+func (r *WantedRepository) AwardBid(
+	ctx context.Context,
+	requestID, bidID, userID uint,
+) (entity.WantedRequest, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	request, err := lockOpenRequest(ctx, tx, squirrel.Eq{
+		"wanted_requests.id":      requestID,
+		"wanted_requests.user_id": userID,
+	})
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	// позиция ставки блокируется раньше самой ставки, в том же порядке, что и при снятии
+	// позиции с продажи, которое сначала блокирует инвентарь, а потом переводит ставки в lost
+	lockItemQuery, args := squirrel.Select("shop_inventory.is_available and products.archived_at is null").
+		From("wanted_bids").
+		InnerJoin("shop_inventory on shop_inventory.shop_id = wanted_bids.shop_id " +
+			"and shop_inventory.product_id = wanted_bids.product_id").
+		InnerJoin("products on products.id = wanted_bids.product_id").
+		Where(squirrel.Eq{"wanted_bids.id": bidID, "wanted_bids.request_id": requestID}).
+		Suffix("for share of shop_inventory").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var available bool
+	err = tx.QueryRowxContext(ctx, lockItemQuery, args...).Scan(&available)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WantedRequest{}, apperror.ErrWantedBidNotFound
+		}
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error locking bid product", err)
+	}
+	if !available {
+		return entity.WantedRequest{}, apperror.New(apperror.Conflict,
+			"bid product is no longer available in the shop", nil)
+	}
+
+	selectBidQuery, args := squirrel.Select("id", "request_id", "shop_id", "product_id", "price", "status",
+		"created_at", "updated_at").
+		From("wanted_bids").
+		Where(squirrel.Eq{"id": bidID, "request_id": requestID, "status": entity.BidActive}).
+		Suffix("for update").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var bid model.WantedBid
+	err = tx.GetContext(ctx, &bid, selectBidQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WantedRequest{}, apperror.ErrWantedBidNotFound
+		}
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error selecting bid", err)
+	}
+
+	insertOfferQuery, args := squirrel.Insert("offers").
+		Columns("offer_price", "currency", "status", "shop_id", "user_id", "product_id").
+		Values(bid.Price, request.Currency, statusAccepted, bid.ShopID, userID, bid.ProductID).
+		Suffix("returning id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var offerID uint
+	err = tx.QueryRowxContext(ctx, insertOfferQuery, args...).Scan(&offerID)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error inserting offer into database", err)
+	}
+
+	err = insertOfferEvent(ctx, tx, model.OfferEvent{
+		OfferID:   offerID,
+		EventType: offerEventCreated,
+		ToStatus:  statusAccepted,
+		Price:     bid.Price,
+		ActorID:   &userID,
+		ActorRole: actorRoleBuyer,
+	})
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	err = insertOrderFromOffer(ctx, tx, offerID)
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	awardBidQuery, args := squirrel.Update("wanted_bids").
+		Set("status", entity.BidWon).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"id": bidID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err = tx.ExecContext(ctx, awardBidQuery, args...)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error awarding bid", err)
+	}
+
+	awarded, err := closeRequest(ctx, tx, requestID, entity.WantedAwarded, &offerID)
+	if err != nil {
+		return entity.WantedRequest{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return awarded, nil
+}
+
+// closeRequest переводит запрос в итоговый статус, а его активные ставки - в lost
+func closeRequest(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	requestID uint,
+	status string,
+	offerID *uint,
+) (entity.WantedRequest, error) {
+	now := time.Now()
+
+	loseBidsQuery, args := squirrel.Update("wanted_bids").
+		Set("status", entity.BidLost).
+		Set("updated_at", now).
+		Where(squirrel.Eq{"request_id": requestID, "status": entity.BidActive}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, loseBidsQuery, args...)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error closing bids", err)
+	}
+
+	closeRequestQuery, args := squirrel.Update("wanted_requests").
+		Set("status", status).
+		Set("offer_id", offerID).
+		Set("updated_at", now).
+		Where(squirrel.Eq{"id": requestID}).
+		Suffix("returning id, user_id, product_id, category_id, target_price, currency, status, " +
+			"deadline, offer_id, created_at, updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var requestModel model.WantedRequest
+	err = tx.QueryRowxContext(ctx, closeRequestQuery, args...).StructScan(&requestModel)
+	if err != nil {
+		return entity.WantedRequest{}, apperror.New(apperror.DatabaseError, "error closing wanted request", err)
+	}
+
+	return requestModel.ConvertToEntity(), nil
+}
+
+// loseItemBids переводит в lost активные ставки на позиции инвентаря, подходящие
+// под condition (по колонкам wanted_bids), когда позицию снимают с продажи
+func loseItemBids(ctx context.Context, tx *sqlx.Tx, condition squirrel.Eq) error {
+	loseBidsQuery, args := squirrel.Update("wanted_bids").
+		Set("status", entity.BidLost).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"status": entity.BidActive}).
+		Where(condition).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := tx.ExecContext(ctx, loseBidsQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error closing bids", err)
+	}
+
+	return nil
+}
+
+// ExpireRequests закрывает не более limit открытых запросов с прошедшим дедлайном
+// без выбранной ставки. Пакет берётся с skip locked, чтобы не ждать транзакции,
+// в которых покупатель как раз выбирает победителя.
+func (r *WantedRepository) ExpireRequests(ctx context.Context, now time.Time, limit int) (int, error) {
+	batchQuery, batchArgs := squirrel.Select("id").
+		From("wanted_requests").
+		Where(squirrel.Eq{"status": entity.WantedOpen}).
+		Where(squirrel.LtOrEq{"deadline": now}).
+		OrderBy("deadline").
+		Limit(uint64(limit)).
+		Suffix("for update skip locked").
+		MustSql()
+
+	updateQuery, updateArgs := squirrel.Update("wanted_requests").
+		Set("status", entity.WantedExpired).
+		Set("updated_at", now).
+		From("batch").
+		Where("wanted_requests.id = batch.id").
+		Suffix("returning wanted_requests.id").
+		MustSql()
+
+	loseBidsQuery, loseBidsArgs := squirrel.Update("wanted_bids").
+		Set("status", entity.BidLost).
+		Set("updated_at", now).
+		From("expired").
+		Where("wanted_bids.request_id = expired.id").
+		Where(squirrel.Eq{"wanted_bids.status": entity.BidActive}).
+		MustSql()
+
+	args := append(append(batchArgs, updateArgs...), loseBidsArgs...)
+	expireQuery, args := squirrel.Select("count(*)").
+		Prefix("with batch as ("+batchQuery+"), expired as ("+updateQuery+"), "+
+			"lost as ("+loseBidsQuery+")", args...).
+		From("expired").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var expired int
+	err := r.db.GetContext(ctx, &expired, expireQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error expiring wanted requests", err)
+	}
+
+	return expired, nil
+}
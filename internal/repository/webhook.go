@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var webhookColumns = []string{"shop_webhooks.id", "shop_webhooks.shop_id", "shop_webhooks.url",
+	"shop_webhooks.secret", "array_to_string(shop_webhooks.events, ',') as events", "shop_webhooks.created_at"}
+
+var webhookDeliveryColumns = []string{"webhook_deliveries.id", "webhook_deliveries.webhook_id",
+	"webhook_deliveries.event_type", "webhook_deliveries.payload", "webhook_deliveries.status",
+	"webhook_deliveries.attempts", "webhook_deliveries.next_attempt_at", "webhook_deliveries.last_status_code",
+	"webhook_deliveries.last_error", "webhook_deliveries.created_at", "webhook_deliveries.updated_at",
+	"webhook_deliveries.delivered_at"}
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// withWebhookShopOwner ограничивает выборку вебхуками магазина shopID, принадлежащего userID
+func withWebhookShopOwner(query squirrel.SelectBuilder, shopID, webhookID, userID uint) squirrel.SelectBuilder {
+	return query.
+		InnerJoin("shops on shops.id = shop_webhooks.shop_id").
+		Where(squirrel.Eq{"shop_webhooks.id": webhookID, "shop_webhooks.shop_id": shopID, "shops.user_id": userID})
+}
+
+func (r *WebhookRepository) InsertWebhook(
+	ctx context.Context,
+	webhook entity.Webhook,
+	userID uint,
+) (entity.Webhook, error) {
+	err := isShopOwner(ctx, webhook.ShopID, userID, r.db)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+
+	insertWebhookQuery, args := squirrel.Insert("shop_webhooks").
+		Columns("shop_id", "url", "secret", "events").
+		Values(webhook.ShopID, webhook.URL, webhook.Secret, webhook.Events).
+		Suffix("returning id, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	err = r.db.QueryRowxContext(ctx, insertWebhookQuery, args...).Scan(&webhook.ID, &webhook.CreatedAt)
+	if err != nil {
+		return entity.Webhook{}, apperror.New(apperror.DatabaseError, "error inserting webhook", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) SelectShopWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error) {
+	err := isShopOwner(ctx, shopID, userID, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	selectWebhooksQuery, args := squirrel.Select(webhookColumns...).
+		From("shop_webhooks").
+		Where(squirrel.Eq{"shop_id": shopID}).
+		OrderBy("id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var webhookModels []model.Webhook
+	err = r.db.SelectContext(ctx, &webhookModels, selectWebhooksQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting webhooks", err)
+	}
+
+	webhooks := make([]entity.Webhook, len(webhookModels))
+	for i, webhookModel := range webhookModels {
+		webhooks[i] = webhookModel.ConvertToEntity()
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook удаляет вебхук вместе с журналом его доставок
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, shopID, webhookID, userID uint) error {
+	deleteWebhookQuery, args := squirrel.Delete("shop_webhooks using shops").
+		Where("shops.id = shop_webhooks.shop_id").
+		Where(squirrel.Eq{
+			"shop_webhooks.id":      webhookID,
+			"shop_webhooks.shop_id": shopID,
+			"shops.user_id":         userID,
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, deleteWebhookQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error deleting webhook", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error deleting webhook", err)
+	}
+	if affected == 0 {
+		return apperror.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// SelectDeliveries возвращает журнал доставок вебхука, новые первыми
+func (r *WebhookRepository) SelectDeliveries(
+	ctx context.Context,
+	shopID, webhookID, userID uint,
+	limit, offset int,
+) ([]entity.WebhookDelivery, int, error) {
+	checkWebhookQuery, args := withWebhookShopOwner(squirrel.Select("count(*)").
+		From("shop_webhooks"), shopID, webhookID, userID).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	err := r.db.GetContext(ctx, &count, checkWebhookQuery, args...)
+	if err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error checking webhook", err)
+	}
+	if count == 0 {
+		return nil, 0, apperror.ErrWebhookNotFound
+	}
+
+	selectDeliveriesQuery, args := squirrel.Select(webhookDeliveryColumns...).
+		Column("COUNT (*) OVER() as total_count").
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"webhook_id": webhookID}).
+		OrderBy("created_at desc", "id desc").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	deliveriesWithCount := make([]model.WebhookDeliveryWithCount, 0, limit)
+	err = r.db.SelectContext(ctx, &deliveriesWithCount, selectDeliveriesQuery, args...)
+	if err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting webhook deliveries", err)
+	}
+
+	if len(deliveriesWithCount) == 0 {
+		return []entity.WebhookDelivery{}, 0, nil
+	}
+
+	deliveries := make([]entity.WebhookDelivery, len(deliveriesWithCount))
+	for i, deliveryModel := range deliveriesWithCount {
+		deliveries[i] = deliveryModel.ConvertToEntity()
+	}
+
+	return deliveries, deliveriesWithCount[0].TotalCount, nil
+}
+
+// Redeliver ставит доставку в очередь заново с полным набором попыток
+func (r *WebhookRepository) Redeliver(
+	ctx context.Context,
+	shopID, webhookID, deliveryID, userID uint,
+	now time.Time,
+) (entity.WebhookDelivery, error) {
+	redeliverQuery, args := squirrel.Update("webhook_deliveries").
+		Set("status", entity.WebhookDeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", now).
+		Set("updated_at", now).
+		From("shop_webhooks, shops").
+		Where("shop_webhooks.id = webhook_deliveries.webhook_id").
+		Where("shops.id = shop_webhooks.shop_id").
+		Where(squirrel.Eq{
+			"webhook_deliveries.id": deliveryID,
+			"shop_webhooks.id":      webhookID,
+			"shop_webhooks.shop_id": shopID,
+			"shops.user_id":         userID,
+		}).
+		Suffix("returning " + strings.Join(webhookDeliveryColumns, ", ")).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var deliveryModel model.WebhookDelivery
+	err := r.db.QueryRowxContext(ctx, redeliverQuery, args...).StructScan(&deliveryModel)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WebhookDelivery{}, apperror.ErrWebhookDeliveryNotFound
+		}
+		return entity.WebhookDelivery{}, apperror.New(apperror.DatabaseError,
+			"error scheduling webhook redelivery", err)
+	}
+
+	return deliveryModel.ConvertToEntity(), nil
+}
+
+// EnqueueDeliveries ставит событие в очередь на все вебхуки магазина, подписанные на него,
+// и возвращает количество созданных доставок. Вебхук, у которого уже есть доставка
+// с тем же dedupeKey, пропускается.
+func (r *WebhookRepository) EnqueueDeliveries(
+	ctx context.Context,
+	shopID uint,
+	eventType, dedupeKey string,
+	payload []byte,
+) (int, error) {
+	enqueueQuery, args := squirrel.Insert("webhook_deliveries").
+		Columns("webhook_id", "event_type", "dedupe_key", "payload").
+		Select(squirrel.Select("id").
+			Column(squirrel.Expr("?", eventType)).
+			Column(squirrel.Expr("?", dedupeKey)).
+			Column(squirrel.Expr("?::jsonb", string(payload))).
+			From("shop_webhooks").
+			Where(squirrel.Eq{"shop_id": shopID}).
+			Where(squirrel.Expr("? = any(events)", eventType))).
+		Suffix("on conflict (webhook_id, dedupe_key) do nothing").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, enqueueQuery, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error enqueuing webhook deliveries", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error enqueuing webhook deliveries", err)
+	}
+
+	return int(affected), nil
+}
+
+// ClaimDueDeliveries берёт в отправку не более limit доставок, время которых наступило.
+// Взятые доставки откладываются до leaseUntil, чтобы другие экземпляры их не отправили
+// повторно; если результат попытки так и не будет записан, доставка вернётся в работу.
+func (r *WebhookRepository) ClaimDueDeliveries(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]entity.WebhookAttempt, error) {
+	batchQuery, batchArgs := squirrel.Select("id").
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"status": entity.WebhookDeliveryPending}).
+		Where(squirrel.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at").
+		Limit(uint64(limit)).
+		Suffix("for update skip locked").
+		MustSql()
+
+	claimQuery, claimArgs := squirrel.Update("webhook_deliveries").
+		Set("next_attempt_at", leaseUntil).
+		From("batch").
+		Where("webhook_deliveries.id = batch.id").
+		Suffix("returning " + strings.Join(webhookDeliveryColumns, ", ")).
+		MustSql()
+
+	args := append(batchArgs, claimArgs...)
+	selectQuery, args := squirrel.Select("claimed.*", "shop_webhooks.url", "shop_webhooks.secret").
+		Prefix("with batch as ("+batchQuery+"), claimed as ("+claimQuery+")", args...).
+		From("claimed").
+		InnerJoin("shop_webhooks on shop_webhooks.id = claimed.webhook_id").
+		OrderBy("claimed.id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	attemptModels := make([]model.WebhookAttempt, 0, limit)
+	err := r.db.SelectContext(ctx, &attemptModels, selectQuery, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error claiming webhook deliveries", err)
+	}
+
+	attempts := make([]entity.WebhookAttempt, len(attemptModels))
+	for i, a := range attemptModels {
+		attempts[i] = a.ConvertToEntity()
+	}
+
+	return attempts, nil
+}
+
+// SaveAttemptResult записывает в журнал итог попытки доставки
+func (r *WebhookRepository) SaveAttemptResult(
+	ctx context.Context,
+	result entity.WebhookAttemptResult,
+	now time.Time,
+) error {
+	var (
+		statusCode  *int
+		lastError   *string
+		deliveredAt *time.Time
+	)
+	if result.StatusCode != 0 {
+		statusCode = &result.StatusCode
+	}
+	if result.Error != "" {
+		lastError = &result.Error
+	}
+	if result.Status == entity.WebhookDeliveryDelivered {
+		deliveredAt = &now
+	}
+
+	saveResult := squirrel.Update("webhook_deliveries").
+		Set("status", result.Status).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_status_code", statusCode).
+		Set("last_error", lastError).
+		Set("delivered_at", deliveredAt).
+		Set("updated_at", now).
+		Where(squirrel.Eq{"id": result.DeliveryID})
+	if result.Status == entity.WebhookDeliveryPending {
+		saveResult = saveResult.Set("next_attempt_at", result.NextAttemptAt)
+	}
+
+	saveResultQuery, args := saveResult.
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	_, err := r.db.ExecContext(ctx, saveResultQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error saving webhook delivery attempt", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE offer_rounds (
+    id SERIAL PRIMARY KEY,
+    offer_id INT NOT NULL,
+    round INT NOT NULL,
+    proposed_by VARCHAR(10) NOT NULL CHECK (proposed_by IN ('shop', 'buyer')),
+    price DECIMAL(10,2) NOT NULL,
+    previous_price DECIMAL(10,2) NOT NULL,
+    currency char(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE CASCADE,
+    UNIQUE (offer_id, round)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS offer_rounds;
+-- +goose StatementEnd
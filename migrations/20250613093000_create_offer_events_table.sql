@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE offer_events (
+    id BIGSERIAL PRIMARY KEY,
+    offer_id INT NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    actor_id INT,
+    actor_role VARCHAR(10) NOT NULL CHECK (actor_role IN ('buyer', 'shop', 'system')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (offer_id) REFERENCES offers(id),
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_offer_events_offer_id ON offer_events(offer_id, created_at);
+
+-- Журнал только дописывается: изменение и удаление событий запрещены
+CREATE FUNCTION offer_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'offer_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER offer_events_append_only
+    BEFORE UPDATE OR DELETE ON offer_events
+    FOR EACH ROW EXECUTE FUNCTION offer_events_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS offer_events;
+DROP FUNCTION IF EXISTS offer_events_append_only();
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Правила автоответа на офферы. Правило без product_id действует на весь магазин,
+-- правило для конкретной позиции инвентаря имеет приоритет над ним.
+CREATE TABLE shop_inventory_rules (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    product_id INT,
+    accept_from DECIMAL(10,2) CHECK (accept_from > 0),
+    decline_below DECIMAL(10,2) CHECK (decline_below > 0),
+    counter_price DECIMAL(10,2) CHECK (counter_price > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id, shop_id) REFERENCES shop_inventory(product_id, shop_id) ON DELETE CASCADE,
+    CHECK (accept_from IS NOT NULL OR decline_below IS NOT NULL OR counter_price IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX idx_shop_inventory_rules_shop_product ON shop_inventory_rules(shop_id, COALESCE(product_id, 0));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_inventory_rules;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Outbox: сообщения пишутся в той же транзакции, что и бизнес-изменение, и доставляются
+-- релеем с гарантией at-least-once. done_sinks - получатели, уже обработавшие сообщение:
+-- при повторе после частичной ошибки они пропускаются. dedupe_key не даёт записать
+-- одно и то же сообщение дважды.
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(50) NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    dedupe_key VARCHAR(255) UNIQUE,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processed', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    done_sinks TEXT[] NOT NULL DEFAULT '{}',
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_due ON outbox(next_attempt_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
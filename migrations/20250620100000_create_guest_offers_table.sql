@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Оффер гостя хранится в offers без покупателя (user_id IS NULL), а его контакты
+-- и хеш токена ссылки из письма - в guest_offers.
+ALTER TABLE offers ALTER COLUMN user_id DROP NOT NULL;
+
+CREATE TABLE guest_offers (
+    offer_id INT PRIMARY KEY,
+    guest_name VARCHAR(255) NOT NULL,
+    guest_email VARCHAR(255) NOT NULL,
+    guest_phone VARCHAR(50) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_guest_offers_guest_email ON guest_offers(guest_email);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS guest_offers;
+-- офферы гостей из журнала не удалить, поэтому откат возможен только без них
+ALTER TABLE offers ALTER COLUMN user_id SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- при регистрации офферы гостя ищутся по почте без учёта регистра
+DROP INDEX IF EXISTS idx_guest_offers_guest_email;
+CREATE INDEX idx_guest_offers_guest_email ON guest_offers(lower(guest_email));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_guest_offers_guest_email;
+CREATE INDEX idx_guest_offers_guest_email ON guest_offers(guest_email);
+-- +goose StatementEnd
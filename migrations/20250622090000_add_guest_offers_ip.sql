@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- адрес, с которого отправлен оффер гостя: по нему и по почте ограничивается число офферов
+ALTER TABLE guest_offers ADD COLUMN guest_ip VARCHAR(45) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_guest_offers_guest_ip ON guest_offers(guest_ip, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE guest_offers DROP COLUMN IF EXISTS guest_ip;
+-- +goose StatementEnd
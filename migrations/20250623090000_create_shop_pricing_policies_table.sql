@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ценовая политика магазина: минимальная цена оффера в процентах от цены позиции инвентаря.
+-- Магазины без записи используют значение по умолчанию из конфига.
+CREATE TABLE shop_pricing_policies (
+    shop_id INT PRIMARY KEY,
+    min_price_percent DECIMAL(5,2) NOT NULL CHECK (min_price_percent >= 0 AND min_price_percent <= 100),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_pricing_policies;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Курсы валют к евро в формате ЕЦБ: rate - сколько единиц валюты дают за 1 EUR.
+-- Загружаются из CSV или XML ЕЦБ командой cmd/rates.
+CREATE TABLE currency_rates (
+    currency CHAR(3) PRIMARY KEY,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO currency_rates (currency, rate) VALUES ('EUR', 1);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS currency_rates;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Обратные аукционы: покупатель публикует запрос на товар или категорию с целевой ценой,
+-- магазины с подходящими позициями инвентаря делают ставки до дедлайна.
+CREATE TABLE wanted_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    product_id INT,
+    category_id INT,
+    target_price DECIMAL(10,2) NOT NULL CHECK (target_price > 0),
+    currency char(3) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'awarded', 'expired', 'cancelled')),
+    deadline TIMESTAMP NOT NULL,
+    offer_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE,
+    FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE SET NULL,
+    CHECK (num_nonnulls(product_id, category_id) = 1)
+);
+
+CREATE INDEX idx_wanted_requests_user_id ON wanted_requests(user_id);
+CREATE INDEX idx_wanted_requests_open_deadline ON wanted_requests(deadline) WHERE status = 'open';
+
+-- Ставка магазина по запросу, одна на магазин: повторная ставка заменяет прежнюю
+CREATE TABLE wanted_bids (
+    id SERIAL PRIMARY KEY,
+    request_id INT NOT NULL,
+    shop_id INT NOT NULL,
+    product_id INT NOT NULL,
+    price DECIMAL(10,2) NOT NULL CHECK (price > 0),
+    status VARCHAR(50) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'won', 'lost', 'withdrawn')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (request_id) REFERENCES wanted_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id, shop_id) REFERENCES shop_inventory(product_id, shop_id) ON DELETE CASCADE,
+    UNIQUE (request_id, shop_id)
+);
+
+CREATE INDEX idx_wanted_bids_shop_id ON wanted_bids(shop_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS wanted_bids;
+DROP TABLE IF EXISTS wanted_requests;
+-- +goose StatementEnd
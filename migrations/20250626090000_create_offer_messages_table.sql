@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Переписка покупателя и владельца магазина по офферу. Писать можно, пока оффер в pending,
+-- read_at проставляется, когда сообщение открывает вторая сторона.
+CREATE TABLE offer_messages (
+    id SERIAL PRIMARY KEY,
+    offer_id INT NOT NULL,
+    sender_id INT NOT NULL,
+    sender_role VARCHAR(50) NOT NULL CHECK (sender_role IN ('buyer', 'shop')),
+    body TEXT NOT NULL CHECK (length(body) BETWEEN 1 AND 2000),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP,
+    FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE CASCADE,
+    FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_offer_messages_offer_id ON offer_messages(offer_id, id);
+CREATE INDEX idx_offer_messages_unread ON offer_messages(offer_id, sender_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS offer_messages;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Товар, заведённый магазином через API, принадлежит ему: только владелец магазина может
+-- его менять и архивировать. У товаров из сидов владельца нет. Архивный товар скрыт из каталога.
+ALTER TABLE products ADD COLUMN shop_id INT REFERENCES shops(id) ON DELETE SET NULL;
+ALTER TABLE products ADD COLUMN archived_at TIMESTAMP;
+
+CREATE INDEX idx_products_shop_id ON products(shop_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_shop_id;
+ALTER TABLE products DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE products DROP COLUMN IF EXISTS shop_id;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- История позиций инвентаря: снимок цены, валюты и доступности после каждого изменения.
+-- Ссылки на shop_inventory нет, чтобы история оставалась после удаления позиции.
+CREATE TABLE shop_inventory_history (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    product_id INT NOT NULL,
+    change VARCHAR(50) NOT NULL CHECK (change IN ('added', 'updated', 'removed')),
+    price DECIMAL(10, 2) NOT NULL,
+    currency char(3) NOT NULL,
+    is_available BOOLEAN NOT NULL,
+    changed_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_shop_inventory_history_item ON shop_inventory_history(shop_id, product_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_inventory_history;
+-- +goose StatementEnd
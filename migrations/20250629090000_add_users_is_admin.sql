@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Флаг администратора. Аккаунт администратора по умолчанию создаётся при старте приложения.
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users SET is_admin = TRUE WHERE email = 'admin@admin.com';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+-- +goose StatementEnd
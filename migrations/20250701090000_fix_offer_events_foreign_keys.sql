@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- actor_id хранится как история без внешнего ключа: ON DELETE SET NULL менял бы строки журнала,
+-- а журнал только дописывается, из-за чего нельзя было удалить ни одного пользователя, действовавшего по офферу.
+ALTER TABLE offer_events DROP CONSTRAINT offer_events_actor_id_fkey;
+
+-- Оффер с историей удалить нельзя: журнал не удаляется вместе с оффером, поэтому офферы
+-- не удаляются, а закрываются статусом (отклонён, отменён, истёк).
+ALTER TABLE offer_events DROP CONSTRAINT offer_events_offer_id_fkey;
+ALTER TABLE offer_events ADD CONSTRAINT offer_events_offer_id_fkey
+    FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE offer_events DROP CONSTRAINT offer_events_offer_id_fkey;
+ALTER TABLE offer_events ADD CONSTRAINT offer_events_offer_id_fkey
+    FOREIGN KEY (offer_id) REFERENCES offers(id);
+ALTER TABLE offer_events ADD CONSTRAINT offer_events_actor_id_fkey
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- dedupe_key - ключ события, по которому создана доставка. Сообщение outbox может быть
+-- обработано повторно, и на один вебхук по одному событию должна остаться одна доставка.
+ALTER TABLE webhook_deliveries ADD COLUMN dedupe_key VARCHAR(255);
+UPDATE webhook_deliveries SET dedupe_key = 'delivery:' || id;
+ALTER TABLE webhook_deliveries ALTER COLUMN dedupe_key SET NOT NULL;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_dedupe_key_unique UNIQUE (webhook_id, dedupe_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries DROP COLUMN dedupe_key;
+-- +goose StatementEnd
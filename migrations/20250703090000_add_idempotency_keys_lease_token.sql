@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Токен аренды ключа: сохранить ответ или освободить ключ может только запрос, который его занял.
+-- Без него запрос, переживший свою аренду, удалял бы или перезаписывал ключ следующего запроса.
+ALTER TABLE idempotency_keys ADD COLUMN lease_token VARCHAR(36);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys DROP COLUMN lease_token;
+-- +goose StatementEnd
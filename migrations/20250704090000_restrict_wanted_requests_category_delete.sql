@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Удаление категории не должно молча удалять запросы покупателей вместе со ставками магазинов:
+-- категорию, на которую ссылаются запросы, удалить нельзя.
+ALTER TABLE wanted_requests DROP CONSTRAINT wanted_requests_category_id_fkey;
+ALTER TABLE wanted_requests ADD CONSTRAINT wanted_requests_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wanted_requests DROP CONSTRAINT wanted_requests_category_id_fkey;
+ALTER TABLE wanted_requests ADD CONSTRAINT wanted_requests_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -90,7 +90,6 @@ func formDefaultUsers() ([]model.User, error) {
 			Email:    fmt.Sprintf("%s@%s.com", psw, psw),
 			Password: hash,
 			IsStore:  strings.Contains(psw, "shop"),
-			// IsAdmin: false,
 		})
 	}
 	return users, nil
@@ -127,13 +126,12 @@ func DefaultAdminAcc() {
 		Email:    "admin@admin.com",
 		Password: hash,
 		IsStore:  false,
-		// IsAdmin: true,
+		IsAdmin:  true,
 	}
 
-	// PLEASE ADD ADMIN FLAG WHEN POSSIBLE
 	q, args := squirrel.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store").
-		Values(admin.Name, admin.Email, admin.Phone, admin.Password, admin.IsStore).
+		Columns("name", "email", "phone_number", "password_hash", "is_store", "is_admin").
+		Values(admin.Name, admin.Email, admin.Phone, admin.Password, admin.IsStore, admin.IsAdmin).
 		PlaceholderFormat(squirrel.Dollar).MustSql()
 
 	_, err = pkgDB.Exec(q, args...)
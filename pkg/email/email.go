@@ -18,7 +18,50 @@ type MailerService interface {
 	StatusUpdate(offerID uint, status string, userMail string)
 	OfferReceived(offerID uint, userMail string)
 	Stop(ctx context.Context)
-	SendGuestOfferNotification(email string, subject string, body string)
+	Send(ctx context.Context, userMail string, subject string, body string) error
+}
+
+// RegisteredMessage тема и текст приветственного письма
+func RegisteredMessage(userName string) (subject, body string) {
+	return "Welcome to Strawberry!", fmt.Sprintf("Thank you for registering, %s.", userName)
+}
+
+// StatusUpdateMessage тема и текст письма о смене статуса оффера
+func StatusUpdateMessage(offerID uint, status string) (subject, body string) {
+	return fmt.Sprintf("Stawberry: Offer Status Update (ID %d)", offerID),
+		fmt.Sprintf("The status of your offer (%d) has been changed to: %s", offerID, status)
+}
+
+// OfferMessageReceivedMessage тема и текст письма о новом сообщении в переписке по офферу
+func OfferMessageReceivedMessage(offerID uint) (subject, body string) {
+	return fmt.Sprintf("Stawberry: New Message on Offer (ID %d)", offerID),
+		fmt.Sprintf("You have a new message about offer (%d). Open the offer to read and reply.", offerID)
+}
+
+// GuestOfferReceivedMessage тема и текст письма владельцу магазина о новом оффере гостя
+func GuestOfferReceivedMessage(
+	offerID, productID, shopID uint,
+	price float64,
+	currency, guestName, guestEmail, guestPhone string,
+) (subject, body string) {
+	return "New guest offer received", fmt.Sprintf(
+		"A new guest offer has been received:\n\n"+
+			"Offer ID: %d\n"+
+			"Product ID: %d\n"+
+			"Store ID: %d\n"+
+			"Proposed Price: %.2f %s\n"+
+			"Guest Name: %s\n"+
+			"Guest Email: %s\n"+
+			"Guest Phone: %s",
+		offerID, productID, shopID, price, currency, guestName, guestEmail, guestPhone)
+}
+
+// GuestOfferSentMessage тема и текст письма гостю со ссылкой на его оффер
+func GuestOfferSentMessage(offerID uint, price float64, currency, link string) (subject, body string) {
+	return "Your offer has been sent", fmt.Sprintf(
+		"Your offer (%d) of %.2f %s has been sent to the store.\n\n"+
+			"Use this link to track or cancel it: %s",
+		offerID, price, currency, link)
 }
 
 type SMTPMailer struct {
@@ -171,8 +214,7 @@ func (m *SMTPMailer) StatusUpdate(offerID uint, status string, userMail string)
 		return
 	}
 
-	subject := fmt.Sprintf("Stawberry: Offer Status Update (ID %d)", offerID)
-	body := fmt.Sprintf("The status of your offer (%d) has been changed to: %s", offerID, status)
+	subject, body := StatusUpdateMessage(offerID, status)
 	msg := m.createMessage(userMail, subject, body)
 
 	m.enqueue(msg)
@@ -195,8 +237,7 @@ func (m *SMTPMailer) Registered(userName string, userMail string) {
 		return
 	}
 
-	subject := "Welcome to Strawberry!"
-	body := fmt.Sprintf("Thank you for registering, %s.", userName)
+	subject, body := RegisteredMessage(userName)
 	msg := m.createMessage(userMail, subject, body)
 
 	m.enqueue(msg)
@@ -211,11 +252,15 @@ func (m *SMTPMailer) createMessage(to, subject, body string) *gomail.Message {
 	return msg
 }
 
-func (m *SMTPMailer) SendGuestOfferNotification(email string, subject string, body string) {
+// Send отправляет письмо сразу, минуя очередь, и возвращает ошибку, если все попытки
+// не удались. Используется релеем outbox, который сам повторяет неудачные отправки.
+func (m *SMTPMailer) Send(ctx context.Context, userMail string, subject string, body string) error {
 	if !m.enabled {
-		return
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	msg := m.createMessage(email, subject, body)
-	m.enqueue(msg)
+	return m.sendEmailWithRetry(m.createMessage(userMail, subject, body))
 }
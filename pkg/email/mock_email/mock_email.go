@@ -64,16 +64,18 @@ func (mr *MockMailerServiceMockRecorder) Registered(userName, userMail any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registered", reflect.TypeOf((*MockMailerService)(nil).Registered), userName, userMail)
 }
 
-// SendGuestOfferNotification mocks base method.
-func (m *MockMailerService) SendGuestOfferNotification(email, subject, body string) {
+// Send mocks base method.
+func (m *MockMailerService) Send(ctx context.Context, userMail, subject, body string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SendGuestOfferNotification", email, subject, body)
+	ret := m.ctrl.Call(m, "Send", ctx, userMail, subject, body)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// SendGuestOfferNotification indicates an expected call of SendGuestOfferNotification.
-func (mr *MockMailerServiceMockRecorder) SendGuestOfferNotification(email, subject, body any) *gomock.Call {
+// Send indicates an expected call of Send.
+func (mr *MockMailerServiceMockRecorder) Send(ctx, userMail, subject, body any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendGuestOfferNotification", reflect.TypeOf((*MockMailerService)(nil).SendGuestOfferNotification), email, subject, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMailerService)(nil).Send), ctx, userMail, subject, body)
 }
 
 // StatusUpdate mocks base method.
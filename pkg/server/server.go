@@ -18,11 +18,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Worker фоновый процесс, который останавливается вместе с сервером
+type Worker interface {
+	Stop(ctx context.Context)
+}
+
 func StartServer(
 	router *gin.Engine,
 	mailer email.MailerService,
 	cfg *config.ServerConfig,
-	log *zap.Logger) error {
+	log *zap.Logger,
+	workers ...Worker) error {
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           router,
@@ -60,6 +66,10 @@ func StartServer(
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
+		// воркеры останавливаются раньше мейлера, чтобы их последние письма попали в очередь
+		for _, w := range workers {
+			w.Stop(ctx)
+		}
 		mailer.Stop(ctx)
 
 		if err := srv.Shutdown(ctx); err != nil {
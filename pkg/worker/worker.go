@@ -0,0 +1,100 @@
+// Package worker запускает периодические фоновые проходы: закрытие просроченных записей,
+// доставку outbox и вебхуков, очистку ключей.
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Runner выполняет pass в отдельной горутине: первый проход сразу после Start, следующие -
+// через interval или сразу после Wake. Ошибка прохода пишется в лог, следующий проход
+// выполняется по расписанию.
+type Runner struct {
+	name     string
+	interval time.Duration
+	pass     func(ctx context.Context) error
+	log      *zap.Logger
+
+	wake    chan struct{}
+	ctx     context.Context
+	ctxCanc context.CancelFunc
+	done    chan struct{}
+}
+
+// NewRunner создаёт Runner. name используется в логах, например "offer expiry worker".
+func NewRunner(name string, interval time.Duration, pass func(ctx context.Context) error, log *zap.Logger) *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		name:     name,
+		interval: interval,
+		pass:     pass,
+		log:      log,
+		wake:     make(chan struct{}, 1),
+		ctx:      ctx,
+		ctxCanc:  cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start запускает проходы в отдельной горутине
+func (r *Runner) Start() {
+	r.log.Info("starting "+r.name, zap.Duration("interval", r.interval))
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.pass(r.ctx); err != nil && r.ctx.Err() == nil {
+				r.log.Error(r.name+" pass failed", zap.Error(err))
+			}
+
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			case <-r.wake:
+			}
+		}
+	}()
+}
+
+// Wake запускает внеочередной проход, не дожидаясь interval. Если проход уже идёт,
+// следующий начнётся сразу после него.
+func (r *Runner) Wake() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop останавливает проходы, дожидаясь завершения текущего, но не дольше ctx
+func (r *Runner) Stop(ctx context.Context) {
+	r.log.Info(r.name + " is stopping")
+	r.ctxCanc()
+
+	select {
+	case <-ctx.Done():
+		r.log.Info(r.name + " forcefully stopped (timeout)")
+	case <-r.done:
+		r.log.Info(r.name + " stopped")
+	}
+}
+
+// Backoff задержка перед следующей попыткой после attempts неудачных: base * 2^attempts,
+// но не больше limit
+func Backoff(base, limit time.Duration, attempts int) time.Duration {
+	delay := base
+	for range attempts {
+		delay *= 2
+		if delay >= limit {
+			return limit
+		}
+	}
+	return min(delay, limit)
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Runner", func() {
+	var passes chan struct{}
+
+	BeforeEach(func() {
+		passes = make(chan struct{}, 10)
+	})
+
+	newRunner := func(interval time.Duration, err error) *Runner {
+		return NewRunner("test worker", interval, func(context.Context) error {
+			passes <- struct{}{}
+			return err
+		}, zap.NewNop())
+	}
+
+	stop := func(r *Runner) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		r.Stop(ctx)
+	}
+
+	It("runs a pass immediately and stops gracefully", func() {
+		runner := newRunner(time.Hour, nil)
+
+		runner.Start()
+		Eventually(passes).Should(Receive())
+
+		stop(runner)
+		Expect(runner.done).To(BeClosed())
+	})
+
+	It("runs the next pass after the interval even if the previous one failed", func() {
+		runner := newRunner(10*time.Millisecond, errors.New("database is down"))
+
+		runner.Start()
+		defer stop(runner)
+
+		Eventually(passes).Should(Receive())
+		Eventually(passes).Should(Receive())
+	})
+
+	It("runs a pass as soon as it is woken up", func() {
+		runner := newRunner(time.Hour, nil)
+
+		runner.Start()
+		defer stop(runner)
+		Eventually(passes).Should(Receive())
+
+		runner.Wake()
+		Eventually(passes).Should(Receive())
+	})
+
+	It("gives up waiting for a pass that does not stop in time", func() {
+		blocked := make(chan struct{})
+		defer close(blocked)
+		runner := NewRunner("test worker", time.Hour, func(context.Context) error {
+			passes <- struct{}{}
+			<-blocked
+			return nil
+		}, zap.NewNop())
+
+		runner.Start()
+		Eventually(passes).Should(Receive())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		runner.Stop(ctx)
+		Expect(runner.done).NotTo(BeClosed())
+	})
+})
+
+var _ = Describe("Backoff", func() {
+	It("doubles the delay after every failed attempt up to the limit", func() {
+		Expect(Backoff(time.Minute, time.Hour, 0)).To(Equal(time.Minute))
+		Expect(Backoff(time.Minute, time.Hour, 3)).To(Equal(8 * time.Minute))
+		Expect(Backoff(time.Minute, time.Hour, 20)).To(Equal(time.Hour))
+		Expect(Backoff(2*time.Hour, time.Hour, 0)).To(Equal(time.Hour))
+	})
+})